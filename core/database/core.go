@@ -3,9 +3,11 @@ package database
 import (
 	"os"
 
+	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/metrics"
@@ -19,6 +21,7 @@ import (
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/kvstore/pebble"
 	"github.com/iotaledger/hive.go/kvstore/rocksdb"
 )
@@ -44,6 +47,19 @@ func init() {
 	}
 }
 
+var (
+	// the store prefixes of the data that is deleted by pruning and therefore compacted at the database cleanup.
+	prunedStorePrefixes = []byte{
+		common.StorePrefixMessages,
+		common.StorePrefixMessageMetadata,
+		common.StorePrefixMilestones,
+		common.StorePrefixChildren,
+		common.StorePrefixUnreferencedMessages,
+		common.StorePrefixIndexation,
+		common.StorePrefixUTXO,
+	}
+)
+
 var (
 	CorePlugin *node.CorePlugin
 	deps       dependencies
@@ -73,6 +89,7 @@ func initConfigPars(c *dig.Container) {
 		dig.Out
 		DatabaseEngine           database.Engine `name:"databaseEngine"`
 		DatabasePath             string          `name:"databasePath"`
+		DatabaseBackupPath       string          `name:"databaseBackupPath"`
 		DeleteDatabaseFlag       bool            `name:"deleteDatabase"`
 		DeleteAllFlag            bool            `name:"deleteAll"`
 		DatabaseDebug            bool            `name:"databaseDebug"`
@@ -87,6 +104,7 @@ func initConfigPars(c *dig.Container) {
 		return cfgResult{
 			DatabaseEngine:           dbEngine,
 			DatabasePath:             deps.NodeConfig.String(CfgDatabasePath),
+			DatabaseBackupPath:       deps.NodeConfig.String(CfgDatabaseBackupPath),
 			DeleteDatabaseFlag:       *deleteDatabase,
 			DeleteAllFlag:            *deleteAll,
 			DatabaseDebug:            deps.NodeConfig.Bool(CfgDatabaseDebug),
@@ -129,6 +147,7 @@ func provide(c *dig.Container) {
 		events := &database.Events{
 			DatabaseCleanup:    events.NewEvent(database.DatabaseCleanupCaller),
 			DatabaseCompaction: events.NewEvent(events.BoolCaller),
+			DatabaseMigration:  events.NewEvent(database.DatabaseMigrationCaller),
 		}
 
 		if deps.DeleteDatabaseFlag || deps.DeleteAllFlag {
@@ -136,6 +155,25 @@ func provide(c *dig.Container) {
 			if err := os.RemoveAll(deps.DatabasePath); err != nil {
 				CorePlugin.Panicf("deleting database folder failed: %s", err)
 			}
+
+			// delete the target database of an online migration
+			if err := os.RemoveAll(database.MigrationDatabasePath(deps.DatabasePath)); err != nil {
+				CorePlugin.Panicf("deleting migration database folder failed: %s", err)
+			}
+		}
+
+		if deps.DatabaseEngine != database.EngineMapDB {
+			oldDatabasePath, err := database.SwitchToMigratedDatabase(deps.DatabasePath, deps.DatabaseEngine)
+			switch {
+			case errors.Is(err, database.ErrMigrationIncomplete):
+				CorePlugin.LogWarn("Removed the target database of an incomplete online migration, the node was not shut down cleanly during the migration")
+			case errors.Is(err, database.ErrMigrationEngineMismatch):
+				CorePlugin.LogWarn(err)
+			case err != nil:
+				CorePlugin.Panicf("switching to the migrated database failed: %s", err)
+			case oldDatabasePath != "":
+				CorePlugin.LogInfof("Switched to the migrated database (engine: %s), the old database was moved to %s and can be deleted", deps.DatabaseEngine, oldDatabasePath)
+			}
 		}
 
 		targetEngine, err := database.CheckDatabaseEngine(deps.DatabasePath, true, deps.DatabaseEngine)
@@ -165,6 +203,9 @@ func provide(c *dig.Container) {
 				events,
 				true,
 				func() bool { return deps.Metrics.CompactionRunning.Load() },
+				database.PebbleCheckpointFunc(db),
+				database.PebbleCleanupFunc(db, prunedStorePrefixes...),
+				database.PebblePrefixSizeFunc(db),
 			)
 
 		case database.EngineRocksDB:
@@ -173,10 +214,12 @@ func provide(c *dig.Container) {
 				CorePlugin.Panicf("database initialization failed: %s", err)
 			}
 
+			store := rocksdb.New(db)
+
 			return database.New(
 				CorePlugin.Logger(),
 				deps.DatabasePath,
-				rocksdb.New(db),
+				store,
 				events,
 				true,
 				func() bool {
@@ -194,18 +237,46 @@ func provide(c *dig.Container) {
 					}
 					return false
 				},
+				database.CopyCheckpointFunc(store, database.EngineRocksDB),
+				// the rocksdb kvstore does not expose manual compactions
+				nil,
+				// the rocksdb kvstore does not expose size approximations
+				nil,
+			)
+
+		case database.EngineMapDB:
+			CorePlugin.LogWarn("Using the in-memory database engine, all data will be lost on shutdown!")
+
+			// in-memory databases have no database folder, do not support compaction and checkpoints
+			return database.New(
+				CorePlugin.Logger(),
+				"",
+				mapdb.NewMapDB(),
+				events,
+				false,
+				func() bool { return false },
+				nil,
+				nil,
+				nil,
 			)
+
 		default:
-			CorePlugin.Panicf("unknown database engine: %s, supported engines: pebble/rocksdb", targetEngine)
+			CorePlugin.Panicf("unknown database engine: %s, supported engines: pebble/rocksdb/mapdb", targetEngine)
 			return nil
 		}
 	}); err != nil {
 		CorePlugin.Panic(err)
 	}
 
-	if err := c.Provide(func(coordinatorPublicKeyRanges coordinator.PublicKeyRanges) *keymanager.KeyManager {
+	type keyManagerDeps struct {
+		dig.In
+		Storage                    *storage.Storage
+		CoordinatorPublicKeyRanges coordinator.PublicKeyRanges
+	}
+
+	if err := c.Provide(func(deps keyManagerDeps) *keymanager.KeyManager {
 		keyManager := keymanager.New()
-		for _, keyRange := range coordinatorPublicKeyRanges {
+		for _, keyRange := range deps.CoordinatorPublicKeyRanges {
 			pubKey, err := utils.ParseEd25519PublicKeyFromString(keyRange.Key)
 			if err != nil {
 				CorePlugin.Panicf("can't load public key ranges: %s", err)
@@ -214,6 +285,14 @@ func provide(c *dig.Container) {
 			keyManager.AddKeyRange(pubKey, keyRange.StartIndex, keyRange.EndIndex)
 		}
 
+		// add the key ranges that were announced by the coordinator
+		if err := deps.Storage.ForEachKeyRange(func(keyRange *keymanager.KeyRange) bool {
+			keyManager.AddKeyRange(keyRange.PublicKey[:], keyRange.StartIndex, keyRange.EndIndex)
+			return true
+		}); err != nil {
+			CorePlugin.Panicf("can't load announced public key ranges: %s", err)
+		}
+
 		return keyManager
 	}); err != nil {
 		CorePlugin.Panic(err)
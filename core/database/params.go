@@ -8,10 +8,12 @@ import (
 )
 
 const (
-	// the used database engine (pebble/rocksdb).
+	// the used database engine (pebble/rocksdb/mapdb).
 	CfgDatabaseEngine = "db.engine"
 	// the path to the database folder.
 	CfgDatabasePath = "db.path"
+	// the path to the folder where database backups are created.
+	CfgDatabaseBackupPath = "db.backupPath"
 	// whether to automatically start revalidation on startup if the database is corrupted.
 	CfgDatabaseAutoRevalidation = "db.autoRevalidation"
 	// ignore the check for corrupted databases (should only be used for debug reasons).
@@ -22,8 +24,9 @@ var params = &node.PluginParams{
 	Params: map[string]*flag.FlagSet{
 		"nodeConfig": func() *flag.FlagSet {
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
-			fs.String(CfgDatabaseEngine, database.EngineRocksDB, "the used database engine (pebble/rocksdb)")
+			fs.String(CfgDatabaseEngine, database.EngineRocksDB, "the used database engine (pebble/rocksdb/mapdb)")
 			fs.String(CfgDatabasePath, "mainnetdb", "the path to the database folder")
+			fs.String(CfgDatabaseBackupPath, "backups", "the path to the folder where database backups are created")
 			fs.Bool(CfgDatabaseAutoRevalidation, false, "whether to automatically start revalidation on startup if the database is corrupted")
 			fs.Bool(CfgDatabaseDebug, false, "ignore the check for corrupted databases (should only be used for debug reasons)")
 			return fs
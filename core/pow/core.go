@@ -10,6 +10,7 @@ import (
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/timeutil"
 )
 
 func init() {
@@ -31,11 +32,14 @@ var (
 
 const (
 	powsrvInitCooldown = 30 * time.Second
+	// the timeout for a single health check of a remote worker.
+	remoteWorkerHealthCheckTimeout = 5 * time.Second
 )
 
 type dependencies struct {
 	dig.In
-	Handler *pow.Handler
+	Handler    *pow.Handler
+	NodeConfig *configuration.Configuration `name:"nodeConfig"`
 }
 
 func provide(c *dig.Container) {
@@ -52,7 +56,20 @@ func provide(c *dig.Container) {
 		if err == nil && len(powsrvAPIKey) > 12 {
 			powsrvAPIKey = powsrvAPIKey[:12]
 		}
-		return pow.New(CorePlugin.Logger(), deps.MinPoWScore, deps.NodeConfig.Duration(CfgPoWRefreshTipsInterval), powsrvAPIKey, powsrvInitCooldown)
+
+		var workerPool *pow.WorkerPool
+		if endpoints := deps.NodeConfig.Strings(CfgPoWRemoteWorkersEndpoints); len(endpoints) > 0 {
+			// the auth token is optional, it is only needed if the workers were started with one
+			authToken, _ := utils.LoadStringFromEnvironment("POW_WORKER_TOKEN")
+
+			workers := make([]pow.RemoteWorker, len(endpoints))
+			for i, endpoint := range endpoints {
+				workers[i] = pow.NewHTTPWorker(endpoint, authToken)
+			}
+			workerPool = pow.NewWorkerPool(CorePlugin.Logger(), workers, deps.MinPoWScore, deps.NodeConfig.Duration(CfgPoWRemoteWorkersRequestTimeout), remoteWorkerHealthCheckTimeout)
+		}
+
+		return pow.New(CorePlugin.Logger(), deps.MinPoWScore, deps.NodeConfig.Duration(CfgPoWRefreshTipsInterval), powsrvAPIKey, powsrvInitCooldown, workerPool)
 	}); err != nil {
 		CorePlugin.Panic(err)
 	}
@@ -70,4 +87,18 @@ func run() {
 	}, shutdown.PriorityPoWHandler); err != nil {
 		CorePlugin.Panicf("failed to start worker: %s", err)
 	}
+
+	workerPool := deps.Handler.WorkerPool()
+	if workerPool == nil {
+		return
+	}
+
+	// check the health of the remote workers periodically, unhealthy workers are not used
+	if err := CorePlugin.Daemon().BackgroundWorker("PoW[RemoteWorkers]", func(shutdownSignal <-chan struct{}) {
+		workerPool.HealthCheck()
+		ticker := timeutil.NewTicker(workerPool.HealthCheck, deps.NodeConfig.Duration(CfgPoWRemoteWorkersHealthCheckInterval), shutdownSignal)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PriorityPoWHandler); err != nil {
+		CorePlugin.Panicf("failed to start worker: %s", err)
+	}
 }
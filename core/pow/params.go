@@ -11,6 +11,12 @@ import (
 const (
 	// CfgPoWRefreshTipsInterval is the interval for refreshing tips during PoW for spammer messages and messages passed without parents via API.
 	CfgPoWRefreshTipsInterval = "pow.refreshTipsInterval"
+	// CfgPoWRemoteWorkersEndpoints are the URLs of the remote workers the PoW is dispatched to.
+	CfgPoWRemoteWorkersEndpoints = "pow.remoteWorkers.endpoints"
+	// CfgPoWRemoteWorkersRequestTimeout is the timeout for a single PoW request to a remote worker.
+	CfgPoWRemoteWorkersRequestTimeout = "pow.remoteWorkers.requestTimeout"
+	// CfgPoWRemoteWorkersHealthCheckInterval is the interval for checking the health of the remote workers.
+	CfgPoWRemoteWorkersHealthCheckInterval = "pow.remoteWorkers.healthCheckInterval"
 )
 
 var params = &node.PluginParams{
@@ -18,6 +24,9 @@ var params = &node.PluginParams{
 		"nodeConfig": func() *flag.FlagSet {
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.Duration(CfgPoWRefreshTipsInterval, 5*time.Second, "interval for refreshing tips during PoW for spammer messages and messages passed without parents via API")
+			fs.StringSlice(CfgPoWRemoteWorkersEndpoints, []string{}, "the URLs of the remote workers the PoW is dispatched to")
+			fs.Duration(CfgPoWRemoteWorkersRequestTimeout, 30*time.Second, "the timeout for a single PoW request to a remote worker")
+			fs.Duration(CfgPoWRemoteWorkersHealthCheckInterval, 10*time.Second, "the interval for checking the health of the remote workers")
 			return fs
 		}(),
 	},
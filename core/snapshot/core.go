@@ -8,7 +8,9 @@ import (
 	"go.uber.org/dig"
 
 	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/coldstorage"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
@@ -19,6 +21,7 @@ import (
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
+	iotago "github.com/iotaledger/iota.go/v2"
 )
 
 const (
@@ -67,6 +70,7 @@ type dependencies struct {
 	Tangle               *tangle.Tangle
 	UTXOManager          *utxo.Manager
 	SnapshotManager      *snapshot.SnapshotManager
+	ColdStorage          *coldstorage.ColdStorage
 	NodeConfig           *configuration.Configuration `name:"nodeConfig"`
 	NetworkID            uint64                       `name:"networkId"`
 	DeleteAllFlag        bool                         `name:"deleteAll"`
@@ -74,6 +78,7 @@ type dependencies struct {
 	SnapshotsFullPath    string                       `name:"snapshotsFullPath"`
 	SnapshotsDeltaPath   string                       `name:"snapshotsDeltaPath"`
 	StorageMetrics       *metrics.StorageMetrics
+	KeyManager           *keymanager.KeyManager
 }
 
 func initConfigPars(c *dig.Container) {
@@ -103,9 +108,48 @@ func initConfigPars(c *dig.Container) {
 
 func provide(c *dig.Container) {
 
+	type coldStorageDeps struct {
+		dig.In
+		NodeConfig     *configuration.Configuration `name:"nodeConfig"`
+		DatabaseEngine database.Engine              `name:"databaseEngine"`
+		DeleteAllFlag  bool                         `name:"deleteAll"`
+	}
+
+	if err := c.Provide(func(deps coldStorageDeps) *coldstorage.ColdStorage {
+
+		if !deps.NodeConfig.Bool(CfgPruningColdStorageEnabled) {
+			// permanode mode disabled => pruned data gets deleted
+			return nil
+		}
+
+		if deps.DatabaseEngine == database.EngineMapDB {
+			// the cold storage uses the engine of the main database, the in-memory engine would lose the pruned data on restart
+			CorePlugin.Panicf("the cold storage of the permanode mode is not supported with the database engine \"%s\"", database.EngineMapDB)
+		}
+
+		coldStoragePath := deps.NodeConfig.String(CfgPruningColdStoragePath)
+
+		if deps.DeleteAllFlag {
+			// delete old cold storage folder
+			if err := os.RemoveAll(coldStoragePath); err != nil {
+				CorePlugin.Panicf("deleting cold storage folder failed: %s", err)
+			}
+		}
+
+		store, err := database.StoreWithDefaultSettings(coldStoragePath, true, deps.DatabaseEngine)
+		if err != nil {
+			CorePlugin.Panicf("cold storage initialization failed: %s", err)
+		}
+
+		return coldstorage.New(store)
+	}); err != nil {
+		CorePlugin.Panic(err)
+	}
+
 	type snapshotDeps struct {
 		dig.In
 		Database             *database.Database
+		ColdStorage          *coldstorage.ColdStorage
 		Storage              *storage.Storage
 		SyncManager          *syncmanager.SyncManager
 		UTXOManager          *utxo.Manager
@@ -113,9 +157,11 @@ func provide(c *dig.Container) {
 		BelowMaxDepth        int                          `name:"belowMaxDepth"`
 		NetworkID            uint64                       `name:"networkId"`
 		NetworkIDName        string                       `name:"networkIdName"`
+		DatabaseEngine       database.Engine              `name:"databaseEngine"`
 		PruningPruneReceipts bool                         `name:"pruneReceipts"`
 		SnapshotsFullPath    string                       `name:"snapshotsFullPath"`
 		SnapshotsDeltaPath   string                       `name:"snapshotsDeltaPath"`
+		Bech32HRP            iotago.NetworkPrefix         `name:"bech32HRP"`
 	}
 
 	if err := c.Provide(func(deps snapshotDeps) *snapshot.SnapshotManager {
@@ -172,6 +218,16 @@ func provide(c *dig.Container) {
 			CorePlugin.Panicf("%s has to be specified if %s is enabled", CfgPruningSizeTargetSize, CfgPruningSizeEnabled)
 		}
 
+		pruningDatabaseCleanup := deps.NodeConfig.Bool(CfgPruningDatabaseCleanup)
+		if pruningDatabaseCleanup && !deps.Database.DatabaseSupportsCleanup() {
+			CorePlugin.Panicf("%s is not supported with the database engine \"%s\"", CfgPruningDatabaseCleanup, deps.DatabaseEngine)
+		}
+
+		var retentionFilter *snapshot.RetentionFilter
+		if filter := loadRetentionFilter(deps.NodeConfig, deps.Bech32HRP); !filter.IsEmpty() {
+			retentionFilter = filter
+		}
+
 		return snapshot.NewSnapshotManager(CorePlugin.Daemon().ContextStopped(),
 			CorePlugin.Logger(),
 			deps.Database,
@@ -196,12 +252,51 @@ func provide(c *dig.Container) {
 			deps.NodeConfig.Float64(CfgPruningSizeThresholdPercentage),
 			deps.NodeConfig.Duration(CfgPruningSizeCooldownTime),
 			deps.PruningPruneReceipts,
+			pruningDatabaseCleanup,
+			deps.ColdStorage,
+			retentionFilter,
 		)
 	}); err != nil {
 		CorePlugin.Panic(err)
 	}
 }
 
+// loadRetentionFilter parses the pruning retention rules from the config.
+func loadRetentionFilter(nodeConfig *configuration.Configuration, bech32HRP iotago.NetworkPrefix) *snapshot.RetentionFilter {
+
+	var indexationPrefixes [][]byte
+	for _, prefix := range nodeConfig.Strings(CfgPruningRetentionIndexationPrefixes) {
+		if prefix == "" {
+			continue
+		}
+		indexationPrefixes = append(indexationPrefixes, []byte(prefix))
+	}
+
+	var addresses []*iotago.Ed25519Address
+	for _, bech32Address := range nodeConfig.Strings(CfgPruningRetentionAddresses) {
+		hrp, address, err := iotago.ParseBech32(bech32Address)
+		if err != nil {
+			CorePlugin.Panicf("parameter %s invalid: %s, error: %s", CfgPruningRetentionAddresses, bech32Address, err)
+		}
+
+		if hrp != bech32HRP {
+			CorePlugin.Panicf("parameter %s invalid: %s, wrong bech32 HRP: %s (expected: %s)", CfgPruningRetentionAddresses, bech32Address, hrp, bech32HRP)
+		}
+
+		ed25519Address, ok := address.(*iotago.Ed25519Address)
+		if !ok {
+			CorePlugin.Panicf("parameter %s invalid: %s, only ed25519 addresses are supported", CfgPruningRetentionAddresses, bech32Address)
+		}
+		addresses = append(addresses, ed25519Address)
+	}
+
+	if len(indexationPrefixes) > 0 || len(addresses) > 0 {
+		CorePlugin.LogInfof("Pruning retention enabled, keeping messages of %d indexation prefixes and %d addresses", len(indexationPrefixes), len(addresses))
+	}
+
+	return snapshot.NewRetentionFilter(indexationPrefixes, addresses)
+}
+
 func configure() {
 
 	if deps.DeleteAllFlag {
@@ -215,6 +310,22 @@ func configure() {
 		}
 	}
 
+	if deps.ColdStorage != nil {
+		CorePlugin.LogInfo("Permanode mode enabled, pruned milestone cones are moved to the cold storage")
+
+		if err := CorePlugin.Daemon().BackgroundWorker("Close cold storage", func(shutdownSignal <-chan struct{}) {
+			<-shutdownSignal
+
+			CorePlugin.LogInfo("Syncing cold storage to disk...")
+			if err := deps.ColdStorage.Close(); err != nil {
+				CorePlugin.Panicf("Syncing cold storage to disk... failed: %s", err)
+			}
+			CorePlugin.LogInfo("Syncing cold storage to disk... done")
+		}, shutdown.PriorityCloseDatabase); err != nil {
+			CorePlugin.Panicf("failed to start worker: %s", err)
+		}
+	}
+
 	snapshotInfo := deps.Storage.SnapshotInfo()
 
 	switch {
@@ -226,6 +337,16 @@ func configure() {
 		if err := deps.SnapshotManager.ImportSnapshots(); err != nil {
 			CorePlugin.Panic(err)
 		}
+
+		// the key manager was initialized before the key ranges of the snapshot were stored
+		if err := deps.Storage.ForEachKeyRange(func(keyRange *keymanager.KeyRange) bool {
+			if deps.KeyManager.AddKeyRange(keyRange.PublicKey[:], keyRange.StartIndex, keyRange.EndIndex) {
+				CorePlugin.LogInfof("added announced public key %x from snapshot, valid from milestone %d to %d", keyRange.PublicKey, keyRange.StartIndex, keyRange.EndIndex)
+			}
+			return true
+		}); err != nil {
+			CorePlugin.Panicf("can't load announced public key ranges: %s", err)
+		}
 	}
 
 }
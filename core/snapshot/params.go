@@ -36,6 +36,16 @@ const (
 	CfgPruningSizeCooldownTime = "pruning.size.cooldownTime"
 	// whether to delete old receipts data from the database
 	CfgPruningPruneReceipts = "pruning.pruneReceipts"
+	// whether to clean up the database after pruning to reclaim the disk space (only supported by the pebble engine)
+	CfgPruningDatabaseCleanup = "pruning.databaseCleanup"
+	// whether to move pruned milestone cones to the cold storage instead of deleting them (permanode)
+	CfgPruningColdStorageEnabled = "pruning.coldStorage.enabled"
+	// the path to the cold storage database folder
+	CfgPruningColdStoragePath = "pruning.coldStorage.path"
+	// messages with an indexation that starts with one of these prefixes are kept during pruning
+	CfgPruningRetentionIndexationPrefixes = "pruning.retention.indexationPrefixes"
+	// messages with transactions that touch one of these bech32 addresses are kept during pruning
+	CfgPruningRetentionAddresses = "pruning.retention.addresses"
 )
 
 var params = &node.PluginParams{
@@ -54,6 +64,11 @@ var params = &node.PluginParams{
 			fs.Float64(CfgPruningSizeThresholdPercentage, 10.0, "the percentage the database size gets reduced if the target size is reached")
 			fs.Duration(CfgPruningSizeCooldownTime, 5*time.Minute, "cooldown time between two pruning by database size events")
 			fs.Bool(CfgPruningPruneReceipts, false, "whether to delete old receipts data from the database")
+			fs.Bool(CfgPruningDatabaseCleanup, false, "whether to clean up the database after pruning to reclaim the disk space (only supported by the pebble engine)")
+			fs.Bool(CfgPruningColdStorageEnabled, false, "whether to move pruned milestone cones to the cold storage instead of deleting them (permanode)")
+			fs.String(CfgPruningColdStoragePath, "mainnetdb_cold", "the path to the cold storage database folder")
+			fs.StringSlice(CfgPruningRetentionIndexationPrefixes, []string{}, "messages with an indexation that starts with one of these prefixes are kept during pruning")
+			fs.StringSlice(CfgPruningRetentionAddresses, []string{}, "messages with transactions that touch one of these bech32 addresses are kept during pruning")
 			return fs
 		}(),
 	},
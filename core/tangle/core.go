@@ -22,6 +22,7 @@ import (
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/snapshot"
 	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
@@ -62,6 +63,7 @@ var (
 	onConfirmedMilestoneIndexChanged *events.Closure
 	onPruningMilestoneIndexChanged   *events.Closure
 	onLatestMilestoneIndexChanged    *events.Closure
+	onMilestoneConfirmed             *events.Closure
 )
 
 type dependencies struct {
@@ -69,6 +71,7 @@ type dependencies struct {
 	Database                 *database.Database
 	Storage                  *storage.Storage
 	Tangle                   *tangle.Tangle
+	MilestoneManager         *milestonemanager.MilestoneManager
 	Requester                *gossip.Requester
 	Broadcaster              *gossip.Broadcaster
 	SnapshotManager          *snapshot.SnapshotManager
@@ -187,6 +190,10 @@ Please restart HORNET with one of the following flags or enable "db.autoRevalida
 	}
 
 	configureEvents()
+
+	// key range announcements of the coordinator have to be applied for every confirmed milestone
+	deps.Tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+
 	deps.Tangle.ConfigureTangleProcessor()
 }
 
@@ -243,6 +250,43 @@ func configureEvents() {
 		// notify peers about our new latest milestone index
 		deps.Broadcaster.BroadcastHeartbeat(nil)
 	})
+
+	onMilestoneConfirmed = events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		applyKeyRangeAnnouncements(confirmation)
+	})
+}
+
+// applyKeyRangeAnnouncements adds the key ranges announced by the coordinator in the messages
+// referenced by the confirmed milestone to the key manager.
+func applyKeyRangeAnnouncements(confirmation *whiteflag.Confirmation) {
+	// announcements are indexation payloads, therefore they never contain ledger transactions
+	for _, messageID := range confirmation.Mutations.MessagesExcludedWithoutTransactions {
+		cachedMsg := deps.Storage.CachedMessageOrNil(messageID) // message +1
+		if cachedMsg == nil {
+			continue
+		}
+
+		keyRanges, err := deps.MilestoneManager.ApplyKeyRangeAnnouncement(cachedMsg.Message(), confirmation.MilestoneIndex)
+		cachedMsg.Release(true) // message -1
+
+		if err != nil {
+			if errors.Is(err, keymanager.ErrInvalidKeyRangeAnnouncement) || errors.Is(err, keymanager.ErrNotEnoughAnnouncementSignatures) {
+				CorePlugin.LogWarnf("ignoring key range announcement: %s", err)
+				continue
+			}
+			CorePlugin.Panicf("failed to apply key range announcement: %s", err)
+		}
+
+		for _, keyRange := range keyRanges {
+			CorePlugin.LogInfof("added announced public key %x, valid from milestone %d to %d", keyRange.PublicKey, keyRange.StartIndex, keyRange.EndIndex)
+		}
+
+		// a syncing node may have received milestones signed with the announced keys already,
+		// they are requested again to verify them with the new keys.
+		for _, msIndex := range deps.MilestoneManager.UnverifiedMilestoneIndexes(keyRanges) {
+			deps.Requester.Request(msIndex, msIndex, true)
+		}
+	}
 }
 
 func attachHeartbeatEvents() {
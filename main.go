@@ -21,6 +21,7 @@ import (
 	"github.com/gohornet/hornet/plugins/mqtt"
 	"github.com/gohornet/hornet/plugins/profiling"
 	"github.com/gohornet/hornet/plugins/prometheus"
+	"github.com/gohornet/hornet/plugins/quorum"
 	"github.com/gohornet/hornet/plugins/receipt"
 	"github.com/gohornet/hornet/plugins/restapi"
 	restapiv1 "github.com/gohornet/hornet/plugins/restapi/v1"
@@ -60,6 +61,7 @@ func main() {
 			receipt.Plugin,
 			prometheus.Plugin,
 			debug.Plugin,
+			quorum.Plugin,
 			faucet.Plugin,
 		}...),
 	)
@@ -11,4 +11,6 @@ const (
 	StorePrefixIndexation           byte = 7
 	StorePrefixUTXO                 byte = 8
 	StorePrefixAutopeering          byte = 9
+	StorePrefixKeyRanges            byte = 10
+	StorePrefixFaucet               byte = 11
 )
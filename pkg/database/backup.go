@@ -0,0 +1,222 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// the name of the folder that contains the database checkpoint inside a backup.
+	backupDatabaseFolderName = "database"
+	// the name of the folder that contains the state files inside a backup.
+	backupStateFilesFolderName = "state"
+	// the name of the file that contains the information about a backup.
+	backupInfoFileName = "backupinfo.json"
+)
+
+// BackupStateFile is a state file (e.g. of the coordinator or the migrator) that is part of a backup.
+type BackupStateFile struct {
+	// The name of the file inside the backup.
+	Name string `json:"name"`
+	// The path the file was copied from.
+	Path string `json:"path"`
+}
+
+// BackupInfo contains the information about a backup.
+type BackupInfo struct {
+	// The time the backup was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// The engine of the database.
+	Engine string `json:"databaseEngine"`
+	// The ledger index of the database at the time the backup was created.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The state files that are part of the backup.
+	StateFiles []*BackupStateFile `json:"stateFiles"`
+}
+
+// BackupDatabasePath returns the path of the database checkpoint inside the given backup.
+func BackupDatabasePath(backupDir string) string {
+	return filepath.Join(backupDir, backupDatabaseFolderName)
+}
+
+// CreateBackup creates a backup in the given directory.
+// The backup consists of a database checkpoint created by the given function and copies of the given state files.
+// State files that do not exist are skipped.
+// The caller has to make sure that neither the database nor the state files are modified while the backup is created.
+func CreateBackup(backupDir string, checkpointFunc CheckpointFunc, stateFilePaths ...string) (*BackupInfo, error) {
+
+	if _, err := os.Stat(backupDir); err == nil || !os.IsNotExist(err) {
+		return nil, fmt.Errorf("backup directory (%s) already exists", backupDir)
+	}
+
+	if err := os.MkdirAll(filepath.Join(backupDir, backupStateFilesFolderName), 0700); err != nil {
+		return nil, fmt.Errorf("could not create backup dir '%s': %w", backupDir, err)
+	}
+
+	databaseDir := BackupDatabasePath(backupDir)
+	if err := checkpointFunc(databaseDir); err != nil {
+		return nil, err
+	}
+
+	engine, err := LoadDatabaseEngineFromFile(filepath.Join(databaseDir, DatabaseInfoFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	// the ledger index is read from the checkpoint, since the ledger of a running node may have changed in the meantime
+	ledgerIndex, err := readLedgerIndex(databaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ledger index of the checkpoint: %w", err)
+	}
+
+	info := &BackupInfo{
+		CreatedAt:   time.Now(),
+		Engine:      string(engine),
+		LedgerIndex: ledgerIndex,
+		StateFiles:  []*BackupStateFile{},
+	}
+
+	for _, stateFilePath := range stateFilePaths {
+		if _, err := os.Stat(stateFilePath); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to check state file (%s): %w", stateFilePath, err)
+		}
+
+		stateFilePathAbs, err := filepath.Abs(stateFilePath)
+		if err != nil {
+			stateFilePathAbs = stateFilePath
+		}
+
+		stateFile := &BackupStateFile{
+			Name: fmt.Sprintf("%d_%s", len(info.StateFiles), filepath.Base(stateFilePath)),
+			Path: stateFilePathAbs,
+		}
+
+		if err := copyFile(stateFilePath, filepath.Join(backupDir, backupStateFilesFolderName, stateFile.Name)); err != nil {
+			return nil, fmt.Errorf("unable to copy state file (%s): %w", stateFilePath, err)
+		}
+
+		info.StateFiles = append(info.StateFiles, stateFile)
+	}
+
+	if err := utils.WriteJSONToFile(filepath.Join(backupDir, backupInfoFileName), info, 0660); err != nil {
+		return nil, fmt.Errorf("unable to write backup info file: %w", err)
+	}
+
+	return info, nil
+}
+
+// LoadBackupInfo loads the information about the backup in the given directory.
+func LoadBackupInfo(backupDir string) (*BackupInfo, error) {
+	info := &BackupInfo{}
+	if err := utils.ReadJSONFromFile(filepath.Join(backupDir, backupInfoFileName), info); err != nil {
+		return nil, fmt.Errorf("unable to read backup info file: %w", err)
+	}
+	return info, nil
+}
+
+// RestoreBackupDatabase copies the database checkpoint of the backup in the given directory to the database path.
+func RestoreBackupDatabase(backupDir string, databasePath string) error {
+
+	if _, err := os.Stat(databasePath); err == nil || !os.IsNotExist(err) {
+		return fmt.Errorf("database path (%s) already exists", databasePath)
+	}
+
+	return copyDir(BackupDatabasePath(backupDir), databasePath)
+}
+
+// RestoreBackupStateFiles copies the state files of the backup in the given directory to their original paths.
+// Existing state files are not overwritten.
+func RestoreBackupStateFiles(backupDir string, info *BackupInfo) error {
+
+	// check all paths first to not restore the state files partially
+	for _, stateFile := range info.StateFiles {
+		if _, err := os.Stat(stateFile.Path); err == nil || !os.IsNotExist(err) {
+			return fmt.Errorf("state file (%s) already exists", stateFile.Path)
+		}
+	}
+
+	for _, stateFile := range info.StateFiles {
+		if err := os.MkdirAll(filepath.Dir(stateFile.Path), 0700); err != nil {
+			return fmt.Errorf("could not create state file dir '%s': %w", filepath.Dir(stateFile.Path), err)
+		}
+
+		if err := copyFile(filepath.Join(backupDir, backupStateFilesFolderName, stateFile.Name), stateFile.Path); err != nil {
+			return fmt.Errorf("unable to restore state file (%s): %w", stateFile.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// readLedgerIndex reads the ledger index of the database in the given path.
+func readLedgerIndex(databasePath string) (milestone.Index, error) {
+
+	store, err := StoreWithDefaultSettings(databasePath, false)
+	if err != nil {
+		return 0, err
+	}
+
+	// clean up store
+	defer func() {
+		store.Shutdown()
+		_ = store.Close()
+	}()
+
+	return utxo.New(store).ReadLedgerIndex()
+}
+
+// copyDir recursively copies the source directory to the target directory.
+func copyDir(sourceDir string, targetDir string) error {
+	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(targetDir, relPath)
+
+		if info.IsDir() {
+			return os.MkdirAll(targetPath, 0700)
+		}
+
+		return copyFile(path, targetPath)
+	})
+}
+
+// copyFile copies the source file to the target file and syncs it to disk.
+func copyFile(sourceFilePath string, targetFilePath string) (err error) {
+	source, err := os.Open(sourceFilePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	target, err := os.OpenFile(targetFilePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0660)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := target.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err := io.Copy(target, source); err != nil {
+		return err
+	}
+
+	return target.Sync()
+}
@@ -0,0 +1,106 @@
+package database_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+
+	tempDir := t.TempDir()
+
+	databasePath := filepath.Join(tempDir, "db")
+	stateFilePath := filepath.Join(tempDir, "coordinator.state")
+	backupPath := filepath.Join(tempDir, "backup")
+
+	store, err := database.StoreWithDefaultSettings(databasePath, true, database.EnginePebble)
+	require.NoError(t, err)
+	require.NoError(t, utxo.New(store).StoreLedgerIndex(1337))
+	require.NoError(t, store.Set([]byte("key"), []byte("value")))
+	require.NoError(t, store.Flush())
+	require.NoError(t, store.Close())
+
+	require.NoError(t, os.WriteFile(stateFilePath, []byte("state"), 0600))
+
+	info, err := database.CreateBackup(backupPath, func(targetDir string) error {
+		return database.CreateCheckpointFromPath(databasePath, targetDir)
+	}, stateFilePath, filepath.Join(tempDir, "missing.state"))
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(1337), info.LedgerIndex)
+	require.Equal(t, database.EnginePebble, info.Engine)
+	require.Len(t, info.StateFiles, 1)
+
+	// the backup must not overwrite existing backups
+	_, err = database.CreateBackup(backupPath, func(targetDir string) error {
+		return database.CreateCheckpointFromPath(databasePath, targetDir)
+	})
+	require.Error(t, err)
+
+	loadedInfo, err := database.LoadBackupInfo(backupPath)
+	require.NoError(t, err)
+	require.Equal(t, info.LedgerIndex, loadedInfo.LedgerIndex)
+	require.Equal(t, info.StateFiles[0].Path, loadedInfo.StateFiles[0].Path)
+
+	// the restore must not overwrite existing files
+	require.Error(t, database.RestoreBackupDatabase(backupPath, databasePath))
+	require.Error(t, database.RestoreBackupStateFiles(backupPath, loadedInfo))
+
+	require.NoError(t, os.RemoveAll(databasePath))
+	require.NoError(t, os.Remove(stateFilePath))
+
+	require.NoError(t, database.RestoreBackupDatabase(backupPath, databasePath))
+	require.NoError(t, database.RestoreBackupStateFiles(backupPath, loadedInfo))
+
+	stateFileContent, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("state"), stateFileContent)
+
+	restoredStore, err := database.StoreWithDefaultSettings(databasePath, false, database.EnginePebble)
+	require.NoError(t, err)
+	defer func() { _ = restoredStore.Close() }()
+
+	value, err := restoredStore.Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), []byte(value))
+
+	ledgerIndex, err := utxo.New(restoredStore).ReadLedgerIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(1337), ledgerIndex)
+}
+
+func TestCopyCheckpoint(t *testing.T) {
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint")
+
+	// more keys than fit into a single batch of the copy
+	store := mapdb.NewMapDB()
+	for i := 0; i < 25000; i++ {
+		key := make([]byte, 4)
+		binary.LittleEndian.PutUint32(key, uint32(i))
+		require.NoError(t, store.Set(key, key))
+	}
+
+	require.NoError(t, database.CopyCheckpointFunc(store, database.EnginePebble)(checkpointPath))
+
+	checkpointStore, err := database.StoreWithDefaultSettings(checkpointPath, false, database.EnginePebble)
+	require.NoError(t, err)
+	defer func() { _ = checkpointStore.Close() }()
+
+	count := 0
+	require.NoError(t, checkpointStore.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		require.Equal(t, []byte(key), []byte(value))
+		count++
+		return true
+	}))
+	require.Equal(t, 25000, count)
+}
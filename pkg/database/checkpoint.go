@@ -0,0 +1,160 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	pebbleDB "github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/rocksdb"
+)
+
+const (
+	// DatabaseInfoFileName is the name of the "database info file" in the database folder.
+	DatabaseInfoFileName = "dbinfo"
+
+	// the amount of key-value pairs that are committed at once while copying a database into a checkpoint.
+	checkpointCopyBatchSize = 10000
+)
+
+var (
+	// ErrCheckpointNotSupported is returned if the database engine does not support checkpoints.
+	ErrCheckpointNotSupported = errors.New("database engine does not support checkpoints")
+)
+
+// CheckpointFunc creates a consistent copy of the database in the given directory.
+type CheckpointFunc func(targetDir string) error
+
+// PebbleCheckpointFunc returns a CheckpointFunc that uses the native checkpoint feature of pebble.
+func PebbleCheckpointFunc(db *pebbleDB.DB) CheckpointFunc {
+	return func(targetDir string) error {
+		// the WAL is disabled, so the memtables need to be flushed to include all data in the checkpoint.
+		if err := db.Flush(); err != nil {
+			return fmt.Errorf("flushing database failed: %w", err)
+		}
+
+		if err := db.Checkpoint(targetDir); err != nil {
+			return fmt.Errorf("creating checkpoint failed: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// CopyCheckpointFunc returns a CheckpointFunc that copies all key-value pairs into a new database with the given engine.
+// This is used for engines where the kvstore does not expose the native checkpoint feature (rocksdb).
+// The iteration is based on an implicit snapshot of the database, so the copy is consistent as well.
+func CopyCheckpointFunc(store kvstore.KVStore, engine Engine) CheckpointFunc {
+	return func(targetDir string) error {
+		targetStore, err := StoreWithDefaultSettings(targetDir, true, engine)
+		if err != nil {
+			return fmt.Errorf("checkpoint database initialization failed: %w", err)
+		}
+		defer func() { _ = targetStore.Close() }()
+
+		copyBytes := func(source []byte) []byte {
+			cpy := make([]byte, len(source))
+			copy(cpy, source)
+			return cpy
+		}
+
+		// the key-value pairs are committed in chunks to not hold the whole database in memory
+		batch := targetStore.Batched()
+		batchSize := 0
+
+		var errDB error
+		if err := store.Iterate(kvstore.EmptyPrefix, func(key []byte, value kvstore.Value) bool {
+			if errDB = batch.Set(copyBytes(key), copyBytes(value)); errDB != nil {
+				return false
+			}
+
+			batchSize++
+			if batchSize < checkpointCopyBatchSize {
+				return true
+			}
+
+			if errDB = batch.Commit(); errDB != nil {
+				return false
+			}
+			batch = targetStore.Batched()
+			batchSize = 0
+
+			return true
+		}); err != nil {
+			batch.Cancel()
+			return fmt.Errorf("database iteration failed: %w", err)
+		}
+
+		if errDB != nil {
+			batch.Cancel()
+			return fmt.Errorf("checkpoint database copy failed: %w", errDB)
+		}
+
+		if err := batch.Commit(); err != nil {
+			return fmt.Errorf("checkpoint database commit failed: %w", err)
+		}
+
+		return targetStore.Flush()
+	}
+}
+
+// createCheckpoint creates a checkpoint of the database with the given function in the target directory
+// and copies the "database info file" of the source database.
+func createCheckpoint(checkpointFunc CheckpointFunc, databaseDir string, targetDir string) error {
+
+	if _, err := os.Stat(targetDir); err == nil || !os.IsNotExist(err) {
+		return fmt.Errorf("checkpoint directory (%s) already exists", targetDir)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(targetDir), 0700); err != nil {
+		return fmt.Errorf("could not create checkpoint parent dir '%s': %w", filepath.Dir(targetDir), err)
+	}
+
+	if err := checkpointFunc(targetDir); err != nil {
+		return err
+	}
+
+	engine, err := LoadDatabaseEngineFromFile(filepath.Join(databaseDir, DatabaseInfoFileName))
+	if err != nil {
+		return err
+	}
+
+	return storeDatabaseInfoToFile(filepath.Join(targetDir, DatabaseInfoFileName), engine)
+}
+
+// CreateCheckpointFromPath opens the database in the given path and creates a checkpoint in the target directory.
+// The database must not be in use by a running node.
+func CreateCheckpointFromPath(databasePath string, targetDir string) error {
+
+	engine, err := CheckDatabaseEngine(databasePath, false)
+	if err != nil {
+		return err
+	}
+
+	switch engine {
+	case EnginePebble:
+		db, err := NewPebbleDB(databasePath, nil, false)
+		if err != nil {
+			return fmt.Errorf("database initialization failed: %w", err)
+		}
+		defer func() { _ = db.Close() }()
+
+		return createCheckpoint(PebbleCheckpointFunc(db), databasePath, targetDir)
+
+	case EngineRocksDB:
+		db, err := NewRocksDB(databasePath)
+		if err != nil {
+			return fmt.Errorf("database initialization failed: %w", err)
+		}
+		store := rocksdb.New(db)
+		defer func() { _ = store.Close() }()
+
+		return createCheckpoint(CopyCheckpointFunc(store, engine), databasePath, targetDir)
+
+	default:
+		return fmt.Errorf("database engine does not support checkpoints: %s, supported engines: pebble/rocksdb", engine)
+	}
+}
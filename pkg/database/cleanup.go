@@ -0,0 +1,30 @@
+package database
+
+import (
+	"fmt"
+
+	pebbleDB "github.com/cockroachdb/pebble"
+)
+
+// CleanupFunc cleans up the database, e.g. by reclaiming the space of deleted entries.
+type CleanupFunc func() error
+
+// PebbleCleanupFunc returns a CleanupFunc that manually compacts the key ranges of the given store prefixes.
+// The compaction removes the tombstones of deleted entries (e.g. after pruning) and reclaims the disk space.
+func PebbleCleanupFunc(db *pebbleDB.DB, storePrefixes ...byte) CleanupFunc {
+	return func() error {
+		// the tombstones in the memtables need to be flushed to be part of the compaction.
+		if err := db.Flush(); err != nil {
+			return fmt.Errorf("flushing database failed: %w", err)
+		}
+
+		for _, prefix := range storePrefixes {
+			start, end := prefixKeyRange([]byte{prefix})
+			if err := db.Compact(start, end); err != nil {
+				return fmt.Errorf("compacting database range of prefix %d failed: %w", prefix, err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore/pebble"
+)
+
+func TestPebbleCleanup(t *testing.T) {
+
+	db, err := database.NewPebbleDB(filepath.Join(t.TempDir(), "db"), nil, false)
+	require.NoError(t, err)
+
+	store := pebble.New(db)
+	defer func() { _ = store.Close() }()
+
+	dbEvents := &database.Events{
+		DatabaseCleanup:    events.NewEvent(database.DatabaseCleanupCaller),
+		DatabaseCompaction: events.NewEvent(events.BoolCaller),
+		DatabaseMigration:  events.NewEvent(database.DatabaseMigrationCaller),
+	}
+
+	prunedStore := store.WithRealm([]byte{1})
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, prunedStore.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(t, prunedStore.DeletePrefix([]byte("key")))
+
+	// without a cleanup function, the database does not support cleanups
+	unsupportedDatabase := database.New(nil, "", store, dbEvents, false, func() bool { return false }, nil, nil, nil)
+	require.False(t, unsupportedDatabase.DatabaseSupportsCleanup())
+	require.ErrorIs(t, unsupportedDatabase.CleanupDatabases(), database.ErrNothingToCleanUp)
+
+	var cleanups []*database.DatabaseCleanup
+	dbEvents.DatabaseCleanup.Attach(events.NewClosure(func(cleanup *database.DatabaseCleanup) {
+		cleanups = append(cleanups, cleanup)
+	}))
+
+	cleanupDatabase := database.New(nil, "", store, dbEvents, false, func() bool { return false }, nil, database.PebbleCleanupFunc(db, 1, 0xff), nil)
+	require.True(t, cleanupDatabase.DatabaseSupportsCleanup())
+
+	cleanupDatabase.RunGarbageCollection()
+	require.Len(t, cleanups, 2)
+	require.True(t, cleanups[0].End.IsZero())
+	require.False(t, cleanups[1].End.IsZero())
+
+	has, err := prunedStore.Has([]byte("key0"))
+	require.NoError(t, err)
+	require.False(t, has)
+}
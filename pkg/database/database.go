@@ -19,6 +19,7 @@ const (
 	EngineUnknown = "unknown"
 	EngineRocksDB = "rocksdb"
 	EnginePebble  = "pebble"
+	EngineMapDB   = "mapdb"
 )
 
 var (
@@ -59,28 +60,36 @@ func DatabaseCleanupCaller(handler interface{}, params ...interface{}) {
 type Events struct {
 	DatabaseCleanup    *events.Event
 	DatabaseCompaction *events.Event
+	DatabaseMigration  *events.Event
 }
 
 // Database holds the underlying KVStore and database specific functions.
 type Database struct {
 	log                   *logger.Logger
 	databaseDir           string
-	store                 kvstore.KVStore
+	store                 *mirroredStore
 	events                *Events
 	compactionSupported   bool
 	compactionRunningFunc func() bool
+	checkpointFunc        CheckpointFunc
+	cleanupFunc           CleanupFunc
+	prefixSizeFunc        PrefixSizeFunc
 	garbageCollectionLock syncutils.Mutex
+	checkpointLock        syncutils.Mutex
 }
 
 // New creates a new Database instance.
-func New(log *logger.Logger, databaseDirectory string, kvStore kvstore.KVStore, events *Events, compactionSupported bool, compactionRunningFunc func() bool) *Database {
+func New(log *logger.Logger, databaseDirectory string, kvStore kvstore.KVStore, events *Events, compactionSupported bool, compactionRunningFunc func() bool, checkpointFunc CheckpointFunc, cleanupFunc CleanupFunc, prefixSizeFunc PrefixSizeFunc) *Database {
 	return &Database{
 		log:                   log,
 		databaseDir:           databaseDirectory,
-		store:                 kvStore,
+		store:                 newMirroredStore(kvStore),
 		events:                events,
 		compactionSupported:   compactionSupported,
 		compactionRunningFunc: compactionRunningFunc,
+		checkpointFunc:        checkpointFunc,
+		cleanupFunc:           cleanupFunc,
+		prefixSizeFunc:        prefixSizeFunc,
 	}
 }
 
@@ -104,14 +113,36 @@ func (db *Database) CompactionRunning() bool {
 	return db.compactionRunningFunc()
 }
 
+// CheckpointSupported returns whether the database engine supports checkpoints.
+func (db *Database) CheckpointSupported() bool {
+	return db.checkpointFunc != nil
+}
+
+// CreateCheckpoint creates a consistent checkpoint of the database in the given directory.
+// The "database info file" is copied as well, so the checkpoint can be used as a database folder.
+func (db *Database) CreateCheckpoint(targetDir string) error {
+	if !db.CheckpointSupported() {
+		return ErrCheckpointNotSupported
+	}
+
+	db.checkpointLock.Lock()
+	defer db.checkpointLock.Unlock()
+
+	return createCheckpoint(db.checkpointFunc, db.databaseDir, targetDir)
+}
+
+// DatabaseSupportsCleanup returns whether the database engine supports cleanup.
 func (db *Database) DatabaseSupportsCleanup() bool {
-	// ToDo: add this to the db initialization of the different database engines in the core module
-	return false
+	return db.cleanupFunc != nil
 }
 
+// CleanupDatabases cleans up the database.
 func (db *Database) CleanupDatabases() error {
-	// ToDo: add this to the db initialization of the different database engines in the core module
-	return ErrNothingToCleanUp
+	if !db.DatabaseSupportsCleanup() {
+		return ErrNothingToCleanUp
+	}
+
+	return db.cleanupFunc()
 }
 
 func (db *Database) RunGarbageCollection() {
@@ -156,5 +187,9 @@ func (db *Database) RunGarbageCollection() {
 
 // Size returns the size of the database.
 func (db *Database) Size() (int64, error) {
+	if db.databaseDir == "" {
+		// in-memory databases do not have a database folder
+		return 0, nil
+	}
 	return utils.FolderSize(db.databaseDir)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/kvstore/pebble"
 	"github.com/iotaledger/hive.go/kvstore/rocksdb"
 )
@@ -24,8 +25,9 @@ func DatabaseEngine(engine string) (Engine, error) {
 	switch engine {
 	case EngineRocksDB:
 	case EnginePebble:
+	case EngineMapDB:
 	default:
-		return "", fmt.Errorf("unknown database engine: %s, supported engines: pebble/rocksdb", engine)
+		return "", fmt.Errorf("unknown database engine: %s, supported engines: pebble/rocksdb/mapdb", engine)
 	}
 
 	return Engine(engine), nil
@@ -35,8 +37,13 @@ func DatabaseEngine(engine string) (Engine, error) {
 // This function stores a so called "database info file" in the database folder or
 // checks if an existing "database info file" contains the correct engine.
 // Otherwise the files in the database folder are not compatible.
+// In-memory databases are not persisted, so no files are checked or created for them.
 func CheckDatabaseEngine(dbPath string, createDatabaseIfNotExists bool, dbEngine ...Engine) (Engine, error) {
 
+	if len(dbEngine) > 0 && dbEngine[0] == EngineMapDB {
+		return EngineMapDB, nil
+	}
+
 	if createDatabaseIfNotExists && len(dbEngine) == 0 {
 		return EngineRocksDB, errors.New("the database engine must be specified if the database should be newly created")
 	}
@@ -58,7 +65,7 @@ func CheckDatabaseEngine(dbPath string, createDatabaseIfNotExists bool, dbEngine
 	var targetEngine Engine
 
 	// check if the database info file exists and if it should be created
-	dbInfoFilePath := filepath.Join(dbPath, "dbinfo")
+	dbInfoFilePath := filepath.Join(dbPath, DatabaseInfoFileName)
 	_, err = os.Stat(dbInfoFilePath)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -87,7 +94,8 @@ func CheckDatabaseEngine(dbPath string, createDatabaseIfNotExists bool, dbEngine
 			if dbEngineFromInfoFile != dbEngine[0] {
 				return EngineUnknown, fmt.Errorf(`database engine does not match the configuration: '%v' != '%v'
 
-If you want to use another database engine, you can use the tool './hornet tool db-migration' to convert the current database.`, dbEngineFromInfoFile, dbEngine[0])
+If you want to use another database engine, you can use the tool './hornet tool db-migration' to convert the current database,
+or migrate the database while the node is running via the control route of the REST API ('/api/v1/control/database/migrate').`, dbEngineFromInfoFile, dbEngine[0])
 			}
 		}
 
@@ -148,7 +156,10 @@ func StoreWithDefaultSettings(path string, createDatabaseIfNotExists bool, dbEng
 		}
 		return rocksdb.New(db), nil
 
+	case EngineMapDB:
+		return mapdb.NewMapDB(), nil
+
 	default:
-		return nil, fmt.Errorf("unknown database engine: %s, supported engines: pebble/rocksdb", dbEngine)
+		return nil, fmt.Errorf("unknown database engine: %s, supported engines: pebble/rocksdb/mapdb", dbEngine)
 	}
 }
@@ -0,0 +1,54 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/database"
+)
+
+func TestCheckDatabaseEngine(t *testing.T) {
+
+	databasePath := filepath.Join(t.TempDir(), "db")
+
+	_, err := database.CheckDatabaseEngine(databasePath, false)
+	require.Error(t, err)
+
+	engine, err := database.CheckDatabaseEngine(databasePath, true, database.EnginePebble)
+	require.NoError(t, err)
+	require.Equal(t, database.Engine(database.EnginePebble), engine)
+
+	// the engine is loaded from the database info file
+	engine, err = database.CheckDatabaseEngine(databasePath, false)
+	require.NoError(t, err)
+	require.Equal(t, database.Engine(database.EnginePebble), engine)
+
+	_, err = database.CheckDatabaseEngine(databasePath, false, database.EngineRocksDB)
+	require.Error(t, err)
+}
+
+func TestMapDBEngine(t *testing.T) {
+
+	engine, err := database.DatabaseEngine(database.EngineMapDB)
+	require.NoError(t, err)
+
+	databasePath := filepath.Join(t.TempDir(), "db")
+
+	store, err := database.StoreWithDefaultSettings(databasePath, true, engine)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Set([]byte("key"), []byte("value")))
+	value, err := store.Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), []byte(value))
+
+	// in-memory databases must not touch the disk
+	_, err = os.Stat(databasePath)
+	require.True(t, os.IsNotExist(err))
+
+	_, err = database.DatabaseEngine("unknown")
+	require.Error(t, err)
+}
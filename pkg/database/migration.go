@@ -0,0 +1,720 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/syncutils"
+)
+
+const (
+	// the suffix of the folder of the target database of an online migration.
+	migrationFolderSuffix = "_migration"
+	// the suffix of the folder the old database is moved to after the switch to the migrated database.
+	migrationOldFolderSuffix = "_old"
+	// the name of the file that marks a completely migrated target database.
+	migrationCompletedFileName = "migrationcompleted"
+	// the amount of keys that are collected by the background copier before they are copied.
+	migrationCopyBatchSize = 1000
+	// the amount of locks the keys are distributed to while a migration is running.
+	migrationKeyLockCount = 256
+	// the interval in which the progress of the migration is reported.
+	migrationProgressInterval = 1 * time.Second
+)
+
+var (
+	// ErrMigrationRunning is returned if a database migration is already running.
+	ErrMigrationRunning = errors.New("database migration already running")
+	// ErrMigrationNotSupported is returned if the database can't be migrated online.
+	ErrMigrationNotSupported = errors.New("database migration not supported")
+	// ErrMigrationIncomplete is returned if an incomplete migrated database was found at startup.
+	ErrMigrationIncomplete = errors.New("incomplete database migration found")
+	// ErrMigrationEngineMismatch is returned if the engine of a completed migration does not match the configured engine.
+	ErrMigrationEngineMismatch = errors.New("database engine of the completed migration does not match the configuration")
+)
+
+// DatabaseMigration contains the progress of an online database migration.
+type DatabaseMigration struct {
+	// The engine of the target database.
+	Engine Engine
+	// The time the migration was started.
+	Start time.Time
+	// The time the background copier finished.
+	End time.Time
+	// The amount of existing keys that were copied.
+	KeysCopied int64
+	// The estimated percentage of the copied keys based on the size of the databases.
+	Percentage float64
+}
+
+func (m *DatabaseMigration) MarshalJSON() ([]byte, error) {
+
+	migration := struct {
+		Engine     string  `json:"engine"`
+		Start      int64   `json:"start"`
+		End        int64   `json:"end"`
+		KeysCopied int64   `json:"keysCopied"`
+		Percentage float64 `json:"percentage"`
+	}{
+		Engine:     string(m.Engine),
+		Start:      0,
+		End:        0,
+		KeysCopied: m.KeysCopied,
+		Percentage: m.Percentage,
+	}
+
+	if !m.Start.IsZero() {
+		migration.Start = m.Start.Unix()
+	}
+
+	if !m.End.IsZero() {
+		migration.End = m.End.Unix()
+	}
+
+	return json.Marshal(migration)
+}
+
+func DatabaseMigrationCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*DatabaseMigration))(params[0].(*DatabaseMigration))
+}
+
+// MigrationDatabasePath returns the path of the target database of an online migration.
+func MigrationDatabasePath(databasePath string) string {
+	return filepath.Clean(databasePath) + migrationFolderSuffix
+}
+
+// migrationTarget is the database all writes are mirrored to during an online migration.
+type migrationTarget struct {
+	path   string
+	engine Engine
+	store  kvstore.KVStore
+	// copied is set after all existing keys were copied by the background copier.
+	copied atomic.Bool
+	// err is set if a mirrored write failed, the migration is aborted in that case.
+	err     error
+	errLock syncutils.Mutex
+}
+
+func (t *migrationTarget) setError(err error) {
+	t.errLock.Lock()
+	defer t.errLock.Unlock()
+
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *migrationTarget) error() error {
+	t.errLock.Lock()
+	defer t.errLock.Unlock()
+
+	return t.err
+}
+
+// mirrorState is shared between all realms of a mirroredStore.
+// Writes don't take any lock as long as no migration is running.
+type mirrorState struct {
+	// the target of the running migration, it is read without locking by the writes.
+	target atomic.Value
+	// the amount of writes that saw no running migration and are not finished yet.
+	// a started migration waits for them, because the copier could miss their keys otherwise.
+	unmirroredWrites atomic.Int64
+	// the read lock is held by the mirrored writes and the copier,
+	// the write lock is held while the target is set or removed.
+	lock syncutils.RWMutex
+	// the mirrored writes and the copier lock the keys they write,
+	// so that the copier can't overwrite mirrored writes with outdated values.
+	keyLocks [migrationKeyLockCount]syncutils.Mutex
+}
+
+func (m *mirrorState) loadTarget() *migrationTarget {
+	target, _ := m.target.Load().(*migrationTarget)
+	return target
+}
+
+func (m *mirrorState) storeTarget(target *migrationTarget) {
+	m.target.Store(target)
+}
+
+// lockKeys locks the given keys of the given realm and returns the function to unlock them.
+// All keys are locked if no keys are given.
+func (m *mirrorState) lockKeys(realm kvstore.Realm, keys ...kvstore.Key) func() {
+
+	var indexes []int
+	if len(keys) == 0 {
+		indexes = make([]int, migrationKeyLockCount)
+		for i := range indexes {
+			indexes[i] = i
+		}
+	} else {
+		indexesMap := make(map[int]struct{}, len(keys))
+		for _, key := range keys {
+			hash := fnv.New32a()
+			_, _ = hash.Write(realm)
+			_, _ = hash.Write(key)
+			indexesMap[int(hash.Sum32()%migrationKeyLockCount)] = struct{}{}
+		}
+
+		indexes = make([]int, 0, len(indexesMap))
+		for index := range indexesMap {
+			indexes = append(indexes, index)
+		}
+		// the locks are always acquired in the same order to prevent deadlocks
+		sort.Ints(indexes)
+	}
+
+	for _, index := range indexes {
+		m.keyLocks[index].Lock()
+	}
+
+	return func() {
+		for i := len(indexes) - 1; i >= 0; i-- {
+			m.keyLocks[indexes[i]].Unlock()
+		}
+	}
+}
+
+// mirroredStore is a kvstore that mirrors all writes to the target database of an online migration.
+// Reads are always served by the source database.
+type mirroredStore struct {
+	state *mirrorState
+	store kvstore.KVStore
+}
+
+func newMirroredStore(store kvstore.KVStore) *mirroredStore {
+	return &mirroredStore{
+		state: &mirrorState{},
+		store: store,
+	}
+}
+
+// write applies the given write to the source database and mirrors it to the target database
+// with the same realm if a migration is running. All keys are locked during a migration if no keys are given.
+func (s *mirroredStore) write(sourceWrite func() error, targetWrite func(target kvstore.KVStore) error, keys ...kvstore.Key) error {
+
+	s.state.unmirroredWrites.Inc()
+	if s.state.loadTarget() == nil {
+		defer s.state.unmirroredWrites.Dec()
+		return sourceWrite()
+	}
+	s.state.unmirroredWrites.Dec()
+
+	s.state.lock.RLock()
+	defer s.state.lock.RUnlock()
+
+	// the migration could have been finished in the meantime
+	target := s.state.loadTarget()
+	if target == nil {
+		return sourceWrite()
+	}
+
+	unlockKeys := s.state.lockKeys(s.store.Realm(), keys...)
+	defer unlockKeys()
+
+	if err := sourceWrite(); err != nil {
+		return err
+	}
+
+	if target.error() != nil {
+		return nil
+	}
+
+	if err := targetWrite(target.store.WithRealm(s.store.Realm())); err != nil {
+		target.setError(fmt.Errorf("mirroring write to target database failed: %w", err))
+	}
+
+	return nil
+}
+
+func (s *mirroredStore) WithRealm(realm kvstore.Realm) kvstore.KVStore {
+	return &mirroredStore{
+		state: s.state,
+		store: s.store.WithRealm(realm),
+	}
+}
+
+func (s *mirroredStore) Realm() kvstore.Realm {
+	return s.store.Realm()
+}
+
+func (s *mirroredStore) Shutdown() {
+	s.state.lock.RLock()
+	defer s.state.lock.RUnlock()
+
+	s.store.Shutdown()
+	if target := s.state.loadTarget(); target != nil {
+		target.store.Shutdown()
+	}
+}
+
+func (s *mirroredStore) Iterate(prefix kvstore.KeyPrefix, kvConsumerFunc kvstore.IteratorKeyValueConsumerFunc, direction ...kvstore.IterDirection) error {
+	return s.store.Iterate(prefix, kvConsumerFunc, direction...)
+}
+
+func (s *mirroredStore) IterateKeys(prefix kvstore.KeyPrefix, consumerFunc kvstore.IteratorKeyConsumerFunc, direction ...kvstore.IterDirection) error {
+	return s.store.IterateKeys(prefix, consumerFunc, direction...)
+}
+
+func (s *mirroredStore) Clear() error {
+	return s.write(s.store.Clear, func(target kvstore.KVStore) error {
+		return target.Clear()
+	})
+}
+
+func (s *mirroredStore) Get(key kvstore.Key) (kvstore.Value, error) {
+	return s.store.Get(key)
+}
+
+func (s *mirroredStore) Set(key kvstore.Key, value kvstore.Value) error {
+	return s.write(func() error {
+		return s.store.Set(key, value)
+	}, func(target kvstore.KVStore) error {
+		return target.Set(key, value)
+	}, key)
+}
+
+func (s *mirroredStore) Has(key kvstore.Key) (bool, error) {
+	return s.store.Has(key)
+}
+
+func (s *mirroredStore) Delete(key kvstore.Key) error {
+	return s.write(func() error {
+		return s.store.Delete(key)
+	}, func(target kvstore.KVStore) error {
+		return target.Delete(key)
+	}, key)
+}
+
+func (s *mirroredStore) DeletePrefix(prefix kvstore.KeyPrefix) error {
+	return s.write(func() error {
+		return s.store.DeletePrefix(prefix)
+	}, func(target kvstore.KVStore) error {
+		return target.DeletePrefix(prefix)
+	})
+}
+
+func (s *mirroredStore) Batched() kvstore.BatchedMutations {
+	return &mirroredBatchedMutations{
+		store: s,
+		batch: s.store.Batched(),
+	}
+}
+
+func (s *mirroredStore) Flush() error {
+	if err := s.store.Flush(); err != nil {
+		return err
+	}
+
+	if target := s.state.loadTarget(); target != nil {
+		s.state.lock.RLock()
+		defer s.state.lock.RUnlock()
+
+		// the target could have been closed in the meantime
+		if s.state.loadTarget() == target && target.error() == nil {
+			if err := target.store.Flush(); err != nil {
+				target.setError(fmt.Errorf("mirroring write to target database failed: %w", err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes the source database and finishes a running migration.
+// The target database is only marked as completed if all existing keys were copied
+// and all writes were mirrored successfully, otherwise it is removed.
+func (s *mirroredStore) Close() error {
+	if err := s.store.Close(); err != nil {
+		return err
+	}
+
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+
+	target := s.state.loadTarget()
+	if target == nil {
+		return nil
+	}
+	s.state.storeTarget(nil)
+
+	if err := target.store.Flush(); err != nil {
+		target.setError(err)
+	}
+
+	if err := target.store.Close(); err != nil {
+		target.setError(err)
+	}
+
+	if !target.copied.Load() || target.error() != nil {
+		return os.RemoveAll(target.path)
+	}
+
+	return os.WriteFile(filepath.Join(target.path, migrationCompletedFileName), []byte(target.engine), 0660)
+}
+
+// mirroredBatchedMutations collects the mutations to apply them to the target database on commit.
+// The mutations are collected even if no migration is running, because a migration could be started before the commit.
+type mirroredBatchedMutations struct {
+	store     *mirroredStore
+	batch     kvstore.BatchedMutations
+	mutations []*mirroredMutation
+}
+
+type mirroredMutation struct {
+	key    kvstore.Key
+	value  kvstore.Value
+	delete bool
+}
+
+func (b *mirroredBatchedMutations) Set(key kvstore.Key, value kvstore.Value) error {
+	if err := b.batch.Set(key, value); err != nil {
+		return err
+	}
+	b.mutations = append(b.mutations, &mirroredMutation{key: key, value: value})
+	return nil
+}
+
+func (b *mirroredBatchedMutations) Delete(key kvstore.Key) error {
+	if err := b.batch.Delete(key); err != nil {
+		return err
+	}
+	b.mutations = append(b.mutations, &mirroredMutation{key: key, delete: true})
+	return nil
+}
+
+func (b *mirroredBatchedMutations) Cancel() {
+	b.batch.Cancel()
+	b.mutations = nil
+}
+
+func (b *mirroredBatchedMutations) Commit() error {
+
+	if len(b.mutations) == 0 {
+		return b.batch.Commit()
+	}
+
+	keys := make([]kvstore.Key, len(b.mutations))
+	for i, mutation := range b.mutations {
+		keys[i] = mutation.key
+	}
+
+	return b.store.write(b.batch.Commit, func(target kvstore.KVStore) error {
+		targetBatch := target.Batched()
+		for _, mutation := range b.mutations {
+			if mutation.delete {
+				if err := targetBatch.Delete(mutation.key); err != nil {
+					targetBatch.Cancel()
+					return err
+				}
+				continue
+			}
+
+			if err := targetBatch.Set(mutation.key, mutation.value); err != nil {
+				targetBatch.Cancel()
+				return err
+			}
+		}
+		return targetBatch.Commit()
+	}, keys...)
+}
+
+// MigrationRunning returns whether an online database migration is running or waiting for the restart of the node.
+func (db *Database) MigrationRunning() bool {
+	return db.store.state.loadTarget() != nil
+}
+
+// MigrateEngine starts an online migration of the database to the given engine.
+// All writes are mirrored to the new database while the existing keys are copied in the background.
+// After the copy finished, the writes are still mirrored until the node is shut down.
+// The migrated database is used at the next start of the node if the configured engine matches the target engine.
+// The progress is reported via the DatabaseMigration event.
+func (db *Database) MigrateEngine(engine Engine, abortSignal <-chan struct{}) error {
+
+	if db.databaseDir == "" {
+		return errors.WithMessage(ErrMigrationNotSupported, "in-memory databases are not persistent")
+	}
+
+	if engine == EngineMapDB {
+		return errors.WithMessage(ErrMigrationNotSupported, "the target database engine is not persistent")
+	}
+
+	sourceEngine, err := LoadDatabaseEngineFromFile(filepath.Join(db.databaseDir, DatabaseInfoFileName))
+	if err != nil {
+		return err
+	}
+
+	if sourceEngine == engine {
+		return errors.WithMessagef(ErrMigrationNotSupported, "the database already uses the engine: %s", engine)
+	}
+
+	target, err := db.startMigration(engine)
+	if err != nil {
+		return err
+	}
+
+	migration := &DatabaseMigration{
+		Engine: engine,
+		Start:  time.Now(),
+	}
+
+	if db.log != nil {
+		db.log.Infof("starting online database migration to %s (target: %s)...", engine, target.path)
+	}
+	db.events.DatabaseMigration.Trigger(migration)
+
+	if err := db.copyExistingKeys(target, migration, abortSignal); err != nil {
+		db.abortMigration(target)
+		return err
+	}
+
+	migration.End = time.Now()
+	migration.Percentage = 100.0
+	db.events.DatabaseMigration.Trigger(migration)
+
+	if db.log != nil {
+		db.log.Infof("online database migration to %s finished, copied %d keys. took: %v. Change the database engine in the configuration and restart the node to use the migrated database.", engine, migration.KeysCopied, migration.End.Sub(migration.Start).Truncate(time.Millisecond))
+	}
+
+	return nil
+}
+
+// startMigration creates the target database and starts mirroring the writes.
+func (db *Database) startMigration(engine Engine) (*migrationTarget, error) {
+	db.store.state.lock.Lock()
+	defer db.store.state.lock.Unlock()
+
+	if db.store.state.loadTarget() != nil {
+		return nil, ErrMigrationRunning
+	}
+
+	targetPath := MigrationDatabasePath(db.databaseDir)
+	if _, err := os.Stat(targetPath); err == nil || !os.IsNotExist(err) {
+		return nil, fmt.Errorf("target database path (%s) already exists", targetPath)
+	}
+
+	targetStore, err := StoreWithDefaultSettings(targetPath, true, engine)
+	if err != nil {
+		_ = os.RemoveAll(targetPath)
+		return nil, fmt.Errorf("target database initialization failed: %w", err)
+	}
+
+	target := &migrationTarget{
+		path:   targetPath,
+		engine: engine,
+		store:  targetStore,
+	}
+	db.store.state.storeTarget(target)
+
+	// wait for the writes that started before the migration, the copier could miss their keys otherwise
+	for db.store.state.unmirroredWrites.Load() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	return target, nil
+}
+
+// abortMigration stops mirroring the writes and removes the target database.
+func (db *Database) abortMigration(target *migrationTarget) {
+	db.store.state.lock.Lock()
+	defer db.store.state.lock.Unlock()
+
+	if db.store.state.loadTarget() == target {
+		db.store.state.storeTarget(nil)
+	}
+
+	_ = target.store.Close()
+	_ = os.RemoveAll(target.path)
+}
+
+// copyExistingKeys copies all existing keys of the source database to the target database.
+func (db *Database) copyExistingKeys(target *migrationTarget, migration *DatabaseMigration, abortSignal <-chan struct{}) error {
+
+	copyBytes := func(source []byte) []byte {
+		cpy := make([]byte, len(source))
+		copy(cpy, source)
+		return cpy
+	}
+
+	// every source value is read again while the key is locked, so the copier does not overwrite
+	// newer mirrored writes or restore deleted keys. Only the writes to the same keys are blocked meanwhile.
+	copyKey := func(key kvstore.Key) error {
+		unlockKey := db.store.state.lockKeys(db.store.store.Realm(), key)
+		defer unlockKey()
+
+		value, err := db.store.store.Get(key)
+		if err != nil {
+			if errors.Is(err, kvstore.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return target.store.Set(key, value)
+	}
+
+	copyKeys := func(keys []kvstore.Key) error {
+		db.store.state.lock.RLock()
+		defer db.store.state.lock.RUnlock()
+
+		for _, key := range keys {
+			if err := copyKey(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	lastProgressTime := time.Now()
+	reportProgress := func() {
+		if time.Since(lastProgressTime) < migrationProgressInterval {
+			return
+		}
+		lastProgressTime = time.Now()
+
+		sourceSizeBytes, _ := utils.FolderSize(db.databaseDir)
+		targetSizeBytes, _ := utils.FolderSize(target.path)
+
+		migration.Percentage, _ = utils.EstimateRemainingTime(migration.Start, targetSizeBytes, sourceSizeBytes)
+		if migration.Percentage > 99.0 {
+			// the sizes of the engines differ, the migration is only finished after all keys were copied
+			migration.Percentage = 99.0
+		}
+		db.events.DatabaseMigration.Trigger(migration)
+	}
+
+	var errCopy error
+	aborted := false
+	keys := make([]kvstore.Key, 0, migrationCopyBatchSize)
+
+	if err := db.store.store.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		keys = append(keys, copyBytes(key))
+		if len(keys) < migrationCopyBatchSize {
+			return true
+		}
+
+		select {
+		case <-abortSignal:
+			aborted = true
+			return false
+		default:
+		}
+
+		if errCopy = copyKeys(keys); errCopy != nil {
+			return false
+		}
+		migration.KeysCopied += int64(len(keys))
+		keys = keys[:0]
+
+		if errCopy = target.error(); errCopy != nil {
+			return false
+		}
+
+		reportProgress()
+
+		return true
+	}); err != nil {
+		return fmt.Errorf("source database iteration failed: %w", err)
+	}
+
+	if aborted {
+		return errors.New("database migration aborted")
+	}
+
+	if errCopy != nil {
+		return fmt.Errorf("copying keys to target database failed: %w", errCopy)
+	}
+
+	if err := copyKeys(keys); err != nil {
+		return fmt.Errorf("copying keys to target database failed: %w", err)
+	}
+	migration.KeysCopied += int64(len(keys))
+
+	db.store.state.lock.RLock()
+	defer db.store.state.lock.RUnlock()
+
+	if err := target.error(); err != nil {
+		return err
+	}
+
+	if err := target.store.Flush(); err != nil {
+		return fmt.Errorf("target database flush failed: %w", err)
+	}
+
+	target.copied.Store(true)
+
+	return nil
+}
+
+// SwitchToMigratedDatabase replaces the database with the target database of a completed online migration.
+// The migrated database is only used if it was migrated to the given engine, the old database is kept in a separate folder.
+// Incomplete migrations are removed and ErrMigrationIncomplete is returned.
+// Returns the path of the old database if the database was switched.
+func SwitchToMigratedDatabase(databasePath string, engine Engine) (string, error) {
+
+	migrationPath := MigrationDatabasePath(databasePath)
+	if _, err := os.Stat(migrationPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to check migration database path (%s): %w", migrationPath, err)
+	}
+
+	completedFilePath := filepath.Join(migrationPath, migrationCompletedFileName)
+	if _, err := os.Stat(completedFilePath); err != nil {
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("unable to check migration completed file (%s): %w", completedFilePath, err)
+		}
+
+		// the node was not shut down cleanly during the migration
+		if err := os.RemoveAll(migrationPath); err != nil {
+			return "", fmt.Errorf("unable to remove incomplete migration database (%s): %w", migrationPath, err)
+		}
+		return "", ErrMigrationIncomplete
+	}
+
+	migrationEngine, err := LoadDatabaseEngineFromFile(filepath.Join(migrationPath, DatabaseInfoFileName))
+	if err != nil {
+		return "", err
+	}
+
+	if migrationEngine != engine {
+		return "", errors.WithMessagef(ErrMigrationEngineMismatch, "'%v' != '%v'. Change the database engine in the configuration to use the migrated database (%s), or delete the folder", migrationEngine, engine, migrationPath)
+	}
+
+	oldPath := filepath.Clean(databasePath) + migrationOldFolderSuffix
+
+	// the switch is done in two steps, if the node crashes in between,
+	// the database path does not exist and the switch is continued at the next start.
+	if _, err := os.Stat(databasePath); err == nil {
+		if _, err := os.Stat(oldPath); err == nil || !os.IsNotExist(err) {
+			return "", fmt.Errorf("old database path (%s) already exists", oldPath)
+		}
+
+		if err := os.Rename(databasePath, oldPath); err != nil {
+			return "", fmt.Errorf("unable to move old database to %s: %w", oldPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("unable to check database path (%s): %w", databasePath, err)
+	}
+
+	if err := os.Rename(migrationPath, databasePath); err != nil {
+		return "", fmt.Errorf("unable to move migrated database to %s: %w", databasePath, err)
+	}
+
+	if err := os.Remove(filepath.Join(databasePath, migrationCompletedFileName)); err != nil {
+		return "", fmt.Errorf("unable to remove migration completed file: %w", err)
+	}
+
+	return oldPath, nil
+}
@@ -0,0 +1,180 @@
+package database_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestOnlineMigration(t *testing.T) {
+
+	databasePath := filepath.Join(t.TempDir(), "db")
+
+	store, err := database.StoreWithDefaultSettings(databasePath, true, database.EnginePebble)
+	require.NoError(t, err)
+
+	// rocksdb is only available with the "rocksdb" build tag,
+	// so the pebble database is declared as a rocksdb database to migrate it to pebble.
+	require.NoError(t, os.WriteFile(filepath.Join(databasePath, database.DatabaseInfoFileName), []byte("databaseEngine = \"rocksdb\"\n"), 0660))
+
+	dbEvents := &database.Events{
+		DatabaseCleanup:    events.NewEvent(database.DatabaseCleanupCaller),
+		DatabaseCompaction: events.NewEvent(events.BoolCaller),
+		DatabaseMigration:  events.NewEvent(database.DatabaseMigrationCaller),
+	}
+	db := database.New(nil, databasePath, store, dbEvents, false, func() bool { return false }, nil, nil, nil)
+
+	realmStore := db.KVStore().WithRealm([]byte("realm"))
+	for i := 0; i < 2500; i++ {
+		require.NoError(t, realmStore.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+
+	var lastMigration *database.DatabaseMigration
+	dbEvents.DatabaseMigration.Attach(events.NewClosure(func(migration *database.DatabaseMigration) {
+		lastMigration = migration
+	}))
+
+	// the migration to the same engine is not possible
+	require.ErrorIs(t, db.MigrateEngine(database.EngineRocksDB, nil), database.ErrMigrationNotSupported)
+
+	require.NoError(t, db.MigrateEngine(database.EnginePebble, nil))
+	require.True(t, db.MigrationRunning())
+	require.NotNil(t, lastMigration)
+	require.EqualValues(t, 2500, lastMigration.KeysCopied)
+	require.False(t, lastMigration.End.IsZero())
+
+	// writes after the copy are mirrored until the node is shut down
+	require.ErrorIs(t, db.MigrateEngine(database.EnginePebble, nil), database.ErrMigrationRunning)
+	require.NoError(t, realmStore.Delete([]byte("key0")))
+	batch := realmStore.Batched()
+	require.NoError(t, batch.Set([]byte("key1"), []byte("updated")))
+	require.NoError(t, batch.Commit())
+
+	require.NoError(t, db.KVStore().Flush())
+	require.NoError(t, db.KVStore().Close())
+
+	// the completed migration is only used if the configured engine matches
+	_, err = database.SwitchToMigratedDatabase(databasePath, database.EngineRocksDB)
+	require.ErrorIs(t, err, database.ErrMigrationEngineMismatch)
+
+	oldDatabasePath, err := database.SwitchToMigratedDatabase(databasePath, database.EnginePebble)
+	require.NoError(t, err)
+	require.DirExists(t, oldDatabasePath)
+	require.NoDirExists(t, database.MigrationDatabasePath(databasePath))
+
+	migratedStore, err := database.StoreWithDefaultSettings(databasePath, false, database.EnginePebble)
+	require.NoError(t, err)
+	defer func() { _ = migratedStore.Close() }()
+
+	migratedRealmStore := migratedStore.WithRealm([]byte("realm"))
+
+	has, err := migratedRealmStore.Has([]byte("key0"))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	value, err := migratedRealmStore.Get([]byte("key1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("updated"), []byte(value))
+
+	value, err = migratedRealmStore.Get([]byte("key2499"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2499"), []byte(value))
+}
+
+func TestIncompleteOnlineMigration(t *testing.T) {
+
+	databasePath := filepath.Join(t.TempDir(), "db")
+
+	require.NoError(t, os.MkdirAll(database.MigrationDatabasePath(databasePath), 0700))
+
+	_, err := database.SwitchToMigratedDatabase(databasePath, database.EnginePebble)
+	require.ErrorIs(t, err, database.ErrMigrationIncomplete)
+	require.NoDirExists(t, database.MigrationDatabasePath(databasePath))
+
+	// nothing to do without a migration
+	oldDatabasePath, err := database.SwitchToMigratedDatabase(databasePath, database.EnginePebble)
+	require.NoError(t, err)
+	require.Empty(t, oldDatabasePath)
+}
+
+func TestOnlineMigrationConcurrentWrites(t *testing.T) {
+
+	databasePath := filepath.Join(t.TempDir(), "db")
+
+	store, err := database.StoreWithDefaultSettings(databasePath, true, database.EnginePebble)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(databasePath, database.DatabaseInfoFileName), []byte("databaseEngine = \"rocksdb\"\n"), 0660))
+
+	dbEvents := &database.Events{
+		DatabaseCleanup:    events.NewEvent(database.DatabaseCleanupCaller),
+		DatabaseCompaction: events.NewEvent(events.BoolCaller),
+		DatabaseMigration:  events.NewEvent(database.DatabaseMigrationCaller),
+	}
+	db := database.New(nil, databasePath, store, dbEvents, false, func() bool { return false }, nil, nil, nil)
+
+	const keyCount = 5000
+
+	realmStore := db.KVStore().WithRealm([]byte("realm"))
+	for i := 0; i < keyCount; i++ {
+		require.NoError(t, realmStore.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+
+	// the keys are updated and deleted while the existing keys are copied
+	writesDone := make(chan error)
+	go func() {
+		for i := 0; i < keyCount; i++ {
+			key := []byte(fmt.Sprintf("key%d", i))
+			if i%3 == 0 {
+				if err := realmStore.Delete(key); err != nil {
+					writesDone <- err
+					return
+				}
+				continue
+			}
+
+			batch := realmStore.Batched()
+			if err := batch.Set(key, []byte(fmt.Sprintf("updated%d", i))); err != nil {
+				writesDone <- err
+				return
+			}
+			if err := batch.Commit(); err != nil {
+				writesDone <- err
+				return
+			}
+		}
+		writesDone <- nil
+	}()
+
+	require.NoError(t, db.MigrateEngine(database.EnginePebble, nil))
+	require.NoError(t, <-writesDone)
+
+	require.NoError(t, db.KVStore().Close())
+
+	_, err = database.SwitchToMigratedDatabase(databasePath, database.EnginePebble)
+	require.NoError(t, err)
+
+	migratedStore, err := database.StoreWithDefaultSettings(databasePath, false, database.EnginePebble)
+	require.NoError(t, err)
+	defer func() { _ = migratedStore.Close() }()
+
+	migratedRealmStore := migratedStore.WithRealm([]byte("realm"))
+	for i := 0; i < keyCount; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		if i%3 == 0 {
+			has, err := migratedRealmStore.Has(key)
+			require.NoError(t, err)
+			require.False(t, has)
+			continue
+		}
+
+		value, err := migratedRealmStore.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("updated%d", i)), []byte(value))
+	}
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"fmt"
+
+	pebbleDB "github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+const (
+	// the amount of keys after which the abort signal is checked while counting keys.
+	prefixCountAbortCheckInterval = 10000
+)
+
+var (
+	// ErrPrefixSizeEstimationNotSupported is returned if the database engine does not support size estimations of key prefixes.
+	ErrPrefixSizeEstimationNotSupported = errors.New("database engine does not support size estimations of key prefixes")
+)
+
+// StorePrefix is a named key prefix of a storage in the database.
+type StorePrefix struct {
+	// The name of the storage.
+	Name string
+	// The key prefix of the storage.
+	Prefix []byte
+}
+
+// StorePrefixes are the key prefixes of all storages in the database.
+// The UTXO storage is split into its sub storages.
+var StorePrefixes = []*StorePrefix{
+	{Name: "health", Prefix: []byte{common.StorePrefixHealth}},
+	{Name: "messages", Prefix: []byte{common.StorePrefixMessages}},
+	{Name: "messageMetadata", Prefix: []byte{common.StorePrefixMessageMetadata}},
+	{Name: "milestones", Prefix: []byte{common.StorePrefixMilestones}},
+	{Name: "children", Prefix: []byte{common.StorePrefixChildren}},
+	{Name: "snapshot", Prefix: []byte{common.StorePrefixSnapshot}},
+	{Name: "unreferencedMessages", Prefix: []byte{common.StorePrefixUnreferencedMessages}},
+	{Name: "indexations", Prefix: []byte{common.StorePrefixIndexation}},
+	{Name: "utxoLedgerIndex", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixLedgerMilestoneIndex}},
+	{Name: "utxoOutputs", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixOutput}},
+	{Name: "utxoUnspentOutputs", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixUnspent}},
+	{Name: "utxoSpentOutputs", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixSpent}},
+	{Name: "utxoMilestoneDiffs", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixMilestoneDiffs}},
+	{Name: "utxoBalances", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixBalances}},
+	{Name: "utxoTreasuryOutputs", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixTreasuryOutput}},
+	{Name: "utxoReceipts", Prefix: []byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixReceipts}},
+	{Name: "autopeering", Prefix: []byte{common.StorePrefixAutopeering}},
+	{Name: "keyRanges", Prefix: []byte{common.StorePrefixKeyRanges}},
+	{Name: "faucet", Prefix: []byte{common.StorePrefixFaucet}},
+}
+
+// PrefixSizeFunc returns the estimated size in bytes of all entries with the given key prefix on disk.
+type PrefixSizeFunc func(prefix []byte) (int64, error)
+
+// PebblePrefixSizeFunc returns a PrefixSizeFunc that uses the disk usage estimation of pebble.
+func PebblePrefixSizeFunc(db *pebbleDB.DB) PrefixSizeFunc {
+	return func(prefix []byte) (int64, error) {
+		start, end := prefixKeyRange(prefix)
+
+		size, err := db.EstimateDiskUsage(start, end)
+		if err != nil {
+			return 0, fmt.Errorf("estimating disk usage of prefix %x failed: %w", prefix, err)
+		}
+
+		return int64(size), nil
+	}
+}
+
+// prefixKeyRange returns the key range that contains all keys with the given prefix.
+// The end of the range is exclusive.
+func prefixKeyRange(prefix []byte) (start []byte, end []byte) {
+	start = append([]byte{}, prefix...)
+
+	// increment the last byte that can be incremented, the following bytes are dropped.
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			end = append([]byte{}, prefix[:i+1]...)
+			end[i]++
+			return start, end
+		}
+	}
+
+	// all bytes of the prefix are 0xff, so there is no following prefix.
+	return start, append(start, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+}
+
+// PrefixStatistics contains the size statistics of a storage in the database.
+type PrefixStatistics struct {
+	// The name of the storage.
+	Name string `json:"name"`
+	// The key prefix of the storage.
+	Prefix string `json:"prefix"`
+	// The amount of keys in the storage (only set if the keys were counted).
+	KeyCount *int64 `json:"keyCount,omitempty"`
+	// The size of the storage in bytes.
+	// If the database engine supports size estimations, this is the estimated size on disk,
+	// otherwise it is the uncompressed size of all keys and values.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
+// PrefixSizeEstimationSupported returns whether the database engine supports size estimations of key prefixes.
+func (db *Database) PrefixSizeEstimationSupported() bool {
+	return db.prefixSizeFunc != nil
+}
+
+// EstimatePrefixSizes returns the estimated sizes on disk of all storages in the database.
+// This is a cheap operation, since the keys are not iterated.
+func (db *Database) EstimatePrefixSizes() ([]*PrefixStatistics, error) {
+	if !db.PrefixSizeEstimationSupported() {
+		return nil, ErrPrefixSizeEstimationNotSupported
+	}
+
+	statistics := make([]*PrefixStatistics, len(StorePrefixes))
+	for i, storePrefix := range StorePrefixes {
+		size, err := db.prefixSizeFunc(storePrefix.Prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		statistics[i] = &PrefixStatistics{
+			Name:      storePrefix.Name,
+			Prefix:    fmt.Sprintf("%x", storePrefix.Prefix),
+			SizeBytes: size,
+		}
+	}
+
+	return statistics, nil
+}
+
+// CountPrefixSizes iterates over all keys in the database and returns the amount of keys and the sizes of all storages.
+// The progressFunc is called after every storage with the amount of storages that were already counted.
+func (db *Database) CountPrefixSizes(progressFunc func(counted int, total int), abortSignal <-chan struct{}) ([]*PrefixStatistics, error) {
+
+	statistics := make([]*PrefixStatistics, len(StorePrefixes))
+	for i, storePrefix := range StorePrefixes {
+		var keyCount, size int64
+
+		if db.PrefixSizeEstimationSupported() {
+			var err error
+			if size, err = db.prefixSizeFunc(storePrefix.Prefix); err != nil {
+				return nil, err
+			}
+		}
+
+		aborted := false
+		countEntry := func(key kvstore.Key, value kvstore.Value) bool {
+			if keyCount%prefixCountAbortCheckInterval == 0 {
+				select {
+				case <-abortSignal:
+					aborted = true
+					return false
+				default:
+				}
+			}
+
+			keyCount++
+			size += int64(len(key) + len(value))
+			return true
+		}
+
+		var err error
+		if db.PrefixSizeEstimationSupported() {
+			// the values are not needed if the engine estimates the size
+			err = db.store.IterateKeys(storePrefix.Prefix, func(_ kvstore.Key) bool {
+				return countEntry(nil, nil)
+			})
+		} else {
+			// without size estimations of the engine, the uncompressed size of the entries is used
+			err = db.store.Iterate(storePrefix.Prefix, countEntry)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("counting keys of %s failed: %w", storePrefix.Name, err)
+		}
+
+		if aborted {
+			return nil, common.ErrOperationAborted
+		}
+
+		statistics[i] = &PrefixStatistics{
+			Name:      storePrefix.Name,
+			Prefix:    fmt.Sprintf("%x", storePrefix.Prefix),
+			KeyCount:  &keyCount,
+			SizeBytes: size,
+		}
+
+		if progressFunc != nil {
+			progressFunc(i+1, len(StorePrefixes))
+		}
+	}
+
+	return statistics, nil
+}
@@ -0,0 +1,111 @@
+package database_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/kvstore/pebble"
+)
+
+func newTestEvents() *database.Events {
+	return &database.Events{
+		DatabaseCleanup:    events.NewEvent(database.DatabaseCleanupCaller),
+		DatabaseCompaction: events.NewEvent(events.BoolCaller),
+		DatabaseMigration:  events.NewEvent(database.DatabaseMigrationCaller),
+	}
+}
+
+func fillTestPrefixes(t *testing.T, store kvstore.KVStore) {
+	messagesStore := store.WithRealm([]byte{common.StorePrefixMessages})
+	for i := 0; i < 100; i++ {
+		require.NoError(t, messagesStore.Set([]byte(fmt.Sprintf("message%03d", i)), []byte("data")))
+	}
+
+	balancesStore := store.WithRealm([]byte{common.StorePrefixUTXO, utxo.UTXOStoreKeyPrefixBalances})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, balancesStore.Set([]byte(fmt.Sprintf("address%d", i)), []byte("balance")))
+	}
+}
+
+func prefixStatisticsByName(statistics []*database.PrefixStatistics) map[string]*database.PrefixStatistics {
+	result := make(map[string]*database.PrefixStatistics)
+	for _, s := range statistics {
+		result[s.Name] = s
+	}
+	return result
+}
+
+func TestCountPrefixSizes(t *testing.T) {
+
+	store := mapdb.NewMapDB()
+	fillTestPrefixes(t, store)
+
+	db := database.New(nil, "", store, newTestEvents(), false, func() bool { return false }, nil, nil, nil)
+	require.False(t, db.PrefixSizeEstimationSupported())
+
+	_, err := db.EstimatePrefixSizes()
+	require.ErrorIs(t, err, database.ErrPrefixSizeEstimationNotSupported)
+
+	var progress []int
+	statistics, err := db.CountPrefixSizes(func(counted int, total int) {
+		require.Equal(t, len(database.StorePrefixes), total)
+		progress = append(progress, counted)
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, statistics, len(database.StorePrefixes))
+	require.Len(t, progress, len(database.StorePrefixes))
+
+	byName := prefixStatisticsByName(statistics)
+
+	// without size estimations, the uncompressed size of the keys (including the prefix) and values is used
+	require.EqualValues(t, 100, *byName["messages"].KeyCount)
+	require.EqualValues(t, 100*(1+len("message000")+len("data")), byName["messages"].SizeBytes)
+	require.EqualValues(t, 10, *byName["utxoBalances"].KeyCount)
+	require.EqualValues(t, 10*(2+len("address0")+len("balance")), byName["utxoBalances"].SizeBytes)
+	require.EqualValues(t, 0, *byName["utxoOutputs"].KeyCount)
+	require.EqualValues(t, 0, byName["utxoOutputs"].SizeBytes)
+
+	abortSignal := make(chan struct{})
+	close(abortSignal)
+	_, err = db.CountPrefixSizes(nil, abortSignal)
+	require.ErrorIs(t, err, common.ErrOperationAborted)
+}
+
+func TestEstimatePrefixSizes(t *testing.T) {
+
+	pebbleDB, err := database.NewPebbleDB(filepath.Join(t.TempDir(), "db"), nil, false)
+	require.NoError(t, err)
+
+	store := pebble.New(pebbleDB)
+	defer func() { _ = store.Close() }()
+
+	fillTestPrefixes(t, store)
+	require.NoError(t, pebbleDB.Flush())
+
+	db := database.New(nil, "", store, newTestEvents(), false, func() bool { return false }, nil, nil, database.PebblePrefixSizeFunc(pebbleDB))
+	require.True(t, db.PrefixSizeEstimationSupported())
+
+	statistics, err := db.EstimatePrefixSizes()
+	require.NoError(t, err)
+	require.Len(t, statistics, len(database.StorePrefixes))
+
+	byName := prefixStatisticsByName(statistics)
+	require.Nil(t, byName["messages"].KeyCount)
+	require.Greater(t, byName["messages"].SizeBytes, int64(0))
+
+	statistics, err = db.CountPrefixSizes(nil, nil)
+	require.NoError(t, err)
+
+	byName = prefixStatisticsByName(statistics)
+	require.EqualValues(t, 100, *byName["messages"].KeyCount)
+	require.EqualValues(t, 10, *byName["utxoBalances"].KeyCount)
+}
@@ -0,0 +1,25 @@
+package hsm
+
+import (
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+var (
+	// ErrPKCS11NotSupported is returned if the node was built without PKCS#11 support.
+	ErrPKCS11NotSupported = errors.New("PKCS#11 is not supported, the node needs to be built with the 'pkcs11' build tag")
+	// ErrKeyNotFound is returned if the token does not hold the private key for a public key.
+	ErrKeyNotFound = errors.New("private key not found on the token")
+)
+
+// Token is a hardware security module that holds Ed25519 private keys
+// and creates signatures without exposing the keys.
+type Token interface {
+	// PublicKeys returns the public keys of all Ed25519 key pairs on the token.
+	PublicKeys() []iotago.MilestonePublicKey
+	// Sign signs the message with the private key that belongs to the given public key.
+	Sign(pubKey iotago.MilestonePublicKey, message []byte) ([]byte, error)
+	// Close closes the session to the token.
+	Close() error
+}
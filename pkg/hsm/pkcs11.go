@@ -0,0 +1,252 @@
+//go:build pkcs11
+// +build pkcs11
+
+package hsm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+const (
+	// the key type and mechanism of Ed25519 keys (PKCS#11 v3.0).
+	ckkECEdwards = 0x00000040
+	ckmEdDSA     = 0x00001057
+
+	// the maximum amount of objects returned by a single FindObjects call.
+	findObjectsBatchSize = 100
+)
+
+// pkcs11Token is a Token that uses a PKCS#11 module.
+type pkcs11Token struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	// PKCS#11 sessions must not be used concurrently.
+	sessionLock sync.Mutex
+	// the handles of the private keys by their public keys.
+	privateKeys map[iotago.MilestonePublicKey]pkcs11.ObjectHandle
+}
+
+// OpenPKCS11Token loads the PKCS#11 module, opens a session to the token with the given label and logs in with the PIN.
+// All Ed25519 key pairs on the token are loaded.
+func OpenPKCS11Token(modulePath string, tokenLabel string, pin string) (Token, error) {
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("unable to load PKCS#11 module: %s", modulePath)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("unable to initialize PKCS#11 module: %w", err)
+	}
+
+	token := &pkcs11Token{
+		ctx:         ctx,
+		privateKeys: make(map[iotago.MilestonePublicKey]pkcs11.ObjectHandle),
+	}
+
+	if err := token.open(tokenLabel, pin); err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (t *pkcs11Token) open(tokenLabel string, pin string) error {
+
+	slots, err := t.ctx.GetSlotList(true)
+	if err != nil {
+		return fmt.Errorf("unable to get PKCS#11 slots: %w", err)
+	}
+
+	slotFound := false
+	var slot uint
+	for _, s := range slots {
+		info, err := t.ctx.GetTokenInfo(s)
+		if err != nil {
+			return fmt.Errorf("unable to get PKCS#11 token info: %w", err)
+		}
+
+		if info.Label == tokenLabel {
+			slot = s
+			slotFound = true
+			break
+		}
+	}
+
+	if !slotFound {
+		return fmt.Errorf("PKCS#11 token not found: %s", tokenLabel)
+	}
+
+	t.session, err = t.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("unable to open PKCS#11 session: %w", err)
+	}
+
+	if err := t.ctx.Login(t.session, pkcs11.CKU_USER, pin); err != nil {
+		_ = t.ctx.CloseSession(t.session)
+		return fmt.Errorf("unable to login to PKCS#11 token: %w", err)
+	}
+
+	if err := t.loadKeys(); err != nil {
+		_ = t.ctx.Logout(t.session)
+		_ = t.ctx.CloseSession(t.session)
+		return err
+	}
+
+	return nil
+}
+
+// findObjects returns the handles of all objects that match the template.
+func (t *pkcs11Token) findObjects(template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+
+	if err := t.ctx.FindObjectsInit(t.session, template); err != nil {
+		return nil, fmt.Errorf("unable to find PKCS#11 objects: %w", err)
+	}
+
+	var result []pkcs11.ObjectHandle
+	for {
+		objects, _, err := t.ctx.FindObjects(t.session, findObjectsBatchSize)
+		if err != nil {
+			_ = t.ctx.FindObjectsFinal(t.session)
+			return nil, fmt.Errorf("unable to find PKCS#11 objects: %w", err)
+		}
+
+		if len(objects) == 0 {
+			break
+		}
+		result = append(result, objects...)
+	}
+
+	if err := t.ctx.FindObjectsFinal(t.session); err != nil {
+		return nil, fmt.Errorf("unable to find PKCS#11 objects: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadKeys loads the public keys of all Ed25519 key pairs and the handles of the belonging private keys.
+func (t *pkcs11Token) loadKeys() error {
+
+	pubKeyHandles, err := t.findObjects([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pubKeyHandle := range pubKeyHandles {
+		attributes, err := t.ctx.GetAttributeValue(t.session, pubKeyHandle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, nil),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get PKCS#11 public key attributes: %w", err)
+		}
+
+		pubKey, err := parseECPoint(attributes[0].Value)
+		if err != nil {
+			return err
+		}
+
+		// the private key of the key pair has the same ID as the public key
+		privateKeyHandles, err := t.findObjects([]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, attributes[1].Value),
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(privateKeyHandles) == 0 {
+			// only the public key is stored on the token
+			continue
+		}
+
+		t.privateKeys[pubKey] = privateKeyHandles[0]
+	}
+
+	return nil
+}
+
+// parseECPoint parses the public key of an Ed25519 key pair.
+// The point is either stored raw or as a DER encoded octet string.
+func parseECPoint(ecPoint []byte) (iotago.MilestonePublicKey, error) {
+	var pubKey iotago.MilestonePublicKey
+
+	switch {
+	case len(ecPoint) == ed25519.PublicKeySize:
+		copy(pubKey[:], ecPoint)
+
+	case len(ecPoint) == ed25519.PublicKeySize+2 && ecPoint[0] == 0x04 && ecPoint[1] == ed25519.PublicKeySize:
+		copy(pubKey[:], ecPoint[2:])
+
+	default:
+		return pubKey, fmt.Errorf("invalid Ed25519 public key on PKCS#11 token, length: %d", len(ecPoint))
+	}
+
+	return pubKey, nil
+}
+
+// PublicKeys returns the public keys of all Ed25519 key pairs on the token.
+func (t *pkcs11Token) PublicKeys() []iotago.MilestonePublicKey {
+	pubKeys := make([]iotago.MilestonePublicKey, 0, len(t.privateKeys))
+	for pubKey := range t.privateKeys {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys
+}
+
+// Sign signs the message with the private key that belongs to the given public key.
+func (t *pkcs11Token) Sign(pubKey iotago.MilestonePublicKey, message []byte) ([]byte, error) {
+
+	privateKeyHandle, exists := t.privateKeys[pubKey]
+	if !exists {
+		return nil, ErrKeyNotFound
+	}
+
+	t.sessionLock.Lock()
+	defer t.sessionLock.Unlock()
+
+	if err := t.ctx.SignInit(t.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(ckmEdDSA, nil)}, privateKeyHandle); err != nil {
+		return nil, fmt.Errorf("unable to initialize PKCS#11 signing: %w", err)
+	}
+
+	signature, err := t.ctx.Sign(t.session, message)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 signing failed: %w", err)
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("PKCS#11 token returned a signature with wrong length: %d", len(signature))
+	}
+
+	return signature, nil
+}
+
+// Close closes the session to the token.
+func (t *pkcs11Token) Close() error {
+	t.sessionLock.Lock()
+	defer t.sessionLock.Unlock()
+
+	_ = t.ctx.Logout(t.session)
+	if err := t.ctx.CloseSession(t.session); err != nil {
+		return err
+	}
+	if err := t.ctx.Finalize(); err != nil {
+		return err
+	}
+	t.ctx.Destroy()
+
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package hsm
+
+// OpenPKCS11Token returns ErrPKCS11NotSupported, since the node was built without the 'pkcs11' build tag.
+func OpenPKCS11Token(_ string, _ string, _ string) (Token, error) {
+	return nil, ErrPKCS11NotSupported
+}
@@ -0,0 +1,44 @@
+//go:build pkcs11
+// +build pkcs11
+
+package hsm_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/hsm"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+// TestPKCS11Token uses SoftHSM as a local stand-in for a hardware security module.
+// The token needs to be prepared with at least one Ed25519 key pair:
+//
+//	softhsm2-util --init-token --free --label coordinator --pin 1234 --so-pin 1234
+//	pkcs11-tool --module /usr/lib/softhsm/libsofthsm2.so --token-label coordinator --login --pin 1234 \
+//	  --keypairgen --key-type EC:edwards25519 --id 01 --label milestone1
+//
+//	HSM_MODULE_PATH=/usr/lib/softhsm/libsofthsm2.so HSM_TOKEN_LABEL=coordinator HSM_PIN=1234 go test -tags pkcs11 ./pkg/hsm/
+func TestPKCS11Token(t *testing.T) {
+
+	modulePath := os.Getenv("HSM_MODULE_PATH")
+	if modulePath == "" {
+		t.Skip("HSM_MODULE_PATH not set")
+	}
+
+	token, err := hsm.OpenPKCS11Token(modulePath, os.Getenv("HSM_TOKEN_LABEL"), os.Getenv("HSM_PIN"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, token.Close()) }()
+
+	pubKeys := token.PublicKeys()
+	require.NotEmpty(t, pubKeys)
+
+	message := []byte("milestone essence")
+	for _, pubKey := range pubKeys {
+		signature, err := token.Sign(pubKey, message)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey[:], message, signature))
+	}
+}
@@ -0,0 +1,358 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/daemon"
+)
+
+const (
+	// the amount of finished jobs that are kept to be able to query their result.
+	maxFinishedJobs = 100
+)
+
+var (
+	// ErrJobNotFound is returned if the job is unknown.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotRunning is returned if a job should be canceled that is not running anymore.
+	ErrJobNotRunning = errors.New("job is not running")
+	// ErrJobCanceled is returned by a job function if the job was canceled.
+	ErrJobCanceled = errors.New("job was canceled")
+	// ErrJobAlreadyRunning is returned if a job should be started while another job is still running.
+	ErrJobAlreadyRunning = errors.New("another job is already running")
+)
+
+// Status is the status of a job.
+type Status string
+
+const (
+	// StatusRunning is the status of a job that is still running.
+	StatusRunning Status = "running"
+	// StatusFinished is the status of a job that finished successfully.
+	StatusFinished Status = "finished"
+	// StatusFailed is the status of a job that returned an error.
+	StatusFailed Status = "failed"
+	// StatusCanceled is the status of a job that was canceled.
+	StatusCanceled Status = "canceled"
+)
+
+// Progress is the progress of a job.
+type Progress struct {
+	// The milestone index the job started at.
+	StartIndex milestone.Index
+	// The milestone index the job is currently processing.
+	CurrentIndex milestone.Index
+	// The milestone index the job is working towards.
+	TargetIndex milestone.Index
+	// The progress of the job in percent.
+	Percentage float64
+}
+
+// NewProgress creates a new Progress and calculates the percentage from the given milestone range.
+func NewProgress(startIndex milestone.Index, currentIndex milestone.Index, targetIndex milestone.Index) Progress {
+	progress := Progress{
+		StartIndex:   startIndex,
+		CurrentIndex: currentIndex,
+		TargetIndex:  targetIndex,
+	}
+
+	switch {
+	case targetIndex <= startIndex || currentIndex >= targetIndex:
+		progress.Percentage = 100.0
+	case currentIndex > startIndex:
+		progress.Percentage = float64(currentIndex-startIndex) * 100.0 / float64(targetIndex-startIndex)
+	}
+
+	return progress
+}
+
+// Info is a snapshot of the state of a job.
+type Info struct {
+	ID         string
+	Type       string
+	Status     Status
+	Progress   Progress
+	Result     interface{}
+	Error      error
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Func is the function that is executed by a job.
+// It should regularly check the abort signal of the job and report its progress.
+type Func func(job *Job) (interface{}, error)
+
+// Job is an asynchronously executed task.
+type Job struct {
+	id        string
+	jobType   string
+	createdAt time.Time
+
+	abortSignal chan struct{}
+	abortOnce   sync.Once
+
+	lock       sync.RWMutex
+	status     Status
+	progress   Progress
+	result     interface{}
+	err        error
+	finishedAt time.Time
+}
+
+// ID returns the ID of the job.
+func (j *Job) ID() string {
+	return j.id
+}
+
+// Type returns the type of the job.
+func (j *Job) Type() string {
+	return j.jobType
+}
+
+// AbortSignal returns the signal that is closed if the job gets canceled.
+func (j *Job) AbortSignal() <-chan struct{} {
+	return j.abortSignal
+}
+
+// SetProgress updates the progress of the job.
+func (j *Job) SetProgress(progress Progress) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.progress = progress
+}
+
+// Info returns a snapshot of the current state of the job.
+func (j *Job) Info() *Info {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return &Info{
+		ID:         j.id,
+		Type:       j.jobType,
+		Status:     j.status,
+		Progress:   j.progress,
+		Result:     j.result,
+		Error:      j.err,
+		CreatedAt:  j.createdAt,
+		FinishedAt: j.finishedAt,
+	}
+}
+
+// IsRunning returns whether the job is still running.
+func (j *Job) IsRunning() bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.status == StatusRunning
+}
+
+// cancel closes the abort signal of the job.
+func (j *Job) cancel() {
+	j.abortOnce.Do(func() {
+		close(j.abortSignal)
+	})
+}
+
+// isCanceled returns whether the abort signal of the job was closed.
+func (j *Job) isCanceled() bool {
+	select {
+	case <-j.abortSignal:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish sets the final state of the job.
+func (j *Job) finish(result interface{}, err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.finishedAt = time.Now()
+
+	switch {
+	case err != nil && j.isCanceled():
+		j.status = StatusCanceled
+		j.err = ErrJobCanceled
+	case err != nil:
+		j.status = StatusFailed
+		j.err = err
+	default:
+		j.status = StatusFinished
+		j.result = result
+	}
+}
+
+// Manager keeps track of asynchronously executed jobs.
+// Only a single job is running at the same time.
+type Manager struct {
+	// the daemon the jobs are executed in as background workers.
+	daemon daemon.Daemon
+	// the name of the background worker of the jobs.
+	workerName string
+	// the shutdown priority of the background workers of the jobs.
+	shutdownPriority int
+
+	lock sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewManager creates a new job manager.
+// The jobs are executed as background worker of the given daemon,
+// they get canceled if the daemon shuts down and the shutdown waits until they finished.
+func NewManager(daemon daemon.Daemon, workerName string, shutdownPriority int) *Manager {
+	return &Manager{
+		daemon:           daemon,
+		workerName:       workerName,
+		shutdownPriority: shutdownPriority,
+		jobs:             make(map[string]*Job),
+	}
+}
+
+// Start creates a new job of the given type and executes the given function in the background.
+// ErrJobAlreadyRunning is returned if another job is still running.
+func (m *Manager) Start(jobType string, jobFunc Func) (*Job, error) {
+	jobID, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		id:          jobID,
+		jobType:     jobType,
+		createdAt:   time.Now(),
+		abortSignal: make(chan struct{}),
+		status:      StatusRunning,
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.hasRunningJobsWithoutLocking() {
+		return nil, ErrJobAlreadyRunning
+	}
+
+	// the jobs share the same background worker because only a single job is running at the same time
+	if err := m.daemon.BackgroundWorker(m.workerName, func(shutdownSignal <-chan struct{}) {
+		jobDone := make(chan struct{})
+		defer close(jobDone)
+
+		go func() {
+			select {
+			case <-shutdownSignal:
+				job.cancel()
+			case <-jobDone:
+			}
+		}()
+
+		job.finish(jobFunc(job))
+	}, m.shutdownPriority); err != nil {
+		if errors.Is(err, daemon.ErrExistingBackgroundWorkerStillRunning) {
+			// the previous job already finished, but the background worker did not return yet
+			return nil, ErrJobAlreadyRunning
+		}
+		return nil, errors.Wrap(err, "starting job failed")
+	}
+
+	m.cleanupFinishedJobsWithoutLocking()
+	m.jobs[jobID] = job
+
+	return job, nil
+}
+
+// Job returns the job with the given ID.
+func (m *Manager) Job(jobID string) (*Job, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+// HasRunningJobs returns whether any job is still running.
+func (m *Manager) HasRunningJobs() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.hasRunningJobsWithoutLocking()
+}
+
+// hasRunningJobsWithoutLocking returns whether any job is still running.
+// read lock must be acquired outside.
+func (m *Manager) hasRunningJobsWithoutLocking() bool {
+	for _, job := range m.jobs {
+		if job.IsRunning() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cancel signals the job with the given ID to abort.
+func (m *Manager) Cancel(jobID string) error {
+	job, err := m.Job(jobID)
+	if err != nil {
+		return err
+	}
+
+	if !job.IsRunning() {
+		return ErrJobNotRunning
+	}
+
+	job.cancel()
+	return nil
+}
+
+// CancelAll signals all running jobs to abort.
+func (m *Manager) CancelAll() {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, job := range m.jobs {
+		job.cancel()
+	}
+}
+
+// cleanupFinishedJobsWithoutLocking removes the oldest finished jobs if there are too many.
+// write lock must be acquired outside.
+func (m *Manager) cleanupFinishedJobsWithoutLocking() {
+	var finishedJobs []*Info
+	for _, job := range m.jobs {
+		if info := job.Info(); info.Status != StatusRunning {
+			finishedJobs = append(finishedJobs, info)
+		}
+	}
+
+	if len(finishedJobs) < maxFinishedJobs {
+		return
+	}
+
+	sort.Slice(finishedJobs, func(i, j int) bool {
+		return finishedJobs[i].FinishedAt.Before(finishedJobs[j].FinishedAt)
+	})
+
+	for _, info := range finishedJobs[:len(finishedJobs)-maxFinishedJobs+1] {
+		delete(m.jobs, info.ID)
+	}
+}
+
+func newJobID() (string, error) {
+	jobID := make([]byte, 16)
+	if _, err := rand.Read(jobID); err != nil {
+		return "", errors.Wrap(err, "generating job ID failed")
+	}
+	return hex.EncodeToString(jobID), nil
+}
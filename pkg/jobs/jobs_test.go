@@ -0,0 +1,138 @@
+package jobs_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/jobs"
+	"github.com/iotaledger/hive.go/daemon"
+)
+
+func newTestManager(t *testing.T) (*jobs.Manager, daemon.Daemon) {
+	d := daemon.New()
+	d.Start()
+	t.Cleanup(d.ShutdownAndWait)
+
+	return jobs.NewManager(d, "Jobs", 0), d
+}
+
+func waitForJob(t *testing.T, job *jobs.Job) *jobs.Info {
+	require.Eventually(t, func() bool { return !job.IsRunning() }, 2*time.Second, 10*time.Millisecond)
+	return job.Info()
+}
+
+func TestJobFinished(t *testing.T) {
+
+	manager, _ := newTestManager(t)
+
+	job, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		job.SetProgress(jobs.NewProgress(10, 15, 20))
+		return 42, nil
+	})
+	require.NoError(t, err)
+
+	info := waitForJob(t, job)
+	require.Equal(t, jobs.StatusFinished, info.Status)
+	require.Equal(t, 42, info.Result)
+	require.NoError(t, info.Error)
+	require.Equal(t, 50.0, info.Progress.Percentage)
+
+	fetchedJob, err := manager.Job(job.ID())
+	require.NoError(t, err)
+	require.Equal(t, job, fetchedJob)
+
+	_, err = manager.Job("unknown")
+	require.True(t, errors.Is(err, jobs.ErrJobNotFound))
+}
+
+func TestJobFailed(t *testing.T) {
+
+	manager, _ := newTestManager(t)
+
+	jobErr := errors.New("job failed")
+	job, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		return nil, jobErr
+	})
+	require.NoError(t, err)
+
+	info := waitForJob(t, job)
+	require.Equal(t, jobs.StatusFailed, info.Status)
+	require.Equal(t, jobErr, info.Error)
+	require.False(t, manager.HasRunningJobs())
+}
+
+func TestJobCanceled(t *testing.T) {
+
+	manager, _ := newTestManager(t)
+
+	job, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		<-job.AbortSignal()
+		return nil, errors.New("aborted")
+	})
+	require.NoError(t, err)
+	require.True(t, manager.HasRunningJobs())
+
+	require.NoError(t, manager.Cancel(job.ID()))
+
+	info := waitForJob(t, job)
+	require.Equal(t, jobs.StatusCanceled, info.Status)
+	require.True(t, errors.Is(info.Error, jobs.ErrJobCanceled))
+
+	require.True(t, errors.Is(manager.Cancel(job.ID()), jobs.ErrJobNotRunning))
+}
+
+func TestNewProgress(t *testing.T) {
+	require.Equal(t, 0.0, jobs.NewProgress(10, 10, 20).Percentage)
+	require.Equal(t, 25.0, jobs.NewProgress(0, 25, 100).Percentage)
+	require.Equal(t, 100.0, jobs.NewProgress(10, 20, 20).Percentage)
+	require.Equal(t, 100.0, jobs.NewProgress(10, 10, 10).Percentage)
+}
+
+func TestJobAlreadyRunning(t *testing.T) {
+
+	manager, _ := newTestManager(t)
+
+	job, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		<-job.AbortSignal()
+		return nil, errors.New("aborted")
+	})
+	require.NoError(t, err)
+
+	_, err = manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		return nil, nil
+	})
+	require.True(t, errors.Is(err, jobs.ErrJobAlreadyRunning))
+
+	require.NoError(t, manager.Cancel(job.ID()))
+	waitForJob(t, job)
+
+	require.Eventually(t, func() bool {
+		_, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+			return nil, nil
+		})
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestJobCanceledOnShutdown(t *testing.T) {
+
+	manager, d := newTestManager(t)
+
+	job, err := manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		<-job.AbortSignal()
+		return nil, errors.New("aborted")
+	})
+	require.NoError(t, err)
+
+	// the shutdown waits for the job to finish
+	d.ShutdownAndWait()
+	require.Equal(t, jobs.StatusCanceled, job.Info().Status)
+
+	_, err = manager.Start("test", func(job *jobs.Job) (interface{}, error) {
+		return nil, nil
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,246 @@
+package keymanager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+const (
+	// KeyRangeAnnouncementIndex is the index of the indexation payloads that contain key range announcements.
+	KeyRangeAnnouncementIndex = "COO_KEY_ROTATION"
+
+	// KeyRangeAnnouncementVersion is the version of the key range announcement format.
+	KeyRangeAnnouncementVersion byte = 1
+
+	// MinKeyRangeStartDistance is the minimum distance between the index of the milestone that confirms
+	// a key range announcement and the start index of the announced key ranges.
+	// This gives all nodes enough time to apply the announcement before the new keys are used.
+	MinKeyRangeStartDistance milestone.Index = 10
+
+	// MaxKeyRangesPerAnnouncement is the maximum amount of key ranges in a single announcement.
+	MaxKeyRangesPerAnnouncement = 16
+)
+
+var (
+	// ErrInvalidKeyRangeAnnouncement is returned if a key range announcement could not be parsed or is invalid.
+	ErrInvalidKeyRangeAnnouncement = errors.New("invalid key range announcement")
+	// ErrNotEnoughAnnouncementSignatures is returned if a key range announcement is not signed by enough valid public keys.
+	ErrNotEnoughAnnouncementSignatures = errors.New("not enough valid signatures in key range announcement")
+)
+
+// KeyRangeAnnouncementSignature is the signature of a key range announcement by a milestone public key.
+type KeyRangeAnnouncementSignature struct {
+	PublicKey iotago.MilestonePublicKey
+	Signature iotago.MilestoneSignature
+}
+
+// KeyRangeAnnouncement announces upcoming key ranges of the coordinator.
+// It is signed by the public keys that are valid at the time the announcement gets confirmed.
+type KeyRangeAnnouncement struct {
+	// the network the announcement is valid for.
+	NetworkID uint64
+	// the announced key ranges.
+	KeyRanges []*KeyRange
+	// the signatures of the essence of the announcement.
+	Signatures []*KeyRangeAnnouncementSignature
+}
+
+// NewKeyRangeAnnouncement creates a new unsigned KeyRangeAnnouncement.
+func NewKeyRangeAnnouncement(networkID uint64, keyRanges []*KeyRange) (*KeyRangeAnnouncement, error) {
+
+	if len(keyRanges) == 0 || len(keyRanges) > MaxKeyRangesPerAnnouncement {
+		return nil, fmt.Errorf("%w: invalid key ranges count %d", ErrInvalidKeyRangeAnnouncement, len(keyRanges))
+	}
+
+	for _, keyRange := range keyRanges {
+		if keyRange.EndIndex < keyRange.StartIndex {
+			return nil, fmt.Errorf("%w: end index %d is smaller than start index %d", ErrInvalidKeyRangeAnnouncement, keyRange.EndIndex, keyRange.StartIndex)
+		}
+	}
+
+	return &KeyRangeAnnouncement{
+		NetworkID: networkID,
+		KeyRanges: keyRanges,
+	}, nil
+}
+
+// Essence returns the signed part of the announcement: version (byte), network ID (uint64), key ranges count (byte)
+// and the key ranges consisting of public key, start index (uint32) and end index (uint32).
+func (a *KeyRangeAnnouncement) Essence() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteByte(KeyRangeAnnouncementVersion)
+	_ = binary.Write(&buf, binary.LittleEndian, a.NetworkID)
+	buf.WriteByte(byte(len(a.KeyRanges)))
+
+	for _, keyRange := range a.KeyRanges {
+		buf.Write(keyRange.PublicKey[:])
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(keyRange.StartIndex))
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(keyRange.EndIndex))
+	}
+
+	return buf.Bytes()
+}
+
+// Sign signs the essence of the announcement with the given private keys.
+func (a *KeyRangeAnnouncement) Sign(privateKeys []ed25519.PrivateKey) {
+	essence := a.Essence()
+
+	for _, privateKey := range privateKeys {
+		signature := &KeyRangeAnnouncementSignature{}
+		copy(signature.PublicKey[:], privateKey.Public().(ed25519.PublicKey))
+		copy(signature.Signature[:], ed25519.Sign(privateKey, essence))
+
+		a.Signatures = append(a.Signatures, signature)
+	}
+}
+
+// Serialize returns the binary representation of the announcement:
+// essence, signatures count (byte) and the signatures consisting of public key and signature.
+func (a *KeyRangeAnnouncement) Serialize() []byte {
+	var buf bytes.Buffer
+
+	buf.Write(a.Essence())
+	buf.WriteByte(byte(len(a.Signatures)))
+
+	for _, signature := range a.Signatures {
+		buf.Write(signature.PublicKey[:])
+		buf.Write(signature.Signature[:])
+	}
+
+	return buf.Bytes()
+}
+
+// KeyRangeAnnouncementFromBytes parses a KeyRangeAnnouncement from its binary representation.
+func KeyRangeAnnouncementFromBytes(data []byte) (*KeyRangeAnnouncement, error) {
+	reader := bytes.NewReader(data)
+
+	var version byte
+	if err := binary.Read(reader, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("%w: unable to read version: %s", ErrInvalidKeyRangeAnnouncement, err)
+	}
+
+	if version != KeyRangeAnnouncementVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidKeyRangeAnnouncement, version)
+	}
+
+	announcement := &KeyRangeAnnouncement{}
+	if err := binary.Read(reader, binary.LittleEndian, &announcement.NetworkID); err != nil {
+		return nil, fmt.Errorf("%w: unable to read network ID: %s", ErrInvalidKeyRangeAnnouncement, err)
+	}
+
+	var keyRangesCount byte
+	if err := binary.Read(reader, binary.LittleEndian, &keyRangesCount); err != nil {
+		return nil, fmt.Errorf("%w: unable to read key ranges count: %s", ErrInvalidKeyRangeAnnouncement, err)
+	}
+
+	keyRanges := make([]*KeyRange, keyRangesCount)
+	for i := range keyRanges {
+		keyRange := &KeyRange{}
+
+		var startIndex, endIndex uint32
+		if err := binary.Read(reader, binary.LittleEndian, &keyRange.PublicKey); err != nil {
+			return nil, fmt.Errorf("%w: unable to read public key: %s", ErrInvalidKeyRangeAnnouncement, err)
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &startIndex); err != nil {
+			return nil, fmt.Errorf("%w: unable to read start index: %s", ErrInvalidKeyRangeAnnouncement, err)
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &endIndex); err != nil {
+			return nil, fmt.Errorf("%w: unable to read end index: %s", ErrInvalidKeyRangeAnnouncement, err)
+		}
+		keyRange.StartIndex = milestone.Index(startIndex)
+		keyRange.EndIndex = milestone.Index(endIndex)
+
+		keyRanges[i] = keyRange
+	}
+
+	var signaturesCount byte
+	if err := binary.Read(reader, binary.LittleEndian, &signaturesCount); err != nil {
+		return nil, fmt.Errorf("%w: unable to read signatures count: %s", ErrInvalidKeyRangeAnnouncement, err)
+	}
+
+	for i := 0; i < int(signaturesCount); i++ {
+		signature := &KeyRangeAnnouncementSignature{}
+		if err := binary.Read(reader, binary.LittleEndian, &signature.PublicKey); err != nil {
+			return nil, fmt.Errorf("%w: unable to read signature public key: %s", ErrInvalidKeyRangeAnnouncement, err)
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &signature.Signature); err != nil {
+			return nil, fmt.Errorf("%w: unable to read signature: %s", ErrInvalidKeyRangeAnnouncement, err)
+		}
+
+		announcement.Signatures = append(announcement.Signatures, signature)
+	}
+
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("%w: %d bytes left after parsing", ErrInvalidKeyRangeAnnouncement, reader.Len())
+	}
+
+	signatures := announcement.Signatures
+	announcement, err := NewKeyRangeAnnouncement(announcement.NetworkID, keyRanges)
+	if err != nil {
+		return nil, err
+	}
+	announcement.Signatures = signatures
+
+	return announcement, nil
+}
+
+// VerifyKeyRangeAnnouncement checks if the announcement is valid for the given network and is signed by at least
+// milestonePublicKeysCount public keys that are valid for the milestone index that confirmed the announcement.
+// All announced key ranges must start at least MinKeyRangeStartDistance milestones after the confirming milestone.
+func (k *KeyManager) VerifyKeyRangeAnnouncement(announcement *KeyRangeAnnouncement, networkID uint64, msIndex milestone.Index, milestonePublicKeysCount int) error {
+
+	if announcement.NetworkID != networkID {
+		return fmt.Errorf("%w: network ID mismatch: %d != %d", ErrInvalidKeyRangeAnnouncement, announcement.NetworkID, networkID)
+	}
+
+	for _, keyRange := range announcement.KeyRanges {
+		if keyRange.StartIndex < msIndex+MinKeyRangeStartDistance {
+			return fmt.Errorf("%w: start index %d of key range has to be at least %d", ErrInvalidKeyRangeAnnouncement, keyRange.StartIndex, msIndex+MinKeyRangeStartDistance)
+		}
+	}
+
+	pubKeySet := k.PublicKeysSetForMilestoneIndex(msIndex)
+	essence := announcement.Essence()
+
+	validSigners := make(map[iotago.MilestonePublicKey]struct{})
+	for _, signature := range announcement.Signatures {
+		if _, exists := pubKeySet[signature.PublicKey]; !exists {
+			continue
+		}
+
+		if !ed25519.Verify(signature.PublicKey[:], essence, signature.Signature[:]) {
+			return fmt.Errorf("%w: invalid signature of public key %x", ErrInvalidKeyRangeAnnouncement, signature.PublicKey)
+		}
+
+		validSigners[signature.PublicKey] = struct{}{}
+	}
+
+	if len(validSigners) < milestonePublicKeysCount {
+		return fmt.Errorf("%w: %d/%d", ErrNotEnoughAnnouncementSignatures, len(validSigners), milestonePublicKeysCount)
+	}
+
+	return nil
+}
+
+// ApplyKeyRangeAnnouncement adds the key ranges of the announcement to the KeyManager.
+// The announcement has to be verified with VerifyKeyRangeAnnouncement before.
+// Returns the key ranges that were not known before.
+func (k *KeyManager) ApplyKeyRangeAnnouncement(announcement *KeyRangeAnnouncement) []*KeyRange {
+	var added []*KeyRange
+
+	for _, keyRange := range announcement.KeyRanges {
+		if k.AddKeyRange(keyRange.PublicKey[:], keyRange.StartIndex, keyRange.EndIndex) {
+			added = append(added, keyRange)
+		}
+	}
+
+	return added
+}
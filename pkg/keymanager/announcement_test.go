@@ -0,0 +1,72 @@
+package keymanager_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+func TestKeyRangeAnnouncement(t *testing.T) {
+
+	pubKey1, privKey1, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	pubKey2, privKey2, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	pubKey3, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	_, privKeyUnknown, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	km := keymanager.New()
+	km.AddKeyRange(pubKey1, 0, 100)
+	km.AddKeyRange(pubKey2, 0, 100)
+
+	newKeyRange := &keymanager.KeyRange{StartIndex: 100, EndIndex: 200}
+	copy(newKeyRange.PublicKey[:], pubKey3)
+
+	_, err = keymanager.NewKeyRangeAnnouncement(1337, []*keymanager.KeyRange{{StartIndex: 200, EndIndex: 100}})
+	require.ErrorIs(t, err, keymanager.ErrInvalidKeyRangeAnnouncement)
+
+	announcement, err := keymanager.NewKeyRangeAnnouncement(1337, []*keymanager.KeyRange{newKeyRange})
+	require.NoError(t, err)
+	announcement.Sign([]ed25519.PrivateKey{privKey1, privKeyUnknown, privKey2})
+
+	parsed, err := keymanager.KeyRangeAnnouncementFromBytes(announcement.Serialize())
+	require.NoError(t, err)
+	require.Equal(t, announcement, parsed)
+
+	// trailing data is not allowed
+	_, err = keymanager.KeyRangeAnnouncementFromBytes(append(announcement.Serialize(), 0))
+	require.ErrorIs(t, err, keymanager.ErrInvalidKeyRangeAnnouncement)
+
+	require.ErrorIs(t, km.VerifyKeyRangeAnnouncement(parsed, 1, 50, 2), keymanager.ErrInvalidKeyRangeAnnouncement)
+
+	// the key ranges have to start far enough in the future
+	require.ErrorIs(t, km.VerifyKeyRangeAnnouncement(parsed, 1337, 100-keymanager.MinKeyRangeStartDistance+1, 2), keymanager.ErrInvalidKeyRangeAnnouncement)
+
+	// signatures of unknown keys are not counted
+	require.ErrorIs(t, km.VerifyKeyRangeAnnouncement(parsed, 1337, 50, 3), keymanager.ErrNotEnoughAnnouncementSignatures)
+
+	require.NoError(t, km.VerifyKeyRangeAnnouncement(parsed, 1337, 50, 2))
+
+	// the signatures have to match the announced key ranges
+	parsed.KeyRanges[0].EndIndex = 300
+	require.ErrorIs(t, km.VerifyKeyRangeAnnouncement(parsed, 1337, 50, 2), keymanager.ErrInvalidKeyRangeAnnouncement)
+
+	require.Len(t, km.PublicKeysForMilestoneIndex(150), 0)
+
+	added := km.ApplyKeyRangeAnnouncement(announcement)
+	require.Equal(t, []*keymanager.KeyRange{newKeyRange}, added)
+	require.Len(t, km.PublicKeysForMilestoneIndex(150), 1)
+	require.Len(t, km.KeyRanges(), 3)
+
+	// known key ranges are not added twice
+	require.Empty(t, km.ApplyKeyRangeAnnouncement(announcement))
+	require.Len(t, km.KeyRanges(), 3)
+}
@@ -2,6 +2,7 @@ package keymanager
 
 import (
 	"sort"
+	"sync"
 
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	iotago "github.com/iotaledger/iota.go/v2"
@@ -17,7 +18,8 @@ type KeyRange struct {
 
 // KeyManager provides public and private keys for ranges of milestone indexes.
 type KeyManager struct {
-	keyRanges []*KeyRange
+	keyRangesLock sync.RWMutex
+	keyRanges     []*KeyRange
 }
 
 // New returns a new KeyManager.
@@ -26,21 +28,48 @@ func New() *KeyManager {
 }
 
 // AddKeyRange adds a new public key to the MilestoneKeyManager including its valid range.
-func (k *KeyManager) AddKeyRange(publicKey ed25519.PublicKey, startIndex milestone.Index, endIndex milestone.Index) {
+// Returns false if the exact same key range was already added.
+func (k *KeyManager) AddKeyRange(publicKey ed25519.PublicKey, startIndex milestone.Index, endIndex milestone.Index) bool {
+	k.keyRangesLock.Lock()
+	defer k.keyRangesLock.Unlock()
 
 	var msPubKey iotago.MilestonePublicKey
 	copy(msPubKey[:], publicKey)
 
+	for _, keyRange := range k.keyRanges {
+		if keyRange.PublicKey == msPubKey && keyRange.StartIndex == startIndex && keyRange.EndIndex == endIndex {
+			return false
+		}
+	}
+
 	k.keyRanges = append(k.keyRanges, &KeyRange{PublicKey: msPubKey, StartIndex: startIndex, EndIndex: endIndex})
 
 	// sort by start index
 	sort.Slice(k.keyRanges, func(i int, j int) bool {
 		return k.keyRanges[i].StartIndex < k.keyRanges[j].StartIndex
 	})
+
+	return true
+}
+
+// KeyRanges returns a copy of all known key ranges sorted by their start index.
+func (k *KeyManager) KeyRanges() []*KeyRange {
+	k.keyRangesLock.RLock()
+	defer k.keyRangesLock.RUnlock()
+
+	keyRanges := make([]*KeyRange, len(k.keyRanges))
+	for i, keyRange := range k.keyRanges {
+		keyRanges[i] = &KeyRange{PublicKey: keyRange.PublicKey, StartIndex: keyRange.StartIndex, EndIndex: keyRange.EndIndex}
+	}
+
+	return keyRanges
 }
 
 // PublicKeysForMilestoneIndex returns the valid public keys for a certain milestone index.
 func (k *KeyManager) PublicKeysForMilestoneIndex(msIndex milestone.Index) []iotago.MilestonePublicKey {
+	k.keyRangesLock.RLock()
+	defer k.keyRangesLock.RUnlock()
+
 	var pubKeys []iotago.MilestonePublicKey
 
 	for _, pubKeyRange := range k.keyRanges {
@@ -0,0 +1,210 @@
+package coldstorage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/kvstore"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	ColdStorePrefixMessages         byte = 0
+	ColdStorePrefixMessageMetadata  byte = 1
+	ColdStorePrefixMilestones       byte = 2
+	ColdStorePrefixIncludedMessages byte = 3
+)
+
+/*
+
+   Cold Storage Database
+
+   Message:
+   ========
+   Key:
+       ColdStorePrefixMessages + MessageID
+                1 byte         + 32 bytes
+
+   Value:
+       serialized iotago.Message
+
+
+   Message metadata:
+   =================
+   Key:
+       ColdStorePrefixMessageMetadata + MessageID
+                   1 byte             + 32 bytes
+
+   Value:
+       storage.MessageMetadata.ObjectStorageValue()
+
+
+   Milestone:
+   ==========
+   Key:
+       ColdStorePrefixMilestones + milestone.Index
+                 1 byte          +     4 bytes
+
+   Value:
+       storage.Milestone.ObjectStorageValue()
+
+
+   Included message:
+   =================
+   Key:
+       ColdStorePrefixIncludedMessages + iotago.TransactionID
+                    1 byte             +        32 bytes
+
+   Value:
+       MessageID
+       32 bytes
+
+*/
+
+// ColdStorage is an append-only secondary store that keeps the data of pruned milestone cones.
+// Entries are only added by the pruning, they are never deleted.
+type ColdStorage struct {
+	store                 kvstore.KVStore
+	messagesStore         kvstore.KVStore
+	metadataStore         kvstore.KVStore
+	milestonesStore       kvstore.KVStore
+	includedMessagesStore kvstore.KVStore
+}
+
+// New creates a new ColdStorage instance on top of the given KVStore.
+func New(store kvstore.KVStore) *ColdStorage {
+	return &ColdStorage{
+		store:                 store,
+		messagesStore:         store.WithRealm([]byte{ColdStorePrefixMessages}),
+		metadataStore:         store.WithRealm([]byte{ColdStorePrefixMessageMetadata}),
+		milestonesStore:       store.WithRealm([]byte{ColdStorePrefixMilestones}),
+		includedMessagesStore: store.WithRealm([]byte{ColdStorePrefixIncludedMessages}),
+	}
+}
+
+// KVStore returns the underlying KVStore.
+func (cs *ColdStorage) KVStore() kvstore.KVStore {
+	return cs.store
+}
+
+// StoreMessage moves the given message and its metadata to the cold storage.
+// If the message contains a transaction that was included in the ledger,
+// the transaction ID is stored as well, so the included message can be looked up later.
+func (cs *ColdStorage) StoreMessage(msg *storage.Message, metadata *storage.MessageMetadata) error {
+
+	mutations := cs.store.Batched()
+
+	if err := mutations.Set(append([]byte{ColdStorePrefixMessages}, msg.MessageID()...), msg.Data()); err != nil {
+		mutations.Cancel()
+		return errors.Wrap(storage.NewDatabaseError(err), "failed to store message in cold storage")
+	}
+
+	if err := mutations.Set(append([]byte{ColdStorePrefixMessageMetadata}, msg.MessageID()...), metadata.ObjectStorageValue()); err != nil {
+		mutations.Cancel()
+		return errors.Wrap(storage.NewDatabaseError(err), "failed to store message metadata in cold storage")
+	}
+
+	if metadata.IsIncludedTxInLedger() {
+		if transaction := msg.Transaction(); transaction != nil {
+			transactionID, err := transaction.ID()
+			if err != nil {
+				mutations.Cancel()
+				return err
+			}
+
+			if err := mutations.Set(append([]byte{ColdStorePrefixIncludedMessages}, transactionID[:]...), msg.MessageID()); err != nil {
+				mutations.Cancel()
+				return errors.Wrap(storage.NewDatabaseError(err), "failed to store included message in cold storage")
+			}
+		}
+	}
+
+	return mutations.Commit()
+}
+
+// StoreMilestone moves the given milestone to the cold storage.
+func (cs *ColdStorage) StoreMilestone(ms *storage.Milestone) error {
+	if err := cs.milestonesStore.Set(ms.ObjectStorageKey(), ms.ObjectStorageValue()); err != nil {
+		return errors.Wrap(storage.NewDatabaseError(err), "failed to store milestone in cold storage")
+	}
+	return nil
+}
+
+// MessageOrNil returns the message with the given message ID from the cold storage.
+func (cs *ColdStorage) MessageOrNil(messageID hornet.MessageID) (*storage.Message, error) {
+	data, err := cs.messagesStore.Get(messageID)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(storage.NewDatabaseError(err), "failed to retrieve message from cold storage")
+	}
+
+	return storage.MessageFromBytes(data, iotago.DeSeriModeNoValidation)
+}
+
+// MessageMetadataOrNil returns the metadata of the message with the given message ID from the cold storage.
+func (cs *ColdStorage) MessageMetadataOrNil(messageID hornet.MessageID) (*storage.MessageMetadata, error) {
+	data, err := cs.metadataStore.Get(messageID)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(storage.NewDatabaseError(err), "failed to retrieve message metadata from cold storage")
+	}
+
+	metadata, err := storage.MetadataFactory(messageID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.(*storage.MessageMetadata), nil
+}
+
+// MilestoneOrNil returns the milestone with the given index from the cold storage.
+func (cs *ColdStorage) MilestoneOrNil(milestoneIndex milestone.Index) (*storage.Milestone, error) {
+	key := (&storage.Milestone{Index: milestoneIndex}).ObjectStorageKey()
+
+	data, err := cs.milestonesStore.Get(key)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(storage.NewDatabaseError(err), "failed to retrieve milestone from cold storage")
+	}
+
+	ms, err := storage.MilestoneFactory(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.(*storage.Milestone), nil
+}
+
+// IncludedMessageIDOrNil returns the ID of the message that included the given transaction in the ledger.
+func (cs *ColdStorage) IncludedMessageIDOrNil(transactionID *iotago.TransactionID) (hornet.MessageID, error) {
+	data, err := cs.includedMessagesStore.Get(transactionID[:])
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(storage.NewDatabaseError(err), "failed to retrieve included message from cold storage")
+	}
+
+	return hornet.MessageIDFromSlice(data), nil
+}
+
+// Flush persists all outstanding write operations to disk.
+func (cs *ColdStorage) Flush() error {
+	return cs.store.Flush()
+}
+
+// Close flushes and closes the underlying KVStore.
+func (cs *ColdStorage) Close() error {
+	if err := cs.store.Flush(); err != nil {
+		return err
+	}
+	return cs.store.Close()
+}
@@ -0,0 +1,83 @@
+package coldstorage_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/coldstorage"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func randMessageID() hornet.MessageID {
+	messageID := make(hornet.MessageID, iotago.MessageIDLength)
+	rand.Read(messageID)
+	return messageID
+}
+
+func TestColdStorageMessages(t *testing.T) {
+
+	s, err := storage.New(mapdb.NewMapDB())
+	require.NoError(t, err)
+
+	cs := coldstorage.New(mapdb.NewMapDB())
+
+	msg, err := storage.NewMessage(&iotago.Message{
+		NetworkID: 1,
+		Parents:   hornet.MessageIDs{randMessageID(), randMessageID()}.ToSliceOfArrays(),
+		Payload:   &iotago.Indexation{Index: []byte("coldstorage"), Data: []byte("permanode")},
+	}, iotago.DeSeriModeNoValidation)
+	require.NoError(t, err)
+
+	cachedMsg, _ := s.StoreMessageIfAbsent(msg) // msg +1
+	cachedMsg.Metadata().SetReferenced(true, 10)
+	require.NoError(t, cs.StoreMessage(cachedMsg.Message(), cachedMsg.Metadata()))
+	cachedMsg.Release(true) // msg -1
+
+	storedMsg, err := cs.MessageOrNil(msg.MessageID())
+	require.NoError(t, err)
+	require.NotNil(t, storedMsg)
+	require.Equal(t, msg.Data(), storedMsg.Data())
+	require.Equal(t, msg.MessageID(), storedMsg.MessageID())
+
+	storedMeta, err := cs.MessageMetadataOrNil(msg.MessageID())
+	require.NoError(t, err)
+	require.NotNil(t, storedMeta)
+	referenced, at := storedMeta.ReferencedWithIndex()
+	require.True(t, referenced)
+	require.Equal(t, milestone.Index(10), at)
+	require.Equal(t, msg.Parents(), storedMeta.Parents())
+
+	unknownMsg, err := cs.MessageOrNil(randMessageID())
+	require.NoError(t, err)
+	require.Nil(t, unknownMsg)
+}
+
+func TestColdStorageMilestones(t *testing.T) {
+
+	cs := coldstorage.New(mapdb.NewMapDB())
+
+	ms := &storage.Milestone{
+		Index:     1337,
+		MessageID: randMessageID(),
+		Timestamp: time.Unix(time.Now().Unix(), 0),
+	}
+	require.NoError(t, cs.StoreMilestone(ms))
+
+	storedMs, err := cs.MilestoneOrNil(1337)
+	require.NoError(t, err)
+	require.NotNil(t, storedMs)
+	require.Equal(t, ms.Index, storedMs.Index)
+	require.Equal(t, ms.MessageID, storedMs.MessageID)
+	require.True(t, ms.Timestamp.Equal(storedMs.Timestamp))
+
+	unknownMs, err := cs.MilestoneOrNil(1338)
+	require.NoError(t, err)
+	require.Nil(t, unknownMs)
+}
@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/pow"
+	quorumprotocol "github.com/gohornet/hornet/pkg/protocol/quorum"
 	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
@@ -43,6 +45,10 @@ var (
 	ErrNetworkBootstrapped = errors.New("network already bootstrapped")
 	// ErrInvalidSiblingsTrytesLength is returned when the computed siblings trytes do not fit into the signature message fragment.
 	ErrInvalidSiblingsTrytesLength = errors.New("siblings trytes too long")
+	// ErrCoordinatorNotActive is returned when a standby coordinator instance should issue checkpoints or milestones.
+	ErrCoordinatorNotActive = errors.New("coordinator instance is not active")
+	// ErrSharedStateNotFound is returned when a coordinator instance takes over, but the network was not bootstrapped yet.
+	ErrSharedStateNotFound = errors.New("coordinator state not found in shared state backend")
 )
 
 // MerkleTreeHash is the merkle tree root hash of all messages.
@@ -58,6 +64,8 @@ type Events struct {
 	SoftError *events.Event
 	// QuorumFinished is triggered after a coordinator quorum call was finished.
 	QuorumFinished *events.Event
+	// Audit is triggered when an action was performed via the control API of the coordinator.
+	Audit *events.Event
 }
 
 // PublicKeyRange is a public key of milestones with a valid range.
@@ -97,8 +105,15 @@ type Coordinator struct {
 	backpressureFuncs []BackPressureFunc
 	// state of the coordinator holds information about the last issued milestones.
 	state *State
+	// a copy of the state that is read without waiting for the milestone lock.
+	stateCopy     *State
+	stateCopyLock syncutils.RWMutex
 	// whether the coordinator was bootstrapped.
 	bootstrapped bool
+	// used to access the lease of the shared state backend.
+	leaseLock syncutils.Mutex
+	// whether the coordinator instance holds the lease of the shared state backend.
+	active bool
 	// events of the coordinator.
 	Events *Events
 }
@@ -134,6 +149,12 @@ type Options struct {
 	powWorkerCount int
 	// the optional quorum used by the coordinator to check for correct ledger state calculation.
 	quorum *quorum
+	// the optional backend that is shared with standby coordinator instances.
+	stateBackend StateBackend
+	// the ID of the coordinator instance in the shared state backend.
+	instanceID string
+	// the duration of the lease in the shared state backend.
+	leaseDuration time.Duration
 }
 
 // applies the given Option.
@@ -195,14 +216,26 @@ func WithPoWWorkerCount(powWorkerCount int) Option {
 }
 
 // WithQuorum defines a quorum, which is used to check the correct ledger state of the coordinator.
+// The peerClient is used to request the white flag computation from quorum clients that are connected peers.
 // If no quorumGroups are given, the quorum is disabled.
-func WithQuorum(quorumEnabled bool, quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration) Option {
+func WithQuorum(quorumEnabled bool, quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration, peerClient *quorumprotocol.Client) Option {
 	return func(opts *Options) {
 		if !quorumEnabled {
 			opts.quorum = nil
 			return
 		}
-		opts.quorum = newQuorum(quorumGroups, timeout)
+		opts.quorum = newQuorum(quorumGroups, timeout, peerClient)
+	}
+}
+
+// WithStateBackend defines a backend that is shared with standby coordinator instances.
+// The coordinator only issues milestones while it holds the lease of the backend,
+// and the state is stored in the backend instead of the state file.
+func WithStateBackend(stateBackend StateBackend, instanceID string, leaseDuration time.Duration) Option {
+	return func(opts *Options) {
+		opts.stateBackend = stateBackend
+		opts.instanceID = instanceID
+		opts.leaseDuration = leaseDuration
 	}
 }
 
@@ -241,6 +274,7 @@ func New(
 			IssuedMilestone:         events.NewEvent(MilestoneCaller),
 			SoftError:               events.NewEvent(events.ErrorCaller),
 			QuorumFinished:          events.NewEvent(QuorumFinishedCaller),
+			Audit:                   events.NewEvent(AuditCaller),
 		},
 	}
 
@@ -248,70 +282,144 @@ func New(
 }
 
 // InitState loads an existing state file or bootstraps the network.
+// If a shared state backend is used, the state is loaded as soon as the coordinator instance becomes active.
 // All errors are critical.
 func (coo *Coordinator) InitState(bootstrap bool, startIndex milestone.Index) error {
 
+	if coo.opts.stateBackend != nil {
+		return coo.initSharedState(bootstrap, startIndex)
+	}
+
 	_, err := os.Stat(coo.opts.stateFilePath)
 	stateFileExists := !os.IsNotExist(err)
 
-	latestMilestoneFromDatabase := coo.storage.SearchLatestMilestoneIndexInStore()
-
 	if bootstrap {
 		if stateFileExists {
 			return ErrNetworkBootstrapped
 		}
 
-		if startIndex == 0 {
-			// start with milestone 1 at least
-			startIndex = 1
+		state, err := coo.bootstrapState(startIndex)
+		if err != nil {
+			return err
 		}
 
-		if latestMilestoneFromDatabase != startIndex-1 {
-			return fmt.Errorf("previous milestone does not match latest milestone in database! previous: %d, database: %d", startIndex-1, latestMilestoneFromDatabase)
-		}
+		coo.setState(state)
+		coo.bootstrapped = false
+		return nil
+	}
 
-		latestMilestoneMessageID := hornet.NullMessageID()
-		if startIndex != 1 {
-			// If we don't start a new network, the last milestone has to be referenced
-			cachedMilestoneMsg := coo.storage.MilestoneCachedMessageOrNil(latestMilestoneFromDatabase)
-			if cachedMilestoneMsg == nil {
-				return fmt.Errorf("latest milestone (%d) not found in database. database is corrupt", latestMilestoneFromDatabase)
-			}
-			latestMilestoneMessageID = cachedMilestoneMsg.Message().MessageID()
-			cachedMilestoneMsg.Release()
+	if !stateFileExists {
+		return fmt.Errorf("state file not found: %v", coo.opts.stateFilePath)
+	}
+
+	state := &State{}
+	if err := utils.ReadJSONFromFile(coo.opts.stateFilePath, state); err != nil {
+		return err
+	}
+
+	if err := coo.checkStateMatchesDatabase(state); err != nil {
+		return err
+	}
+
+	coo.setState(state)
+	coo.bootstrapped = true
+	return nil
+}
+
+// initSharedState checks whether the network was already bootstrapped by another coordinator instance.
+func (coo *Coordinator) initSharedState(bootstrap bool, startIndex milestone.Index) error {
+
+	sharedState, err := coo.opts.stateBackend.LoadState()
+	if err != nil {
+		return fmt.Errorf("unable to load state from shared state backend: %w", err)
+	}
+
+	if bootstrap {
+		if sharedState != nil {
+			return ErrNetworkBootstrapped
 		}
 
-		// create a new coordinator state to bootstrap the network
-		state := &State{}
-		state.LatestMilestoneMessageID = latestMilestoneMessageID
-		state.LatestMilestoneIndex = startIndex - 1
-		state.LatestMilestoneTime = time.Now()
+		state, err := coo.bootstrapState(startIndex)
+		if err != nil {
+			return err
+		}
 
-		coo.state = state
+		coo.setState(state)
 		coo.bootstrapped = false
 		return nil
 	}
 
-	if !stateFileExists {
-		return fmt.Errorf("state file not found: %v", coo.opts.stateFilePath)
+	// the state is loaded as soon as the coordinator instance becomes active
+	coo.bootstrapped = true
+	return nil
+}
+
+// bootstrapState creates a new coordinator state to bootstrap the network.
+func (coo *Coordinator) bootstrapState(startIndex milestone.Index) (*State, error) {
+
+	latestMilestoneFromDatabase := coo.storage.SearchLatestMilestoneIndexInStore()
+
+	if startIndex == 0 {
+		// start with milestone 1 at least
+		startIndex = 1
 	}
 
-	coo.state = &State{}
-	if err := utils.ReadJSONFromFile(coo.opts.stateFilePath, coo.state); err != nil {
-		return err
+	if latestMilestoneFromDatabase != startIndex-1 {
+		return nil, fmt.Errorf("previous milestone does not match latest milestone in database! previous: %d, database: %d", startIndex-1, latestMilestoneFromDatabase)
 	}
 
-	if latestMilestoneFromDatabase != coo.state.LatestMilestoneIndex {
-		return fmt.Errorf("previous milestone does not match latest milestone in database. previous: %d, database: %d", coo.state.LatestMilestoneIndex, latestMilestoneFromDatabase)
+	latestMilestoneMessageID := hornet.NullMessageID()
+	if startIndex != 1 {
+		// If we don't start a new network, the last milestone has to be referenced
+		cachedMilestoneMsg := coo.storage.MilestoneCachedMessageOrNil(latestMilestoneFromDatabase)
+		if cachedMilestoneMsg == nil {
+			return nil, fmt.Errorf("latest milestone (%d) not found in database. database is corrupt", latestMilestoneFromDatabase)
+		}
+		latestMilestoneMessageID = cachedMilestoneMsg.Message().MessageID()
+		cachedMilestoneMsg.Release()
+	}
+
+	return &State{
+		LatestMilestoneMessageID: latestMilestoneMessageID,
+		LatestMilestoneIndex:     startIndex - 1,
+		LatestMilestoneTime:      time.Now(),
+	}, nil
+}
+
+// latestMilestoneIndexInDatabase searches the latest milestone in the database.
+// In contrast to the startup, a standby instance takes over while milestones are received,
+// so the milestones in the cache layer that were not persisted yet are included.
+func (coo *Coordinator) latestMilestoneIndexInDatabase() milestone.Index {
+	var latestMilestoneIndex milestone.Index
+
+	coo.storage.ForEachMilestoneIndex(func(msIndex milestone.Index) bool {
+		if latestMilestoneIndex < msIndex {
+			latestMilestoneIndex = msIndex
+		}
+		return true
+	})
+
+	return latestMilestoneIndex
+}
+
+// checkStateMatchesDatabase checks that the latest milestone of the state is the latest milestone in the database.
+func (coo *Coordinator) checkStateMatchesDatabase(state *State) error {
+
+	latestMilestoneFromDatabase := coo.latestMilestoneIndexInDatabase()
+	if latestMilestoneFromDatabase != state.LatestMilestoneIndex {
+		return fmt.Errorf("previous milestone does not match latest milestone in database. previous: %d, database: %d", state.LatestMilestoneIndex, latestMilestoneFromDatabase)
 	}
 
 	cachedMilestoneMsg := coo.storage.MilestoneCachedMessageOrNil(latestMilestoneFromDatabase)
 	if cachedMilestoneMsg == nil {
 		return fmt.Errorf("latest milestone (%d) not found in database. database is corrupt", latestMilestoneFromDatabase)
 	}
-	cachedMilestoneMsg.Release()
+	defer cachedMilestoneMsg.Release()
+
+	if coo.opts.stateBackend != nil && !bytes.Equal(cachedMilestoneMsg.Message().MessageID(), state.LatestMilestoneMessageID) {
+		return fmt.Errorf("latest milestone (%d) in database does not match the state. database: %s, state: %s", latestMilestoneFromDatabase, cachedMilestoneMsg.Message().MessageID().ToHex(), state.LatestMilestoneMessageID.ToHex())
+	}
 
-	coo.bootstrapped = true
 	return nil
 }
 
@@ -345,7 +453,7 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 		ts := time.Now()
 		err := coo.opts.quorum.checkMerkleTreeHash(mutations.MerkleTreeHash, newMilestoneIndex, parents, func(groupName string, entry *quorumGroupEntry, err error) {
 			if coo.opts.logger != nil {
-				coo.opts.logger.Infof("coordinator quorum group encountered an error, group: %s, baseURL: %s, err: %s", groupName, entry.stats.BaseURL, err)
+				coo.opts.logger.Infof("coordinator quorum group encountered an error, group: %s, %s, err: %s", groupName, entry.stats, err)
 			}
 		})
 
@@ -394,6 +502,14 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 		return common.CriticalError(fmt.Errorf("failed to create milestone: %w", err))
 	}
 
+	if coo.opts.stateBackend != nil {
+		// store the milestone before it is sent, so a standby instance that takes over
+		// sends the same milestone again instead of issuing a conflicting one with the same index.
+		if err := coo.storeSharedState(coo.state, milestoneMsg.Data()); err != nil {
+			return err
+		}
+	}
+
 	if err := coo.sendMesssageFunc(milestoneMsg, newMilestoneIndex); err != nil {
 		return common.CriticalError(fmt.Errorf("failed to send milestone: %w", err))
 	}
@@ -410,8 +526,13 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 	coo.state.LatestMilestoneMessageID = latestMilestoneMessageID
 	coo.state.LatestMilestoneIndex = newMilestoneIndex
 	coo.state.LatestMilestoneTime = time.Now()
+	coo.setState(coo.state)
 
-	if err := utils.WriteJSONToFile(coo.opts.stateFilePath, coo.state, 0660); err != nil {
+	if coo.opts.stateBackend != nil {
+		if err := coo.storeSharedState(coo.state, nil); err != nil {
+			return err
+		}
+	} else if err := utils.WriteJSONToFile(coo.opts.stateFilePath, coo.state, 0660); err != nil {
 		return common.CriticalError(fmt.Errorf("failed to update coordinator state file: %w", err))
 	}
 
@@ -420,13 +541,28 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 	return nil
 }
 
+// LockMilestoneIssuance waits until the milestone that is currently issued was sent and its state was stored,
+// and blocks the issuance of milestones and checkpoints until UnlockMilestoneIssuance is called.
+func (coo *Coordinator) LockMilestoneIssuance() {
+	coo.milestoneLock.Lock()
+}
+
+// UnlockMilestoneIssuance unblocks the issuance of milestones and checkpoints.
+func (coo *Coordinator) UnlockMilestoneIssuance() {
+	coo.milestoneLock.Unlock()
+}
+
 // Bootstrap creates the first milestone, if the network was not bootstrapped yet.
-// Returns critical errors.
+// Returns non-critical and critical errors.
 func (coo *Coordinator) Bootstrap() (hornet.MessageID, error) {
 
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	if !coo.IsActive() {
+		return nil, common.SoftError(ErrCoordinatorNotActive)
+	}
+
 	if !coo.bootstrapped {
 		// create first milestone to bootstrap the network
 		// only one parent references the last known milestone or NullMessageID if startIndex = 1 (see InitState)
@@ -455,6 +591,10 @@ func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointMessa
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	if !coo.IsActive() {
+		return nil, common.SoftError(ErrCoordinatorNotActive)
+	}
+
 	if !coo.syncManager.IsNodeSynced() {
 		return nil, common.SoftError(common.ErrNodeNotSynced)
 	}
@@ -505,6 +645,10 @@ func (coo *Coordinator) IssueMilestone(parents hornet.MessageIDs) (hornet.Messag
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	if !coo.IsActive() {
+		return nil, common.SoftError(ErrCoordinatorNotActive)
+	}
+
 	if !coo.syncManager.IsNodeSynced() {
 		// return a non-critical error to not kill the database
 		return nil, common.SoftError(common.ErrNodeNotSynced)
@@ -529,9 +673,229 @@ func (coo *Coordinator) Interval() time.Duration {
 	return coo.opts.milestoneInterval
 }
 
-// State returns the current state of the coordinator.
+// setState sets the state of the coordinator and updates the copy that is returned by State.
+func (coo *Coordinator) setState(state *State) {
+	coo.state = state
+
+	coo.stateCopyLock.Lock()
+	defer coo.stateCopyLock.Unlock()
+
+	coo.stateCopy = state.copy()
+}
+
+// State returns a copy of the current state of the coordinator.
+// It does not wait for a milestone that is currently issued.
+// Returns nil if a standby instance did not load the state yet.
 func (coo *Coordinator) State() *State {
-	return coo.state
+	coo.stateCopyLock.RLock()
+	defer coo.stateCopyLock.RUnlock()
+
+	return coo.stateCopy.copy()
+}
+
+// IsActive returns whether the coordinator instance is allowed to issue milestones.
+// Without a shared state backend the coordinator is always active.
+func (coo *Coordinator) IsActive() bool {
+	if coo.opts.stateBackend == nil {
+		return true
+	}
+
+	coo.leaseLock.Lock()
+	defer coo.leaseLock.Unlock()
+
+	return coo.active
+}
+
+// Activate tries to acquire the lease of the shared state backend.
+// If the lease was acquired, the coordinator instance takes over issuing milestones from the state in the backend.
+// Returns false if another coordinator instance holds the lease.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) Activate() (bool, error) {
+
+	if coo.opts.stateBackend == nil || coo.IsActive() {
+		return true, nil
+	}
+
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	coo.leaseLock.Lock()
+	defer coo.leaseLock.Unlock()
+
+	acquired, err := coo.opts.stateBackend.AcquireLease(coo.opts.instanceID, coo.opts.leaseDuration)
+	if err != nil {
+		return false, common.SoftError(fmt.Errorf("failed to acquire lease: %w", err))
+	}
+
+	if !acquired {
+		return false, nil
+	}
+
+	if err := coo.takeOver(); err != nil {
+		if errRelease := coo.opts.stateBackend.ReleaseLease(coo.opts.instanceID); errRelease != nil && coo.opts.logger != nil {
+			coo.opts.logger.Warnf("failed to release lease: %s", errRelease)
+		}
+		return false, err
+	}
+
+	coo.active = true
+	return true, nil
+}
+
+// takeOver loads the state from the shared state backend.
+// A pending milestone of the previously active instance is sent again and confirmed in the state.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) takeOver() error {
+
+	sharedState, err := coo.opts.stateBackend.LoadState()
+	if err != nil {
+		return common.SoftError(fmt.Errorf("unable to load state from shared state backend: %w", err))
+	}
+
+	if sharedState == nil {
+		if !coo.bootstrapped {
+			// the network gets bootstrapped by this instance
+			return nil
+		}
+		return common.SoftError(ErrSharedStateNotFound)
+	}
+
+	if sharedState.State == nil {
+		return common.CriticalError(fmt.Errorf("invalid state in shared state backend: %w", ErrSharedStateNotFound))
+	}
+
+	state := sharedState.State
+	if len(sharedState.PendingMilestoneMessage) > 0 {
+		state, err = coo.confirmPendingMilestone(state, sharedState.PendingMilestoneMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	if latestMilestoneFromDatabase := coo.latestMilestoneIndexInDatabase(); latestMilestoneFromDatabase < state.LatestMilestoneIndex {
+		// the node has to catch up before the coordinator instance can take over
+		return common.SoftError(fmt.Errorf("%w: latest milestone in database: %d, state: %d", common.ErrNodeNotSynced, latestMilestoneFromDatabase, state.LatestMilestoneIndex))
+	}
+
+	if err := coo.checkStateMatchesDatabase(state); err != nil {
+		return common.CriticalError(err)
+	}
+
+	coo.setState(state)
+	coo.bootstrapped = true
+	return nil
+}
+
+// confirmPendingMilestone sends the pending milestone of the previously active instance again,
+// if it is not known to the node yet, and returns the state that contains the milestone.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) confirmPendingMilestone(state *State, pendingMilestoneMessage []byte) (*State, error) {
+
+	msg, err := storage.MessageFromBytes(pendingMilestoneMessage, iotago.DeSeriModePerformValidation)
+	if err != nil {
+		return nil, common.CriticalError(fmt.Errorf("invalid pending milestone in shared state backend: %w", err))
+	}
+
+	ms := msg.Milestone()
+	if ms == nil {
+		return nil, common.CriticalError(errors.New("invalid pending milestone in shared state backend: no milestone payload"))
+	}
+
+	pendingIndex := milestone.Index(ms.Index)
+	if pendingIndex != state.LatestMilestoneIndex+1 {
+		// the pending milestone is outdated
+		return state, nil
+	}
+
+	if latestMilestoneFromDatabase := coo.latestMilestoneIndexInDatabase(); latestMilestoneFromDatabase < pendingIndex {
+		if latestMilestoneFromDatabase < state.LatestMilestoneIndex {
+			return nil, common.SoftError(fmt.Errorf("%w: latest milestone in database: %d, state: %d", common.ErrNodeNotSynced, latestMilestoneFromDatabase, state.LatestMilestoneIndex))
+		}
+
+		if coo.opts.logger != nil {
+			coo.opts.logger.Infof("sending pending milestone %d of the previously active coordinator instance", pendingIndex)
+		}
+
+		if err := coo.sendMesssageFunc(msg, pendingIndex); err != nil {
+			return nil, common.SoftError(fmt.Errorf("failed to send pending milestone: %w", err))
+		}
+	}
+
+	newState := &State{
+		LatestMilestoneIndex:     pendingIndex,
+		LatestMilestoneMessageID: msg.MessageID(),
+		LatestMilestoneTime:      time.Unix(int64(ms.Timestamp), 0),
+	}
+
+	if err := coo.opts.stateBackend.StoreState(coo.opts.instanceID, &SharedState{State: newState}); err != nil {
+		return nil, common.SoftError(fmt.Errorf("failed to update shared state: %w", err))
+	}
+
+	return newState, nil
+}
+
+// RenewLease renews the lease of the shared state backend if the coordinator instance is active.
+// The coordinator instance becomes a standby instance if the lease was lost.
+// Returns non-critical errors.
+func (coo *Coordinator) RenewLease() error {
+
+	if coo.opts.stateBackend == nil {
+		return nil
+	}
+
+	coo.leaseLock.Lock()
+	defer coo.leaseLock.Unlock()
+
+	if !coo.active {
+		return nil
+	}
+
+	acquired, err := coo.opts.stateBackend.AcquireLease(coo.opts.instanceID, coo.opts.leaseDuration)
+	if err != nil {
+		return common.SoftError(fmt.Errorf("failed to renew lease: %w", err))
+	}
+
+	if !acquired {
+		coo.active = false
+		return common.SoftError(ErrLeaseNotHeld)
+	}
+
+	return nil
+}
+
+// Deactivate releases the lease of the shared state backend, so a standby instance can take over.
+func (coo *Coordinator) Deactivate() error {
+
+	if coo.opts.stateBackend == nil {
+		return nil
+	}
+
+	coo.leaseLock.Lock()
+	defer coo.leaseLock.Unlock()
+
+	if !coo.active {
+		return nil
+	}
+	coo.active = false
+
+	return coo.opts.stateBackend.ReleaseLease(coo.opts.instanceID)
+}
+
+// storeSharedState stores the state in the shared state backend.
+// The coordinator instance becomes a standby instance if the lease was lost.
+// Returns non-critical errors.
+func (coo *Coordinator) storeSharedState(state *State, pendingMilestoneMessage []byte) error {
+
+	if err := coo.opts.stateBackend.StoreState(coo.opts.instanceID, &SharedState{State: state, PendingMilestoneMessage: pendingMilestoneMessage}); err != nil {
+		if errors.Is(err, ErrLeaseNotHeld) {
+			coo.leaseLock.Lock()
+			coo.active = false
+			coo.leaseLock.Unlock()
+		}
+		return common.SoftError(fmt.Errorf("failed to update shared state: %w", err))
+	}
+
+	return nil
 }
 
 // AddBackPressureFunc adds a BackPressureFunc.
@@ -540,6 +904,11 @@ func (coo *Coordinator) AddBackPressureFunc(bpFunc BackPressureFunc) {
 	coo.backpressureFuncs = append(coo.backpressureFuncs, bpFunc)
 }
 
+// IsUnderBackPressure returns whether any back pressure function is signaling congestion.
+func (coo *Coordinator) IsUnderBackPressure() bool {
+	return coo.checkBackPressureFunctions()
+}
+
 // checkBackPressureFunctions checks whether any back pressure function is signaling congestion.
 func (coo *Coordinator) checkBackPressureFunctions() bool {
 	for _, f := range coo.backpressureFuncs {
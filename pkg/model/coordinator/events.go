@@ -1,6 +1,8 @@
 package coordinator
 
 import (
+	"time"
+
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 )
@@ -19,3 +21,22 @@ func MilestoneCaller(handler interface{}, params ...interface{}) {
 func QuorumFinishedCaller(handler interface{}, params ...interface{}) {
 	handler.(func(result *QuorumFinishedResult))(params[0].(*QuorumFinishedResult))
 }
+
+// AuditEntry describes an action that was performed via the control API of the coordinator.
+type AuditEntry struct {
+	// the time the action was performed.
+	Time time.Time
+	// the name of the action.
+	Action string
+	// the address of the client that performed the action.
+	RemoteAddress string
+	// optional details of the action.
+	Details string
+	// the error if the action failed.
+	Err error
+}
+
+// AuditCaller is used to signal performed actions of the control API.
+func AuditCaller(handler interface{}, params ...interface{}) {
+	handler.(func(entry *AuditEntry))(params[0].(*AuditEntry))
+}
@@ -0,0 +1,235 @@
+package coordinator_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	testLeaseDuration = 200 * time.Millisecond
+	belowMaxDepth     = 15
+	minPoWScore       = 1.0
+)
+
+var errSendFailed = errors.New("send failed")
+
+// testInstance is a coordinator instance with a SendMessageFunc that can be manipulated by the tests.
+type testInstance struct {
+	*coordinator.Coordinator
+	te *testsuite.TestEnvironment
+
+	// the amount of sent milestones.
+	sentMilestones int
+	// the next milestone is not sent.
+	failNextMilestone bool
+	// called after the next milestone was sent.
+	afterNextMilestone func()
+}
+
+func newTestInstance(te *testsuite.TestEnvironment, stateBackend coordinator.StateBackend, instanceID string) *testInstance {
+
+	instance := &testInstance{te: te}
+	instance.Coordinator = te.NewCoordinatorInstance(stateBackend, instanceID, testLeaseDuration, func(msg *storage.Message, msIndex ...milestone.Index) error {
+		if msg.Milestone() == nil {
+			return te.SendMessage(msg, msIndex...)
+		}
+
+		if instance.failNextMilestone {
+			instance.failNextMilestone = false
+			return errSendFailed
+		}
+
+		if err := te.SendMessage(msg, msIndex...); err != nil {
+			return err
+		}
+		instance.sentMilestones++
+
+		if instance.afterNextMilestone != nil {
+			instance.afterNextMilestone()
+			instance.afterNextMilestone = nil
+		}
+
+		return nil
+	})
+
+	return instance
+}
+
+// issueMilestone issues the next milestone on top of the latest milestone.
+func (i *testInstance) issueMilestone() (hornet.MessageID, error) {
+	return i.IssueMilestone(hornet.MessageIDs{i.State().LatestMilestoneMessageID})
+}
+
+// initFailoverTest sets up a test environment and a shared state backend that contains the state
+// of the coordinator of the test environment, and creates two coordinator instances that use the backend.
+func initFailoverTest(t *testing.T) (*testsuite.TestEnvironment, coordinator.StateBackend, *testInstance, *testInstance) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, belowMaxDepth, minPoWScore, false)
+
+	stateBackend, err := coordinator.NewFileStateBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cachedMilestoneMsg := te.Storage().MilestoneCachedMessageOrNil(1) // message +1
+	require.NotNil(t, cachedMilestoneMsg)
+	defer cachedMilestoneMsg.Release(true) // message -1
+
+	// the first instance bootstrapped the network
+	acquired, err := stateBackend.AcquireLease("coo1", testLeaseDuration)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	require.NoError(t, stateBackend.StoreState("coo1", &coordinator.SharedState{
+		State: &coordinator.State{
+			LatestMilestoneIndex:     1,
+			LatestMilestoneMessageID: cachedMilestoneMsg.Message().MessageID(),
+			LatestMilestoneTime:      time.Now(),
+		},
+	}))
+
+	coo1 := newTestInstance(te, stateBackend, "coo1")
+	coo2 := newTestInstance(te, stateBackend, "coo2")
+
+	active, err := coo1.Activate()
+	require.NoError(t, err)
+	require.True(t, active)
+
+	active, err = coo2.Activate()
+	require.NoError(t, err)
+	require.False(t, active)
+
+	_, err = coo1.issueMilestone()
+	require.NoError(t, err)
+	te.ConfirmMilestone(2, false)
+
+	// the standby instance doesn't issue milestones
+	_, err = coo2.IssueMilestone(hornet.MessageIDs{hornet.NullMessageID()})
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorNotActive)
+
+	return te, stateBackend, coo1, coo2
+}
+
+// TestFailoverPendingMilestoneNotSent checks that a standby instance sends the pending milestone
+// of an active instance that crashed before the milestone was sent.
+func TestFailoverPendingMilestoneNotSent(t *testing.T) {
+	te, stateBackend, coo1, coo2 := initFailoverTest(t)
+	defer te.CleanupTestEnvironment(true)
+
+	// the active instance crashes after the milestone was stored in the shared state
+	coo1.failNextMilestone = true
+	_, err := coo1.issueMilestone()
+	require.ErrorIs(t, err, errSendFailed)
+	require.Nil(t, te.Storage().CachedMilestoneOrNil(3))
+
+	sharedState, err := stateBackend.LoadState()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(2), sharedState.State.LatestMilestoneIndex)
+	require.NotEmpty(t, sharedState.PendingMilestoneMessage)
+
+	pendingMilestoneMsg, err := storage.MessageFromBytes(sharedState.PendingMilestoneMessage, iotago.DeSeriModePerformValidation)
+	require.NoError(t, err)
+
+	// the standby instance can't take over before the lease expired
+	active, err := coo2.Activate()
+	require.NoError(t, err)
+	require.False(t, active)
+
+	time.Sleep(testLeaseDuration)
+
+	active, err = coo2.Activate()
+	require.NoError(t, err)
+	require.True(t, active)
+
+	// the pending milestone was sent instead of a new one with the same index
+	require.Equal(t, 1, coo2.sentMilestones)
+	require.Equal(t, milestone.Index(3), coo2.State().LatestMilestoneIndex)
+	require.Equal(t, pendingMilestoneMsg.MessageID(), coo2.State().LatestMilestoneMessageID)
+	te.ConfirmMilestone(3, false)
+
+	sharedState, err = stateBackend.LoadState()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(3), sharedState.State.LatestMilestoneIndex)
+	require.Empty(t, sharedState.PendingMilestoneMessage)
+
+	// the previously active instance is fenced off
+	require.ErrorIs(t, coo1.RenewLease(), coordinator.ErrLeaseNotHeld)
+	_, err = coo1.IssueMilestone(hornet.MessageIDs{hornet.NullMessageID()})
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorNotActive)
+
+	_, err = coo2.issueMilestone()
+	require.NoError(t, err)
+	te.ConfirmMilestone(4, false)
+}
+
+// TestFailoverPendingMilestoneAlreadySent checks that a standby instance confirms the pending milestone
+// of an active instance that lost the lease after the milestone was sent, without sending it again.
+func TestFailoverPendingMilestoneAlreadySent(t *testing.T) {
+	te, stateBackend, coo1, coo2 := initFailoverTest(t)
+	defer te.CleanupTestEnvironment(true)
+
+	// the active instance loses the lease after the milestone was sent
+	coo1.afterNextMilestone = func() {
+		require.NoError(t, stateBackend.ReleaseLease("coo1"))
+	}
+	milestoneMessageID, err := coo1.issueMilestone()
+	require.ErrorIs(t, err, coordinator.ErrLeaseNotHeld)
+	require.Nil(t, milestoneMessageID)
+	require.False(t, coo1.IsActive())
+	te.ConfirmMilestone(3, false)
+
+	sharedState, err := stateBackend.LoadState()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(2), sharedState.State.LatestMilestoneIndex)
+	require.NotEmpty(t, sharedState.PendingMilestoneMessage)
+
+	active, err := coo2.Activate()
+	require.NoError(t, err)
+	require.True(t, active)
+
+	// the pending milestone is already known to the node, so it is not sent again
+	require.Zero(t, coo2.sentMilestones)
+
+	cachedMilestoneMsg := te.Storage().MilestoneCachedMessageOrNil(3) // message +1
+	require.NotNil(t, cachedMilestoneMsg)
+	defer cachedMilestoneMsg.Release(true) // message -1
+
+	require.Equal(t, milestone.Index(3), coo2.State().LatestMilestoneIndex)
+	require.Equal(t, cachedMilestoneMsg.Message().MessageID(), coo2.State().LatestMilestoneMessageID)
+
+	// the returned state is a copy
+	state := coo2.State()
+	state.LatestMilestoneIndex = 0
+	require.Equal(t, milestone.Index(3), coo2.State().LatestMilestoneIndex)
+
+	// the state can be read while a milestone is issued
+	coo2.LockMilestoneIssuance()
+	stateRead := make(chan *coordinator.State)
+	go func() {
+		stateRead <- coo2.State()
+	}()
+	select {
+	case state = <-stateRead:
+		require.Equal(t, milestone.Index(3), state.LatestMilestoneIndex)
+	case <-time.After(time.Second):
+		t.Fatal("reading the state waited for the milestone issuance")
+	}
+	coo2.UnlockMilestoneIssuance()
+
+	_, err = coo2.issueMilestone()
+	require.NoError(t, err)
+	te.ConfirmMilestone(4, false)
+
+	sharedState, err = stateBackend.LoadState()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(4), sharedState.State.LatestMilestoneIndex)
+	require.Empty(t, sharedState.PendingMilestoneMessage)
+}
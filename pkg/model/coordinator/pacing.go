@@ -0,0 +1,176 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PacingModeFixed issues milestones in a fixed interval.
+	PacingModeFixed = "fixed"
+	// PacingModeAdaptive adapts the milestone interval to the load of the network.
+	PacingModeAdaptive = "adaptive"
+	// PacingModeOnDemand only issues milestones if they are triggered or enough new messages arrived.
+	PacingModeOnDemand = "onDemand"
+)
+
+var (
+	// ErrUnknownPacingMode is returned if an unknown pacing mode is configured.
+	ErrUnknownPacingMode = errors.New("unknown pacing mode")
+	// ErrInvalidPacingIntervals is returned if the bounds of the milestone interval are invalid.
+	ErrInvalidPacingIntervals = errors.New("invalid pacing intervals")
+)
+
+// PacingStats are the metrics the pacing policies base their decision on.
+type PacingStats struct {
+	// the amount of new messages per second.
+	MPS float64
+	// the amount of unreferenced messages that are tracked for the milestone tipselection.
+	BacklogMessagesCount int
+	// the amount of new solid messages since the last milestone was issued.
+	NewMessagesCount int
+	// the time that passed since the last milestone was issued.
+	SinceLastMilestone time.Duration
+}
+
+// PacingPolicy decides when the next milestone is issued.
+type PacingPolicy interface {
+	// MilestoneDue returns whether the next milestone should be issued.
+	MilestoneDue(stats *PacingStats) bool
+}
+
+// AdaptivePacingPolicy adapts the milestone interval to the load of the network.
+// Without load milestones are issued in the maximum interval, the interval decreases linearly with
+// the amount of new messages per second and the backlog of unreferenced messages until the minimum interval is reached.
+type AdaptivePacingPolicy struct {
+	minInterval      time.Duration
+	maxInterval      time.Duration
+	mpsThreshold     float64
+	backlogThreshold int
+}
+
+// NewAdaptivePacingPolicy creates a new AdaptivePacingPolicy.
+// The minimum interval is used as soon as either the mpsThreshold or the backlogThreshold is reached.
+func NewAdaptivePacingPolicy(minInterval time.Duration, maxInterval time.Duration, mpsThreshold float64, backlogThreshold int) (*AdaptivePacingPolicy, error) {
+
+	if minInterval <= 0 || maxInterval < minInterval {
+		return nil, fmt.Errorf("%w: min %v, max %v", ErrInvalidPacingIntervals, minInterval, maxInterval)
+	}
+
+	return &AdaptivePacingPolicy{
+		minInterval:      minInterval,
+		maxInterval:      maxInterval,
+		mpsThreshold:     mpsThreshold,
+		backlogThreshold: backlogThreshold,
+	}, nil
+}
+
+// Interval returns the milestone interval for the given load.
+func (p *AdaptivePacingPolicy) Interval(stats *PacingStats) time.Duration {
+
+	load := 0.0
+	if p.mpsThreshold > 0 {
+		load = stats.MPS / p.mpsThreshold
+	}
+	if p.backlogThreshold > 0 {
+		if backlogLoad := float64(stats.BacklogMessagesCount) / float64(p.backlogThreshold); backlogLoad > load {
+			load = backlogLoad
+		}
+	}
+	if load > 1 {
+		load = 1
+	}
+
+	return p.maxInterval - time.Duration(load*float64(p.maxInterval-p.minInterval))
+}
+
+// MilestoneDue returns whether the interval for the current load passed since the last milestone.
+func (p *AdaptivePacingPolicy) MilestoneDue(stats *PacingStats) bool {
+	return stats.SinceLastMilestone >= p.Interval(stats)
+}
+
+// OnDemandPacingPolicy issues a milestone as soon as enough new messages arrived.
+// Milestones can additionally be triggered manually.
+type OnDemandPacingPolicy struct {
+	newMessagesThreshold int
+}
+
+// NewOnDemandPacingPolicy creates a new OnDemandPacingPolicy.
+// If newMessagesThreshold is zero, milestones are only issued if they are triggered manually.
+func NewOnDemandPacingPolicy(newMessagesThreshold int) *OnDemandPacingPolicy {
+	return &OnDemandPacingPolicy{newMessagesThreshold: newMessagesThreshold}
+}
+
+// MilestoneDue returns whether enough new messages arrived since the last milestone.
+func (p *OnDemandPacingPolicy) MilestoneDue(stats *PacingStats) bool {
+	return p.newMessagesThreshold > 0 && stats.NewMessagesCount >= p.newMessagesThreshold
+}
+
+// Pacer collects the metrics for a PacingPolicy and decides when the next milestone is issued.
+type Pacer struct {
+	policy PacingPolicy
+
+	lock             sync.Mutex
+	mps              float64
+	newMessagesCount int
+	lastMilestone    time.Time
+}
+
+// NewPacer creates a new Pacer with the given policy.
+func NewPacer(policy PacingPolicy) *Pacer {
+	return &Pacer{
+		policy:        policy,
+		lastMilestone: time.Now(),
+	}
+}
+
+// Policy returns the used pacing policy.
+func (p *Pacer) Policy() PacingPolicy {
+	return p.policy
+}
+
+// UpdateMPS sets the current amount of new messages per second.
+func (p *Pacer) UpdateMPS(mps float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.mps = mps
+}
+
+// MessageSeen counts a new solid message.
+func (p *Pacer) MessageSeen() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.newMessagesCount++
+}
+
+// Reset resets the metrics after a milestone was issued.
+func (p *Pacer) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.newMessagesCount = 0
+	p.lastMilestone = time.Now()
+}
+
+// Stats returns the current metrics of the pacer.
+func (p *Pacer) Stats(backlogMessagesCount int) *PacingStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return &PacingStats{
+		MPS:                  p.mps,
+		BacklogMessagesCount: backlogMessagesCount,
+		NewMessagesCount:     p.newMessagesCount,
+		SinceLastMilestone:   time.Since(p.lastMilestone),
+	}
+}
+
+// MilestoneDue returns whether the next milestone should be issued.
+func (p *Pacer) MilestoneDue(backlogMessagesCount int) bool {
+	return p.policy.MilestoneDue(p.Stats(backlogMessagesCount))
+}
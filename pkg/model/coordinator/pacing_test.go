@@ -0,0 +1,57 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+)
+
+func TestAdaptivePacingPolicy(t *testing.T) {
+
+	_, err := coordinator.NewAdaptivePacingPolicy(10*time.Second, 2*time.Second, 100, 1000)
+	require.ErrorIs(t, err, coordinator.ErrInvalidPacingIntervals)
+
+	policy, err := coordinator.NewAdaptivePacingPolicy(2*time.Second, 10*time.Second, 100, 1000)
+	require.NoError(t, err)
+
+	// without load the maximum interval is used
+	require.Equal(t, 10*time.Second, policy.Interval(&coordinator.PacingStats{}))
+
+	// the interval decreases linearly with the load
+	require.Equal(t, 6*time.Second, policy.Interval(&coordinator.PacingStats{MPS: 50}))
+	require.Equal(t, 6*time.Second, policy.Interval(&coordinator.PacingStats{MPS: 10, BacklogMessagesCount: 500}))
+
+	// the minimum interval is never undercut
+	require.Equal(t, 2*time.Second, policy.Interval(&coordinator.PacingStats{MPS: 500}))
+	require.Equal(t, 2*time.Second, policy.Interval(&coordinator.PacingStats{BacklogMessagesCount: 5000}))
+
+	require.False(t, policy.MilestoneDue(&coordinator.PacingStats{MPS: 50, SinceLastMilestone: 5 * time.Second}))
+	require.True(t, policy.MilestoneDue(&coordinator.PacingStats{MPS: 50, SinceLastMilestone: 6 * time.Second}))
+}
+
+func TestOnDemandPacingPolicy(t *testing.T) {
+
+	pacer := coordinator.NewPacer(coordinator.NewOnDemandPacingPolicy(3))
+	require.False(t, pacer.MilestoneDue(0))
+
+	pacer.MessageSeen()
+	pacer.MessageSeen()
+	require.False(t, pacer.MilestoneDue(0))
+
+	pacer.MessageSeen()
+	require.True(t, pacer.MilestoneDue(0))
+
+	// the new messages are counted from the last milestone
+	pacer.Reset()
+	require.False(t, pacer.MilestoneDue(0))
+
+	// without a threshold, milestones are only issued if they are triggered
+	pacer = coordinator.NewPacer(coordinator.NewOnDemandPacingPolicy(0))
+	for i := 0; i < 100; i++ {
+		pacer.MessageSeen()
+	}
+	require.False(t, pacer.MilestoneDue(0))
+}
@@ -8,11 +8,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	quorumprotocol "github.com/gohornet/hornet/pkg/protocol/quorum"
 	"github.com/iotaledger/hive.go/syncutils"
 	iotago "github.com/iotaledger/iota.go/v2"
 )
@@ -29,7 +31,11 @@ type QuorumClientConfig struct {
 	// optional alias of the quorum client.
 	Alias string `json:"alias" koanf:"alias"`
 	// baseURL of the quorum client.
+	// either the baseURL or the peerID has to be given.
 	BaseURL string `json:"baseURL" koanf:"baseURL"`
+	// peerID of the quorum client if the white flag computation is requested via the quorum protocol.
+	// the quorum client has to be a connected peer.
+	PeerID string `json:"peerID" koanf:"peerID"`
 	// optional username for basic auth.
 	UserName string `json:"userName" koanf:"userName"`
 	// optional password for basic auth.
@@ -44,6 +50,8 @@ type QuorumClientStatistic struct {
 	Alias string
 	// baseURL of the quorum client.
 	BaseURL string
+	// peerID of the quorum client.
+	PeerID string
 	// last response time of the whiteflag API call.
 	ResponseTimeSeconds float64
 	// error of last whiteflag API call.
@@ -56,9 +64,44 @@ type QuorumFinishedResult struct {
 	Err      error
 }
 
+// String returns the address of the quorum client.
+func (s *QuorumClientStatistic) String() string {
+	if s.PeerID != "" {
+		return fmt.Sprintf("peerID: %s", s.PeerID)
+	}
+	return fmt.Sprintf("baseURL: %s", s.BaseURL)
+}
+
+// quorumClientAPI requests the white flag computation from a quorum client.
+type quorumClientAPI interface {
+	Whiteflag(index milestone.Index, parents hornet.MessageIDs) (*MerkleTreeHash, error)
+}
+
+// peerQuorumClientAPI requests the white flag computation from a connected peer via the quorum protocol.
+type peerQuorumClientAPI struct {
+	client  *quorumprotocol.Client
+	peerID  peer.ID
+	timeout time.Duration
+}
+
+// Whiteflag requests the white flag computation from the peer.
+func (api *peerQuorumClientAPI) Whiteflag(index milestone.Index, parents hornet.MessageIDs) (*MerkleTreeHash, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), api.timeout)
+	defer cancel()
+
+	merkleTreeHash, err := api.client.RequestWhiteFlag(ctx, api.peerID, index, parents)
+	if err != nil {
+		return nil, err
+	}
+
+	result := MerkleTreeHash(merkleTreeHash)
+	return &result, nil
+}
+
 // quorumGroupEntry holds the api and statistics of a quorum client.
 type quorumGroupEntry struct {
-	api   *DebugNodeAPIClient
+	api   quorumClientAPI
 	stats *QuorumClientStatistic
 }
 
@@ -73,8 +116,9 @@ type quorum struct {
 }
 
 // newQuorum creates a new quorum, which is used to check the correct ledger state of the coordinator.
+// The peerClient is used for quorum clients that are configured with a peerID.
 // If no groups are given, nil is returned.
-func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration) *quorum {
+func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration, peerClient *quorumprotocol.Client) *quorum {
 	if len(quorumGroups) == 0 {
 		panic("coordinator quorum groups not found")
 	}
@@ -87,20 +131,47 @@ func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Durat
 
 		groups[groupName] = make([]*quorumGroupEntry, len(groupNodes))
 		for i, client := range groupNodes {
-			var userInfo *url.Userinfo
-			if client.UserName != "" || client.Password != "" {
-				userInfo = url.UserPassword(client.UserName, client.Password)
-			}
+			var api quorumClientAPI
 
-			groups[groupName][i] = &quorumGroupEntry{
-				api: NewDebugNodeAPIClient(client.BaseURL,
+			switch {
+			case client.PeerID != "":
+				if peerClient == nil {
+					panic(fmt.Sprintf("invalid coo quorum client: %s, quorum protocol not available", client.PeerID))
+				}
+
+				peerID, err := peer.Decode(client.PeerID)
+				if err != nil {
+					panic(fmt.Sprintf("invalid coo quorum client peerID: %s, %s", client.PeerID, err))
+				}
+
+				api = &peerQuorumClientAPI{
+					client:  peerClient,
+					peerID:  peerID,
+					timeout: timeout,
+				}
+
+			case client.BaseURL != "":
+				var userInfo *url.Userinfo
+				if client.UserName != "" || client.Password != "" {
+					userInfo = url.UserPassword(client.UserName, client.Password)
+				}
+
+				api = NewDebugNodeAPIClient(client.BaseURL,
 					iotago.WithNodeHTTPAPIClientHTTPClient(&http.Client{Timeout: timeout}),
 					iotago.WithNodeHTTPAPIClientUserInfo(userInfo),
-				),
+				)
+
+			default:
+				panic(fmt.Sprintf("invalid coo quorum group: %s, neither baseURL nor peerID given", groupName))
+			}
+
+			groups[groupName][i] = &quorumGroupEntry{
+				api: api,
 				stats: &QuorumClientStatistic{
 					Group:   groupName,
 					Alias:   client.Alias,
 					BaseURL: client.BaseURL,
+					PeerID:  client.PeerID,
 				},
 			}
 		}
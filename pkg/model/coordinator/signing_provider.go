@@ -1,8 +1,13 @@
 package coordinator
 
 import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/hsm"
 	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/remotesigner"
 	iotago "github.com/iotaledger/iota.go/v2"
 	"github.com/iotaledger/iota.go/v2/ed25519"
 )
@@ -142,3 +147,150 @@ func (s *InsecureRemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.Miles
 func (s *InsecureRemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
 	return s.signingFunc
 }
+
+// RemoteEd25519MilestoneSignerProvider provides RemoteEd25519MilestoneIndexSigner.
+type RemoteEd25519MilestoneSignerProvider struct {
+	signingFunc     iotago.MilestoneSigningFunc
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+}
+
+// NewRemoteEd25519MilestoneSignerProvider creates a new RemoteEd25519MilestoneSignerProvider.
+// The connection to the remote signer is secured with mutual TLS.
+func NewRemoteEd25519MilestoneSignerProvider(remoteEndpoint string, tlsConfig *tls.Config, keyManager *keymanager.KeyManager, publicKeysCount int) *RemoteEd25519MilestoneSignerProvider {
+
+	return &RemoteEd25519MilestoneSignerProvider{
+		signingFunc:     remotesigner.Ed25519MilestoneSigner(remoteEndpoint, tlsConfig),
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+	}
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *RemoteEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) MilestoneIndexSigner {
+
+	return &RemoteEd25519MilestoneIndexSigner{
+		pubKeys:     p.keyManger.PublicKeysForMilestoneIndex(index),
+		pubKeySet:   p.keyManger.PublicKeysSetForMilestoneIndex(index),
+		signingFunc: p.signingFunc,
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *RemoteEd25519MilestoneSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// RemoteEd25519MilestoneIndexSigner is a remote signer for a particular milestone.
+type RemoteEd25519MilestoneIndexSigner struct {
+	pubKeys     []iotago.MilestonePublicKey
+	pubKeySet   iotago.MilestonePublicKeySet
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *RemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
+
+// HSMEd25519MilestoneSignerProvider provides HSMEd25519MilestoneIndexSigner.
+type HSMEd25519MilestoneSignerProvider struct {
+	token           hsm.Token
+	tokenPubKeys    iotago.MilestonePublicKeySet
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+}
+
+// NewHSMEd25519MilestoneSignerProvider creates a new HSMEd25519MilestoneSignerProvider.
+// The private keys never leave the token, the signatures are created by the token itself.
+func NewHSMEd25519MilestoneSignerProvider(token hsm.Token, keyManager *keymanager.KeyManager, publicKeysCount int) *HSMEd25519MilestoneSignerProvider {
+
+	tokenPubKeys := iotago.MilestonePublicKeySet{}
+	for _, pubKey := range token.PublicKeys() {
+		tokenPubKeys[pubKey] = struct{}{}
+	}
+
+	return &HSMEd25519MilestoneSignerProvider{
+		token:           token,
+		tokenPubKeys:    tokenPubKeys,
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+	}
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *HSMEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) MilestoneIndexSigner {
+
+	pubKeySet := p.keyManger.PublicKeysSetForMilestoneIndex(index)
+
+	// select the keys on the token that are valid for the milestone index (same order as the key ranges)
+	var pubKeys []iotago.MilestonePublicKey
+	for _, pubKey := range p.keyManger.PublicKeysForMilestoneIndex(index) {
+		if _, exists := p.tokenPubKeys[pubKey]; !exists {
+			continue
+		}
+
+		pubKeys = append(pubKeys, pubKey)
+		if len(pubKeys) == p.PublicKeysCount() {
+			break
+		}
+	}
+
+	return &HSMEd25519MilestoneIndexSigner{
+		pubKeys:     pubKeys,
+		pubKeySet:   pubKeySet,
+		signingFunc: p.signingFunc,
+	}
+}
+
+// signingFunc signs the milestone essence with the keys on the token.
+func (p *HSMEd25519MilestoneSignerProvider) signingFunc(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+
+	sigs := make([]iotago.MilestoneSignature, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		sig, err := p.token.Sign(pubKey, msEssence)
+		if err != nil {
+			return nil, fmt.Errorf("signing with public key %x failed: %w", pubKey[:], err)
+		}
+		copy(sigs[i][:], sig)
+	}
+
+	return sigs, nil
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *HSMEd25519MilestoneSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// HSMEd25519MilestoneIndexSigner is a hardware security module signer for a particular milestone.
+type HSMEd25519MilestoneIndexSigner struct {
+	pubKeys     []iotago.MilestonePublicKey
+	pubKeySet   iotago.MilestonePublicKeySet
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *HSMEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *HSMEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *HSMEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
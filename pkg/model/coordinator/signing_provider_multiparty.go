@@ -0,0 +1,265 @@
+package coordinator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+var (
+	// ErrNoSignerServiceForPublicKey is returned if none of the signer services holds the private key of a public key.
+	ErrNoSignerServiceForPublicKey = errors.New("no signer service for public key")
+	// ErrMissingPartialSignatures is returned if not all signer services provided their partial signatures.
+	ErrMissingPartialSignatures = errors.New("missing partial signatures")
+	// ErrInvalidPartialSignature is returned if a signer service provided an invalid signature.
+	ErrInvalidPartialSignature = errors.New("invalid partial signature")
+)
+
+// SignerService is an independent signer service that holds a part of the milestone keys.
+type SignerService struct {
+	// Alias is used to identify the signer service in errors.
+	Alias string
+	// PublicKeys are the public keys of the private keys the signer service holds.
+	PublicKeys []iotago.MilestonePublicKey
+	// SigningFunc requests the signatures for the given public keys from the signer service.
+	SigningFunc iotago.MilestoneSigningFunc
+}
+
+// MultiPartyEd25519MilestoneSignerProvider provides MultiPartyEd25519MilestoneIndexSigner.
+type MultiPartyEd25519MilestoneSignerProvider struct {
+	signerServices  map[iotago.MilestonePublicKey][]*SignerService
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+
+	// the signer services that failed during their latest signing request.
+	failedSignerServicesLock sync.RWMutex
+	failedSignerServices     map[*SignerService]struct{}
+}
+
+// NewMultiPartyEd25519MilestoneSignerProvider creates a new MultiPartyEd25519MilestoneSignerProvider.
+// The partial signatures are collected from the signer services and the milestone is assembled
+// as soon as all the distinct keys of the milestone signed. A key can be held by several signer services,
+// in that case a failed signer service is replaced by another one in the next signing attempt.
+func NewMultiPartyEd25519MilestoneSignerProvider(signerServices []*SignerService, keyManager *keymanager.KeyManager, publicKeysCount int) *MultiPartyEd25519MilestoneSignerProvider {
+
+	signerServicesByPubKey := make(map[iotago.MilestonePublicKey][]*SignerService)
+	for _, signerService := range signerServices {
+		for _, pubKey := range signerService.PublicKeys {
+			signerServicesByPubKey[pubKey] = append(signerServicesByPubKey[pubKey], signerService)
+		}
+	}
+
+	return &MultiPartyEd25519MilestoneSignerProvider{
+		signerServices:       signerServicesByPubKey,
+		keyManger:            keyManager,
+		publicKeysCount:      publicKeysCount,
+		failedSignerServices: make(map[*SignerService]struct{}),
+	}
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *MultiPartyEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) MilestoneIndexSigner {
+
+	pubKeySet := p.keyManger.PublicKeysSetForMilestoneIndex(index)
+
+	// the public keys are part of the milestone essence and can't be changed after the first signing attempt,
+	// so keys held by signer services that did not fail recently are preferred (same order as the key ranges).
+	var pubKeys, fallbackPubKeys []iotago.MilestonePublicKey
+	for _, pubKey := range p.keyManger.PublicKeysForMilestoneIndex(index) {
+		signerServices, exists := p.signerServices[pubKey]
+		if !exists {
+			continue
+		}
+
+		if len(p.healthySignerServices(signerServices)) == 0 {
+			fallbackPubKeys = append(fallbackPubKeys, pubKey)
+			continue
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	pubKeys = append(pubKeys, fallbackPubKeys...)
+	if len(pubKeys) > p.PublicKeysCount() {
+		pubKeys = pubKeys[:p.PublicKeysCount()]
+	}
+
+	return &MultiPartyEd25519MilestoneIndexSigner{
+		pubKeys:     pubKeys,
+		pubKeySet:   pubKeySet,
+		signingFunc: p.newSigningFunc(),
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *MultiPartyEd25519MilestoneSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// healthySignerServices returns the signer services that did not fail during their latest signing request.
+func (p *MultiPartyEd25519MilestoneSignerProvider) healthySignerServices(signerServices []*SignerService) []*SignerService {
+	p.failedSignerServicesLock.RLock()
+	defer p.failedSignerServicesLock.RUnlock()
+
+	var healthy []*SignerService
+	for _, signerService := range signerServices {
+		if _, failed := p.failedSignerServices[signerService]; !failed {
+			healthy = append(healthy, signerService)
+		}
+	}
+
+	return healthy
+}
+
+func (p *MultiPartyEd25519MilestoneSignerProvider) setSignerServiceFailed(signerService *SignerService, failed bool) {
+	p.failedSignerServicesLock.Lock()
+	defer p.failedSignerServicesLock.Unlock()
+
+	if failed {
+		p.failedSignerServices[signerService] = struct{}{}
+		return
+	}
+	delete(p.failedSignerServices, signerService)
+}
+
+// selectSignerService selects the signer service that is asked for the signature of a public key.
+// Healthy signer services are preferred, the selection rotates with every signing attempt.
+func (p *MultiPartyEd25519MilestoneSignerProvider) selectSignerService(pubKey iotago.MilestonePublicKey, attempt int) (*SignerService, error) {
+
+	signerServices, exists := p.signerServices[pubKey]
+	if !exists {
+		return nil, fmt.Errorf("%w: %x", ErrNoSignerServiceForPublicKey, pubKey[:])
+	}
+
+	if healthy := p.healthySignerServices(signerServices); len(healthy) > 0 {
+		signerServices = healthy
+	}
+
+	return signerServices[attempt%len(signerServices)], nil
+}
+
+// partialSigningResult is the result of a signing request to a signer service.
+type partialSigningResult struct {
+	signerService *SignerService
+	pubKeys       []iotago.MilestonePublicKey
+	sigs          []iotago.MilestoneSignature
+	err           error
+}
+
+// newSigningFunc returns a signing function that collects the partial signatures from the signer services.
+// The partial signatures of a milestone essence are kept between the signing attempts,
+// so a retry only requests the signatures that are still missing.
+func (p *MultiPartyEd25519MilestoneSignerProvider) newSigningFunc() iotago.MilestoneSigningFunc {
+
+	var signedEssence []byte
+	partialSigs := make(map[iotago.MilestonePublicKey]iotago.MilestoneSignature)
+	attempt := 0
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+
+		if !bytes.Equal(signedEssence, msEssence) {
+			signedEssence = append([]byte(nil), msEssence...)
+			partialSigs = make(map[iotago.MilestonePublicKey]iotago.MilestoneSignature)
+			attempt = 0
+		}
+
+		// every signer service is asked once for all the missing signatures it is responsible for
+		requests := make(map[*SignerService][]iotago.MilestonePublicKey)
+		for _, pubKey := range pubKeys {
+			if _, signed := partialSigs[pubKey]; signed {
+				continue
+			}
+
+			signerService, err := p.selectSignerService(pubKey, attempt)
+			if err != nil {
+				return nil, err
+			}
+			requests[signerService] = append(requests[signerService], pubKey)
+		}
+		attempt++
+
+		resultChan := make(chan *partialSigningResult, len(requests))
+		for signerService, requestPubKeys := range requests {
+			go func(signerService *SignerService, requestPubKeys []iotago.MilestonePublicKey) {
+				sigs, err := signerService.SigningFunc(requestPubKeys, msEssence)
+				resultChan <- &partialSigningResult{signerService: signerService, pubKeys: requestPubKeys, sigs: sigs, err: err}
+			}(signerService, requestPubKeys)
+		}
+
+		var failures []string
+		for range requests {
+			result := <-resultChan
+
+			err := result.err
+			if err == nil {
+				err = verifyPartialSignatures(result.pubKeys, result.sigs, msEssence)
+			}
+			p.setSignerServiceFailed(result.signerService, err != nil)
+
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", result.signerService.Alias, err))
+				continue
+			}
+
+			for i, pubKey := range result.pubKeys {
+				partialSigs[pubKey] = result.sigs[i]
+			}
+		}
+
+		if len(failures) > 0 {
+			return nil, fmt.Errorf("%w, %d/%d signatures collected: %s", ErrMissingPartialSignatures, len(partialSigs), len(pubKeys), strings.Join(failures, ", "))
+		}
+
+		sigs := make([]iotago.MilestoneSignature, len(pubKeys))
+		for i, pubKey := range pubKeys {
+			sigs[i] = partialSigs[pubKey]
+		}
+
+		return sigs, nil
+	}
+}
+
+// verifyPartialSignatures checks that a signer service provided valid signatures for all the requested public keys.
+func verifyPartialSignatures(pubKeys []iotago.MilestonePublicKey, sigs []iotago.MilestoneSignature, msEssence []byte) error {
+
+	if len(sigs) != len(pubKeys) {
+		return fmt.Errorf("%w: expected %d signatures, got %d", iotago.ErrMilestoneProducedSignaturesCountMismatch, len(pubKeys), len(sigs))
+	}
+
+	for i, pubKey := range pubKeys {
+		if !ed25519.Verify(pubKey[:], msEssence, sigs[i][:]) {
+			return fmt.Errorf("%w: public key %x", ErrInvalidPartialSignature, pubKey[:])
+		}
+	}
+
+	return nil
+}
+
+// MultiPartyEd25519MilestoneIndexSigner is a multi party signer for a particular milestone.
+type MultiPartyEd25519MilestoneIndexSigner struct {
+	pubKeys     []iotago.MilestonePublicKey
+	pubKeySet   iotago.MilestonePublicKeySet
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *MultiPartyEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *MultiPartyEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *MultiPartyEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
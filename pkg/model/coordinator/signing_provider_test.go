@@ -0,0 +1,212 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/hsm"
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+// memoryToken is a hsm.Token that holds the private keys in memory.
+type memoryToken struct {
+	privateKeys map[iotago.MilestonePublicKey]ed25519.PrivateKey
+}
+
+func (t *memoryToken) PublicKeys() []iotago.MilestonePublicKey {
+	pubKeys := make([]iotago.MilestonePublicKey, 0, len(t.privateKeys))
+	for pubKey := range t.privateKeys {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys
+}
+
+func (t *memoryToken) Sign(pubKey iotago.MilestonePublicKey, message []byte) ([]byte, error) {
+	privateKey, exists := t.privateKeys[pubKey]
+	if !exists {
+		return nil, hsm.ErrKeyNotFound
+	}
+	return ed25519.Sign(privateKey, message), nil
+}
+
+func (t *memoryToken) Close() error {
+	return nil
+}
+
+func TestHSMEd25519MilestoneSignerProvider(t *testing.T) {
+
+	token := &memoryToken{privateKeys: make(map[iotago.MilestonePublicKey]ed25519.PrivateKey)}
+	keyManager := keymanager.New()
+
+	// the first key is not stored on the token
+	var pubKeys []ed25519.PublicKey
+	for i := 0; i < 4; i++ {
+		pubKey, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		pubKeys = append(pubKeys, pubKey)
+
+		if i > 0 {
+			var msPubKey iotago.MilestonePublicKey
+			copy(msPubKey[:], pubKey)
+			token.privateKeys[msPubKey] = privateKey
+		}
+	}
+
+	keyManager.AddKeyRange(pubKeys[0], 0, 0)
+	keyManager.AddKeyRange(pubKeys[1], 0, 0)
+	keyManager.AddKeyRange(pubKeys[2], 5, 10)
+	keyManager.AddKeyRange(pubKeys[3], 8, 20)
+
+	provider := coordinator.NewHSMEd25519MilestoneSignerProvider(token, keyManager, 2)
+
+	signer := provider.MilestoneIndexSigner(3)
+	require.Len(t, signer.PublicKeys(), 1)
+	require.Len(t, signer.PublicKeysSet(), 2)
+
+	signer = provider.MilestoneIndexSigner(9)
+	require.Len(t, signer.PublicKeys(), 2)
+	require.Len(t, signer.PublicKeysSet(), 4)
+
+	essence := []byte("milestone essence")
+	signatures, err := signer.SigningFunc()(signer.PublicKeys(), essence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+
+	for i, pubKey := range signer.PublicKeys() {
+		require.True(t, ed25519.Verify(pubKey[:], essence, signatures[i][:]))
+	}
+
+	// keys that are not on the token can't be used for signing
+	var missingPubKey iotago.MilestonePublicKey
+	copy(missingPubKey[:], pubKeys[0])
+	_, err = signer.SigningFunc()([]iotago.MilestonePublicKey{missingPubKey}, essence)
+	require.ErrorIs(t, err, hsm.ErrKeyNotFound)
+}
+
+// newSignerService creates a signer service that holds the given private keys in memory.
+// The signer service fails as long as failing is set and counts the signed public keys.
+func newSignerService(alias string, privateKeys []ed25519.PrivateKey, failing *bool, signedKeysCount *int) *coordinator.SignerService {
+
+	keys := make(map[iotago.MilestonePublicKey]ed25519.PrivateKey)
+	pubKeys := make([]iotago.MilestonePublicKey, 0, len(privateKeys))
+	for _, privateKey := range privateKeys {
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], privateKey.Public().(ed25519.PublicKey))
+		keys[pubKey] = privateKey
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	return &coordinator.SignerService{
+		Alias:      alias,
+		PublicKeys: pubKeys,
+		SigningFunc: func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+			if *failing {
+				return nil, errors.New("signer service unavailable")
+			}
+
+			sigs := make([]iotago.MilestoneSignature, len(pubKeys))
+			for i, pubKey := range pubKeys {
+				privateKey, exists := keys[pubKey]
+				if !exists {
+					return nil, hsm.ErrKeyNotFound
+				}
+				copy(sigs[i][:], ed25519.Sign(privateKey, msEssence))
+			}
+			*signedKeysCount += len(pubKeys)
+
+			return sigs, nil
+		},
+	}
+}
+
+func TestMultiPartyEd25519MilestoneSignerProvider(t *testing.T) {
+
+	keyManager := keymanager.New()
+
+	var privateKeys []ed25519.PrivateKey
+	for i := 0; i < 3; i++ {
+		pubKey, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		privateKeys = append(privateKeys, privateKey)
+		keyManager.AddKeyRange(pubKey, 0, 0)
+	}
+
+	// the first key is held by two signer services, the third key by none
+	var failing1, failing2, failing3 bool
+	var signed1, signed2, signed3 int
+	provider := coordinator.NewMultiPartyEd25519MilestoneSignerProvider([]*coordinator.SignerService{
+		newSignerService("signer1", []ed25519.PrivateKey{privateKeys[0]}, &failing1, &signed1),
+		newSignerService("signer2", []ed25519.PrivateKey{privateKeys[0]}, &failing2, &signed2),
+		newSignerService("signer3", []ed25519.PrivateKey{privateKeys[1]}, &failing3, &signed3),
+	}, keyManager, 3)
+
+	signer := provider.MilestoneIndexSigner(1)
+	require.Len(t, signer.PublicKeys(), 2)
+	require.Len(t, signer.PublicKeysSet(), 3)
+
+	essence := []byte("milestone essence")
+	signatures, err := signer.SigningFunc()(signer.PublicKeys(), essence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	for i, pubKey := range signer.PublicKeys() {
+		require.True(t, ed25519.Verify(pubKey[:], essence, signatures[i][:]))
+	}
+	require.Equal(t, 1, signed1)
+	require.Equal(t, 0, signed2)
+	require.Equal(t, 1, signed3)
+
+	// a failed signer service is replaced in the next attempt and the collected signatures are kept
+	failing1 = true
+	failing3 = true
+	signer = provider.MilestoneIndexSigner(2)
+	signingFunc := signer.SigningFunc()
+
+	_, err = signingFunc(signer.PublicKeys(), essence)
+	require.ErrorIs(t, err, coordinator.ErrMissingPartialSignatures)
+
+	failing3 = false
+	signatures, err = signingFunc(signer.PublicKeys(), essence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	require.Equal(t, 1, signed2)
+	require.Equal(t, 2, signed3)
+
+	// the partial signatures are only kept for the same milestone essence
+	otherEssence := []byte("other milestone essence")
+	signatures, err = signingFunc(signer.PublicKeys(), otherEssence)
+	require.NoError(t, err)
+	for i, pubKey := range signer.PublicKeys() {
+		require.True(t, ed25519.Verify(pubKey[:], otherEssence, signatures[i][:]))
+	}
+	require.Equal(t, 2, signed2)
+	require.Equal(t, 3, signed3)
+
+	// keys without a signer service can't be used for signing
+	var missingPubKey iotago.MilestonePublicKey
+	copy(missingPubKey[:], privateKeys[2].Public().(ed25519.PublicKey))
+	_, err = signingFunc([]iotago.MilestonePublicKey{missingPubKey}, essence)
+	require.ErrorIs(t, err, coordinator.ErrNoSignerServiceForPublicKey)
+
+	// invalid signatures of a signer service are rejected
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], privateKeys[0].Public().(ed25519.PublicKey))
+	invalidProvider := coordinator.NewMultiPartyEd25519MilestoneSignerProvider([]*coordinator.SignerService{
+		{
+			Alias:      "invalid",
+			PublicKeys: []iotago.MilestonePublicKey{pubKey},
+			SigningFunc: func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+				return make([]iotago.MilestoneSignature, len(pubKeys)), nil
+			},
+		},
+	}, keyManager, 3)
+
+	signer = invalidProvider.MilestoneIndexSigner(3)
+	require.Len(t, signer.PublicKeys(), 1)
+	_, err = signer.SigningFunc()(signer.PublicKeys(), essence)
+	require.ErrorIs(t, err, coordinator.ErrMissingPartialSignatures)
+}
@@ -16,6 +16,19 @@ type State struct {
 	LatestMilestoneTime      time.Time
 }
 
+// copy returns a deep copy of the state or nil if the state is nil.
+func (cs *State) copy() *State {
+	if cs == nil {
+		return nil
+	}
+
+	return &State{
+		LatestMilestoneIndex:     cs.LatestMilestoneIndex,
+		LatestMilestoneMessageID: append(hornet.MessageID{}, cs.LatestMilestoneMessageID...),
+		LatestMilestoneTime:      cs.LatestMilestoneTime,
+	}
+}
+
 // jsoncoostate is the JSON representation of a coordinator state.
 type jsoncoostate struct {
 	LatestMilestoneIndex     uint32 `json:"latestMilestoneIndex"`
@@ -0,0 +1,235 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	fileStateBackendLockFileName  = "coordinator.lock"
+	fileStateBackendLeaseFileName = "coordinator.lease"
+	fileStateBackendStateFileName = "coordinator.state"
+)
+
+var (
+	// ErrLeaseNotHeld is returned if the coordinator instance does not hold the lease of the shared state backend.
+	ErrLeaseNotHeld = errors.New("coordinator instance does not hold the lease")
+	// ErrStateIndexDecreased is returned if a state with an older milestone index than the stored state should be stored.
+	ErrStateIndexDecreased = errors.New("milestone index of the state is older than the stored state")
+)
+
+// SharedState is the coordinator state that is shared between the coordinator instances.
+type SharedState struct {
+	// the latest confirmed state of the coordinator.
+	State *State `json:"state"`
+	// the milestone message that was created by the active instance, but is not part of the state yet.
+	// a coordinator instance that takes over sends this message again instead of issuing a new milestone with the same index.
+	PendingMilestoneMessage []byte `json:"pendingMilestoneMessage,omitempty"`
+}
+
+// StateBackend is a backend that is shared between an active and several standby coordinator instances.
+// Only the instance that holds the lease is allowed to issue milestones.
+type StateBackend interface {
+	// AcquireLease acquires or renews the lease for the coordinator instance.
+	// Returns false if another instance holds a lease that is not expired yet.
+	AcquireLease(instanceID string, duration time.Duration) (bool, error)
+	// ReleaseLease releases the lease if it is held by the coordinator instance.
+	ReleaseLease(instanceID string) error
+	// LoadState returns the stored state or nil if no state was stored yet.
+	LoadState() (*SharedState, error)
+	// StoreState stores the state if the coordinator instance holds the lease.
+	StoreState(instanceID string, state *SharedState) error
+}
+
+// fileLease is the lease stored by the FileStateBackend.
+type fileLease struct {
+	InstanceID string `json:"instanceID"`
+	Expiry     int64  `json:"expiry"`
+}
+
+// FileStateBackend is a StateBackend that stores the lease and the state in a directory.
+// The directory can be shared between hosts via a network file system that supports file locks (e.g. NFSv4),
+// or it is served to the coordinator instances by a StateServer.
+// The lease expiry is based on the local clocks, so the clocks of the hosts must be synchronized.
+type FileStateBackend struct {
+	lockFilePath  string
+	leaseFilePath string
+	stateFilePath string
+}
+
+// NewFileStateBackend creates a new FileStateBackend that uses the given directory.
+func NewFileStateBackend(directory string) (*FileStateBackend, error) {
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create shared state directory (%s): %w", directory, err)
+	}
+
+	return &FileStateBackend{
+		lockFilePath:  filepath.Join(directory, fileStateBackendLockFileName),
+		leaseFilePath: filepath.Join(directory, fileStateBackendLeaseFileName),
+		stateFilePath: filepath.Join(directory, fileStateBackendStateFileName),
+	}, nil
+}
+
+// withLock executes the function while holding the exclusive lock of the lock file.
+// The lock file is never removed, the lock is released by the operating system if the instance crashes.
+func (b *FileStateBackend) withLock(f func() error) error {
+
+	lockFile, err := os.OpenFile(b.lockFilePath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open lock file (%s): %w", b.lockFilePath, err)
+	}
+	defer func() { _ = lockFile.Close() }()
+
+	if err := lockFileExclusive(lockFile); err != nil {
+		return fmt.Errorf("unable to lock file (%s): %w", b.lockFilePath, err)
+	}
+	defer func() { _ = unlockFile(lockFile) }()
+
+	return f()
+}
+
+// readLease returns the stored lease or nil if no lease exists.
+func (b *FileStateBackend) readLease() (*fileLease, error) {
+
+	if _, err := os.Stat(b.leaseFilePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	lease := &fileLease{}
+	if err := utils.ReadJSONFromFile(b.leaseFilePath, lease); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// leaseHeld checks whether the coordinator instance holds a lease that is not expired.
+func (b *FileStateBackend) leaseHeld(instanceID string) (bool, error) {
+
+	lease, err := b.readLease()
+	if err != nil {
+		return false, err
+	}
+
+	return lease != nil && lease.InstanceID == instanceID && time.Now().UnixNano() < lease.Expiry, nil
+}
+
+// AcquireLease acquires or renews the lease for the coordinator instance.
+// Returns false if another instance holds a lease that is not expired yet.
+func (b *FileStateBackend) AcquireLease(instanceID string, duration time.Duration) (bool, error) {
+
+	acquired := false
+	if err := b.withLock(func() error {
+		lease, err := b.readLease()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if lease != nil && lease.InstanceID != instanceID && now.UnixNano() < lease.Expiry {
+			// another instance holds the lease
+			return nil
+		}
+
+		if err := writeJSONToFileAtomic(b.leaseFilePath, &fileLease{InstanceID: instanceID, Expiry: now.Add(duration).UnixNano()}); err != nil {
+			return fmt.Errorf("unable to write lease file: %w", err)
+		}
+
+		acquired = true
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// ReleaseLease releases the lease if it is held by the coordinator instance.
+func (b *FileStateBackend) ReleaseLease(instanceID string) error {
+
+	return b.withLock(func() error {
+		lease, err := b.readLease()
+		if err != nil {
+			return err
+		}
+
+		if lease == nil || lease.InstanceID != instanceID {
+			return nil
+		}
+
+		if err := os.Remove(b.leaseFilePath); err != nil {
+			return fmt.Errorf("unable to remove lease file: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// LoadState returns the stored state or nil if no state was stored yet.
+func (b *FileStateBackend) LoadState() (*SharedState, error) {
+
+	var state *SharedState
+	if err := b.withLock(func() error {
+		if _, err := os.Stat(b.stateFilePath); os.IsNotExist(err) {
+			return nil
+		}
+
+		state = &SharedState{}
+		return utils.ReadJSONFromFile(b.stateFilePath, state)
+	}); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// StoreState stores the state if the coordinator instance holds the lease.
+func (b *FileStateBackend) StoreState(instanceID string, state *SharedState) error {
+
+	return b.withLock(func() error {
+		held, err := b.leaseHeld(instanceID)
+		if err != nil {
+			return err
+		}
+
+		if !held {
+			return ErrLeaseNotHeld
+		}
+
+		if _, err := os.Stat(b.stateFilePath); err == nil {
+			storedState := &SharedState{}
+			if err := utils.ReadJSONFromFile(b.stateFilePath, storedState); err != nil {
+				return err
+			}
+
+			if storedState.State != nil && state.State.LatestMilestoneIndex < storedState.State.LatestMilestoneIndex {
+				return fmt.Errorf("%w: %d < %d", ErrStateIndexDecreased, state.State.LatestMilestoneIndex, storedState.State.LatestMilestoneIndex)
+			}
+		}
+
+		if err := writeJSONToFileAtomic(b.stateFilePath, state); err != nil {
+			return fmt.Errorf("unable to write state file: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// writeJSONToFileAtomic writes the JSON data to a temporary file and renames it afterwards,
+// so readers never see a partially written file.
+func writeJSONToFileAtomic(filename string, data interface{}) error {
+
+	tmpFilename := filename + ".tmp"
+	if err := utils.WriteJSONToFile(tmpFilename, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilename, filename)
+}
@@ -0,0 +1,279 @@
+package coordinator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// StateServerRouteLeaseAcquire is the route of a state server to acquire or renew the lease.
+	// POST the instance ID and the lease duration, returns whether the lease was acquired.
+	StateServerRouteLeaseAcquire = "/lease/acquire"
+	// StateServerRouteLeaseRelease is the route of a state server to release the lease.
+	// POST the instance ID.
+	StateServerRouteLeaseRelease = "/lease/release"
+	// StateServerRouteState is the route of a state server to load (GET) or store (POST) the state.
+	StateServerRouteState = "/state"
+
+	// the maximum size of a request body, the pending milestone message is base64 encoded.
+	maxStateServerRequestSize = 2*iotago.MessageBinSerializedMaxSize + 1024
+)
+
+// StateServerLeaseRequest is the request to acquire or release the lease on a state server.
+type StateServerLeaseRequest struct {
+	// The unique ID of the coordinator instance.
+	InstanceID string `json:"instanceID"`
+	// The duration of the lease in milliseconds.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// StateServerLeaseResponse is the response of a state server to a lease request.
+type StateServerLeaseResponse struct {
+	// Whether the lease was acquired.
+	Acquired bool `json:"acquired"`
+}
+
+// StateServerStoreRequest is the request to store the state on a state server.
+type StateServerStoreRequest struct {
+	// The unique ID of the coordinator instance.
+	InstanceID string `json:"instanceID"`
+	// The state that should be stored.
+	State *SharedState `json:"state"`
+}
+
+// StateServerLoadResponse is the response of a state server with the stored state.
+type StateServerLoadResponse struct {
+	// The stored state, nil if no state was stored yet.
+	State *SharedState `json:"state"`
+}
+
+// HTTPStateBackend is a StateBackend that is served by a StateServer.
+// The lease expiry is based on the clock of the state server.
+type HTTPStateBackend struct {
+	url        string
+	authToken  string
+	httpClient *http.Client
+}
+
+// NewHTTPStateBackend creates a new HTTPStateBackend for the given base URL of a state server.
+// If the auth token is not empty, it is sent as a bearer token with every request.
+func NewHTTPStateBackend(url string, authToken string, timeout time.Duration) *HTTPStateBackend {
+	return &HTTPStateBackend{
+		url:        strings.TrimSuffix(url, "/"),
+		authToken:  authToken,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// do sends a request to the state server and decodes the JSON response.
+func (b *HTTPStateBackend) do(method string, route string, reqObj interface{}, resObj interface{}) error {
+
+	var reqData []byte
+	if reqObj != nil {
+		var err error
+		if reqData, err = json.Marshal(reqObj); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, b.url+route, bytes.NewReader(reqData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if b.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+b.authToken)
+	}
+
+	res, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusConflict:
+		return ErrLeaseNotHeld
+	case http.StatusPreconditionFailed:
+		return fmt.Errorf("%w: %s", ErrStateIndexDecreased, strings.TrimSpace(string(resData)))
+	default:
+		return fmt.Errorf("unexpected status code %d: %s", res.StatusCode, strings.TrimSpace(string(resData)))
+	}
+
+	if resObj == nil {
+		return nil
+	}
+
+	return json.Unmarshal(resData, resObj)
+}
+
+// AcquireLease acquires or renews the lease for the coordinator instance.
+// Returns false if another instance holds a lease that is not expired yet.
+func (b *HTTPStateBackend) AcquireLease(instanceID string, duration time.Duration) (bool, error) {
+
+	res := &StateServerLeaseResponse{}
+	if err := b.do(http.MethodPost, StateServerRouteLeaseAcquire, &StateServerLeaseRequest{InstanceID: instanceID, Duration: duration.Milliseconds()}, res); err != nil {
+		return false, err
+	}
+
+	return res.Acquired, nil
+}
+
+// ReleaseLease releases the lease if it is held by the coordinator instance.
+func (b *HTTPStateBackend) ReleaseLease(instanceID string) error {
+	return b.do(http.MethodPost, StateServerRouteLeaseRelease, &StateServerLeaseRequest{InstanceID: instanceID}, nil)
+}
+
+// LoadState returns the stored state or nil if no state was stored yet.
+func (b *HTTPStateBackend) LoadState() (*SharedState, error) {
+
+	res := &StateServerLoadResponse{}
+	if err := b.do(http.MethodGet, StateServerRouteState, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res.State, nil
+}
+
+// StoreState stores the state if the coordinator instance holds the lease.
+func (b *HTTPStateBackend) StoreState(instanceID string, state *SharedState) error {
+	return b.do(http.MethodPost, StateServerRouteState, &StateServerStoreRequest{InstanceID: instanceID, State: state}, nil)
+}
+
+// StateServer serves a StateBackend to the coordinator instances over HTTP.
+type StateServer struct {
+	backend   StateBackend
+	authToken string
+}
+
+// NewStateServer creates a new state server for the given backend.
+// If the auth token is not empty, the requests have to contain it as a bearer token.
+func NewStateServer(backend StateBackend, authToken string) *StateServer {
+	return &StateServer{
+		backend:   backend,
+		authToken: authToken,
+	}
+}
+
+// Handler returns the HTTP handler of the state server.
+func (s *StateServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(StateServerRouteLeaseAcquire, utils.BearerTokenHandler(s.authToken, s.handleLeaseAcquire))
+	mux.HandleFunc(StateServerRouteLeaseRelease, utils.BearerTokenHandler(s.authToken, s.handleLeaseRelease))
+	mux.HandleFunc(StateServerRouteState, utils.BearerTokenHandler(s.authToken, s.handleState))
+	return mux
+}
+
+// decodeLeaseRequest decodes and validates a lease request.
+func decodeLeaseRequest(w http.ResponseWriter, r *http.Request) (*StateServerLeaseRequest, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	req := &StateServerLeaseRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxStateServerRequestSize)).Decode(req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.InstanceID == "" {
+		http.Error(w, "invalid instance ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return req, true
+}
+
+func (s *StateServer) handleLeaseAcquire(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLeaseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Duration <= 0 {
+		http.Error(w, "invalid lease duration", http.StatusBadRequest)
+		return
+	}
+
+	acquired, err := s.backend.AcquireLease(req.InstanceID, time.Duration(req.Duration)*time.Millisecond)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.WriteJSONResponse(w, &StateServerLeaseResponse{Acquired: acquired})
+}
+
+func (s *StateServer) handleLeaseRelease(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLeaseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.backend.ReleaseLease(req.InstanceID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.WriteJSONResponse(w, struct{}{})
+}
+
+func (s *StateServer) handleState(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		state, err := s.backend.LoadState()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		utils.WriteJSONResponse(w, &StateServerLoadResponse{State: state})
+
+	case http.MethodPost:
+		req := &StateServerStoreRequest{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxStateServerRequestSize)).Decode(req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.InstanceID == "" || req.State == nil || req.State.State == nil {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.backend.StoreState(req.InstanceID, req.State); err != nil {
+			switch {
+			case errors.Is(err, ErrLeaseNotHeld):
+				http.Error(w, err.Error(), http.StatusConflict)
+			case errors.Is(err, ErrStateIndexDecreased):
+				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		utils.WriteJSONResponse(w, struct{}{})
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
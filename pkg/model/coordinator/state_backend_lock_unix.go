@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package coordinator
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFileExclusive blocks until the exclusive lock of the file is acquired.
+// The lock is released by the operating system if the process dies.
+func lockFileExclusive(file *os.File) error {
+	for {
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the lock of the file.
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,144 @@
+package coordinator_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+func sharedState(index milestone.Index, pendingMilestoneMessage []byte) *coordinator.SharedState {
+	return &coordinator.SharedState{
+		State: &coordinator.State{
+			LatestMilestoneIndex:     index,
+			LatestMilestoneMessageID: hornet.NullMessageID(),
+			LatestMilestoneTime:      time.Unix(int64(index), 0),
+		},
+		PendingMilestoneMessage: pendingMilestoneMessage,
+	}
+}
+
+// testStateBackend checks the lease and the state handling of a StateBackend.
+func testStateBackend(t *testing.T, backend coordinator.StateBackend) {
+
+	state, err := backend.LoadState()
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	// the state can only be stored by the lease holder
+	require.ErrorIs(t, backend.StoreState("coo1", sharedState(1, nil)), coordinator.ErrLeaseNotHeld)
+
+	acquired, err := backend.AcquireLease("coo1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// the standby instance can't acquire the lease while it is held by the active instance
+	acquired, err = backend.AcquireLease("coo2", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	// the active instance can renew its lease
+	acquired, err = backend.AcquireLease("coo1", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	require.NoError(t, backend.StoreState("coo1", sharedState(5, []byte{1, 2, 3})))
+	require.ErrorIs(t, backend.StoreState("coo2", sharedState(6, nil)), coordinator.ErrLeaseNotHeld)
+
+	state, err = backend.LoadState()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, state.State.LatestMilestoneIndex)
+	require.Equal(t, []byte{1, 2, 3}, state.PendingMilestoneMessage)
+
+	// the standby instance takes over after the lease expired
+	time.Sleep(100 * time.Millisecond)
+	acquired, err = backend.AcquireLease("coo2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// the previously active instance is fenced off
+	require.ErrorIs(t, backend.StoreState("coo1", sharedState(6, nil)), coordinator.ErrLeaseNotHeld)
+
+	// the milestone index of the state never decreases
+	require.ErrorIs(t, backend.StoreState("coo2", sharedState(4, nil)), coordinator.ErrStateIndexDecreased)
+	require.NoError(t, backend.StoreState("coo2", sharedState(6, nil)))
+
+	state, err = backend.LoadState()
+	require.NoError(t, err)
+	require.EqualValues(t, 6, state.State.LatestMilestoneIndex)
+	require.Empty(t, state.PendingMilestoneMessage)
+
+	// releasing the lease of another instance has no effect
+	require.NoError(t, backend.ReleaseLease("coo1"))
+	acquired, err = backend.AcquireLease("coo1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	require.NoError(t, backend.ReleaseLease("coo2"))
+	acquired, err = backend.AcquireLease("coo1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+}
+
+func TestFileStateBackend(t *testing.T) {
+
+	backend, err := coordinator.NewFileStateBackend(t.TempDir())
+	require.NoError(t, err)
+
+	testStateBackend(t, backend)
+}
+
+func TestFileStateBackendConcurrentLease(t *testing.T) {
+
+	directory := t.TempDir()
+
+	// every instance uses its own backend, like coordinator instances on different hosts
+	const instanceCount = 10
+	var acquiredCount int
+	var acquiredLock sync.Mutex
+
+	var wg sync.WaitGroup
+	for i := 0; i < instanceCount; i++ {
+		backend, err := coordinator.NewFileStateBackend(directory)
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(instanceID string) {
+			defer wg.Done()
+
+			acquired, err := backend.AcquireLease(instanceID, time.Minute)
+			require.NoError(t, err)
+
+			if acquired {
+				acquiredLock.Lock()
+				acquiredCount++
+				acquiredLock.Unlock()
+			}
+		}(fmt.Sprintf("coo%d", i))
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, acquiredCount)
+}
+
+func TestHTTPStateBackend(t *testing.T) {
+
+	fileBackend, err := coordinator.NewFileStateBackend(t.TempDir())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(coordinator.NewStateServer(fileBackend, "secret").Handler())
+	defer server.Close()
+
+	// requests without the auth token are rejected
+	_, err = coordinator.NewHTTPStateBackend(server.URL, "", time.Second).LoadState()
+	require.Error(t, err)
+
+	testStateBackend(t, coordinator.NewHTTPStateBackend(server.URL, "secret", time.Second))
+}
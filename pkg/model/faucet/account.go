@@ -0,0 +1,244 @@
+package faucet
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+// FundingAccount is an address the faucet spends funds from.
+type FundingAccount struct {
+	// the address of the funding account.
+	Address *iotago.Ed25519Address
+	// used to sign the transactions of the funding account.
+	Signer iotago.AddressSigner
+}
+
+// account holds the state of a funding account.
+// the state is only accessed by the loop of the account.
+type account struct {
+	*FundingAccount
+
+	// the message ID of the last sent faucet message of the account.
+	lastMessageID hornet.MessageID
+	// the remainder output of the last sent faucet message of the account.
+	lastRemainderOutput *utxo.Output
+}
+
+// FaucetAccountInfo holds the balance and the output fragmentation of a funding account.
+type FaucetAccountInfo struct {
+	// The bech32 address of the funding account.
+	Address string `json:"address"`
+	// The balance of the funding account.
+	Balance uint64 `json:"balance"`
+	// The amount of unspent outputs of the funding account.
+	UnspentOutputs int `json:"unspentOutputs"`
+}
+
+// AccountsInfo returns the balance and the amount of unspent outputs of all funding accounts.
+func (f *Faucet) AccountsInfo() ([]*FaucetAccountInfo, error) {
+
+	infos := make([]*FaucetAccountInfo, len(f.accounts))
+	for i, acc := range f.accounts {
+		balance, count, err := f.utxoManager.ComputeBalance(utxo.FilterAddress(acc.Address), utxo.ReadLockLedger(false), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
+		if err != nil {
+			return nil, fmt.Errorf("reading unspent outputs failed: %s, error: %w", acc.Address.Bech32(f.opts.hrpNetworkPrefix), err)
+		}
+
+		infos[i] = &FaucetAccountInfo{
+			Address:        acc.Address.Bech32(f.opts.hrpNetworkPrefix),
+			Balance:        balance,
+			UnspentOutputs: count,
+		}
+	}
+
+	return infos, nil
+}
+
+// lastRemainderConfirmed checks if the remainder output of the last sent faucet message of the account is confirmed.
+func (f *Faucet) lastRemainderConfirmed(acc *account) bool {
+	if acc.lastRemainderOutput == nil {
+		return true
+	}
+
+	if _, err := f.utxoManager.ReadOutputByOutputIDWithoutLocking(acc.lastRemainderOutput.OutputID()); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// consolidateOutputs sweeps the unspent outputs of the account into a single output
+// if the amount of unspent outputs reached the consolidation threshold.
+// returns false if no consolidation was necessary.
+func (f *Faucet) consolidateOutputs(acc *account, shutdownSignal <-chan struct{}) (bool, error) {
+
+	if f.opts.consolidationThreshold == 0 {
+		return false, nil
+	}
+
+	_, count, err := f.utxoManager.ComputeBalance(utxo.FilterAddress(acc.Address), utxo.ReadLockLedger(false), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
+	if err != nil {
+		return false, fmt.Errorf("reading unspent outputs failed: %s, error: %w", acc.Address.Bech32(f.opts.hrpNetworkPrefix), err)
+	}
+
+	if count < f.opts.consolidationThreshold {
+		return false, nil
+	}
+
+	unspentOutputs, err := f.utxoManager.UnspentOutputs(utxo.FilterAddress(acc.Address), utxo.ReadLockLedger(false), utxo.MaxResultCount(iotago.MaxInputsCount), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
+	if err != nil {
+		return false, fmt.Errorf("reading unspent outputs failed: %s, error: %w", acc.Address.Bech32(f.opts.hrpNetworkPrefix), err)
+	}
+
+	remainderOutput, err := f.sendFaucetMessage(acc, unspentOutputs, nil, shutdownSignal)
+	if err != nil {
+		return false, fmt.Errorf("consolidation of %d outputs failed: %w", len(unspentOutputs), err)
+	}
+
+	acc.lastRemainderOutput = remainderOutput
+	f.Events.ConsolidatedOutputs.Trigger(len(unspentOutputs))
+
+	return true, nil
+}
+
+// runAccountLoop collects unspent outputs on the funding account and batches the requests from the queue.
+func (f *Faucet) runAccountLoop(acc *account, shutdownSignal <-chan struct{}) error {
+
+	for {
+		select {
+		case <-shutdownSignal:
+			// faucet was stopped
+			return nil
+
+		default:
+
+			// only collect unspent outputs if the lastRemainderOutput is not pending
+			collectUnspentOutputs := f.lastRemainderConfirmed(acc)
+
+			if collectUnspentOutputs {
+				consolidated, err := f.consolidateOutputs(acc, shutdownSignal)
+				if err != nil {
+					if common.IsCriticalError(err) != nil {
+						// error is a critical error
+						// => stop the faucet
+						return err
+					}
+
+					f.logSoftError(err)
+					continue
+				}
+
+				if consolidated {
+					// wait until the consolidation is confirmed before the funds are used again
+					continue
+				}
+			}
+
+			var err error
+			unspentOutputs := []*utxo.Output{}
+			if collectUnspentOutputs {
+				unspentOutputs, err = f.utxoManager.UnspentOutputs(utxo.FilterAddress(acc.Address), utxo.ReadLockLedger(false), utxo.MaxResultCount(f.opts.maxOutputCount-2), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
+				if err != nil {
+					return fmt.Errorf("reading unspent outputs failed: %s, error: %w", acc.Address.Bech32(f.opts.hrpNetworkPrefix), err)
+				}
+			} else {
+				unspentOutputs = append(unspentOutputs, acc.lastRemainderOutput)
+			}
+
+			var amount uint64 = 0
+			found := false
+			for _, unspentOutput := range unspentOutputs {
+				amount += unspentOutput.Amount()
+				if acc.lastRemainderOutput != nil && bytes.Equal(unspentOutput.OutputID()[:], acc.lastRemainderOutput.OutputID()[:]) {
+					found = true
+				}
+			}
+
+			if acc.lastRemainderOutput != nil && !found {
+				unspentOutputs = append(unspentOutputs, acc.lastRemainderOutput)
+				amount += acc.lastRemainderOutput.Amount()
+			}
+
+			// the balances of the funding accounts may have decreased
+			if err := f.rejectUnpayableParkedRequests(); err != nil {
+				f.logSoftError(err)
+			}
+
+			// the amounts can be changed at runtime
+			faucetAmount := f.Amounts().Amount
+
+			collectedRequestsCounter := len(unspentOutputs)
+			batchWriterTimeoutChan := time.After(f.opts.batchTimeout)
+			batchWriterTimeoutReached := false
+			batchedRequests := []*queueItem{}
+
+		CollectValues:
+			for collectedRequestsCounter < f.opts.maxOutputCount-1 && amount > faucetAmount {
+				// parked requests that fit into the remaining funds are paid first
+				if request := f.takeParkedRequest(amount); request != nil {
+					batchedRequests = append(batchedRequests, request)
+					collectedRequestsCounter++
+					amount -= request.Amount
+					continue
+				}
+
+				select {
+				case <-shutdownSignal:
+					// faucet was stopped
+					return nil
+
+				case <-batchWriterTimeoutChan:
+					// timeout was reached => stop collecting requests
+					batchWriterTimeoutReached = true
+					break CollectValues
+
+				case request := <-f.queue:
+					if request.Amount > amount {
+						// refunds may exceed the remaining funds, they are only paid in full
+						f.parkRequest(request)
+						continue
+					}
+
+					batchedRequests = append(batchedRequests, request)
+					collectedRequestsCounter++
+					amount -= request.Amount
+				}
+			}
+
+			f.clearRequests(batchedRequests)
+
+			if len(unspentOutputs) < 2 && len(batchedRequests) == 0 {
+				// no need to sweep or send funds
+				if !batchWriterTimeoutReached {
+					// the account has not enough funds, wait before checking again
+					select {
+					case <-shutdownSignal:
+						return nil
+					case <-batchWriterTimeoutChan:
+					}
+				}
+				continue
+			}
+
+			remainderOutput, err := f.sendFaucetMessage(acc, unspentOutputs, batchedRequests, shutdownSignal)
+			if err != nil {
+				if common.IsCriticalError(err) != nil {
+					// error is a critical error
+					// => stop the faucet
+					return err
+				}
+
+				f.logSoftError(err)
+				continue
+			}
+
+			acc.lastRemainderOutput = remainderOutput
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package faucet
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func TestFaucetParkedRequests(t *testing.T) {
+
+	utxoManager := utxo.New(mapdb.NewMapDB())
+
+	accountAddress := &iotago.Ed25519Address{}
+	rand.Read(accountAddress[:])
+
+	outputID := &iotago.UTXOInputID{}
+	rand.Read(outputID[:])
+	messageID := hornet.MessageID(make([]byte, iotago.MessageIDLength))
+	rand.Read(messageID)
+	require.NoError(t, utxoManager.AddUnspentOutput(utxo.CreateOutput(outputID, messageID, iotago.OutputSigLockedSingleOutput, accountAddress, 5000000)))
+
+	f, err := New(nil, mapdb.NewMapDB(), nil, 0, 15, utxoManager, []*FundingAccount{{Address: accountAddress}}, nil, nil, nil)
+	require.NoError(t, err)
+
+	newRequest := func(amount uint64) *queueItem {
+		address := &iotago.Ed25519Address{}
+		rand.Read(address[:])
+		request := &queueItem{
+			Bech32:         address.Bech32(iotago.PrefixTestnet),
+			Amount:         amount,
+			Ed25519Address: address,
+			EnqueuedAt:     time.Now(),
+		}
+		f.queueMap[request.Bech32] = request
+		require.NoError(t, f.storeQueueItem(request))
+		return request
+	}
+
+	// requests that exceed the balance of every funding account are rejected
+	request := newRequest(6000000)
+	f.parkRequest(request)
+	require.Nil(t, f.takeParkedRequest(10000000))
+	require.Equal(t, 0, f.PendingRequests())
+
+	// requests that are covered by the balance of a funding account are kept
+	request1 := newRequest(4000000)
+	request2 := newRequest(2000000)
+	f.parkRequest(request1)
+	f.parkRequest(request2)
+	require.Equal(t, 2, f.PendingRequests())
+
+	// the oldest parked request that fits into the remaining funds is taken
+	require.Nil(t, f.takeParkedRequest(1000000))
+	require.Equal(t, request2, f.takeParkedRequest(3000000))
+	require.Nil(t, f.takeParkedRequest(3000000))
+	require.NoError(t, f.rejectUnpayableParkedRequests())
+	require.Equal(t, request1, f.takeParkedRequest(5000000))
+
+	// parked requests are rejected if the balance of the funding accounts decreased
+	f.parkRequest(request1)
+	spentOutput, err := utxoManager.ReadOutputByOutputIDWithoutLocking(outputID)
+	require.NoError(t, err)
+	remainderOutputID := &iotago.UTXOInputID{}
+	rand.Read(remainderOutputID[:])
+	require.NoError(t, utxoManager.ApplyConfirmation(1,
+		utxo.Outputs{utxo.CreateOutput(remainderOutputID, messageID, iotago.OutputSigLockedSingleOutput, accountAddress, 3000000)},
+		utxo.Spents{utxo.NewSpent(spentOutput, &iotago.TransactionID{}, 1)},
+		nil, nil))
+
+	require.NoError(t, f.rejectUnpayableParkedRequests())
+	require.Nil(t, f.takeParkedRequest(5000000))
+
+	// only the taken request2 is still pending until it is paid
+	require.Equal(t, 1, f.PendingRequests())
+}
@@ -0,0 +1,316 @@
+package faucet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/iotaledger/hive.go/kvstore"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// prefix, issue time (int64), output ID.
+	historyKeyLength = 1 + iotago.UInt64ByteSize + iotago.TransactionIDLength + iotago.UInt16ByteSize
+	// ed25519 address, amount (uint64), message ID.
+	historyValueLength = iotago.Ed25519AddressBytesLength + iotago.UInt64ByteSize + iotago.MessageIDLength
+)
+
+// FaucetHistoryEntry is a payout of the faucet.
+type FaucetHistoryEntry struct {
+	// The bech32 address that received the funds.
+	Address string `json:"address"`
+	// The amount of funds that were sent.
+	Amount uint64 `json:"amount"`
+	// The ID of the message that contains the payout.
+	MessageID string `json:"messageId"`
+	// The index of the milestone that referenced the message (0 if not referenced yet).
+	ReferencedByMilestoneIndex milestone.Index `json:"referencedByMilestoneIndex"`
+	// The unix time the payout was issued.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// FaucetAmountsResponse defines the amounts of funds the requesters receive.
+type FaucetAmountsResponse struct {
+	// The amount of funds the requester receives.
+	Amount uint64 `json:"amount"`
+	// The amount of funds the requester receives if the target address has more funds than the faucet amount and less than maximum.
+	SmallAmount uint64 `json:"smallAmount"`
+}
+
+// Amounts returns the amounts of funds the requesters receive.
+func (f *Faucet) Amounts() *FaucetAmountsResponse {
+	f.Lock()
+	defer f.Unlock()
+
+	return &FaucetAmountsResponse{
+		Amount:      f.opts.amount,
+		SmallAmount: f.opts.smallAmount,
+	}
+}
+
+// SetAmounts changes the amounts of funds the requesters receive.
+// Requests that are already in the queue keep their amount.
+func (f *Faucet) SetAmounts(amount uint64, smallAmount uint64) error {
+
+	if amount == 0 || smallAmount == 0 {
+		return errors.WithMessage(restapi.ErrInvalidParameter, "Amounts must be greater than zero.")
+	}
+
+	if smallAmount > amount {
+		return errors.WithMessage(restapi.ErrInvalidParameter, "Small amount must not be greater than the amount.")
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if amount > f.opts.maxAddressBalance {
+		return errors.WithMessagef(restapi.ErrInvalidParameter, "Amount must not be greater than the maximum address balance (%d).", f.opts.maxAddressBalance)
+	}
+
+	f.opts.amount = amount
+	f.opts.smallAmount = smallAmount
+
+	return nil
+}
+
+// EnqueueRefund adds a payout with the given amount to the queue.
+// The challenges, the cooldown windows, the blacklist and the balance of the address are not checked.
+// The amount has to be covered by the balance of a single funding account.
+func (f *Faucet) EnqueueRefund(bech32 string, ed25519Addr *iotago.Ed25519Address, amount uint64) (*FaucetEnqueueResponse, error) {
+
+	f.Lock()
+	defer f.Unlock()
+
+	if amount == 0 || amount > f.opts.maxAddressBalance {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Amount must be greater than zero and not greater than the maximum address balance (%d).", f.opts.maxAddressBalance)
+	}
+
+	if _, exists := f.queueMap[bech32]; exists {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "Address is already in the queue.")
+	}
+
+	maxAccountBalance, err := f.maxConfirmedAccountBalance()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "Reading balance failed: %s", err)
+	}
+
+	if amount > maxAccountBalance {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Amount exceeds the available funds of the funding accounts (%d).", maxAccountBalance)
+	}
+
+	request := &queueItem{
+		Bech32:         bech32,
+		Amount:         amount,
+		Ed25519Address: ed25519Addr,
+		EnqueuedAt:     time.Now(),
+	}
+
+	select {
+	case f.queue <- request:
+		f.queueMap[bech32] = request
+
+		if err := f.storeQueueItem(request); err != nil {
+			// the request was already enqueued, the faucet still works without the persistence
+			f.logSoftError(err)
+		} else if err := f.store.Flush(); err != nil {
+			f.logSoftError(fmt.Errorf("failed to flush pending request: %w", err))
+		}
+
+		return &FaucetEnqueueResponse{
+			Address:         bech32,
+			WaitingRequests: len(f.queueMap),
+		}, nil
+
+	default:
+		// queue is full
+		return nil, errors.WithMessage(echo.ErrInternalServerError, "Faucet queue is full. Please try again later!")
+	}
+}
+
+// isBlacklisted checks if the address is blacklisted.
+func (f *Faucet) isBlacklisted(bech32 string) (bool, error) {
+
+	blacklisted, err := f.store.Has(storeKey(storeKeyPrefixBlacklist, bech32))
+	if err != nil {
+		return false, fmt.Errorf("failed to read blacklist: %w", err)
+	}
+
+	return blacklisted, nil
+}
+
+// AddToBlacklist blacklists the address, further requests of the address are rejected.
+func (f *Faucet) AddToBlacklist(bech32 string) error {
+
+	if err := f.store.Set(storeKey(storeKeyPrefixBlacklist, bech32), []byte{}); err != nil {
+		return fmt.Errorf("failed to store blacklisted address: %w", err)
+	}
+
+	return f.store.Flush()
+}
+
+// RemoveFromBlacklist removes the address from the blacklist.
+func (f *Faucet) RemoveFromBlacklist(bech32 string) error {
+
+	if err := f.store.Delete(storeKey(storeKeyPrefixBlacklist, bech32)); err != nil {
+		return fmt.Errorf("failed to delete blacklisted address: %w", err)
+	}
+
+	return f.store.Flush()
+}
+
+// Blacklist returns all blacklisted addresses.
+func (f *Faucet) Blacklist() ([]string, error) {
+
+	blacklist := []string{}
+	if err := f.store.IterateKeys([]byte{storeKeyPrefixBlacklist}, func(key kvstore.Key) bool {
+		blacklist = append(blacklist, string(key[1:]))
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("failed to iterate blacklist: %w", err)
+	}
+
+	sort.Strings(blacklist)
+
+	return blacklist, nil
+}
+
+// storeHistory stores all payouts of the given faucet transaction.
+func (f *Faucet) storeHistory(acc *account, txPayload *iotago.Transaction, messageID hornet.MessageID, issuedAt time.Time) error {
+
+	transactionID, err := txPayload.ID()
+	if err != nil {
+		return fmt.Errorf("can't compute the transaction ID, error: %w", err)
+	}
+
+	for outputIndex, output := range txPayload.Essence.(*iotago.TransactionEssence).Outputs {
+		sigLock := output.(*iotago.SigLockedSingleOutput)
+		ed25519Addr := sigLock.Address.(*iotago.Ed25519Address)
+
+		if bytes.Equal(ed25519Addr[:], acc.Address[:]) {
+			// skip the remainder output
+			continue
+		}
+
+		key := make([]byte, historyKeyLength)
+		key[0] = storeKeyPrefixHistory
+		binary.BigEndian.PutUint64(key[1:], uint64(issuedAt.UnixNano()))
+		copy(key[1+iotago.UInt64ByteSize:], transactionID[:])
+		binary.LittleEndian.PutUint16(key[1+iotago.UInt64ByteSize+iotago.TransactionIDLength:], uint16(outputIndex))
+
+		value := make([]byte, historyValueLength)
+		copy(value, ed25519Addr[:])
+		binary.LittleEndian.PutUint64(value[iotago.Ed25519AddressBytesLength:], sigLock.Amount)
+		copy(value[iotago.Ed25519AddressBytesLength+iotago.UInt64ByteSize:], messageID)
+
+		if err := f.store.Set(key, value); err != nil {
+			return fmt.Errorf("failed to store payout: %w", err)
+		}
+	}
+
+	return f.store.Flush()
+}
+
+// historyEntry parses a stored payout and resolves the message that included the payout.
+func (f *Faucet) historyEntry(key kvstore.Key, value kvstore.Value) (*FaucetHistoryEntry, error) {
+
+	if len(key) != historyKeyLength || len(value) != historyValueLength {
+		return nil, fmt.Errorf("invalid payout length: %d, %d", len(key), len(value))
+	}
+
+	address := &iotago.Ed25519Address{}
+	copy(address[:], value[:iotago.Ed25519AddressBytesLength])
+
+	messageID := hornet.MessageIDFromSlice(value[iotago.Ed25519AddressBytesLength+iotago.UInt64ByteSize:])
+
+	// the faucet message may have been reattached, the output contains the message that was included in the ledger
+	outputID := &iotago.UTXOInputID{}
+	copy(outputID[:], key[1+iotago.UInt64ByteSize:])
+	if output, err := f.utxoManager.ReadOutputByOutputIDWithoutLocking(outputID); err == nil {
+		messageID = output.MessageID()
+	}
+
+	var referencedIndex milestone.Index
+	if f.storage != nil {
+		if cachedMsgMeta := f.storage.CachedMessageMetadataOrNil(messageID); cachedMsgMeta != nil { // meta +
+			_, referencedIndex = cachedMsgMeta.Metadata().ReferencedWithIndex()
+			cachedMsgMeta.Release(true) // meta -
+		}
+	}
+
+	return &FaucetHistoryEntry{
+		Address:                    address.Bech32(f.opts.hrpNetworkPrefix),
+		Amount:                     binary.LittleEndian.Uint64(value[iotago.Ed25519AddressBytesLength:]),
+		MessageID:                  messageID.ToHex(),
+		ReferencedByMilestoneIndex: referencedIndex,
+		Timestamp:                  time.Unix(0, int64(binary.BigEndian.Uint64(key[1:]))).Unix(),
+	}, nil
+}
+
+// History returns the latest payouts of the faucet, newest first.
+func (f *Faucet) History(maxResults int) ([]*FaucetHistoryEntry, error) {
+
+	type storedEntry struct {
+		key   kvstore.Key
+		value kvstore.Value
+	}
+
+	var storedEntries []*storedEntry
+	// the keys start with the issue time in big endian, so the newest payouts are iterated first
+	if err := f.store.Iterate([]byte{storeKeyPrefixHistory}, func(key kvstore.Key, value kvstore.Value) bool {
+		storedEntries = append(storedEntries, &storedEntry{
+			key:   append(kvstore.Key{}, key...),
+			value: append(kvstore.Value{}, value...),
+		})
+		return maxResults <= 0 || len(storedEntries) < maxResults
+	}, kvstore.IterDirectionBackward); err != nil {
+		return nil, fmt.Errorf("failed to iterate payouts: %w", err)
+	}
+
+	history := make([]*FaucetHistoryEntry, 0, len(storedEntries))
+	for _, entry := range storedEntries {
+		historyEntry, err := f.historyEntry(entry.key, entry.value)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, historyEntry)
+	}
+
+	return history, nil
+}
+
+// CleanupHistory removes all payouts that are older than the history retention from the store.
+func (f *Faucet) CleanupHistory() error {
+
+	if f.opts.historyRetention == 0 {
+		return nil
+	}
+
+	threshold := time.Now().Add(-f.opts.historyRetention)
+
+	var expiredKeys []kvstore.Key
+	if err := f.store.IterateKeys([]byte{storeKeyPrefixHistory}, func(key kvstore.Key) bool {
+		if len(key) != historyKeyLength || time.Unix(0, int64(binary.BigEndian.Uint64(key[1:]))).Before(threshold) {
+			expiredKeys = append(expiredKeys, key)
+		}
+		return true
+	}); err != nil {
+		return fmt.Errorf("failed to iterate payouts: %w", err)
+	}
+
+	for _, key := range expiredKeys {
+		if err := f.store.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete payout: %w", err)
+		}
+	}
+
+	return nil
+}
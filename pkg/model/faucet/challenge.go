@@ -0,0 +1,272 @@
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/pow"
+)
+
+const (
+	// the timeout for requests to the captcha verification endpoint.
+	captchaVerifyTimeout = 10 * time.Second
+	// the interval in which a new seed for the proof-of-work challenges is created.
+	// a seed stays valid for two intervals.
+	powChallengeSeedInterval = 10 * time.Minute
+	// the length of a seed for the proof-of-work challenges.
+	powChallengeSeedLength = 32
+)
+
+var (
+	// ErrChallengeFailed is returned if a requester did not solve the challenges of the faucet.
+	ErrChallengeFailed = errors.New("challenge failed")
+)
+
+// ChallengeSolution contains the solutions of the challenges a requester has to solve before funds are enqueued.
+type ChallengeSolution struct {
+	// the seed of the proof-of-work challenge.
+	Seed []byte
+	// the nonce of the proof-of-work challenge.
+	Nonce uint64
+	// the response token of the captcha challenge.
+	CaptchaToken string
+}
+
+// CaptchaVerifyFunc verifies the captcha response token of a requester.
+type CaptchaVerifyFunc func(ctx context.Context, token string, remoteIP string) error
+
+// PoWChallengeData returns the data that has to be mined by the requester to solve the proof-of-work challenge.
+// The seed is provided by the faucet, the nonce is appended to the data as uint64 in little endian before the score is computed.
+func PoWChallengeData(seed []byte, bech32 string) []byte {
+	data := make([]byte, 0, len(seed)+len(bech32))
+	data = append(data, seed...)
+	return append(data, bech32...)
+}
+
+// verifyPoWChallenge checks if the nonce results in the required proof-of-work score for the given seed and address.
+func verifyPoWChallenge(seed []byte, bech32 string, nonce uint64, minScore float64) error {
+
+	data := PoWChallengeData(seed, bech32)
+	powData := make([]byte, len(data)+iotago.UInt64ByteSize)
+	copy(powData, data)
+	binary.LittleEndian.PutUint64(powData[len(data):], nonce)
+
+	if score := pow.Score(powData); score < minScore {
+		return fmt.Errorf("%w: proof-of-work score too low: %0.2f < %0.2f", ErrChallengeFailed, score, minScore)
+	}
+
+	return nil
+}
+
+// powChallengeSeeds holds the random seeds the proof-of-work challenges are bound to,
+// so that a solution can't be replayed after the seed expired.
+// Every address can only solve one challenge per seed.
+type powChallengeSeeds struct {
+	sync.Mutex
+
+	current  []byte
+	previous []byte
+	// the time the current seed was created.
+	createdAt time.Time
+	// the addresses that solved a challenge with the current or the previous seed.
+	usedCurrent  map[string]struct{}
+	usedPrevious map[string]struct{}
+}
+
+// rotateWithoutLocking creates a new seed if the current one is older than the seed interval.
+func (s *powChallengeSeeds) rotateWithoutLocking(now time.Time) error {
+
+	if s.current != nil && now.Sub(s.createdAt) < powChallengeSeedInterval {
+		return nil
+	}
+
+	seed := make([]byte, powChallengeSeedLength)
+	if _, err := rand.Read(seed); err != nil {
+		return fmt.Errorf("unable to create proof-of-work challenge seed: %w", err)
+	}
+
+	s.previous, s.usedPrevious = s.current, s.usedCurrent
+	if now.Sub(s.createdAt) >= 2*powChallengeSeedInterval {
+		// the previous seed expired as well
+		s.previous, s.usedPrevious = nil, nil
+	}
+	s.current, s.usedCurrent = seed, make(map[string]struct{})
+	s.createdAt = now
+
+	return nil
+}
+
+// seed returns the current seed.
+func (s *powChallengeSeeds) seed() ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.rotateWithoutLocking(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return append([]byte{}, s.current...), nil
+}
+
+// usedAddressesWithoutLocking returns the used addresses of the given seed, or nil if the seed is unknown or expired.
+func (s *powChallengeSeeds) usedAddressesWithoutLocking(seed []byte) map[string]struct{} {
+	switch {
+	case len(seed) == 0:
+		return nil
+	case bytes.Equal(seed, s.current):
+		return s.usedCurrent
+	case bytes.Equal(seed, s.previous):
+		return s.usedPrevious
+	default:
+		return nil
+	}
+}
+
+// valid checks if the seed is known and was not used by the address yet.
+func (s *powChallengeSeeds) valid(seed []byte, bech32 string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.rotateWithoutLocking(time.Now()); err != nil {
+		return err
+	}
+
+	usedAddresses := s.usedAddressesWithoutLocking(seed)
+	if usedAddresses == nil {
+		return fmt.Errorf("%w: unknown or expired proof-of-work seed", ErrChallengeFailed)
+	}
+
+	if _, used := usedAddresses[bech32]; used {
+		return fmt.Errorf("%w: proof-of-work seed was already used", ErrChallengeFailed)
+	}
+
+	return nil
+}
+
+// use marks the seed as used by the address.
+// Returns an error if the seed is unknown or was already used by the address.
+func (s *powChallengeSeeds) use(seed []byte, bech32 string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	usedAddresses := s.usedAddressesWithoutLocking(seed)
+	if usedAddresses == nil {
+		return fmt.Errorf("%w: unknown or expired proof-of-work seed", ErrChallengeFailed)
+	}
+
+	if _, used := usedAddresses[bech32]; used {
+		return fmt.Errorf("%w: proof-of-work seed was already used", ErrChallengeFailed)
+	}
+	usedAddresses[bech32] = struct{}{}
+
+	return nil
+}
+
+// captchaVerifyResponse is the response of a captcha verification endpoint.
+type captchaVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// NewCaptchaVerifyFunc returns a CaptchaVerifyFunc that uses a verification endpoint compatible
+// with the "siteverify" API of hCaptcha and reCAPTCHA.
+func NewCaptchaVerifyFunc(verifyURL string, secret string) CaptchaVerifyFunc {
+
+	httpClient := &http.Client{Timeout: captchaVerifyTimeout}
+
+	return func(ctx context.Context, token string, remoteIP string) error {
+		if token == "" {
+			return fmt.Errorf("%w: captcha token missing", ErrChallengeFailed)
+		}
+
+		form := url.Values{}
+		form.Set("secret", secret)
+		form.Set("response", token)
+		if remoteIP != "" {
+			form.Set("remoteip", remoteIP)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("unable to create captcha verification request: %w", err)
+		}
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("captcha verification failed: %w", err)
+		}
+		defer func() { _ = res.Body.Close() }()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("captcha verification failed: status code %d", res.StatusCode)
+		}
+
+		verifyResponse := &captchaVerifyResponse{}
+		if err := json.NewDecoder(res.Body).Decode(verifyResponse); err != nil {
+			return fmt.Errorf("unable to decode captcha verification response: %w", err)
+		}
+
+		if !verifyResponse.Success {
+			return fmt.Errorf("%w: invalid captcha token %v", ErrChallengeFailed, verifyResponse.ErrorCodes)
+		}
+
+		return nil
+	}
+}
+
+// verifyChallenges checks if the requester solved all enabled challenges.
+func (f *Faucet) verifyChallenges(ctx context.Context, bech32 string, remoteIP string, solution *ChallengeSolution) error {
+
+	if f.opts.powChallengeMinScore == 0 && f.opts.captchaVerifyFunc == nil {
+		return nil
+	}
+
+	if solution == nil {
+		return errors.WithMessage(echo.ErrForbidden, "Challenge solution missing.")
+	}
+
+	if f.opts.powChallengeMinScore > 0 {
+		if err := f.powChallengeSeeds.valid(solution.Seed, bech32); err != nil {
+			if errors.Is(err, ErrChallengeFailed) {
+				return errors.WithMessage(echo.ErrForbidden, "Invalid proof-of-work seed.")
+			}
+			return errors.WithMessagef(echo.ErrInternalServerError, "Proof-of-work verification failed: %s", err)
+		}
+
+		if err := verifyPoWChallenge(solution.Seed, bech32, solution.Nonce, f.opts.powChallengeMinScore); err != nil {
+			return errors.WithMessage(echo.ErrForbidden, "Invalid proof-of-work nonce.")
+		}
+	}
+
+	if f.opts.captchaVerifyFunc != nil {
+		if err := f.opts.captchaVerifyFunc(ctx, solution.CaptchaToken, remoteIP); err != nil {
+			if errors.Is(err, ErrChallengeFailed) {
+				return errors.WithMessage(echo.ErrForbidden, "Invalid captcha.")
+			}
+			return errors.WithMessagef(echo.ErrInternalServerError, "Captcha verification failed: %s", err)
+		}
+	}
+
+	if f.opts.powChallengeMinScore > 0 {
+		// the solution can't be replayed
+		if err := f.powChallengeSeeds.use(solution.Seed, bech32); err != nil {
+			return errors.WithMessage(echo.ErrForbidden, "Invalid proof-of-work seed.")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package faucet
+
+import (
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+// StorePayouts stores the history of a faucet transaction of the first funding account
+// that pays the given amounts to the address.
+func StorePayouts(f *Faucet, address *iotago.Ed25519Address, amounts []uint64, messageID hornet.MessageID, issuedAt time.Time) error {
+	acc := f.accounts[0]
+
+	outputs := iotago.Serializables{
+		// the remainder output is not part of the history
+		&iotago.SigLockedSingleOutput{Address: acc.Address, Amount: 1},
+	}
+	for _, amount := range amounts {
+		outputs = append(outputs, &iotago.SigLockedSingleOutput{Address: address, Amount: amount})
+	}
+
+	txPayload := &iotago.Transaction{
+		Essence: &iotago.TransactionEssence{
+			Inputs:  iotago.Serializables{&iotago.UTXOInput{}},
+			Outputs: outputs,
+		},
+	}
+
+	return f.storeHistory(acc, txPayload, messageID, issuedAt)
+}
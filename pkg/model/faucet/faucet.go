@@ -2,8 +2,11 @@ package faucet
 
 import (
 	"bytes"
+	"context"
+	"encoding/hex"
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -19,6 +22,7 @@ import (
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/restapi"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/syncutils"
 	iotago "github.com/iotaledger/iota.go/v2"
@@ -33,6 +37,8 @@ type TipselFunc = func() (tips hornet.MessageIDs, err error)
 var (
 	// ErrNoTipsGiven is returned when no tips were given to issue a message.
 	ErrNoTipsGiven = errors.New("no tips given")
+	// ErrNoFundingAccounts is returned when the faucet was created without funding accounts.
+	ErrNoFundingAccounts = errors.New("no funding accounts given")
 )
 
 // Events are the events issued by the faucet.
@@ -41,6 +47,8 @@ type Events struct {
 	IssuedMessage *events.Event
 	// SoftError is triggered when a soft error is encountered.
 	SoftError *events.Event
+	// ConsolidatedOutputs is triggered with the amount of consolidated outputs when a consolidation message is issued.
+	ConsolidatedOutputs *events.Event
 }
 
 // queueItem is an item for the faucet requests queue.
@@ -48,14 +56,21 @@ type queueItem struct {
 	Bech32         string
 	Amount         uint64
 	Ed25519Address *iotago.Ed25519Address
+	EnqueuedAt     time.Time
 }
 
 // FaucetInfoResponse defines the response of a GET RouteFaucetInfo REST API call.
 type FaucetInfoResponse struct {
-	// The bech32 address of the faucet.
+	// The bech32 address of the first funding account of the faucet.
 	Address string `json:"address"`
-	// The remaining balance of faucet.
+	// The remaining balance of all funding accounts of the faucet.
 	Balance uint64 `json:"balance"`
+	// The minimum proof-of-work score of the challenge that has to be solved before funds are requested.
+	PoWChallengeMinScore float64 `json:"powChallengeMinScore,omitempty"`
+	// The hex encoded seed of the proof-of-work challenge, it is prepended to the bech32 address before mining.
+	PoWChallengeSeed string `json:"powChallengeSeed,omitempty"`
+	// Whether a captcha has to be solved before funds are requested.
+	CaptchaRequired bool `json:"captchaRequired,omitempty"`
 }
 
 // FaucetEnqueueResponse defines the response of a POST RouteFaucetEnqueue REST API call.
@@ -72,6 +87,8 @@ type Faucet struct {
 
 	// used to access the node storage.
 	storage *storage.Storage
+	// used to persist the cooldown windows and the pending requests.
+	store kvstore.KVStore
 	// used to determine the sync status of the node.
 	syncManager *syncmanager.SyncManager
 	// id of the network the faucet is running in.
@@ -81,10 +98,8 @@ type Faucet struct {
 	belowMaxDepth milestone.Index
 	// used to get the outputs.
 	utxoManager *utxo.Manager
-	// the address of the faucet.
-	address *iotago.Ed25519Address
-	// used to sign the faucet transactions.
-	addressSigner iotago.AddressSigner
+	// the funding accounts of the faucet.
+	accounts []*account
 	// used to get valid tips for new faucet messages.
 	tipselFunc TipselFunc
 	// used to do the PoW for the faucet messages.
@@ -97,12 +112,15 @@ type Faucet struct {
 	// events of the faucet.
 	Events *Events
 
-	// the message ID of the last sent faucet message.
-	lastMessageID hornet.MessageID
 	// map with all queued requests per address.
 	queueMap map[string]*queueItem
 	// queue of new requests.
 	queue chan *queueItem
+	// requests that exceeded the remaining funds of a funding account.
+	// they are paid as soon as a funding account has enough funds.
+	parkedRequests []*queueItem
+	// the seeds of the proof-of-work challenges.
+	powChallengeSeeds *powChallengeSeeds
 }
 
 // the default options applied to the faucet.
@@ -115,20 +133,29 @@ var defaultOptions = []Option{
 	WithIndexationMessage("HORNET FAUCET"),
 	WithBatchTimeout(2 * time.Second),
 	WithPowWorkerCount(0),
+	WithConsolidationThreshold(64),
+	WithHistoryRetention(7 * 24 * time.Hour),
 }
 
 // Options define options for the faucet.
 type Options struct {
 	logger *logger.Logger
 
-	hrpNetworkPrefix  iotago.NetworkPrefix
-	amount            uint64
-	smallAmount       uint64
-	maxAddressBalance uint64
-	maxOutputCount    int
-	indexationMessage []byte
-	batchTimeout      time.Duration
-	powWorkerCount    int
+	hrpNetworkPrefix       iotago.NetworkPrefix
+	amount                 uint64
+	smallAmount            uint64
+	maxAddressBalance      uint64
+	maxOutputCount         int
+	indexationMessage      []byte
+	batchTimeout           time.Duration
+	powWorkerCount         int
+	consolidationThreshold int
+	historyRetention       time.Duration
+
+	addressCooldown      time.Duration
+	ipCooldown           time.Duration
+	powChallengeMinScore float64
+	captchaVerifyFunc    CaptchaVerifyFunc
 }
 
 // applies the given Option.
@@ -215,54 +242,117 @@ func WithPowWorkerCount(powWorkerCount int) Option {
 	}
 }
 
+// WithConsolidationThreshold defines the amount of unspent outputs on a funding account
+// that triggers a consolidation of the outputs. The consolidation is disabled if the threshold is zero.
+func WithConsolidationThreshold(consolidationThreshold int) Option {
+	return func(opts *Options) {
+		opts.consolidationThreshold = consolidationThreshold
+	}
+}
+
+// WithHistoryRetention defines how long the payouts of the faucet are kept in the history.
+// The payouts are kept forever if the retention is zero.
+func WithHistoryRetention(retention time.Duration) Option {
+	return func(opts *Options) {
+		opts.historyRetention = retention
+	}
+}
+
+// WithAddressCooldown defines the duration an address has to wait before it can request funds again.
+func WithAddressCooldown(cooldown time.Duration) Option {
+	return func(opts *Options) {
+		opts.addressCooldown = cooldown
+	}
+}
+
+// WithIPCooldown defines the duration an IP has to wait before it can request funds again.
+func WithIPCooldown(cooldown time.Duration) Option {
+	return func(opts *Options) {
+		opts.ipCooldown = cooldown
+	}
+}
+
+// WithPoWChallenge defines the minimum proof-of-work score the requester has to reach before funds are enqueued.
+// The challenge is disabled if the score is zero.
+func WithPoWChallenge(minScore float64) Option {
+	return func(opts *Options) {
+		opts.powChallengeMinScore = minScore
+	}
+}
+
+// WithCaptchaChallenge defines the function that verifies the captcha token of the requester before funds are enqueued.
+func WithCaptchaChallenge(verifyFunc CaptchaVerifyFunc) Option {
+	return func(opts *Options) {
+		opts.captchaVerifyFunc = verifyFunc
+	}
+}
+
 // Option is a function setting a faucet option.
 type Option func(opts *Options)
 
 // New creates a new faucet instance.
+// The pending requests that were persisted in the given store are restored.
 func New(
 	dbStorage *storage.Storage,
+	store kvstore.KVStore,
 	syncManager *syncmanager.SyncManager,
 	networkID uint64,
 	belowMaxDepth int,
 	utxoManager *utxo.Manager,
-	address *iotago.Ed25519Address,
-	addressSigner iotago.AddressSigner,
+	fundingAccounts []*FundingAccount,
 	tipselFunc TipselFunc,
 	powHandler *pow.Handler,
 	sendMessageFunc SendMessageFunc,
-	opts ...Option) *Faucet {
+	opts ...Option) (*Faucet, error) {
+
+	if len(fundingAccounts) == 0 {
+		return nil, ErrNoFundingAccounts
+	}
 
 	options := &Options{}
 	options.apply(defaultOptions...)
 	options.apply(opts...)
 
+	accounts := make([]*account, len(fundingAccounts))
+	for i, fundingAccount := range fundingAccounts {
+		accounts[i] = &account{
+			FundingAccount: fundingAccount,
+			lastMessageID:  hornet.NullMessageID(),
+		}
+	}
+
 	faucet := &Faucet{
-		storage:         dbStorage,
-		syncManager:     syncManager,
-		networkID:       networkID,
-		belowMaxDepth:   milestone.Index(belowMaxDepth),
-		utxoManager:     utxoManager,
-		address:         address,
-		addressSigner:   addressSigner,
-		tipselFunc:      tipselFunc,
-		powHandler:      powHandler,
-		sendMessageFunc: sendMessageFunc,
-		opts:            options,
+		storage:           dbStorage,
+		store:             store,
+		syncManager:       syncManager,
+		networkID:         networkID,
+		belowMaxDepth:     milestone.Index(belowMaxDepth),
+		utxoManager:       utxoManager,
+		accounts:          accounts,
+		tipselFunc:        tipselFunc,
+		powHandler:        powHandler,
+		sendMessageFunc:   sendMessageFunc,
+		opts:              options,
+		powChallengeSeeds: &powChallengeSeeds{},
 
 		Events: &Events{
-			IssuedMessage: events.NewEvent(events.VoidCaller),
-			SoftError:     events.NewEvent(events.ErrorCaller),
+			IssuedMessage:       events.NewEvent(events.VoidCaller),
+			SoftError:           events.NewEvent(events.ErrorCaller),
+			ConsolidatedOutputs: events.NewEvent(events.IntCaller),
 		},
 	}
-	faucet.init()
+	if err := faucet.init(); err != nil {
+		return nil, err
+	}
 
-	return faucet
+	return faucet, nil
 }
 
-func (f *Faucet) init() {
+func (f *Faucet) init() error {
 	f.queue = make(chan *queueItem, 5000)
 	f.queueMap = make(map[string]*queueItem)
-	f.lastMessageID = hornet.NullMessageID()
+
+	return f.loadQueue()
 }
 
 // NetworkPrefix returns the used network prefix.
@@ -270,21 +360,51 @@ func (f *Faucet) NetworkPrefix() iotago.NetworkPrefix {
 	return f.opts.hrpNetworkPrefix
 }
 
-// Info returns the used faucet address and remaining balance.
+// Info returns the address of the first funding account and the remaining balance of all funding accounts.
 func (f *Faucet) Info() (*FaucetInfoResponse, error) {
-	balance, _, err := f.utxoManager.AddressBalanceWithoutLocking(f.address)
-	if err != nil {
-		return nil, err
+	var balance uint64
+	for _, acc := range f.accounts {
+		accountBalance, _, err := f.utxoManager.AddressBalanceWithoutLocking(acc.Address)
+		if err != nil {
+			return nil, err
+		}
+		balance += accountBalance
 	}
 
-	return &FaucetInfoResponse{
-		Address: f.address.Bech32(f.opts.hrpNetworkPrefix),
-		Balance: balance,
-	}, nil
+	info := &FaucetInfoResponse{
+		Address:         f.accounts[0].Address.Bech32(f.opts.hrpNetworkPrefix),
+		Balance:         balance,
+		CaptchaRequired: f.opts.captchaVerifyFunc != nil,
+	}
+
+	if f.opts.powChallengeMinScore > 0 {
+		seed, err := f.powChallengeSeeds.seed()
+		if err != nil {
+			return nil, err
+		}
+		info.PoWChallengeMinScore = f.opts.powChallengeMinScore
+		info.PoWChallengeSeed = hex.EncodeToString(seed)
+	}
+
+	return info, nil
+}
+
+// PendingRequests returns the amount of requests waiting in the queue.
+func (f *Faucet) PendingRequests() int {
+	f.Lock()
+	defer f.Unlock()
+
+	return len(f.queueMap)
 }
 
 // Enqueue adds a new faucet request to the queue.
-func (f *Faucet) Enqueue(bech32 string, ed25519Addr *iotago.Ed25519Address) (*FaucetEnqueueResponse, error) {
+// The requester has to solve the enabled challenges and the cooldown windows of the address and the IP have to be expired.
+func (f *Faucet) Enqueue(ctx context.Context, bech32 string, ed25519Addr *iotago.Ed25519Address, remoteIP string, solution *ChallengeSolution) (*FaucetEnqueueResponse, error) {
+
+	if err := f.verifyChallenges(ctx, bech32, remoteIP, solution); err != nil {
+		return nil, err
+	}
+
 	f.Lock()
 	defer f.Unlock()
 
@@ -292,6 +412,35 @@ func (f *Faucet) Enqueue(bech32 string, ed25519Addr *iotago.Ed25519Address) (*Fa
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "Address is already in the queue.")
 	}
 
+	blacklisted, err := f.isBlacklisted(bech32)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "Reading blacklist failed: %s", err)
+	}
+
+	if blacklisted {
+		return nil, errors.WithMessage(echo.ErrForbidden, "Address is blacklisted.")
+	}
+
+	now := time.Now()
+
+	addressCooldown, err := f.cooldownRemaining(storeKeyPrefixAddressCooldown, bech32, f.opts.addressCooldown, now)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "Reading cooldown failed: %s", err)
+	}
+
+	ipCooldown, err := f.cooldownRemaining(storeKeyPrefixIPCooldown, remoteIP, f.opts.ipCooldown, now)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "Reading cooldown failed: %s", err)
+	}
+
+	if ipCooldown > addressCooldown {
+		addressCooldown = ipCooldown
+	}
+
+	if addressCooldown > 0 {
+		return nil, errors.WithMessagef(echo.ErrTooManyRequests, "Please wait %v before requesting funds again.", addressCooldown.Round(time.Second))
+	}
+
 	amount := f.opts.amount
 	balance, _, err := f.utxoManager.AddressBalanceWithoutLocking(ed25519Addr)
 	if err == nil && balance >= f.opts.amount {
@@ -306,11 +455,18 @@ func (f *Faucet) Enqueue(bech32 string, ed25519Addr *iotago.Ed25519Address) (*Fa
 		Bech32:         bech32,
 		Amount:         amount,
 		Ed25519Address: ed25519Addr,
+		EnqueuedAt:     now,
 	}
 
 	select {
 	case f.queue <- request:
 		f.queueMap[bech32] = request
+
+		if err := f.persistRequest(request, remoteIP); err != nil {
+			// the request was already enqueued, the faucet still works without the persistence
+			f.logSoftError(err)
+		}
+
 		return &FaucetEnqueueResponse{
 			Address:         bech32,
 			WaitingRequests: len(f.queueMap),
@@ -322,7 +478,29 @@ func (f *Faucet) Enqueue(bech32 string, ed25519Addr *iotago.Ed25519Address) (*Fa
 	}
 }
 
-// clearRequests clears the old requests from the map.
+// persistRequest stores the pending request and starts the cooldown windows of the address and the IP.
+func (f *Faucet) persistRequest(request *queueItem, remoteIP string) error {
+
+	if err := f.storeQueueItem(request); err != nil {
+		return err
+	}
+
+	if f.opts.addressCooldown > 0 {
+		if err := f.storeRequestTime(storeKeyPrefixAddressCooldown, request.Bech32, request.EnqueuedAt); err != nil {
+			return err
+		}
+	}
+
+	if f.opts.ipCooldown > 0 && remoteIP != "" {
+		if err := f.storeRequestTime(storeKeyPrefixIPCooldown, remoteIP, request.EnqueuedAt); err != nil {
+			return err
+		}
+	}
+
+	return f.store.Flush()
+}
+
+// clearRequests clears the old requests from the map and the store.
 // this is necessary to be able to send new requests to the same addresses.
 func (f *Faucet) clearRequests(batchedRequests []*queueItem) {
 	f.Lock()
@@ -330,11 +508,101 @@ func (f *Faucet) clearRequests(batchedRequests []*queueItem) {
 
 	for _, request := range batchedRequests {
 		delete(f.queueMap, request.Bech32)
+
+		if err := f.deleteQueueItem(request.Bech32); err != nil {
+			f.logSoftError(err)
+		}
+	}
+}
+
+// maxConfirmedAccountBalance returns the highest confirmed balance of all funding accounts.
+func (f *Faucet) maxConfirmedAccountBalance() (uint64, error) {
+	var maxBalance uint64
+	for _, acc := range f.accounts {
+		balance, _, err := f.utxoManager.AddressBalanceWithoutLocking(acc.Address)
+		if err != nil {
+			return 0, fmt.Errorf("reading balance failed: %s, error: %w", acc.Address.Bech32(f.opts.hrpNetworkPrefix), err)
+		}
+		if balance > maxBalance {
+			maxBalance = balance
+		}
+	}
+
+	return maxBalance, nil
+}
+
+// parkRequest keeps a request that could not be paid in full aside until a funding account has enough funds.
+// the request is rejected if it exceeds the confirmed balance of every funding account.
+func (f *Faucet) parkRequest(request *queueItem) {
+	maxBalance, err := f.maxConfirmedAccountBalance()
+	if err != nil {
+		f.logSoftError(err)
+	} else if request.Amount > maxBalance {
+		f.clearRequests([]*queueItem{request})
+		f.logSoftError(fmt.Errorf("rejected the request of %d to %s, it exceeds the balance of every funding account (%d)", request.Amount, request.Bech32, maxBalance))
+		return
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	f.parkedRequests = append(f.parkedRequests, request)
+}
+
+// takeParkedRequest returns the oldest parked request that can be paid with the given amount and removes it,
+// or nil if there is none.
+func (f *Faucet) takeParkedRequest(amount uint64) *queueItem {
+	f.Lock()
+	defer f.Unlock()
+
+	for i, request := range f.parkedRequests {
+		if request.Amount <= amount {
+			f.parkedRequests = append(f.parkedRequests[:i], f.parkedRequests[i+1:]...)
+			return request
+		}
+	}
+
+	return nil
+}
+
+// rejectUnpayableParkedRequests rejects the parked requests that exceed the confirmed balance of every funding account.
+func (f *Faucet) rejectUnpayableParkedRequests() error {
+	f.Lock()
+	parkedCount := len(f.parkedRequests)
+	f.Unlock()
+
+	if parkedCount == 0 {
+		return nil
+	}
+
+	maxBalance, err := f.maxConfirmedAccountBalance()
+	if err != nil {
+		return err
 	}
+
+	var rejectedRequests []*queueItem
+	f.Lock()
+	parkedRequests := f.parkedRequests[:0]
+	for _, request := range f.parkedRequests {
+		if request.Amount > maxBalance {
+			rejectedRequests = append(rejectedRequests, request)
+			continue
+		}
+		parkedRequests = append(parkedRequests, request)
+	}
+	f.parkedRequests = parkedRequests
+	f.Unlock()
+
+	f.clearRequests(rejectedRequests)
+	for _, request := range rejectedRequests {
+		f.logSoftError(fmt.Errorf("rejected the request of %d to %s, it exceeds the balance of every funding account (%d)", request.Amount, request.Bech32, maxBalance))
+	}
+
+	return nil
 }
 
-// createMessage creates a new message and references the last faucet message (also reattaches if below max depth).
-func (f *Faucet) createMessage(txPayload iotago.Serializable, shutdownSignal <-chan struct{}) (*storage.Message, error) {
+// createMessage creates a new message and references the last faucet message of the account (also reattaches if below max depth).
+func (f *Faucet) createMessage(acc *account, txPayload iotago.Serializable, shutdownSignal <-chan struct{}) (*storage.Message, error) {
 
 	tips, err := f.tipselFunc()
 	if err != nil {
@@ -342,7 +610,7 @@ func (f *Faucet) createMessage(txPayload iotago.Serializable, shutdownSignal <-c
 	}
 
 	reattachMessage := func(messageID hornet.MessageID) (*storage.Message, error) {
-		cachedMsg := f.storage.CachedMessageOrNil(f.lastMessageID)
+		cachedMsg := f.storage.CachedMessageOrNil(messageID)
 		if cachedMsg == nil {
 			// message unknown
 			return nil, fmt.Errorf("message not found: %s", messageID.ToHex())
@@ -381,12 +649,12 @@ func (f *Faucet) createMessage(txPayload iotago.Serializable, shutdownSignal <-c
 	// we need to check for the last faucet message, because we reference the last message as a tip
 	// to be sure the tangle consumes our UTXOs in the correct order.
 	if err = func() error {
-		if bytes.Equal(f.lastMessageID, hornet.NullMessageID()) {
+		if bytes.Equal(acc.lastMessageID, hornet.NullMessageID()) {
 			// do not reference NullMessage
 			return nil
 		}
 
-		cachedMsgMeta := f.storage.CachedMessageMetadataOrNil(f.lastMessageID)
+		cachedMsgMeta := f.storage.CachedMessageMetadataOrNil(acc.lastMessageID)
 		if cachedMsgMeta == nil {
 			// message unknown
 			return nil
@@ -402,16 +670,16 @@ func (f *Faucet) createMessage(txPayload iotago.Serializable, shutdownSignal <-c
 		if (f.syncManager.LatestMilestoneIndex() - ocri) > f.belowMaxDepth {
 			// the last faucet message is not confirmed yet, but it is already below max depth
 			// we need to reattach it
-			msg, err := reattachMessage(f.lastMessageID)
+			msg, err := reattachMessage(acc.lastMessageID)
 			if err != nil {
 				return common.CriticalError(fmt.Errorf("faucet message was below max depth and couldn't be reattached: %w", err))
 			}
 
 			// update the lastMessasgeID because we reattached the message
-			f.lastMessageID = msg.MessageID()
+			acc.lastMessageID = msg.MessageID()
 		}
 
-		tips[0] = f.lastMessageID
+		tips[0] = acc.lastMessageID
 		tips = tips.RemoveDupsAndSortByLexicalOrder()
 
 		return nil
@@ -438,8 +706,8 @@ func (f *Faucet) createMessage(txPayload iotago.Serializable, shutdownSignal <-c
 	return msg, nil
 }
 
-// buildTransactionPayload creates a signed transaction payload with all UTXO and batched requests.
-func (f *Faucet) buildTransactionPayload(unspentOutputs []*utxo.Output, batchedRequests []*queueItem) (*iotago.Transaction, *iotago.UTXOInput, uint64, error) {
+// buildTransactionPayload creates a signed transaction payload with all UTXO of the account and batched requests.
+func (f *Faucet) buildTransactionPayload(acc *account, unspentOutputs []*utxo.Output, batchedRequests []*queueItem) (*iotago.Transaction, *iotago.UTXOInput, uint64, error) {
 
 	txBuilder := iotago.NewTransactionBuilder()
 	txBuilder.AddIndexationPayload(&iotago.Indexation{Index: f.opts.indexationMessage, Data: nil})
@@ -447,11 +715,11 @@ func (f *Faucet) buildTransactionPayload(unspentOutputs []*utxo.Output, batchedR
 	outputCount := 0
 	var remainderAmount int64 = 0
 
-	// collect all unspent output of the faucet address
+	// collect all unspent output of the funding account
 	for _, unspentOutput := range unspentOutputs {
 		outputCount++
 		remainderAmount += int64(unspentOutput.Amount())
-		txBuilder.AddInput(&iotago.ToBeSignedUTXOInput{Address: f.address, Input: unspentOutput.UTXOInput()})
+		txBuilder.AddInput(&iotago.ToBeSignedUTXOInput{Address: acc.Address, Input: unspentOutput.UTXOInput()})
 	}
 
 	// add all requests as outputs
@@ -480,10 +748,10 @@ func (f *Faucet) buildTransactionPayload(unspentOutputs []*utxo.Output, batchedR
 	}
 
 	if remainderAmount > 0 {
-		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: f.address, Amount: uint64(remainderAmount)})
+		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: acc.Address, Amount: uint64(remainderAmount)})
 	}
 
-	txPayload, err := txBuilder.Build(f.addressSigner)
+	txPayload, err := txBuilder.Build(acc.Signer)
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -508,7 +776,7 @@ func (f *Faucet) buildTransactionPayload(unspentOutputs []*utxo.Output, batchedR
 		sigLock := output.(*iotago.SigLockedSingleOutput)
 		ed25519Addr := sigLock.Address.(*iotago.Ed25519Address)
 
-		if bytes.Equal(ed25519Addr[:], f.address[:]) {
+		if bytes.Equal(ed25519Addr[:], acc.Address[:]) {
 			// found the remainder address in the outputs
 			found = true
 			remainderOutput.TransactionOutputIndex = outputIndex
@@ -524,15 +792,15 @@ func (f *Faucet) buildTransactionPayload(unspentOutputs []*utxo.Output, batchedR
 	return txPayload, remainderOutput, uint64(remainderAmount), nil
 }
 
-// sendFaucetMessage creates a faucet transaction payload and remembers the last sent messageID.
-func (f *Faucet) sendFaucetMessage(unspentOutputs []*utxo.Output, batchedRequests []*queueItem, shutdownSignal <-chan struct{}) (*utxo.Output, error) {
+// sendFaucetMessage creates a faucet transaction payload and remembers the last sent messageID of the account.
+func (f *Faucet) sendFaucetMessage(acc *account, unspentOutputs []*utxo.Output, batchedRequests []*queueItem, shutdownSignal <-chan struct{}) (*utxo.Output, error) {
 
-	txPayload, remainderIotaGoOutput, remainderAmount, err := f.buildTransactionPayload(unspentOutputs, batchedRequests)
+	txPayload, remainderIotaGoOutput, remainderAmount, err := f.buildTransactionPayload(acc, unspentOutputs, batchedRequests)
 	if err != nil {
 		return nil, fmt.Errorf("build transaction payload failed, error: %w", err)
 	}
 
-	msg, err := f.createMessage(txPayload, shutdownSignal)
+	msg, err := f.createMessage(acc, txPayload, shutdownSignal)
 	if err != nil {
 		return nil, fmt.Errorf("build faucet message failed, error: %w", err)
 	}
@@ -541,9 +809,21 @@ func (f *Faucet) sendFaucetMessage(unspentOutputs []*utxo.Output, batchedRequest
 		return nil, fmt.Errorf("send faucet message failed, error: %w", err)
 	}
 
-	f.lastMessageID = msg.MessageID()
+	acc.lastMessageID = msg.MessageID()
+	f.Events.IssuedMessage.Trigger()
+
+	if err := f.storeHistory(acc, txPayload, msg.MessageID(), time.Now()); err != nil {
+		// the message was already sent, the faucet still works without the history
+		f.logSoftError(err)
+	}
+
+	if remainderIotaGoOutput == nil {
+		// all funds of the account were sent
+		return nil, nil
+	}
+
 	remainderIotaGoOutputID := remainderIotaGoOutput.ID()
-	remainderOutput := utxo.CreateOutput(&remainderIotaGoOutputID, msg.MessageID(), iotago.OutputSigLockedSingleOutput, f.address, uint64(remainderAmount))
+	remainderOutput := utxo.CreateOutput(&remainderIotaGoOutputID, msg.MessageID(), iotago.OutputSigLockedSingleOutput, acc.Address, uint64(remainderAmount))
 
 	return remainderOutput, nil
 }
@@ -556,99 +836,44 @@ func (f *Faucet) logSoftError(err error) {
 	f.Events.SoftError.Trigger(err)
 }
 
-// RunFaucetLoop collects unspent outputs on the faucet address and batches the requests from the queue.
+// RunFaucetLoop runs a loop for every funding account that collects the unspent outputs
+// of the account and batches the requests from the shared queue.
+// The loops are stopped if the shutdown signal is received or one of the loops hit a critical error.
 func (f *Faucet) RunFaucetLoop(shutdownSignal <-chan struct{}) error {
 
-	var lastRemainderOutput *utxo.Output
+	stopSignal := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() { close(stopSignal) })
+	}
 
-	for {
+	go func() {
 		select {
 		case <-shutdownSignal:
-			// faucet was stopped
-			return nil
-
-		default:
-
-			// only collect unspent outputs if the lastRemainderOutput is not pending
-			shouldCollectUnspentOutputs := func() bool {
-				if lastRemainderOutput == nil {
-					return true
-				}
-
-				if _, err := f.utxoManager.ReadOutputByOutputIDWithoutLocking(lastRemainderOutput.OutputID()); err != nil {
-					return false
-				}
-
-				return true
-			}
-
-			var err error
-			unspentOutputs := []*utxo.Output{}
-			if shouldCollectUnspentOutputs() {
-				unspentOutputs, err = f.utxoManager.UnspentOutputs(utxo.FilterAddress(f.address), utxo.ReadLockLedger(false), utxo.MaxResultCount(f.opts.maxOutputCount-2), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
-				if err != nil {
-					return fmt.Errorf("reading unspent outputs failed: %s, error: %w", f.address.Bech32(f.opts.hrpNetworkPrefix), err)
-				}
-			} else {
-				unspentOutputs = append(unspentOutputs, lastRemainderOutput)
-			}
-
-			var amount uint64 = 0
-			found := false
-			for _, unspentOutput := range unspentOutputs {
-				amount += unspentOutput.Amount()
-				if lastRemainderOutput != nil && bytes.Equal(unspentOutput.OutputID()[:], lastRemainderOutput.OutputID()[:]) {
-					found = true
-				}
-			}
+			stop()
+		case <-stopSignal:
+		}
+	}()
 
-			if lastRemainderOutput != nil && !found {
-				unspentOutputs = append(unspentOutputs, lastRemainderOutput)
-				amount += lastRemainderOutput.Amount()
-			}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(f.accounts))
 
-			collectedRequestsCounter := len(unspentOutputs)
-			batchWriterTimeoutChan := time.After(f.opts.batchTimeout)
-			batchedRequests := []*queueItem{}
-
-		CollectValues:
-			for collectedRequestsCounter < f.opts.maxOutputCount-1 && amount > f.opts.amount {
-				select {
-				case <-shutdownSignal:
-					// faucet was stopped
-					return nil
-
-				case <-batchWriterTimeoutChan:
-					// timeout was reached => stop collecting requests
-					break CollectValues
-
-				case request := <-f.queue:
-					batchedRequests = append(batchedRequests, request)
-					collectedRequestsCounter++
-					amount -= request.Amount
-				}
-			}
+	for _, acc := range f.accounts {
+		wg.Add(1)
+		go func(acc *account) {
+			defer wg.Done()
 
-			f.clearRequests(batchedRequests)
-
-			if len(unspentOutputs) < 2 && len(batchedRequests) == 0 {
-				// no need to sweep or send funds
-				continue
+			if err := f.runAccountLoop(acc, stopSignal); err != nil {
+				errs <- err
+				stop()
 			}
+		}(acc)
+	}
 
-			remainderOutput, err := f.sendFaucetMessage(unspentOutputs, batchedRequests, shutdownSignal)
-			if err != nil {
-				if common.IsCriticalError(err) != nil {
-					// error is a critical error
-					// => stop the faucet
-					return err
-				}
-
-				f.logSoftError(err)
-				continue
-			}
+	wg.Wait()
+	stop()
+	close(errs)
 
-			lastRemainderOutput = remainderOutput
-		}
-	}
+	// return the first critical error
+	return <-errs
 }
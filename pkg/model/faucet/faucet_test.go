@@ -0,0 +1,259 @@
+package faucet_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/faucet"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/pow"
+)
+
+func randAddress() *iotago.Ed25519Address {
+	address := &iotago.Ed25519Address{}
+	rand.Read(address[:])
+	return address
+}
+
+func newFaucet(t *testing.T, store kvstore.KVStore, opts ...faucet.Option) *faucet.Faucet {
+	f, err := faucet.New(nil, store, nil, 0, 15, utxo.New(mapdb.NewMapDB()), []*faucet.FundingAccount{{Address: randAddress()}}, nil, nil, nil, opts...)
+	require.NoError(t, err)
+	return f
+}
+
+func randOutput(address *iotago.Ed25519Address, amount uint64) *utxo.Output {
+	outputID := &iotago.UTXOInputID{}
+	rand.Read(outputID[:])
+	messageID := hornet.MessageID(make([]byte, iotago.MessageIDLength))
+	rand.Read(messageID)
+	return utxo.CreateOutput(outputID, messageID, iotago.OutputSigLockedSingleOutput, address, amount)
+}
+
+func enqueue(f *faucet.Faucet, remoteIP string, solution *faucet.ChallengeSolution) (*iotago.Ed25519Address, *faucet.FaucetEnqueueResponse, error) {
+	address := randAddress()
+	response, err := f.Enqueue(context.Background(), address.Bech32(iotago.PrefixTestnet), address, remoteIP, solution)
+	return address, response, err
+}
+
+func requireHTTPError(t *testing.T, err error, code int) {
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, code, httpErr.Code)
+}
+
+func TestFaucetCooldownsAndPersistence(t *testing.T) {
+
+	store := mapdb.NewMapDB()
+	f := newFaucet(t, store, faucet.WithAddressCooldown(time.Hour), faucet.WithIPCooldown(time.Hour))
+
+	address, response, err := enqueue(f, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, response.WaitingRequests)
+
+	// the IP is in its cooldown window
+	_, _, err = enqueue(f, "10.0.0.1", nil)
+	requireHTTPError(t, err, http.StatusTooManyRequests)
+
+	_, response, err = enqueue(f, "10.0.0.2", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, response.WaitingRequests)
+
+	// the pending requests and the cooldown windows survive a restart
+	f = newFaucet(t, store, faucet.WithAddressCooldown(time.Hour), faucet.WithIPCooldown(time.Hour))
+
+	_, err = f.Enqueue(context.Background(), address.Bech32(iotago.PrefixTestnet), address, "10.0.0.3", nil)
+	requireHTTPError(t, err, http.StatusBadRequest)
+
+	_, _, err = enqueue(f, "10.0.0.2", nil)
+	requireHTTPError(t, err, http.StatusTooManyRequests)
+
+	_, response, err = enqueue(f, "10.0.0.3", nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, response.WaitingRequests)
+
+	// without cooldown windows, all entries are expired and removed
+	require.NoError(t, newFaucet(t, store).CleanupCooldowns())
+
+	f = newFaucet(t, store, faucet.WithAddressCooldown(time.Hour), faucet.WithIPCooldown(time.Hour))
+	_, response, err = enqueue(f, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, response.WaitingRequests)
+}
+
+func TestFaucetChallenges(t *testing.T) {
+
+	const minScore = 100
+
+	captchaVerifyFunc := func(_ context.Context, token string, _ string) error {
+		if token != "valid" {
+			return faucet.ErrChallengeFailed
+		}
+		return nil
+	}
+
+	f := newFaucet(t, mapdb.NewMapDB(), faucet.WithPoWChallenge(minScore), faucet.WithCaptchaChallenge(captchaVerifyFunc))
+
+	info, err := f.Info()
+	require.NoError(t, err)
+	require.Equal(t, float64(minScore), info.PoWChallengeMinScore)
+	require.True(t, info.CaptchaRequired)
+
+	_, _, err = enqueue(f, "10.0.0.1", nil)
+	requireHTTPError(t, err, http.StatusForbidden)
+
+	seed, err := hex.DecodeString(info.PoWChallengeSeed)
+	require.NoError(t, err)
+	require.NotEmpty(t, seed)
+
+	address := randAddress()
+	bech32 := address.Bech32(iotago.PrefixTestnet)
+
+	nonce, err := pow.New(1).Mine(context.Background(), faucet.PoWChallengeData(seed, bech32), minScore)
+	require.NoError(t, err)
+
+	_, err = f.Enqueue(context.Background(), bech32, address, "10.0.0.1", &faucet.ChallengeSolution{Seed: seed, Nonce: nonce, CaptchaToken: "invalid"})
+	requireHTTPError(t, err, http.StatusForbidden)
+
+	// the nonce is only valid for the address it was mined for
+	_, _, err = enqueue(f, "10.0.0.1", &faucet.ChallengeSolution{Seed: seed, Nonce: nonce, CaptchaToken: "valid"})
+	requireHTTPError(t, err, http.StatusForbidden)
+
+	// the nonce is only valid for the seed it was mined with
+	unknownSeed := make([]byte, len(seed))
+	rand.Read(unknownSeed)
+	_, err = f.Enqueue(context.Background(), bech32, address, "10.0.0.1", &faucet.ChallengeSolution{Seed: unknownSeed, Nonce: nonce, CaptchaToken: "valid"})
+	requireHTTPError(t, err, http.StatusForbidden)
+
+	_, err = f.Enqueue(context.Background(), bech32, address, "10.0.0.1", &faucet.ChallengeSolution{Seed: seed, Nonce: nonce, CaptchaToken: "valid"})
+	require.NoError(t, err)
+
+	// the solution can't be replayed
+	_, err = f.Enqueue(context.Background(), bech32, address, "10.0.0.2", &faucet.ChallengeSolution{Seed: seed, Nonce: nonce, CaptchaToken: "valid"})
+	requireHTTPError(t, err, http.StatusForbidden)
+}
+
+func TestFaucetAccountsInfo(t *testing.T) {
+
+	_, err := faucet.New(nil, mapdb.NewMapDB(), nil, 0, 15, utxo.New(mapdb.NewMapDB()), nil, nil, nil, nil)
+	require.ErrorIs(t, err, faucet.ErrNoFundingAccounts)
+
+	utxoManager := utxo.New(mapdb.NewMapDB())
+
+	address1 := randAddress()
+	address2 := randAddress()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, utxoManager.AddUnspentOutput(randOutput(address1, 1000000)))
+	}
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(address2, 5000000)))
+
+	f, err := faucet.New(nil, mapdb.NewMapDB(), nil, 0, 15, utxoManager, []*faucet.FundingAccount{{Address: address1}, {Address: address2}}, nil, nil, nil)
+	require.NoError(t, err)
+
+	info, err := f.Info()
+	require.NoError(t, err)
+	require.Equal(t, address1.Bech32(iotago.PrefixTestnet), info.Address)
+	require.EqualValues(t, 8000000, info.Balance)
+
+	accountsInfo, err := f.AccountsInfo()
+	require.NoError(t, err)
+	require.Equal(t, []*faucet.FaucetAccountInfo{
+		{Address: address1.Bech32(iotago.PrefixTestnet), Balance: 3000000, UnspentOutputs: 3},
+		{Address: address2.Bech32(iotago.PrefixTestnet), Balance: 5000000, UnspentOutputs: 1},
+	}, accountsInfo)
+
+	require.Equal(t, 0, f.PendingRequests())
+	_, _, err = enqueue(f, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, f.PendingRequests())
+}
+
+func TestFaucetAdmin(t *testing.T) {
+
+	store := mapdb.NewMapDB()
+	utxoManager := utxo.New(mapdb.NewMapDB())
+
+	fundingAddress := randAddress()
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(fundingAddress, 8000000)))
+
+	f, err := faucet.New(nil, store, nil, 0, 15, utxoManager, []*faucet.FundingAccount{{Address: fundingAddress}}, nil, nil, nil)
+	require.NoError(t, err)
+
+	address := randAddress()
+	bech32 := address.Bech32(iotago.PrefixTestnet)
+
+	require.NoError(t, f.AddToBlacklist(bech32))
+
+	blacklist, err := f.Blacklist()
+	require.NoError(t, err)
+	require.Equal(t, []string{bech32}, blacklist)
+
+	_, err = f.Enqueue(context.Background(), bech32, address, "10.0.0.1", nil)
+	requireHTTPError(t, err, http.StatusForbidden)
+
+	// refunds ignore the blacklist
+	_, err = f.EnqueueRefund(bech32, address, 0)
+	requireHTTPError(t, err, http.StatusBadRequest)
+
+	// refunds are not paid in part
+	_, err = f.EnqueueRefund(bech32, address, 9000000)
+	requireHTTPError(t, err, http.StatusBadRequest)
+
+	response, err := f.EnqueueRefund(bech32, address, 5000000)
+	require.NoError(t, err)
+	require.Equal(t, 1, response.WaitingRequests)
+
+	// the refund is persisted
+	f, err = faucet.New(nil, store, nil, 0, 15, utxoManager, []*faucet.FundingAccount{{Address: fundingAddress}}, nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, f.PendingRequests())
+
+	require.NoError(t, f.RemoveFromBlacklist(bech32))
+	blacklist, err = f.Blacklist()
+	require.NoError(t, err)
+	require.Empty(t, blacklist)
+
+	requireHTTPError(t, f.SetAmounts(1000, 2000), http.StatusBadRequest)
+	requireHTTPError(t, f.SetAmounts(100000000, 1000), http.StatusBadRequest)
+
+	require.NoError(t, f.SetAmounts(2000000, 500000))
+	require.Equal(t, &faucet.FaucetAmountsResponse{Amount: 2000000, SmallAmount: 500000}, f.Amounts())
+
+	history, err := f.History(10)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	// store some payouts of faucet transactions that were issued one after another
+	issuedAt := time.Now()
+	messageID := hornet.MessageID(make([]byte, iotago.MessageIDLength))
+	rand.Read(messageID)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, faucet.StorePayouts(f, address, []uint64{uint64(i + 1)}, messageID, issuedAt.Add(time.Duration(i)*time.Second)))
+	}
+
+	// the newest payouts are returned first
+	history, err = f.History(3)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	for i, entry := range history {
+		require.Equal(t, bech32, entry.Address)
+		require.EqualValues(t, 5-i, entry.Amount)
+		require.Equal(t, messageID.ToHex(), entry.MessageID)
+		require.Equal(t, issuedAt.Add(time.Duration(4-i)*time.Second).Unix(), entry.Timestamp)
+	}
+
+	history, err = f.History(0)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+}
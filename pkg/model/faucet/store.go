@@ -0,0 +1,187 @@
+package faucet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// the last request times of the addresses.
+	storeKeyPrefixAddressCooldown byte = 0
+	// the last request times of the IPs.
+	storeKeyPrefixIPCooldown byte = 1
+	// the pending requests.
+	storeKeyPrefixQueue byte = 2
+	// the history of the payouts.
+	storeKeyPrefixHistory byte = 3
+	// the blacklisted addresses.
+	storeKeyPrefixBlacklist byte = 4
+
+	// enqueue time (int64), amount (uint64), ed25519 address.
+	queueItemValueLength = iotago.UInt64ByteSize + iotago.UInt64ByteSize + iotago.Ed25519AddressBytesLength
+)
+
+func storeKey(prefix byte, identifier string) []byte {
+	return append([]byte{prefix}, identifier...)
+}
+
+// lastRequestTime returns the time of the last request of the given identifier.
+// Returns the zero time if no request is known.
+func (f *Faucet) lastRequestTime(prefix byte, identifier string) (time.Time, error) {
+
+	value, err := f.store.Get(storeKey(prefix, identifier))
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to read last request time: %w", err)
+	}
+
+	if len(value) != iotago.UInt64ByteSize {
+		return time.Time{}, fmt.Errorf("invalid last request time length: %d", len(value))
+	}
+
+	return time.Unix(0, int64(binary.LittleEndian.Uint64(value))), nil
+}
+
+// storeRequestTime stores the time of the last request of the given identifier.
+func (f *Faucet) storeRequestTime(prefix byte, identifier string, requestTime time.Time) error {
+
+	value := make([]byte, iotago.UInt64ByteSize)
+	binary.LittleEndian.PutUint64(value, uint64(requestTime.UnixNano()))
+
+	if err := f.store.Set(storeKey(prefix, identifier), value); err != nil {
+		return fmt.Errorf("failed to store last request time: %w", err)
+	}
+
+	return nil
+}
+
+// cooldownRemaining returns the remaining duration of the cooldown window of the given identifier.
+func (f *Faucet) cooldownRemaining(prefix byte, identifier string, cooldown time.Duration, now time.Time) (time.Duration, error) {
+
+	if cooldown == 0 {
+		return 0, nil
+	}
+
+	lastRequest, err := f.lastRequestTime(prefix, identifier)
+	if err != nil {
+		return 0, err
+	}
+
+	if remaining := lastRequest.Add(cooldown).Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+
+	return 0, nil
+}
+
+// CleanupCooldowns removes all expired cooldown windows from the store.
+func (f *Faucet) CleanupCooldowns() error {
+
+	now := time.Now()
+
+	for prefix, cooldown := range map[byte]time.Duration{
+		storeKeyPrefixAddressCooldown: f.opts.addressCooldown,
+		storeKeyPrefixIPCooldown:      f.opts.ipCooldown,
+	} {
+		var expiredKeys []kvstore.Key
+		if err := f.store.Iterate([]byte{prefix}, func(key kvstore.Key, value kvstore.Value) bool {
+			if len(value) != iotago.UInt64ByteSize || time.Unix(0, int64(binary.LittleEndian.Uint64(value))).Add(cooldown).Before(now) {
+				expiredKeys = append(expiredKeys, key)
+			}
+			return true
+		}); err != nil {
+			return fmt.Errorf("failed to iterate cooldowns: %w", err)
+		}
+
+		for _, key := range expiredKeys {
+			if err := f.store.Delete(key); err != nil {
+				return fmt.Errorf("failed to delete cooldown: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// storeQueueItem persists a pending request.
+func (f *Faucet) storeQueueItem(item *queueItem) error {
+
+	value := make([]byte, queueItemValueLength)
+	binary.LittleEndian.PutUint64(value, uint64(item.EnqueuedAt.UnixNano()))
+	binary.LittleEndian.PutUint64(value[iotago.UInt64ByteSize:], item.Amount)
+	copy(value[2*iotago.UInt64ByteSize:], item.Ed25519Address[:])
+
+	if err := f.store.Set(storeKey(storeKeyPrefixQueue, item.Bech32), value); err != nil {
+		return fmt.Errorf("failed to store pending request: %w", err)
+	}
+
+	return nil
+}
+
+// deleteQueueItem removes a pending request from the store.
+func (f *Faucet) deleteQueueItem(bech32 string) error {
+
+	if err := f.store.Delete(storeKey(storeKeyPrefixQueue, bech32)); err != nil {
+		return fmt.Errorf("failed to delete pending request: %w", err)
+	}
+
+	return nil
+}
+
+// loadQueue restores the persisted pending requests in the order they were enqueued.
+func (f *Faucet) loadQueue() error {
+
+	var items []*queueItem
+	var innerErr error
+	if err := f.store.Iterate([]byte{storeKeyPrefixQueue}, func(key kvstore.Key, value kvstore.Value) bool {
+		if len(value) != queueItemValueLength {
+			innerErr = fmt.Errorf("invalid pending request length: %d", len(value))
+			return false
+		}
+
+		address := &iotago.Ed25519Address{}
+		copy(address[:], value[2*iotago.UInt64ByteSize:])
+
+		items = append(items, &queueItem{
+			Bech32:         string(key[1:]),
+			Amount:         binary.LittleEndian.Uint64(value[iotago.UInt64ByteSize:]),
+			Ed25519Address: address,
+			EnqueuedAt:     time.Unix(0, int64(binary.LittleEndian.Uint64(value))),
+		})
+		return true
+	}); err != nil {
+		return fmt.Errorf("failed to iterate pending requests: %w", err)
+	}
+
+	if innerErr != nil {
+		return innerErr
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].EnqueuedAt.Before(items[j].EnqueuedAt)
+	})
+
+	for _, item := range items {
+		select {
+		case f.queue <- item:
+			f.queueMap[item.Bech32] = item
+
+		default:
+			// queue is full, drop the remaining requests
+			if err := f.deleteQueueItem(item.Bech32); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package milestonemanager
 
 import (
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/gohornet/hornet/pkg/keymanager"
@@ -11,6 +14,12 @@ import (
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
+const (
+	// the maximum distance to the confirmed milestone index of milestone indexes with unknown public keys that are remembered.
+	// this also limits the amount of remembered milestone indexes.
+	maxUnverifiedMilestoneIndexDistance = 1000
+)
+
 type packageEvents struct {
 	ReceivedValidMilestone *events.Event
 }
@@ -26,6 +35,11 @@ type MilestoneManager struct {
 	// amount of public keys in a milestone.
 	milestonePublicKeyCount int
 
+	// the indexes of received milestones with invalid signatures.
+	// they are requested again if new key ranges were announced, because the signing keys may not have been known yet.
+	unverifiedMilestoneIndexes     map[milestone.Index]struct{}
+	unverifiedMilestoneIndexesLock sync.Mutex
+
 	// events
 	Events *packageEvents
 }
@@ -37,10 +51,11 @@ func New(
 	milestonePublicKeyCount int) *MilestoneManager {
 
 	t := &MilestoneManager{
-		storage:                 dbStorage,
-		syncManager:             syncManager,
-		keyManager:              keyManager,
-		milestonePublicKeyCount: milestonePublicKeyCount,
+		storage:                    dbStorage,
+		syncManager:                syncManager,
+		keyManager:                 keyManager,
+		milestonePublicKeyCount:    milestonePublicKeyCount,
+		unverifiedMilestoneIndexes: make(map[milestone.Index]struct{}),
 
 		Events: &packageEvents{
 			ReceivedValidMilestone: events.NewEvent(storage.MilestoneWithRequestedCaller),
@@ -98,12 +113,69 @@ func (m *MilestoneManager) VerifyMilestone(message *storage.Message) *iotago.Mil
 	}
 
 	if err := ms.VerifySignatures(m.milestonePublicKeyCount, m.keyManager.PublicKeysSetForMilestoneIndex(milestone.Index(ms.Index))); err != nil {
+		m.addUnverifiedMilestoneIndex(milestone.Index(ms.Index))
 		return nil
 	}
 
 	return ms
 }
 
+// addUnverifiedMilestoneIndex remembers the index of a milestone with an invalid signature.
+// A syncing node may receive milestones that are signed with announced keys before the announcement was confirmed.
+// Only indexes up to maxUnverifiedMilestoneIndexDistance above the confirmed milestone index are remembered,
+// milestones further ahead are received again while the node is syncing.
+func (m *MilestoneManager) addUnverifiedMilestoneIndex(msIndex milestone.Index) {
+	cmi := m.syncManager.ConfirmedMilestoneIndex()
+	if msIndex <= cmi || msIndex > cmi+maxUnverifiedMilestoneIndexDistance {
+		return
+	}
+
+	m.unverifiedMilestoneIndexesLock.Lock()
+	defer m.unverifiedMilestoneIndexesLock.Unlock()
+
+	if len(m.unverifiedMilestoneIndexes) >= maxUnverifiedMilestoneIndexDistance {
+		// forget the indexes that were confirmed in the meantime,
+		// the remaining indexes are all within the distance to the confirmed milestone index.
+		for unverifiedMsIndex := range m.unverifiedMilestoneIndexes {
+			if unverifiedMsIndex <= cmi {
+				delete(m.unverifiedMilestoneIndexes, unverifiedMsIndex)
+			}
+		}
+	}
+	m.unverifiedMilestoneIndexes[msIndex] = struct{}{}
+}
+
+// UnverifiedMilestoneIndexes returns the indexes of the received milestones with invalid signatures
+// that are covered by the given key ranges, and forgets them. The milestones should be requested again.
+// Indexes that are already confirmed are dropped.
+func (m *MilestoneManager) UnverifiedMilestoneIndexes(keyRanges []*keymanager.KeyRange) []milestone.Index {
+	cmi := m.syncManager.ConfirmedMilestoneIndex()
+
+	m.unverifiedMilestoneIndexesLock.Lock()
+	defer m.unverifiedMilestoneIndexesLock.Unlock()
+
+	var msIndexes []milestone.Index
+	for msIndex := range m.unverifiedMilestoneIndexes {
+		if msIndex <= cmi {
+			delete(m.unverifiedMilestoneIndexes, msIndex)
+			continue
+		}
+
+		for _, keyRange := range keyRanges {
+			// startIndex == endIndex means the key is valid forever
+			if msIndex >= keyRange.StartIndex && (msIndex <= keyRange.EndIndex || keyRange.StartIndex == keyRange.EndIndex) {
+				msIndexes = append(msIndexes, msIndex)
+				delete(m.unverifiedMilestoneIndexes, msIndex)
+				break
+			}
+		}
+	}
+
+	sort.Slice(msIndexes, func(i, j int) bool { return msIndexes[i] < msIndexes[j] })
+
+	return msIndexes
+}
+
 // StoreMilestone stores the milestone in the storage layer and triggers the ReceivedValidMilestone event.
 func (m *MilestoneManager) StoreMilestone(cachedMessage *storage.CachedMessage, ms *iotago.Milestone, requested bool) {
 	defer cachedMessage.Release(true)
@@ -118,3 +190,31 @@ func (m *MilestoneManager) StoreMilestone(cachedMessage *storage.CachedMessage,
 
 	m.Events.ReceivedValidMilestone.Trigger(cachedMilestone, requested) // milestone pass +1
 }
+
+// ApplyKeyRangeAnnouncement verifies the key range announcement contained in the indexation payload of the given message,
+// which was confirmed by the milestone with the given index, and adds the announced key ranges to the key manager.
+// The newly added key ranges are persisted and returned. Messages without a key range announcement are ignored.
+func (m *MilestoneManager) ApplyKeyRangeAnnouncement(message *storage.Message, msIndex milestone.Index) ([]*keymanager.KeyRange, error) {
+	indexation := message.Indexation()
+	if indexation == nil || string(indexation.Index) != keymanager.KeyRangeAnnouncementIndex {
+		return nil, nil
+	}
+
+	announcement, err := keymanager.KeyRangeAnnouncementFromBytes(indexation.Data)
+	if err != nil {
+		return nil, fmt.Errorf("message %s: %w", message.MessageID().ToHex(), err)
+	}
+
+	if err := m.keyManager.VerifyKeyRangeAnnouncement(announcement, message.NetworkID(), msIndex, m.milestonePublicKeyCount); err != nil {
+		return nil, fmt.Errorf("message %s: %w", message.MessageID().ToHex(), err)
+	}
+
+	keyRanges := m.keyManager.ApplyKeyRangeAnnouncement(announcement)
+	for _, keyRange := range keyRanges {
+		if err := m.storage.StoreKeyRange(keyRange); err != nil {
+			return nil, err
+		}
+	}
+
+	return keyRanges, nil
+}
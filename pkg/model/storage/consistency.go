@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/objectstorage"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+// ConsistencyIssueType is the type of an inconsistency in the database.
+type ConsistencyIssueType string
+
+const (
+	// ConsistencyIssueMessageWithoutMetadata is a message without metadata.
+	// Repair: the message is deleted, it will be requested again if needed.
+	ConsistencyIssueMessageWithoutMetadata ConsistencyIssueType = "messageWithoutMetadata"
+	// ConsistencyIssueMetadataWithoutMessage is a metadata without message.
+	// Repair: the metadata is deleted.
+	ConsistencyIssueMetadataWithoutMessage ConsistencyIssueType = "metadataWithoutMessage"
+	// ConsistencyIssueChildWithoutMessage is a child entry that references a missing child message.
+	// Repair: the child entry is deleted.
+	ConsistencyIssueChildWithoutMessage ConsistencyIssueType = "childWithoutMessage"
+	// ConsistencyIssueChildParentMismatch is a child entry whose child message does not reference the parent.
+	// Repair: the child entry is deleted.
+	ConsistencyIssueChildParentMismatch ConsistencyIssueType = "childParentMismatch"
+	// ConsistencyIssueMissingChild is a parent of a message without the corresponding child entry.
+	// Repair: the child entry is added.
+	ConsistencyIssueMissingChild ConsistencyIssueType = "missingChild"
+	// ConsistencyIssueIndexationWithoutMessage is an indexation entry that references a missing message.
+	// Repair: the indexation entry is deleted.
+	ConsistencyIssueIndexationWithoutMessage ConsistencyIssueType = "indexationWithoutMessage"
+	// ConsistencyIssueIndexationMismatch is an indexation entry whose message does not contain the index.
+	// Repair: the indexation entry is deleted.
+	ConsistencyIssueIndexationMismatch ConsistencyIssueType = "indexationMismatch"
+	// ConsistencyIssueMissingIndexation is a message with an indexation payload without the corresponding indexation entry.
+	// Repair: the indexation entry is added.
+	ConsistencyIssueMissingIndexation ConsistencyIssueType = "missingIndexation"
+	// ConsistencyIssueMilestoneWithoutMessage is a milestone entry that references a missing message.
+	// Repair: the milestone entry is deleted, it will be added again if the milestone message is received.
+	ConsistencyIssueMilestoneWithoutMessage ConsistencyIssueType = "milestoneWithoutMessage"
+	// ConsistencyIssueMilestoneMismatch is a milestone entry whose message does not contain a milestone with the same index.
+	// Repair: the milestone entry is deleted.
+	ConsistencyIssueMilestoneMismatch ConsistencyIssueType = "milestoneMismatch"
+	// ConsistencyIssueMissingMilestone is a message marked as milestone without the corresponding milestone entry.
+	// Repair: the milestone entry is added.
+	ConsistencyIssueMissingMilestone ConsistencyIssueType = "missingMilestone"
+	// ConsistencyIssueBalanceMismatch is an address with a stored balance that does not match the unspent outputs.
+	// Repair: the balance is replaced by the balance computed from the unspent outputs.
+	ConsistencyIssueBalanceMismatch ConsistencyIssueType = "balanceMismatch"
+)
+
+// ConsistencyIssue is an inconsistency found in the database.
+type ConsistencyIssue struct {
+	// The type of the issue.
+	Type ConsistencyIssueType
+	// The description of the affected entries.
+	Description string
+	// Whether the issue was repaired.
+	Repaired bool
+
+	repairFunc func()
+}
+
+// ConsistencyReport is the result of a consistency check of the database.
+type ConsistencyReport struct {
+	// The amount of checked messages.
+	MessagesChecked int
+	// The amount of checked child entries.
+	ChildrenChecked int
+	// The amount of checked indexation entries.
+	IndexationsChecked int
+	// The amount of checked milestone entries.
+	MilestonesChecked int
+	// The found inconsistencies.
+	Issues []*ConsistencyIssue
+	// The time the check took.
+	Duration time.Duration
+}
+
+// IssueCounts returns the amount of issues per type.
+func (r *ConsistencyReport) IssueCounts() map[ConsistencyIssueType]int {
+	counts := make(map[ConsistencyIssueType]int)
+	for _, issue := range r.Issues {
+		counts[issue.Type]++
+	}
+	return counts
+}
+
+// consistencyChecker collects the issues of a single check step and repairs them after the step,
+// so that the database is not modified while it is iterated.
+type consistencyChecker struct {
+	storage *Storage
+	report  *ConsistencyReport
+	issues  []*ConsistencyIssue
+}
+
+func (c *consistencyChecker) addIssue(issueType ConsistencyIssueType, repairFunc func(), format string, args ...interface{}) {
+	c.issues = append(c.issues, &ConsistencyIssue{
+		Type:        issueType,
+		Description: fmt.Sprintf(format, args...),
+		repairFunc:  repairFunc,
+	})
+}
+
+func (c *consistencyChecker) finishStep(repair bool) {
+	defer func() {
+		c.report.Issues = append(c.report.Issues, c.issues...)
+		c.issues = nil
+	}()
+
+	if !repair {
+		return
+	}
+
+	for _, issue := range c.issues {
+		issue.repairFunc()
+		issue.Repaired = true
+	}
+
+	// the next step reads from the persistence layer, so the repairs need to be persisted
+	c.storage.FlushStorages()
+}
+
+// storedMessageOrNil returns a message object without accessing the cache layer.
+func (s *Storage) storedMessageOrNil(messageID hornet.MessageID) *Message {
+	storedMsg := s.messagesStorage.LoadObjectFromStore(messageID)
+	if storedMsg == nil {
+		return nil
+	}
+	return storedMsg.(*Message)
+}
+
+// storedMilestoneOrNil returns a milestone object without accessing the cache layer.
+func (s *Storage) storedMilestoneOrNil(milestoneIndex milestone.Index) *Milestone {
+	storedMilestone := s.milestoneStorage.LoadObjectFromStore(databaseKeyForMilestoneIndex(milestoneIndex))
+	if storedMilestone == nil {
+		return nil
+	}
+	return storedMilestone.(*Milestone)
+}
+
+// CheckConsistency verifies the referential integrity of the database:
+//   - Messages		<=> MessageMetadata
+//   - Children		<=> Parents of the messages
+//   - Indexations	<=> Indexation payloads of the messages
+//   - Milestones	<=> Milestone payloads of the messages
+//   - Balances		<=> Unspent outputs
+//
+// If repair is true, the found issues are repaired without deleting unaffected data.
+// The database must not be in use by a running node.
+func (s *Storage) CheckConsistency(repair bool) (*ConsistencyReport, error) {
+
+	ts := time.Now()
+
+	report := &ConsistencyReport{}
+	checker := &consistencyChecker{storage: s, report: report}
+
+	// messages <=> metadata
+	s.ForEachMessageID(func(messageID hornet.MessageID) bool {
+		report.MessagesChecked++
+
+		if !s.MessageMetadataExistsInStore(messageID) {
+			checker.addIssue(ConsistencyIssueMessageWithoutMetadata, func() {
+				s.DeleteMessage(messageID)
+			}, "message %s", messageID.ToHex())
+		}
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	s.ForEachMessageMetadataMessageID(func(messageID hornet.MessageID) bool {
+		if !s.MessageExistsInStore(messageID) {
+			checker.addIssue(ConsistencyIssueMetadataWithoutMessage, func() {
+				s.DeleteMessageMetadata(messageID)
+			}, "metadata %s", messageID.ToHex())
+		}
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	checker.finishStep(repair)
+
+	// children => parents
+	s.ForEachChild(func(messageID hornet.MessageID, childMessageID hornet.MessageID) bool {
+		report.ChildrenChecked++
+
+		deleteChild := func() {
+			s.DeleteChild(messageID, childMessageID)
+		}
+
+		childMsg := s.storedMessageOrNil(childMessageID)
+		if childMsg == nil {
+			checker.addIssue(ConsistencyIssueChildWithoutMessage, deleteChild, "parent %s, child %s", messageID.ToHex(), childMessageID.ToHex())
+			return true
+		}
+
+		for _, parent := range childMsg.Parents() {
+			if bytes.Equal(parent, messageID) {
+				return true
+			}
+		}
+
+		checker.addIssue(ConsistencyIssueChildParentMismatch, deleteChild, "parent %s, child %s", messageID.ToHex(), childMessageID.ToHex())
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	// indexations => messages
+	s.indexationStorage.ForEachKeyOnly(func(key []byte) bool {
+		report.IndexationsChecked++
+
+		indexationKey := make([]byte, len(key))
+		copy(indexationKey, key)
+
+		index := indexationKey[:IndexationIndexLength]
+		messageID := hornet.MessageIDFromSlice(indexationKey[IndexationIndexLength : IndexationIndexLength+iotago.MessageIDLength])
+
+		deleteIndexation := func() {
+			s.DeleteIndexationByKey(indexationKey)
+		}
+
+		msg := s.storedMessageOrNil(messageID)
+		if msg == nil {
+			checker.addIssue(ConsistencyIssueIndexationWithoutMessage, deleteIndexation, "index %s, message %s", hex.EncodeToString(index), messageID.ToHex())
+			return true
+		}
+
+		if indexation := CheckIfIndexation(msg); indexation == nil || !bytes.Equal(PadIndexationIndex(indexation.Index), index) {
+			checker.addIssue(ConsistencyIssueIndexationMismatch, deleteIndexation, "index %s, message %s", hex.EncodeToString(index), messageID.ToHex())
+		}
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	// milestones => messages
+	s.ForEachMilestoneIndex(func(msIndex milestone.Index) bool {
+		report.MilestonesChecked++
+
+		deleteMilestone := func() {
+			s.DeleteMilestone(msIndex)
+		}
+
+		ms := s.storedMilestoneOrNil(msIndex)
+		if ms == nil {
+			return true
+		}
+
+		msg := s.storedMessageOrNil(ms.MessageID)
+		if msg == nil {
+			checker.addIssue(ConsistencyIssueMilestoneWithoutMessage, deleteMilestone, "milestone %d, message %s", msIndex, ms.MessageID.ToHex())
+			return true
+		}
+
+		if msPayload := msg.Milestone(); msPayload == nil || milestone.Index(msPayload.Index) != msIndex {
+			checker.addIssue(ConsistencyIssueMilestoneMismatch, deleteMilestone, "milestone %d, message %s", msIndex, ms.MessageID.ToHex())
+		}
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	// messages => children, indexations and milestones
+	snapshotInfo := s.SnapshotInfo()
+	s.ForEachMessageID(func(messageID hornet.MessageID) bool {
+		msg := s.storedMessageOrNil(messageID)
+		if msg == nil {
+			return true
+		}
+
+		metadata := s.StoredMetadataOrNil(messageID)
+
+		// messages referenced below the pruning index were kept by the retention filter
+		// and the child entries in their parents were removed during pruning.
+		retained := false
+		if metadata != nil && snapshotInfo != nil {
+			referenced, at := metadata.ReferencedWithIndex()
+			retained = referenced && at <= snapshotInfo.PruningIndex
+		}
+
+		if !retained {
+			for _, parent := range msg.Parents() {
+				parentMessageID := parent
+				if !s.ContainsChild(parentMessageID, messageID, objectstorage.WithReadSkipCache(true)) {
+					checker.addIssue(ConsistencyIssueMissingChild, func() {
+						s.StoreChild(parentMessageID, messageID).Release(true) // child +-0
+					}, "parent %s, child %s", parentMessageID.ToHex(), messageID.ToHex())
+				}
+			}
+		}
+
+		if indexation := CheckIfIndexation(msg); indexation != nil {
+			if !s.indexationStorage.Contains(NewIndexation(indexation.Index, messageID).ObjectStorageKey(), objectstorage.WithReadSkipCache(true)) {
+				checker.addIssue(ConsistencyIssueMissingIndexation, func() {
+					s.StoreIndexation(indexation.Index, messageID).Release(true) // indexation +-0
+				}, "index %s, message %s", hex.EncodeToString(indexation.Index), messageID.ToHex())
+			}
+		}
+
+		if metadata == nil || !metadata.IsMilestone() {
+			return true
+		}
+
+		msPayload := msg.Milestone()
+		if msPayload == nil {
+			return true
+		}
+
+		msIndex := milestone.Index(msPayload.Index)
+		if ms := s.storedMilestoneOrNil(msIndex); ms == nil {
+			checker.addIssue(ConsistencyIssueMissingMilestone, func() {
+				if cachedMilestone, newlyAdded := s.StoreMilestoneIfAbsent(msIndex, messageID, time.Unix(int64(msPayload.Timestamp), 0)); newlyAdded { // milestone +1
+					cachedMilestone.Release(true) // milestone -1
+				}
+			}, "milestone %d, message %s", msIndex, messageID.ToHex())
+		}
+		return true
+	}, objectstorage.WithIteratorSkipCache(true))
+
+	checker.finishStep(repair)
+
+	// balances <=> unspent outputs
+	balanceInconsistencies, err := s.UTXOManager().CheckBalances(repair)
+	if err != nil {
+		return nil, fmt.Errorf("checking balances failed: %w", err)
+	}
+
+	for _, inconsistency := range balanceInconsistencies {
+		report.Issues = append(report.Issues, &ConsistencyIssue{
+			Type:        ConsistencyIssueBalanceMismatch,
+			Description: fmt.Sprintf("address %s, stored balance %d, computed balance %d, stored dust allowance %d, computed dust allowance %d, stored dust outputs %d, computed dust outputs %d", hex.EncodeToString(inconsistency.AddressKey), inconsistency.StoredBalance, inconsistency.ComputedBalance, inconsistency.StoredDustAllowanceBalance, inconsistency.ComputedDustAllowanceBalance, inconsistency.StoredDustOutputCount, inconsistency.ComputedDustOutputCount),
+			Repaired:    repair,
+		})
+	}
+
+	report.Duration = time.Since(ts)
+
+	return report, nil
+}
@@ -0,0 +1,114 @@
+package storage_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func randMessageID() hornet.MessageID {
+	messageID := make(hornet.MessageID, iotago.MessageIDLength)
+	rand.Read(messageID)
+	return messageID
+}
+
+// storeTestMessage stores a message with an indexation payload and the child entries in its parents.
+func storeTestMessage(t *testing.T, s *storage.Storage, parents hornet.MessageIDs, referencedIndex milestone.Index) hornet.MessageID {
+	msg, err := storage.NewMessage(&iotago.Message{
+		NetworkID: 1,
+		Parents:   parents.ToSliceOfArrays(),
+		Payload:   &iotago.Indexation{Index: []byte("consistency"), Data: randMessageID()},
+	}, iotago.DeSeriModeNoValidation)
+	require.NoError(t, err)
+
+	cachedMsg, _ := s.StoreMessageIfAbsent(msg) // msg +1
+	defer cachedMsg.Release(true)               // msg -1
+
+	if referencedIndex != 0 {
+		cachedMsg.Metadata().SetReferenced(true, referencedIndex)
+	}
+
+	for _, parent := range msg.Parents() {
+		s.StoreChild(parent, msg.MessageID()).Release(true) // child +-0
+	}
+	s.StoreIndexation([]byte("consistency"), msg.MessageID()).Release(true) // indexation +-0
+
+	return msg.MessageID()
+}
+
+func newTestStorage(t *testing.T) *storage.Storage {
+	s, err := storage.New(mapdb.NewMapDB())
+	require.NoError(t, err)
+	return s
+}
+
+func TestCheckConsistency(t *testing.T) {
+
+	s := newTestStorage(t)
+
+	parentID := storeTestMessage(t, s, hornet.MessageIDs{randMessageID()}, 0)
+	childID := storeTestMessage(t, s, hornet.MessageIDs{parentID}, 0)
+	s.FlushStorages()
+
+	report, err := s.CheckConsistency(false)
+	require.NoError(t, err)
+	require.Empty(t, report.Issues)
+	require.Equal(t, 2, report.MessagesChecked)
+	require.Equal(t, 2, report.ChildrenChecked)
+	require.Equal(t, 2, report.IndexationsChecked)
+
+	// remove the child entry and add one for an unknown message
+	s.DeleteChild(parentID, childID)
+	s.StoreChild(parentID, randMessageID()).Release(true) // child +-0
+	s.FlushStorages()
+
+	report, err = s.CheckConsistency(false)
+	require.NoError(t, err)
+	require.Equal(t, map[storage.ConsistencyIssueType]int{
+		storage.ConsistencyIssueMissingChild:        1,
+		storage.ConsistencyIssueChildWithoutMessage: 1,
+	}, report.IssueCounts())
+
+	report, err = s.CheckConsistency(true)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	for _, issue := range report.Issues {
+		require.True(t, issue.Repaired)
+	}
+
+	report, err = s.CheckConsistency(false)
+	require.NoError(t, err)
+	require.Empty(t, report.Issues)
+	require.True(t, s.ContainsChild(parentID, childID))
+}
+
+func TestCheckConsistencyRetainedMessages(t *testing.T) {
+
+	s := newTestStorage(t)
+	require.NoError(t, s.SetSnapshotMilestone(1, 20, 20, 10, time.Now()))
+
+	// the parent of the retained message was pruned together with the child entry
+	prunedParentID := randMessageID()
+	retainedID := storeTestMessage(t, s, hornet.MessageIDs{prunedParentID}, 5)
+	s.DeleteChild(prunedParentID, retainedID)
+
+	// messages above the pruning index still need their child entries
+	storeTestMessage(t, s, hornet.MessageIDs{retainedID}, 15)
+	unreferencedID := storeTestMessage(t, s, hornet.MessageIDs{prunedParentID}, 0)
+	s.DeleteChild(prunedParentID, unreferencedID)
+	s.FlushStorages()
+
+	report, err := s.CheckConsistency(false)
+	require.NoError(t, err)
+	require.Equal(t, map[storage.ConsistencyIssueType]int{
+		storage.ConsistencyIssueMissingChild: 1,
+	}, report.IssueCounts())
+}
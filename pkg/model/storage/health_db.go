@@ -41,6 +41,17 @@ func (s *Storage) MarkDatabaseHealthy() error {
 	return nil
 }
 
+// MarkStoreHealthy removes the corrupted flag from the health status of the given store.
+// This is used for checkpoints of a running node, which are consistent but contain the health status of the node.
+func MarkStoreHealthy(store kvstore.KVStore) error {
+
+	healthStore := store.WithRealm([]byte{common.StorePrefixHealth})
+	if err := healthStore.Delete([]byte("dbCorrupted")); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to set database health status")
+	}
+	return healthStore.Flush()
+}
+
 func (s *Storage) IsDatabaseCorrupted() (bool, error) {
 
 	contains, err := s.healthStore.Has([]byte("dbCorrupted"))
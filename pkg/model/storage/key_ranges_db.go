@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/kvstore"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+// KeyRangeConsumer is a function that consumes a key range.
+// Returning false from this function indicates to abort the iteration.
+type KeyRangeConsumer func(keyRange *keymanager.KeyRange) bool
+
+func (s *Storage) configureKeyRangesStore(store kvstore.KVStore) {
+	s.keyRangesStore = store.WithRealm([]byte{common.StorePrefixKeyRanges})
+}
+
+// databaseKeyForKeyRange returns the key of a key range: public key, start index (uint32) and end index (uint32).
+func databaseKeyForKeyRange(keyRange *keymanager.KeyRange) []byte {
+	key := make([]byte, iotago.MilestonePublicKeyLength+iotago.UInt32ByteSize+iotago.UInt32ByteSize)
+	copy(key, keyRange.PublicKey[:])
+	binary.LittleEndian.PutUint32(key[iotago.MilestonePublicKeyLength:], uint32(keyRange.StartIndex))
+	binary.LittleEndian.PutUint32(key[iotago.MilestonePublicKeyLength+iotago.UInt32ByteSize:], uint32(keyRange.EndIndex))
+	return key
+}
+
+func keyRangeFromDatabaseKey(key []byte) (*keymanager.KeyRange, error) {
+	if len(key) != iotago.MilestonePublicKeyLength+iotago.UInt32ByteSize+iotago.UInt32ByteSize {
+		return nil, errors.Errorf("invalid key range key length: %d", len(key))
+	}
+
+	keyRange := &keymanager.KeyRange{
+		StartIndex: milestone.Index(binary.LittleEndian.Uint32(key[iotago.MilestonePublicKeyLength:])),
+		EndIndex:   milestone.Index(binary.LittleEndian.Uint32(key[iotago.MilestonePublicKeyLength+iotago.UInt32ByteSize:])),
+	}
+	copy(keyRange.PublicKey[:], key[:iotago.MilestonePublicKeyLength])
+
+	return keyRange, nil
+}
+
+// StoreKeyRange persists a key range that was announced by the coordinator.
+func (s *Storage) StoreKeyRange(keyRange *keymanager.KeyRange) error {
+
+	if err := s.keyRangesStore.Set(databaseKeyForKeyRange(keyRange), []byte{}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store key range")
+	}
+
+	return s.keyRangesStore.Flush()
+}
+
+// ForEachKeyRange loops over all persisted key ranges.
+func (s *Storage) ForEachKeyRange(consumer KeyRangeConsumer) error {
+
+	var innerErr error
+	if err := s.keyRangesStore.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		keyRange, err := keyRangeFromDatabaseKey(key)
+		if err != nil {
+			innerErr = err
+			return false
+		}
+
+		return consumer(keyRange)
+	}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to iterate key ranges")
+	}
+
+	return innerErr
+}
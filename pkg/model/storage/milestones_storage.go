@@ -25,7 +25,7 @@ func milestoneIndexFromDatabaseKey(key []byte) milestone.Index {
 	return milestone.Index(binary.LittleEndian.Uint32(key))
 }
 
-func milestoneFactory(key []byte, data []byte) (objectstorage.StorableObject, error) {
+func MilestoneFactory(key []byte, data []byte) (objectstorage.StorableObject, error) {
 	return &Milestone{
 		Index:     milestoneIndexFromDatabaseKey(key),
 		MessageID: hornet.MessageIDFromSlice(data[:iotago.MessageIDLength]),
@@ -51,7 +51,7 @@ func (s *Storage) configureMilestoneStorage(store kvstore.KVStore, opts *profile
 
 	s.milestoneStorage = objectstorage.New(
 		store.WithRealm([]byte{common.StorePrefixMilestones}),
-		milestoneFactory,
+		MilestoneFactory,
 		objectstorage.CacheTime(cacheTime),
 		objectstorage.PersistenceEnabled(true),
 		objectstorage.ReleaseExecutorWorkerCount(opts.ReleaseExecutorWorkerCount),
@@ -23,8 +23,9 @@ type Storage struct {
 	store kvstore.KVStore
 
 	// kv storages
-	healthStore   kvstore.KVStore
-	snapshotStore kvstore.KVStore
+	healthStore    kvstore.KVStore
+	snapshotStore  kvstore.KVStore
+	keyRangesStore kvstore.KVStore
 
 	// object storages
 	childrenStorage             *objectstorage.ObjectStorage
@@ -185,6 +186,7 @@ func (s *Storage) configureStorages(store kvstore.KVStore, cachesProfile ...*pro
 	}
 
 	s.configureSnapshotStore(store)
+	s.configureKeyRangesStore(store)
 
 	return nil
 }
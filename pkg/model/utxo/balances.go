@@ -251,3 +251,113 @@ func (u *Manager) storeBalanceForUnspentOutput(unspentOutput *Output, mutations
 	}
 	return u.applyBalanceDiff(balances, mutations)
 }
+
+// BalanceInconsistency is an address with a stored balance that does not match the balance computed from the unspent outputs.
+type BalanceInconsistency struct {
+	// The serialized address.
+	AddressKey []byte
+	// The balance stored in the database.
+	StoredBalance uint64
+	// The balance computed from the unspent outputs.
+	ComputedBalance uint64
+	// The dust allowance balance stored in the database.
+	StoredDustAllowanceBalance uint64
+	// The dust allowance balance computed from the unspent outputs.
+	ComputedDustAllowanceBalance uint64
+	// The dust output count stored in the database.
+	StoredDustOutputCount int64
+	// The dust output count computed from the unspent outputs.
+	ComputedDustOutputCount int64
+}
+
+// CheckBalances compares the stored balances of all addresses with the balances computed from the unspent outputs.
+// If repair is true, the inconsistent balances are replaced by the computed balances.
+func (u *Manager) CheckBalances(repair bool) ([]*BalanceInconsistency, error) {
+
+	u.WriteLockLedger()
+	defer u.WriteUnlockLedger()
+
+	unspentOutputs, err := u.UnspentOutputs(ReadLockLedger(false))
+	if err != nil {
+		return nil, err
+	}
+
+	computedBalances := NewBalanceDiff()
+	if err := computedBalances.Add(unspentOutputs, Spents{}); err != nil {
+		return nil, err
+	}
+
+	var inconsistencies []*BalanceInconsistency
+	var innerErr error
+
+	// check all stored balances against the computed balances
+	if err := u.utxoStorage.IterateKeys([]byte{UTXOStoreKeyPrefixBalances}, func(key kvstore.Key) bool {
+
+		addressKey := byteutils.ConcatBytes(key[1:])
+
+		balance, dustAllowanceBalance, dustOutputCount, err := u.readBalanceForAddress(addressKey)
+		if err != nil {
+			innerErr = err
+			return false
+		}
+
+		computed := &singleBalanceDiff{}
+		if diff, found := computedBalances.balances[string(addressKey)]; found {
+			computed = diff
+			delete(computedBalances.balances, string(addressKey))
+		}
+
+		if int64(balance) != computed.balanceDiff || int64(dustAllowanceBalance) != computed.dustAllowanceBalanceDiff || dustOutputCount != computed.dustOutputCountDiff {
+			inconsistencies = append(inconsistencies, &BalanceInconsistency{
+				AddressKey:                   addressKey,
+				StoredBalance:                balance,
+				ComputedBalance:              uint64(computed.balanceDiff),
+				StoredDustAllowanceBalance:   dustAllowanceBalance,
+				ComputedDustAllowanceBalance: uint64(computed.dustAllowanceBalanceDiff),
+				StoredDustOutputCount:        dustOutputCount,
+				ComputedDustOutputCount:      computed.dustOutputCountDiff,
+			})
+		}
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	// the remaining computed balances have no stored balance
+	for addressMapKey, computed := range computedBalances.balances {
+		if computed.balanceDiff == 0 && computed.dustAllowanceBalanceDiff == 0 && computed.dustOutputCountDiff == 0 {
+			continue
+		}
+
+		inconsistencies = append(inconsistencies, &BalanceInconsistency{
+			AddressKey:                   []byte(addressMapKey),
+			ComputedBalance:              uint64(computed.balanceDiff),
+			ComputedDustAllowanceBalance: uint64(computed.dustAllowanceBalanceDiff),
+			ComputedDustOutputCount:      computed.dustOutputCountDiff,
+		})
+	}
+
+	if !repair || len(inconsistencies) == 0 {
+		return inconsistencies, nil
+	}
+
+	mutations := u.utxoStorage.Batched()
+
+	for _, inconsistency := range inconsistencies {
+		if err := u.storeBalanceForAddress(inconsistency.AddressKey, inconsistency.ComputedBalance, inconsistency.ComputedDustAllowanceBalance, inconsistency.ComputedDustOutputCount, mutations); err != nil {
+			mutations.Cancel()
+			return nil, err
+		}
+	}
+
+	if err := mutations.Commit(); err != nil {
+		return nil, err
+	}
+
+	return inconsistencies, nil
+}
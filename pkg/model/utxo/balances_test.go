@@ -0,0 +1,64 @@
+package utxo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func TestCheckBalances(t *testing.T) {
+
+	utxo := New(mapdb.NewMapDB())
+
+	outputs := Outputs{
+		randomOutput(iotago.OutputSigLockedSingleOutput),
+		randomOutput(iotago.OutputSigLockedSingleOutput),
+		randomOutput(iotago.OutputSigLockedDustAllowanceOutput),
+	}
+
+	require.NoError(t, utxo.ApplyConfirmationWithoutLocking(milestone.Index(756), outputs, Spents{}, nil, nil))
+
+	inconsistencies, err := utxo.CheckBalances(false)
+	require.NoError(t, err)
+	require.Empty(t, inconsistencies)
+
+	// corrupt the balance of the first address and remove the balance of the second address
+	addressKey0, err := outputs[0].Address().Serialize(iotago.DeSeriModeNoValidation)
+	require.NoError(t, err)
+	addressKey1, err := outputs[1].Address().Serialize(iotago.DeSeriModeNoValidation)
+	require.NoError(t, err)
+
+	require.NoError(t, utxo.utxoStorage.Set(byteutils.ConcatBytes([]byte{UTXOStoreKeyPrefixBalances}, addressKey0), bytesFromBalance(outputs[0].Amount()+1, 0, 0)))
+	require.NoError(t, utxo.utxoStorage.Delete(byteutils.ConcatBytes([]byte{UTXOStoreKeyPrefixBalances}, addressKey1)))
+
+	inconsistencies, err = utxo.CheckBalances(true)
+	require.NoError(t, err)
+	require.Len(t, inconsistencies, 2)
+
+	for _, inconsistency := range inconsistencies {
+		switch string(inconsistency.AddressKey) {
+		case string(addressKey0):
+			require.Equal(t, outputs[0].Amount()+1, inconsistency.StoredBalance)
+			require.Equal(t, outputs[0].Amount(), inconsistency.ComputedBalance)
+		case string(addressKey1):
+			require.Equal(t, uint64(0), inconsistency.StoredBalance)
+			require.Equal(t, outputs[1].Amount(), inconsistency.ComputedBalance)
+		default:
+			require.Fail(t, "unexpected inconsistency")
+		}
+	}
+
+	// the balances were repaired
+	inconsistencies, err = utxo.CheckBalances(false)
+	require.NoError(t, err)
+	require.Empty(t, inconsistencies)
+
+	balance, _, err := utxo.AddressBalanceWithoutLocking(outputs[1].Address())
+	require.NoError(t, err)
+	require.Equal(t, outputs[1].Amount(), balance)
+}
@@ -0,0 +1,260 @@
+package pow
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/pow"
+)
+
+const (
+	// WorkerRouteMine is the route of a remote worker to do proof-of-work.
+	// POST the data and the target score, returns the nonce.
+	WorkerRouteMine = "/mine"
+	// WorkerRouteHealth is the route of a remote worker to check its health.
+	// GET the parallelism, the amount of active requests and the limits of the worker.
+	WorkerRouteHealth = "/health"
+
+	// the maximum size of a proof-of-work request body.
+	maxWorkerRequestSize = 2*iotago.MessageBinSerializedMaxSize + 1024
+)
+
+// WorkerMineRequest is the request to do proof-of-work on a remote worker.
+type WorkerMineRequest struct {
+	// The hex encoded data of the message without the nonce.
+	Data string `json:"data"`
+	// The target score of the proof-of-work.
+	TargetScore float64 `json:"targetScore"`
+}
+
+// WorkerMineResponse is the response of a remote worker with the found nonce.
+type WorkerMineResponse struct {
+	// The nonce as a string because JSON numbers can't hold uint64.
+	Nonce string `json:"nonce"`
+}
+
+// WorkerHealthResponse is the response of the health check of a remote worker.
+type WorkerHealthResponse struct {
+	// The amount of parallel workers used for a single request.
+	Parallelism int `json:"parallelism"`
+	// The amount of proof-of-work requests that are currently handled.
+	ActiveRequests int `json:"activeRequests"`
+	// The maximum amount of proof-of-work requests that are handled at the same time.
+	MaxRequests int `json:"maxRequests"`
+	// The maximum target score of a proof-of-work request.
+	MaxTargetScore float64 `json:"maxTargetScore"`
+}
+
+// HTTPWorker is a remote worker that does proof-of-work over HTTP.
+type HTTPWorker struct {
+	url        string
+	authToken  string
+	httpClient *http.Client
+}
+
+// NewHTTPWorker creates a new remote worker for the given base URL.
+// If the auth token is not empty, it is sent as a bearer token with every request.
+func NewHTTPWorker(url string, authToken string) *HTTPWorker {
+	return &HTTPWorker{
+		url:        strings.TrimSuffix(url, "/"),
+		authToken:  authToken,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name returns the URL of the worker.
+func (w *HTTPWorker) Name() string {
+	return w.url
+}
+
+// do sends a request to the worker and decodes the JSON response.
+func (w *HTTPWorker) do(ctx context.Context, method string, route string, reqObj interface{}, resObj interface{}) error {
+
+	var reqData []byte
+	if reqObj != nil {
+		var err error
+		if reqData, err = json.Marshal(reqObj); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, w.url+route, bytes.NewReader(reqData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.authToken)
+	}
+
+	res, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return ErrWorkerBusy
+	default:
+		return fmt.Errorf("unexpected status code %d: %s", res.StatusCode, strings.TrimSpace(string(resData)))
+	}
+
+	return json.Unmarshal(resData, resObj)
+}
+
+// Mine does the proof-of-work on the remote worker.
+func (w *HTTPWorker) Mine(ctx context.Context, data []byte, targetScore float64) (uint64, error) {
+
+	res := &WorkerMineResponse{}
+	if err := w.do(ctx, http.MethodPost, WorkerRouteMine, &WorkerMineRequest{Data: hex.EncodeToString(data), TargetScore: targetScore}, res); err != nil {
+		return 0, err
+	}
+
+	nonce, err := strconv.ParseUint(res.Nonce, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce: %w", err)
+	}
+
+	// the nonce of the worker is verified before it is used
+	nonceBuf := make([]byte, nonceBytes)
+	binary.LittleEndian.PutUint64(nonceBuf, nonce)
+
+	if score := pow.Score(append(append([]byte{}, data...), nonceBuf...)); score < targetScore {
+		return 0, fmt.Errorf("nonce does not hit the target score, got %f, expected %f", score, targetScore)
+	}
+
+	return nonce, nil
+}
+
+// HealthCheck checks whether the remote worker is reachable and supports the given target score.
+func (w *HTTPWorker) HealthCheck(ctx context.Context, targetScore float64) error {
+
+	res := &WorkerHealthResponse{}
+	if err := w.do(ctx, http.MethodGet, WorkerRouteHealth, nil, res); err != nil {
+		return err
+	}
+
+	if res.MaxTargetScore < targetScore {
+		return fmt.Errorf("%w: %0.2f > %0.2f", ErrTargetScoreNotSupported, targetScore, res.MaxTargetScore)
+	}
+
+	return nil
+}
+
+// WorkerServer does proof-of-work for the nodes that use it as a remote worker.
+type WorkerServer struct {
+	parallelism    int
+	maxTargetScore float64
+	authToken      string
+	// a request occupies a slot until it is finished, requests are rejected if all slots are occupied.
+	requestSlots   chan struct{}
+	activeRequests atomic.Int32
+}
+
+// NewWorkerServer creates a new remote worker server.
+// Requests with a target score above the maximum are rejected, as well as requests that exceed the maximum amount of concurrent requests.
+// If the auth token is not empty, the requests have to contain it as a bearer token.
+func NewWorkerServer(parallelism int, maxTargetScore float64, maxRequests int, authToken string) *WorkerServer {
+	return &WorkerServer{
+		parallelism:    parallelism,
+		maxTargetScore: maxTargetScore,
+		authToken:      authToken,
+		requestSlots:   make(chan struct{}, maxRequests),
+	}
+}
+
+// Handler returns the HTTP handler of the worker server.
+func (s *WorkerServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(WorkerRouteMine, utils.BearerTokenHandler(s.authToken, s.handleMine))
+	mux.HandleFunc(WorkerRouteHealth, utils.BearerTokenHandler(s.authToken, s.handleHealth))
+	return mux
+}
+
+func (s *WorkerServer) handleMine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := &WorkerMineRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWorkerRequestSize)).Decode(req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid data: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(data) == 0 || len(data) > iotago.MessageBinSerializedMaxSize {
+		http.Error(w, "invalid data length", http.StatusBadRequest)
+		return
+	}
+
+	if req.TargetScore <= 0 || req.TargetScore > s.maxTargetScore {
+		http.Error(w, fmt.Sprintf("invalid target score, must be between 0 and %0.2f", s.maxTargetScore), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case s.requestSlots <- struct{}{}:
+		defer func() { <-s.requestSlots }()
+	default:
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	s.activeRequests.Inc()
+	defer s.activeRequests.Dec()
+
+	// the proof-of-work is aborted if the node cancels the request
+	nonce, err := pow.New(s.parallelism).Mine(r.Context(), data, req.TargetScore)
+	if err != nil {
+		if errors.Is(err, pow.ErrCancelled) {
+			http.Error(w, "canceled", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.WriteJSONResponse(w, &WorkerMineResponse{Nonce: strconv.FormatUint(nonce, 10)})
+}
+
+func (s *WorkerServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	utils.WriteJSONResponse(w, &WorkerHealthResponse{
+		Parallelism:    s.parallelism,
+		ActiveRequests: int(s.activeRequests.Load()),
+		MaxRequests:    cap(s.requestSlots),
+		MaxTargetScore: s.maxTargetScore,
+	})
+}
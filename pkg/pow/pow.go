@@ -26,8 +26,8 @@ type proofOfWorkFunc func(ctx context.Context, data []byte, parallelism ...int)
 // RefreshTipsFunc refreshes tips of the message if PoW takes longer than a configured duration.
 type RefreshTipsFunc = func() (tips hornet.MessageIDs, err error)
 
-// Handler handles PoW requests of the node and tunnels them to powsrv.io
-// or uses local PoW if no API key was specified or the connection failed.
+// Handler handles PoW requests of the node and dispatches them to a pool of remote workers or tunnels them to powsrv.io.
+// It uses local PoW if no remote workers or API key were specified or the remote PoW failed.
 type Handler struct {
 	log *logger.Logger
 
@@ -41,13 +41,16 @@ type Handler struct {
 	powsrvConnected    bool
 	powsrvErrorHandled bool
 
+	workerPool *WorkerPool
+
 	localPoWFunc proofOfWorkFunc
 	localPoWType string
 }
 
 // New creates a new PoW handler instance.
 // If the given powsrv.io API key is not empty, powsrv.io will be used to do proof-of-work.
-func New(log *logger.Logger, targetScore float64, refreshTipsInterval time.Duration, powsrvAPIKey string, powsrvInitCooldown time.Duration) *Handler {
+// If the given worker pool is not nil, the proof-of-work is dispatched to its healthy workers first.
+func New(log *logger.Logger, targetScore float64, refreshTipsInterval time.Duration, powsrvAPIKey string, powsrvInitCooldown time.Duration, workerPool *WorkerPool) *Handler {
 
 	localPoWType := "local"
 	localPoWFunc := func(ctx context.Context, data []byte, parallelism ...int) (uint64, error) {
@@ -74,6 +77,7 @@ func New(log *logger.Logger, targetScore float64, refreshTipsInterval time.Durat
 		powsrvLastInit:      time.Time{},
 		powsrvConnected:     false,
 		powsrvErrorHandled:  false,
+		workerPool:          workerPool,
 		localPoWFunc:        localPoWFunc,
 		localPoWType:        localPoWType,
 	}
@@ -151,8 +155,17 @@ func (h *Handler) disconnectPowsrv() {
 	h.powsrvClient.Close()
 }
 
+// WorkerPool returns the pool of remote workers, it is nil if no remote workers were specified.
+func (h *Handler) WorkerPool() *WorkerPool {
+	return h.workerPool
+}
+
 // PoWType returns the fastest available PoW type which gets used for PoW requests
 func (h *Handler) PoWType() string {
+	if h.workerPool != nil && h.workerPool.HasHealthyWorkers() {
+		return "remote"
+	}
+
 	h.powsrvLock.RLock()
 	defer h.powsrvLock.RUnlock()
 
@@ -165,6 +178,7 @@ func (h *Handler) PoWType() string {
 
 // DoPoW does the proof-of-work required to hit the target score configured on this Handler.
 // The given iota.Message's nonce is automatically updated.
+// If remote workers are healthy, the proof-of-work is dispatched to them.
 // If a powsrv.io key was provided, then powsrv.io is used to commence the proof-of-work.
 func (h *Handler) DoPoW(msg *iotago.Message, shutdownSignal <-chan struct{}, parallelism int, refreshTipsFunc ...RefreshTipsFunc) (err error) {
 
@@ -188,7 +202,8 @@ func (h *Handler) DoPoW(msg *iotago.Message, shutdownSignal <-chan struct{}, par
 		return err
 	}
 
-	if h.connectPowsrv() {
+	// the remote workers are preferred over powsrv.io
+	if (h.workerPool == nil || !h.workerPool.HasHealthyWorkers()) && h.connectPowsrv() {
 		// connected to powsrv.io
 		// powsrv.io only accepts targetScore <= 4000
 		if h.targetScore <= 4000 {
@@ -216,17 +231,23 @@ func (h *Handler) DoPoW(msg *iotago.Message, shutdownSignal <-chan struct{}, par
 
 	refreshTips := len(refreshTipsFunc) > 0 && refreshTipsFunc[0] != nil
 
-	// Fall back to local PoW
+	// Use the remote workers if available and fall back to local PoW
 	for {
 		powCtx, powCancel := context.WithCancel(context.Background())
 		if refreshTips {
 			powCtx, powCancel = context.WithTimeout(powCtx, h.refreshTipsInterval)
 		}
 
-		nonce, err := h.localPoWFunc(powCtx, powData, parallelism)
+		nonce, err := h.mine(powCtx, powData, parallelism, shutdownSignal)
 		powCancel()
 
 		if err != nil {
+			select {
+			case <-shutdownSignal:
+				return common.ErrOperationAborted
+			default:
+			}
+
 			if errors.Is(err, pow.ErrCancelled) && refreshTips {
 				// context was canceled and tips can be refreshed
 				tips, err := refreshTipsFunc[0]()
@@ -251,6 +272,40 @@ func (h *Handler) DoPoW(msg *iotago.Message, shutdownSignal <-chan struct{}, par
 	}
 }
 
+// mine does the proof-of-work with the remote workers if available, otherwise or if they fail it uses local PoW.
+// The proof-of-work is aborted if the shutdown signal is received.
+func (h *Handler) mine(ctx context.Context, powData []byte, parallelism int, shutdownSignal <-chan struct{}) (uint64, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-shutdownSignal:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if h.workerPool != nil && h.workerPool.HasHealthyWorkers() {
+		nonce, err := h.workerPool.Mine(ctx, powData, h.targetScore)
+		if err == nil {
+			return nonce, nil
+		}
+
+		if ctx.Err() != nil {
+			// the context was canceled, the tips may be refreshed by the caller
+			return 0, pow.ErrCancelled
+		}
+
+		if h.log != nil {
+			h.log.Warnf("Error during PoW via remote workers, falling back to local PoW: %s", err)
+		}
+	}
+
+	return h.localPoWFunc(ctx, powData, parallelism)
+}
+
 // Close closes the PoW handler
 func (h *Handler) Close() {
 	h.powsrvLock.Lock()
@@ -0,0 +1,221 @@
+package pow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+
+	"github.com/iotaledger/hive.go/logger"
+)
+
+var (
+	// ErrNoHealthyWorkers is returned if none of the remote workers is healthy.
+	ErrNoHealthyWorkers = errors.New("no healthy remote PoW workers")
+	// ErrWorkerBusy is returned if a remote worker handles its maximum amount of requests already.
+	ErrWorkerBusy = errors.New("remote PoW worker is busy")
+	// ErrAllWorkersBusy is returned if all healthy remote workers are busy.
+	ErrAllWorkersBusy = errors.New("all healthy remote PoW workers are busy")
+	// ErrTargetScoreNotSupported is returned if the target score is above the maximum target score of a remote worker.
+	ErrTargetScoreNotSupported = errors.New("target score is not supported by the remote PoW worker")
+)
+
+// RemoteWorker is a remote backend that does proof-of-work for the node.
+type RemoteWorker interface {
+	// Name returns the name of the worker used in logs.
+	Name() string
+	// Mine does the proof-of-work for the given data and returns the nonce that hits the target score.
+	// The proof-of-work is aborted if the context is canceled.
+	Mine(ctx context.Context, data []byte, targetScore float64) (uint64, error)
+	// HealthCheck returns an error if the worker is not able to do proof-of-work for the given target score.
+	HealthCheck(ctx context.Context, targetScore float64) error
+}
+
+// poolWorker is a remote worker of the pool with its current state.
+type poolWorker struct {
+	RemoteWorker
+
+	healthy  atomic.Bool
+	inFlight atomic.Int32
+}
+
+// WorkerPoolStatus is the status of a remote worker of the pool.
+type WorkerPoolStatus struct {
+	// The name of the worker.
+	Name string
+	// Whether the worker passed the last health check.
+	Healthy bool
+	// The amount of proof-of-work requests that are currently handled by the worker.
+	InFlight int
+}
+
+// WorkerPool dispatches proof-of-work requests to a pool of remote workers.
+// The requests are balanced between the healthy workers by the amount of requests in flight.
+// Workers that fail a request are not used until they pass the next health check, busy workers are skipped for the request.
+type WorkerPool struct {
+	sync.Mutex
+
+	log *logger.Logger
+
+	workers []*poolWorker
+	// the target score the workers are checked for.
+	targetScore float64
+	// the timeout for a single proof-of-work request.
+	requestTimeout time.Duration
+	// the timeout for a single health check.
+	healthCheckTimeout time.Duration
+	// the index of the worker the next search for a free worker starts at.
+	nextWorkerIndex int
+}
+
+// NewWorkerPool creates a new pool of remote workers.
+// All workers are considered unhealthy until they passed the first health check.
+func NewWorkerPool(log *logger.Logger, workers []RemoteWorker, targetScore float64, requestTimeout time.Duration, healthCheckTimeout time.Duration) *WorkerPool {
+
+	poolWorkers := make([]*poolWorker, len(workers))
+	for i, worker := range workers {
+		poolWorkers[i] = &poolWorker{RemoteWorker: worker}
+	}
+
+	return &WorkerPool{
+		log:                log,
+		workers:            poolWorkers,
+		targetScore:        targetScore,
+		requestTimeout:     requestTimeout,
+		healthCheckTimeout: healthCheckTimeout,
+	}
+}
+
+// HealthCheck checks the health of all workers of the pool.
+func (p *WorkerPool) HealthCheck() {
+
+	var wg sync.WaitGroup
+	for _, worker := range p.workers {
+		wg.Add(1)
+
+		go func(worker *poolWorker) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), p.healthCheckTimeout)
+			defer cancel()
+
+			err := worker.HealthCheck(ctx, p.targetScore)
+			healthy := err == nil
+
+			if worker.healthy.Swap(healthy) != healthy && p.log != nil {
+				if healthy {
+					p.log.Infof("Remote PoW worker %s is healthy", worker.Name())
+				} else {
+					p.log.Warnf("Remote PoW worker %s is unhealthy: %s", worker.Name(), err)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
+
+// HasHealthyWorkers returns whether at least one worker of the pool is healthy.
+func (p *WorkerPool) HasHealthyWorkers() bool {
+	for _, worker := range p.workers {
+		if worker.healthy.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the status of all workers of the pool.
+func (p *WorkerPool) Status() []*WorkerPoolStatus {
+
+	status := make([]*WorkerPoolStatus, len(p.workers))
+	for i, worker := range p.workers {
+		status[i] = &WorkerPoolStatus{
+			Name:     worker.Name(),
+			Healthy:  worker.healthy.Load(),
+			InFlight: int(worker.inFlight.Load()),
+		}
+	}
+
+	return status
+}
+
+// selectWorker returns the healthy worker with the least requests in flight that was not tried yet.
+// the requests in flight of the selected worker are increased.
+func (p *WorkerPool) selectWorker(tried map[*poolWorker]struct{}) *poolWorker {
+	p.Lock()
+	defer p.Unlock()
+
+	var selected *poolWorker
+	for i := 0; i < len(p.workers); i++ {
+		worker := p.workers[(p.nextWorkerIndex+i)%len(p.workers)]
+
+		if !worker.healthy.Load() {
+			continue
+		}
+
+		if _, alreadyTried := tried[worker]; alreadyTried {
+			continue
+		}
+
+		if selected == nil || worker.inFlight.Load() < selected.inFlight.Load() {
+			selected = worker
+		}
+	}
+
+	if selected == nil {
+		return nil
+	}
+
+	// start the next search at another worker to distribute the requests between idle workers
+	p.nextWorkerIndex = (p.nextWorkerIndex + 1) % len(p.workers)
+	selected.inFlight.Inc()
+
+	return selected
+}
+
+// Mine dispatches the proof-of-work to the healthy workers of the pool.
+// If a worker fails, it is marked as unhealthy and the next worker is tried. Busy workers are skipped, but stay healthy.
+func (p *WorkerPool) Mine(ctx context.Context, data []byte, targetScore float64) (uint64, error) {
+
+	busy := false
+	tried := make(map[*poolWorker]struct{})
+	for {
+		worker := p.selectWorker(tried)
+		if worker == nil {
+			if busy {
+				return 0, ErrAllWorkersBusy
+			}
+			return 0, ErrNoHealthyWorkers
+		}
+		tried[worker] = struct{}{}
+
+		nonce, err := func() (uint64, error) {
+			defer worker.inFlight.Dec()
+
+			reqCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+			defer cancel()
+
+			return worker.Mine(reqCtx, data, targetScore)
+		}()
+		if err == nil {
+			return nonce, nil
+		}
+
+		if ctx.Err() != nil {
+			// the proof-of-work was canceled by the caller, this is not the fault of the worker
+			return 0, ctx.Err()
+		}
+
+		if errors.Is(err, ErrWorkerBusy) {
+			busy = true
+			continue
+		}
+
+		worker.healthy.Store(false)
+		if p.log != nil {
+			p.log.Warnf("Error during PoW via remote worker %s: %s", worker.Name(), err)
+		}
+	}
+}
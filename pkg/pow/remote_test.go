@@ -0,0 +1,193 @@
+package pow_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/pow"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const targetScore = 100
+
+type fakeWorker struct {
+	name     string
+	healthy  bool
+	mineErr  error
+	mineCnt  int
+	blocking bool
+	busy     bool
+}
+
+func (w *fakeWorker) Name() string {
+	return w.name
+}
+
+func (w *fakeWorker) Mine(ctx context.Context, _ []byte, _ float64) (uint64, error) {
+	w.mineCnt++
+	if w.busy {
+		return 0, pow.ErrWorkerBusy
+	}
+	if w.blocking {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	return 42, w.mineErr
+}
+
+func (w *fakeWorker) HealthCheck(_ context.Context, _ float64) error {
+	if !w.healthy {
+		return errors.New("unhealthy")
+	}
+	return nil
+}
+
+func TestWorkerPool(t *testing.T) {
+
+	failing := &fakeWorker{name: "failing", healthy: true, mineErr: errors.New("failed")}
+	working := &fakeWorker{name: "working", healthy: true}
+	offline := &fakeWorker{name: "offline", healthy: false}
+
+	pool := pow.NewWorkerPool(nil, []pow.RemoteWorker{failing, working, offline}, targetScore, time.Second, time.Second)
+
+	// workers are unhealthy until the first health check
+	require.False(t, pool.HasHealthyWorkers())
+	_, err := pool.Mine(context.Background(), []byte{1}, targetScore)
+	require.ErrorIs(t, err, pow.ErrNoHealthyWorkers)
+
+	pool.HealthCheck()
+	require.True(t, pool.HasHealthyWorkers())
+
+	// the failing worker is skipped and marked as unhealthy
+	nonce, err := pool.Mine(context.Background(), []byte{1}, targetScore)
+	require.NoError(t, err)
+	require.EqualValues(t, 42, nonce)
+	require.Equal(t, 1, failing.mineCnt)
+	require.Equal(t, 1, working.mineCnt)
+	require.Equal(t, 0, offline.mineCnt)
+
+	status := pool.Status()
+	require.False(t, status[0].Healthy)
+	require.True(t, status[1].Healthy)
+	require.False(t, status[2].Healthy)
+
+	// a canceled request does not mark the worker as unhealthy
+	working.blocking = true
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.Mine(ctx, []byte{1}, targetScore)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, pool.Status()[1].Healthy)
+}
+
+func TestWorkerPoolBusyWorkers(t *testing.T) {
+
+	busy := &fakeWorker{name: "busy", healthy: true, busy: true}
+	working := &fakeWorker{name: "working", healthy: true}
+
+	pool := pow.NewWorkerPool(nil, []pow.RemoteWorker{busy, working}, targetScore, time.Second, time.Second)
+	pool.HealthCheck()
+
+	// a busy worker is skipped, but stays healthy
+	for i := 0; i < 2; i++ {
+		nonce, err := pool.Mine(context.Background(), []byte{1}, targetScore)
+		require.NoError(t, err)
+		require.EqualValues(t, 42, nonce)
+	}
+	require.Equal(t, 2, working.mineCnt)
+	require.True(t, pool.Status()[0].Healthy)
+
+	working.busy = true
+	_, err := pool.Mine(context.Background(), []byte{1}, targetScore)
+	require.ErrorIs(t, err, pow.ErrAllWorkersBusy)
+	require.True(t, pool.HasHealthyWorkers())
+}
+
+func TestHTTPWorker(t *testing.T) {
+
+	server := httptest.NewServer(pow.NewWorkerServer(2, 1000, 2, "secret").Handler())
+	defer server.Close()
+
+	data := make([]byte, 100)
+
+	unauthorized := pow.NewHTTPWorker(server.URL, "")
+	require.Error(t, unauthorized.HealthCheck(context.Background(), targetScore))
+
+	worker := pow.NewHTTPWorker(server.URL+"/", "secret")
+	require.NoError(t, worker.HealthCheck(context.Background(), targetScore))
+
+	// the worker is unhealthy for nodes with a target score above its maximum
+	require.ErrorIs(t, worker.HealthCheck(context.Background(), 2000), pow.ErrTargetScoreNotSupported)
+
+	_, err := worker.Mine(context.Background(), data, targetScore)
+	require.NoError(t, err)
+
+	// the target score is limited
+	_, err = worker.Mine(context.Background(), data, 2000)
+	require.Error(t, err)
+
+	// the handler uses the remote worker
+	pool := pow.NewWorkerPool(nil, []pow.RemoteWorker{worker}, targetScore, 10*time.Second, time.Second)
+	pool.HealthCheck()
+
+	handler := pow.New(nil, targetScore, 5*time.Second, "", 30*time.Second, pool)
+	require.Equal(t, "remote", handler.PoWType())
+
+	msg := &iotago.Message{NetworkID: 1, Parents: iotago.MessageIDs{{}}}
+	require.NoError(t, handler.DoPoW(msg, nil, 1))
+
+	score, err := msg.POW()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score, float64(targetScore))
+}
+
+func TestHTTPWorkerMaxRequests(t *testing.T) {
+
+	server := httptest.NewServer(pow.NewWorkerServer(1, 1e12, 1, "").Handler())
+	defer server.Close()
+
+	data := make([]byte, 100)
+	worker := pow.NewHTTPWorker(server.URL, "")
+
+	// occupy the only request slot with a request that doesn't finish
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, _ = worker.Mine(ctx, data, 1e12)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := worker.Mine(context.Background(), data, targetScore)
+		return errors.Is(err, pow.ErrWorkerBusy)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the slot is freed after the request was canceled
+	cancel()
+	require.Eventually(t, func() bool {
+		_, err := worker.Mine(context.Background(), data, targetScore)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDoPoWShutdown(t *testing.T) {
+
+	blocking := &fakeWorker{name: "blocking", healthy: true, blocking: true}
+	pool := pow.NewWorkerPool(nil, []pow.RemoteWorker{blocking}, targetScore, time.Minute, time.Second)
+	pool.HealthCheck()
+
+	handler := pow.New(nil, targetScore, 5*time.Second, "", 30*time.Second, pool)
+
+	shutdownSignal := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(shutdownSignal) })
+
+	// the remote proof-of-work is canceled on shutdown instead of running until the request timeout
+	msg := &iotago.Message{NetworkID: 1, Parents: iotago.MessageIDs{{}}}
+	require.ErrorIs(t, handler.DoPoW(msg, shutdownSignal, 1), common.ErrOperationAborted)
+	require.Equal(t, 1, blocking.mineCnt)
+}
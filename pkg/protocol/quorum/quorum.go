@@ -0,0 +1,336 @@
+package quorum
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/logger"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// ProtocolIDTemplate is the template of the protocol ID used to request white flag computations from quorum nodes.
+	ProtocolIDTemplate = "/iota-coo-quorum/%d/1.0.0"
+
+	// the maximum amount of parents of a milestone.
+	maxParentsCount = iotago.MaxParentsInAMessage
+	// the maximum length of an error message in a response.
+	maxErrorMessageLength = 1024
+
+	responseStatusOK    byte = 0
+	responseStatusError byte = 1
+
+	defaultStreamTimeout = 10 * time.Second
+)
+
+var (
+	// ErrPeerNotConnected is returned if the quorum node is not connected.
+	ErrPeerNotConnected = errors.New("quorum node is not connected")
+	// ErrPeerNotAllowed is returned if a peer that is not allowed requests a white flag computation.
+	ErrPeerNotAllowed = errors.New("peer is not allowed to request white flag computations")
+	// ErrInvalidRequest is returned if a request could not be parsed.
+	ErrInvalidRequest = errors.New("invalid white flag request")
+	// ErrInvalidResponse is returned if a response could not be parsed.
+	ErrInvalidResponse = errors.New("invalid white flag response")
+)
+
+// MerkleTreeHash is the merkle tree root hash of the white flag computation.
+type MerkleTreeHash = [iotago.MilestoneInclusionMerkleProofLength]byte
+
+// WhiteFlagComputeFunc computes the white flag confirmation for the cone of the given parents.
+type WhiteFlagComputeFunc func(ctx context.Context, index milestone.Index, parents hornet.MessageIDs) (MerkleTreeHash, error)
+
+// ProtocolID returns the protocol ID of the quorum protocol for the given network.
+func ProtocolID(networkID uint64) protocol.ID {
+	return protocol.ID(fmt.Sprintf(ProtocolIDTemplate, networkID))
+}
+
+// Options define options for the quorum client and server.
+type Options struct {
+	// the logger used to log events.
+	logger *logger.Logger
+	// the timeout for a complete request including the white flag computation.
+	streamTimeout time.Duration
+}
+
+// applies the given Option.
+func (o *Options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// Option is a function setting an option of the quorum client or server.
+type Option func(opts *Options)
+
+// WithLogger enables logging.
+func WithLogger(logger *logger.Logger) Option {
+	return func(opts *Options) {
+		opts.logger = logger
+	}
+}
+
+// WithStreamTimeout defines the timeout for a complete request including the white flag computation.
+func WithStreamTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.streamTimeout = timeout
+	}
+}
+
+func newOptions(opts ...Option) *Options {
+	options := &Options{streamTimeout: defaultStreamTimeout}
+	options.apply(opts...)
+	return options
+}
+
+// Client requests white flag computations from quorum nodes that are already connected.
+type Client struct {
+	host     host.Host
+	protocol protocol.ID
+	opts     *Options
+}
+
+// NewClient creates a new Client.
+func NewClient(host host.Host, protocol protocol.ID, opts ...Option) *Client {
+	return &Client{
+		host:     host,
+		protocol: protocol,
+		opts:     newOptions(opts...),
+	}
+}
+
+// RequestWhiteFlag requests the white flag computation for the cone of the given parents from the quorum node.
+// No new connections are established, the quorum node has to be a connected peer.
+func (c *Client) RequestWhiteFlag(ctx context.Context, peerID peer.ID, index milestone.Index, parents hornet.MessageIDs) (MerkleTreeHash, error) {
+	var merkleTreeHash MerkleTreeHash
+
+	if c.host.Network().Connectedness(peerID) != network.Connected {
+		return merkleTreeHash, fmt.Errorf("%w: %s", ErrPeerNotConnected, peerID)
+	}
+
+	request, err := serializeRequest(index, parents)
+	if err != nil {
+		return merkleTreeHash, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.streamTimeout)
+	defer cancel()
+
+	stream, err := c.host.NewStream(network.WithNoDial(ctx, "quorum request"), peerID, c.protocol)
+	if err != nil {
+		return merkleTreeHash, fmt.Errorf("unable to create quorum stream to %s: %w", peerID, err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+
+	if _, err := stream.Write(request); err != nil {
+		_ = stream.Reset()
+		return merkleTreeHash, fmt.Errorf("unable to send quorum request to %s: %w", peerID, err)
+	}
+
+	return readResponse(bufio.NewReader(stream))
+}
+
+// Server answers white flag computation requests of the allowed peers.
+type Server struct {
+	host         host.Host
+	protocol     protocol.ID
+	computeFunc  WhiteFlagComputeFunc
+	allowedPeers map[peer.ID]struct{}
+	opts         *Options
+
+	// white flag computations are done one after another.
+	computeLock sync.Mutex
+}
+
+// NewServer creates a new Server that answers the requests of the allowed peers.
+func NewServer(host host.Host, protocol protocol.ID, computeFunc WhiteFlagComputeFunc, allowedPeers []peer.ID, opts ...Option) *Server {
+
+	allowed := make(map[peer.ID]struct{}, len(allowedPeers))
+	for _, peerID := range allowedPeers {
+		allowed[peerID] = struct{}{}
+	}
+
+	return &Server{
+		host:         host,
+		protocol:     protocol,
+		computeFunc:  computeFunc,
+		allowedPeers: allowed,
+		opts:         newOptions(opts...),
+	}
+}
+
+// Start registers the stream handler of the quorum protocol.
+func (s *Server) Start() {
+	s.host.SetStreamHandler(s.protocol, s.handleStream)
+}
+
+// Stop removes the stream handler of the quorum protocol.
+func (s *Server) Stop() {
+	s.host.RemoveStreamHandler(s.protocol)
+}
+
+func (s *Server) handleStream(stream network.Stream) {
+	defer func() { _ = stream.Close() }()
+
+	peerID := stream.Conn().RemotePeer()
+	if _, allowed := s.allowedPeers[peerID]; !allowed {
+		s.logWarnf("rejected quorum request: %s: %s", ErrPeerNotAllowed, peerID)
+		_ = stream.Reset()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.streamTimeout)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+
+	index, parents, err := readRequest(bufio.NewReader(stream))
+	if err != nil {
+		s.logWarnf("invalid quorum request from %s: %s", peerID, err)
+		_ = stream.Reset()
+		return
+	}
+
+	merkleTreeHash, err := s.compute(ctx, index, parents)
+	if err != nil {
+		s.logWarnf("quorum request from %s failed: %s", peerID, err)
+	}
+
+	if _, err := stream.Write(serializeResponse(merkleTreeHash, err)); err != nil {
+		s.logWarnf("unable to send quorum response to %s: %s", peerID, err)
+		_ = stream.Reset()
+	}
+}
+
+func (s *Server) compute(ctx context.Context, index milestone.Index, parents hornet.MessageIDs) (MerkleTreeHash, error) {
+	s.computeLock.Lock()
+	defer s.computeLock.Unlock()
+
+	return s.computeFunc(ctx, index, parents)
+}
+
+func (s *Server) logWarnf(template string, args ...interface{}) {
+	if s.opts.logger != nil {
+		s.opts.logger.Warnf(template, args...)
+	}
+}
+
+// serializeRequest serializes a request: milestone index (uint32), parents count (byte), parents.
+func serializeRequest(index milestone.Index, parents hornet.MessageIDs) ([]byte, error) {
+
+	if len(parents) < 1 || len(parents) > maxParentsCount {
+		return nil, fmt.Errorf("%w: invalid parents count %d", ErrInvalidRequest, len(parents))
+	}
+
+	request := make([]byte, iotago.UInt32ByteSize+iotago.OneByte, iotago.UInt32ByteSize+iotago.OneByte+len(parents)*iotago.MessageIDLength)
+	binary.LittleEndian.PutUint32(request, uint32(index))
+	request[iotago.UInt32ByteSize] = byte(len(parents))
+
+	for _, parent := range parents {
+		if len(parent) != iotago.MessageIDLength {
+			return nil, fmt.Errorf("%w: invalid parent length %d", ErrInvalidRequest, len(parent))
+		}
+		request = append(request, parent...)
+	}
+
+	return request, nil
+}
+
+func readRequest(reader io.Reader) (milestone.Index, hornet.MessageIDs, error) {
+
+	header := make([]byte, iotago.UInt32ByteSize+iotago.OneByte)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+	}
+
+	index := milestone.Index(binary.LittleEndian.Uint32(header))
+	parentsCount := int(header[iotago.UInt32ByteSize])
+	if parentsCount < 1 || parentsCount > maxParentsCount {
+		return 0, nil, fmt.Errorf("%w: invalid parents count %d", ErrInvalidRequest, parentsCount)
+	}
+
+	parents := make(hornet.MessageIDs, parentsCount)
+	for i := range parents {
+		parents[i] = make(hornet.MessageID, iotago.MessageIDLength)
+		if _, err := io.ReadFull(reader, parents[i]); err != nil {
+			return 0, nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+		}
+	}
+
+	return index, parents, nil
+}
+
+// serializeResponse serializes a response: status (byte), merkle tree hash or error message length (uint16) and error message.
+func serializeResponse(merkleTreeHash MerkleTreeHash, err error) []byte {
+
+	if err == nil {
+		return append([]byte{responseStatusOK}, merkleTreeHash[:]...)
+	}
+
+	errMessage := []byte(err.Error())
+	if len(errMessage) > maxErrorMessageLength {
+		errMessage = errMessage[:maxErrorMessageLength]
+	}
+
+	response := make([]byte, iotago.OneByte+iotago.UInt16ByteSize, iotago.OneByte+iotago.UInt16ByteSize+len(errMessage))
+	response[0] = responseStatusError
+	binary.LittleEndian.PutUint16(response[iotago.OneByte:], uint16(len(errMessage)))
+
+	return append(response, errMessage...)
+}
+
+func readResponse(reader io.Reader) (MerkleTreeHash, error) {
+	var merkleTreeHash MerkleTreeHash
+
+	status := make([]byte, iotago.OneByte)
+	if _, err := io.ReadFull(reader, status); err != nil {
+		return merkleTreeHash, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+
+	switch status[0] {
+	case responseStatusOK:
+		if _, err := io.ReadFull(reader, merkleTreeHash[:]); err != nil {
+			return merkleTreeHash, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+		}
+		return merkleTreeHash, nil
+
+	case responseStatusError:
+		length := make([]byte, iotago.UInt16ByteSize)
+		if _, err := io.ReadFull(reader, length); err != nil {
+			return merkleTreeHash, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+		}
+
+		errMessageLength := int(binary.LittleEndian.Uint16(length))
+		if errMessageLength > maxErrorMessageLength {
+			return merkleTreeHash, fmt.Errorf("%w: error message too long", ErrInvalidResponse)
+		}
+
+		errMessage := make([]byte, errMessageLength)
+		if _, err := io.ReadFull(reader, errMessage); err != nil {
+			return merkleTreeHash, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+		}
+		return merkleTreeHash, fmt.Errorf("quorum node failed to compute white flag: %s", string(errMessage))
+
+	default:
+		return merkleTreeHash, fmt.Errorf("%w: unknown status %d", ErrInvalidResponse, status[0])
+	}
+}
@@ -0,0 +1,93 @@
+package quorum_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/protocol/quorum"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+var errNotSolid = errors.New("parents not solid")
+
+func randMessageID() hornet.MessageID {
+	messageID := make(hornet.MessageID, iotago.MessageIDLength)
+	rand.Read(messageID)
+	return messageID
+}
+
+func newHost(ctx context.Context, t *testing.T) host.Host {
+	// we use Ed25519 because otherwise it takes longer as the default is RSA
+	sk, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+
+	h, err := libp2p.New(ctx, libp2p.Identity(sk), libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	require.NoError(t, err)
+
+	return h
+}
+
+func TestWhiteFlagRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	protocolID := quorum.ProtocolID(1337)
+
+	coordinatorHost := newHost(ctx, t)
+	quorumHost := newHost(ctx, t)
+	otherHost := newHost(ctx, t)
+
+	var expectedMerkleTreeHash quorum.MerkleTreeHash
+	copy(expectedMerkleTreeHash[:], randMessageID())
+
+	server := quorum.NewServer(quorumHost, protocolID, func(_ context.Context, index milestone.Index, parents hornet.MessageIDs) (quorum.MerkleTreeHash, error) {
+		if index > 10 {
+			return quorum.MerkleTreeHash{}, errNotSolid
+		}
+		require.Len(t, parents, 2)
+		return expectedMerkleTreeHash, nil
+	}, []peer.ID{coordinatorHost.ID()})
+	server.Start()
+	defer server.Stop()
+
+	coordinatorClient := quorum.NewClient(coordinatorHost, protocolID, quorum.WithStreamTimeout(5*time.Second))
+	otherClient := quorum.NewClient(otherHost, protocolID, quorum.WithStreamTimeout(5*time.Second))
+
+	parents := hornet.MessageIDs{randMessageID(), randMessageID()}
+
+	// no new connections are established by the client
+	_, err := coordinatorClient.RequestWhiteFlag(ctx, quorumHost.ID(), 5, parents)
+	require.ErrorIs(t, err, quorum.ErrPeerNotConnected)
+
+	quorumAddrInfo := peer.AddrInfo{ID: quorumHost.ID(), Addrs: quorumHost.Addrs()}
+	require.NoError(t, coordinatorHost.Connect(ctx, quorumAddrInfo))
+	require.NoError(t, otherHost.Connect(ctx, quorumAddrInfo))
+
+	merkleTreeHash, err := coordinatorClient.RequestWhiteFlag(ctx, quorumHost.ID(), 5, parents)
+	require.NoError(t, err)
+	require.Equal(t, expectedMerkleTreeHash, merkleTreeHash)
+
+	// errors of the computation are returned to the client
+	_, err = coordinatorClient.RequestWhiteFlag(ctx, quorumHost.ID(), 11, parents)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errNotSolid.Error())
+
+	// invalid requests are not sent
+	_, err = coordinatorClient.RequestWhiteFlag(ctx, quorumHost.ID(), 5, hornet.MessageIDs{})
+	require.ErrorIs(t, err, quorum.ErrInvalidRequest)
+
+	// peers that are not allowed don't get a response
+	_, err = otherClient.RequestWhiteFlag(ctx, quorumHost.ID(), 5, parents)
+	require.Error(t, err)
+}
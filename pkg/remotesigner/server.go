@@ -0,0 +1,223 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+	"github.com/iotaledger/iota.go/v2/remotesigner"
+)
+
+var (
+	// ErrMilestoneIndexAlreadySigned is returned if a different milestone essence for an already signed milestone index should be signed.
+	ErrMilestoneIndexAlreadySigned = errors.New("a different milestone essence was already signed for this milestone index")
+	// ErrMilestoneIndexTooOld is returned if the milestone index is older than the latest signed milestone index.
+	ErrMilestoneIndexTooOld = errors.New("milestone index is older than the latest signed milestone index")
+	// ErrUnknownPublicKey is returned if the signer has no private key for a requested public key.
+	ErrUnknownPublicKey = errors.New("no private key for public key")
+	// ErrInvalidMilestoneEssence is returned if the milestone essence is too short to contain a milestone index.
+	ErrInvalidMilestoneEssence = errors.New("invalid milestone essence")
+	// ErrResigningNotAllowed is returned if signing a different milestone essence is allowed for an index that can't be signed again.
+	ErrResigningNotAllowed = errors.New("signing a different milestone essence is not allowed for this milestone index")
+)
+
+// SignerState is the state of the remote signer that is used for the replay protection.
+type SignerState struct {
+	// The latest milestone index that was signed.
+	LatestMilestoneIndex milestone.Index `json:"latestMilestoneIndex"`
+	// The hex encoded SHA-256 hash of the latest milestone essence that was signed.
+	LatestMilestoneEssenceHash string `json:"latestMilestoneEssenceHash"`
+	// The latest milestone index a different milestone essence was signed for by an override of the operator.
+	ResignedMilestoneIndex milestone.Index `json:"resignedMilestoneIndex,omitempty"`
+}
+
+// Server is a remote signer that signs milestone essences with its private keys.
+// It signs at most one milestone essence per milestone index and never signs milestone indexes older
+// than the latest signed milestone index. The state is persisted before the signatures are returned,
+// so the protection is kept across restarts of the signer.
+type Server struct {
+	remotesigner.UnimplementedSignatureDispatcherServer
+
+	privateKeys   map[iotago.MilestonePublicKey]ed25519.PrivateKey
+	stateFilePath string
+	state         *SignerState
+	// whether a different milestone essence may be signed once for the latest signed milestone index.
+	resigningAllowed bool
+	stateLock        sync.Mutex
+}
+
+// NewServer creates a new remote signer with the given private keys.
+// The state file is created if it does not exist.
+func NewServer(privateKeys []ed25519.PrivateKey, stateFilePath string) (*Server, error) {
+	if len(privateKeys) == 0 {
+		return nil, errors.New("no private keys given")
+	}
+
+	keys := make(map[iotago.MilestonePublicKey]ed25519.PrivateKey, len(privateKeys))
+	for _, privateKey := range privateKeys {
+		if len(privateKey) != ed25519.PrivateKeySize {
+			return nil, errors.New("wrong private key length")
+		}
+
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], privateKey.Public().(ed25519.PublicKey))
+		keys[pubKey] = privateKey
+	}
+
+	state := &SignerState{}
+	if _, err := os.Stat(stateFilePath); err == nil {
+		if err := utils.ReadJSONFromFile(stateFilePath, state); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to check state file (%s): %w", stateFilePath, err)
+	}
+
+	return &Server{
+		privateKeys:   keys,
+		stateFilePath: stateFilePath,
+		state:         state,
+	}, nil
+}
+
+// State returns a copy of the state of the signer.
+func (s *Server) State() SignerState {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	return *s.state
+}
+
+// AllowResigning allows to sign a different milestone essence for the latest signed milestone index once.
+// This is needed if the coordinator crashed after the milestone was signed but before it stored its state,
+// because it creates a different milestone essence for the same index after the restart.
+// The operator has to make sure that the previously signed milestone was never sent to the network,
+// otherwise two conflicting milestones with the same index are valid. It is only allowed once per milestone index.
+func (s *Server) AllowResigning(index milestone.Index) error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if s.state.LatestMilestoneEssenceHash == "" || index != s.state.LatestMilestoneIndex {
+		return fmt.Errorf("%w: %d, latest signed milestone index: %d", ErrResigningNotAllowed, index, s.state.LatestMilestoneIndex)
+	}
+
+	if index == s.state.ResignedMilestoneIndex {
+		return fmt.Errorf("%w: %d was already signed again", ErrResigningNotAllowed, index)
+	}
+
+	s.resigningAllowed = true
+
+	return nil
+}
+
+// checkReplay checks that the milestone essence may be signed and persists the new state.
+func (s *Server) checkReplay(msEssence []byte) error {
+
+	// the milestone index is the first field in the milestone essence
+	if len(msEssence) < iotago.UInt32ByteSize {
+		return ErrInvalidMilestoneEssence
+	}
+	index := milestone.Index(binary.LittleEndian.Uint32(msEssence[:iotago.UInt32ByteSize]))
+	essenceHash := sha256.Sum256(msEssence)
+
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	newState := &SignerState{
+		LatestMilestoneIndex:       index,
+		LatestMilestoneEssenceHash: hex.EncodeToString(essenceHash[:]),
+		ResignedMilestoneIndex:     s.state.ResignedMilestoneIndex,
+	}
+
+	if s.state.LatestMilestoneEssenceHash != "" {
+		switch {
+		case index < s.state.LatestMilestoneIndex:
+			return fmt.Errorf("%w: %d < %d", ErrMilestoneIndexTooOld, index, s.state.LatestMilestoneIndex)
+
+		case index == s.state.LatestMilestoneIndex:
+			latestEssenceHash, err := hex.DecodeString(s.state.LatestMilestoneEssenceHash)
+			if err != nil {
+				return fmt.Errorf("invalid essence hash in state file: %w", err)
+			}
+
+			if bytes.Equal(latestEssenceHash, essenceHash[:]) {
+				// the same milestone essence is signed again (e.g. the coordinator retries after a failure)
+				return nil
+			}
+
+			if !s.resigningAllowed {
+				return fmt.Errorf("%w: %d", ErrMilestoneIndexAlreadySigned, index)
+			}
+
+			// the override of the operator is used up
+			newState.ResignedMilestoneIndex = index
+		}
+	}
+
+	if err := utils.WriteJSONToFile(s.stateFilePath, newState, 0660); err != nil {
+		return fmt.Errorf("failed to update signer state file: %w", err)
+	}
+	s.state = newState
+	s.resigningAllowed = false
+
+	return nil
+}
+
+// SignMilestone signs the milestone essence with the private keys of the requested public keys.
+func (s *Server) SignMilestone(_ context.Context, request *remotesigner.SignMilestoneRequest) (*remotesigner.SignMilestoneResponse, error) {
+
+	privateKeys := make([]ed25519.PrivateKey, len(request.GetPubKeys()))
+	for i, pubKeyBytes := range request.GetPubKeys() {
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], pubKeyBytes)
+
+		privateKey, exists := s.privateKeys[pubKey]
+		if !exists || len(pubKeyBytes) != ed25519.PublicKeySize {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %s", ErrUnknownPublicKey, hex.EncodeToString(pubKeyBytes))
+		}
+		privateKeys[i] = privateKey
+	}
+
+	if err := s.checkReplay(request.GetMsEssence()); err != nil {
+		if errors.Is(err, ErrMilestoneIndexTooOld) || errors.Is(err, ErrMilestoneIndexAlreadySigned) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, ErrInvalidMilestoneEssence) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	signatures := make([][]byte, len(privateKeys))
+	for i, privateKey := range privateKeys {
+		signatures[i] = ed25519.Sign(privateKey, request.GetMsEssence())
+	}
+
+	return &remotesigner.SignMilestoneResponse{
+		Signatures: signatures,
+	}, nil
+}
+
+// NewGRPCServer creates a gRPC server that serves the remote signer via mutual TLS.
+func NewGRPCServer(server *Server, tlsConfig *tls.Config) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	remotesigner.RegisterSignatureDispatcherServer(grpcServer, server)
+
+	return grpcServer
+}
@@ -0,0 +1,163 @@
+package remotesigner_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/remotesigner"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+	iotagoremotesigner "github.com/iotaledger/iota.go/v2/remotesigner"
+)
+
+// writeSelfSignedCertificate creates a self-signed certificate and returns the paths to the certificate and key files.
+func writeSelfSignedCertificate(t *testing.T, dir string, name string) (string, string) {
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certPath, keyPath
+}
+
+func certificatePin(t *testing.T, certPath string) remotesigner.CertificatePin {
+	pin, err := remotesigner.CertificatePinFromFile(certPath)
+	require.NoError(t, err)
+	return pin
+}
+
+func milestoneEssence(index uint32, data string) []byte {
+	essence := make([]byte, iotago.UInt32ByteSize)
+	binary.LittleEndian.PutUint32(essence, index)
+	return append(essence, []byte(data)...)
+}
+
+// signDirectly signs the milestone essence without a gRPC connection.
+func signDirectly(server *remotesigner.Server, pubKey ed25519.PublicKey, essence []byte) error {
+	_, err := server.SignMilestone(context.Background(), &iotagoremotesigner.SignMilestoneRequest{
+		PubKeys:   [][]byte{pubKey},
+		MsEssence: essence,
+	})
+	return err
+}
+
+func TestRemoteSigner(t *testing.T) {
+
+	dir := t.TempDir()
+	serverCertPath, serverKeyPath := writeSelfSignedCertificate(t, dir, "signer")
+	clientCertPath, clientKeyPath := writeSelfSignedCertificate(t, dir, "coordinator")
+	otherCertPath, otherKeyPath := writeSelfSignedCertificate(t, dir, "other")
+
+	pubKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	stateFilePath := filepath.Join(dir, "signer.state")
+	server, err := remotesigner.NewServer([]ed25519.PrivateKey{privateKey}, stateFilePath)
+	require.NoError(t, err)
+
+	serverTLSConfig, err := remotesigner.NewServerTLSConfig(serverCertPath, serverKeyPath, []remotesigner.CertificatePin{certificatePin(t, clientCertPath)})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := remotesigner.NewGRPCServer(server, serverTLSConfig)
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	clientTLSConfig, err := remotesigner.NewClientTLSConfig(clientCertPath, clientKeyPath, []remotesigner.CertificatePin{certificatePin(t, serverCertPath)})
+	require.NoError(t, err)
+
+	var milestonePubKey iotago.MilestonePublicKey
+	copy(milestonePubKey[:], pubKey)
+	pubKeys := []iotago.MilestonePublicKey{milestonePubKey}
+
+	signingFunc := remotesigner.Ed25519MilestoneSigner(listener.Addr().String(), clientTLSConfig)
+
+	essence := milestoneEssence(10, "milestone 10")
+	signatures, err := signingFunc(pubKeys, essence)
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	require.True(t, ed25519.Verify(pubKey, essence, signatures[0][:]))
+
+	// the same essence can be signed again (retries)
+	_, err = signingFunc(pubKeys, essence)
+	require.NoError(t, err)
+
+	// a different essence for the same milestone index is rejected
+	_, err = signingFunc(pubKeys, milestoneEssence(10, "conflicting milestone 10"))
+	require.Error(t, err)
+
+	// older milestone indexes are rejected
+	_, err = signingFunc(pubKeys, milestoneEssence(9, "milestone 9"))
+	require.Error(t, err)
+
+	_, err = signingFunc(pubKeys, milestoneEssence(11, "milestone 11"))
+	require.NoError(t, err)
+
+	// unknown public keys are rejected
+	_, err = signingFunc([]iotago.MilestonePublicKey{{}}, milestoneEssence(12, "milestone 12"))
+	require.Error(t, err)
+
+	// the state is persisted
+	restartedServer, err := remotesigner.NewServer([]ed25519.PrivateKey{privateKey}, stateFilePath)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, restartedServer.State().LatestMilestoneIndex)
+
+	// the coordinator crashed after milestone 11 was signed and creates a different essence after the restart
+	require.ErrorIs(t, restartedServer.AllowResigning(10), remotesigner.ErrResigningNotAllowed)
+	require.NoError(t, restartedServer.AllowResigning(11))
+	require.NoError(t, signDirectly(restartedServer, pubKey, milestoneEssence(11, "milestone 11 after restart")))
+	require.Error(t, signDirectly(restartedServer, pubKey, milestoneEssence(11, "another milestone 11")))
+	require.EqualValues(t, 11, restartedServer.State().ResignedMilestoneIndex)
+
+	// the override is only allowed once per milestone index
+	require.ErrorIs(t, restartedServer.AllowResigning(11), remotesigner.ErrResigningNotAllowed)
+
+	// clients without a pinned certificate are rejected
+	otherTLSConfig, err := remotesigner.NewClientTLSConfig(otherCertPath, otherKeyPath, []remotesigner.CertificatePin{certificatePin(t, serverCertPath)})
+	require.NoError(t, err)
+	_, err = remotesigner.Ed25519MilestoneSigner(listener.Addr().String(), otherTLSConfig)(pubKeys, milestoneEssence(12, "milestone 12"))
+	require.Error(t, err)
+
+	// servers without a pinned certificate are rejected
+	wrongPinTLSConfig, err := remotesigner.NewClientTLSConfig(clientCertPath, clientKeyPath, []remotesigner.CertificatePin{certificatePin(t, otherCertPath)})
+	require.NoError(t, err)
+	_, err = remotesigner.Ed25519MilestoneSigner(listener.Addr().String(), wrongPinTLSConfig)(pubKeys, milestoneEssence(12, "milestone 12"))
+	require.Error(t, err)
+
+	require.EqualValues(t, 11, server.State().LatestMilestoneIndex)
+}
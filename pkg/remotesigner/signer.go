@@ -0,0 +1,70 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/remotesigner"
+)
+
+// Ed25519MilestoneSigner is a function which uses a remote signer via mutual TLS
+// to produce signatures for the milestone essence data.
+func Ed25519MilestoneSigner(remoteEndpoint string, tlsConfig *tls.Config) iotago.MilestoneSigningFunc {
+	return Ed25519MilestoneSignerWithTimeout(remoteEndpoint, tlsConfig, 0)
+}
+
+// Ed25519MilestoneSignerWithTimeout is the same as Ed25519MilestoneSigner,
+// but the request to the remote signer fails if it was not answered within the timeout.
+// A timeout of zero disables the timeout.
+func Ed25519MilestoneSignerWithTimeout(remoteEndpoint string, tlsConfig *tls.Config, timeout time.Duration) iotago.MilestoneSigningFunc {
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		pubKeysUnbound := make([][]byte, len(pubKeys))
+		for i := range pubKeys {
+			pubKeysUnbound[i] = make([]byte, len(pubKeys[i]))
+			copy(pubKeysUnbound[i], pubKeys[i][:])
+		}
+
+		conn, err := grpc.Dial(remoteEndpoint, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = conn.Close() }()
+
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		client := remotesigner.NewSignatureDispatcherClient(conn)
+		response, err := client.SignMilestone(ctx, &remotesigner.SignMilestoneRequest{
+			PubKeys:   pubKeysUnbound,
+			MsEssence: msEssence,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sigs := response.GetSignatures()
+		if len(sigs) != len(pubKeys) {
+			return nil, fmt.Errorf("%w: remote did not provide the correct count of signatures", iotago.ErrMilestoneProducedSignaturesCountMismatch)
+		}
+
+		sigs64 := make([]iotago.MilestoneSignature, len(sigs))
+		for i := range sigs {
+			if len(sigs[i]) != len(sigs64[i]) {
+				return nil, fmt.Errorf("remote provided a signature with wrong length: %d", len(sigs[i]))
+			}
+			copy(sigs64[i][:], sigs[i])
+		}
+
+		return sigs64, nil
+	}
+}
@@ -0,0 +1,137 @@
+package remotesigner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoPinnedCertificates is returned if no pinned certificates are given.
+	ErrNoPinnedCertificates = errors.New("no pinned certificates given")
+	// ErrCertificateNotPinned is returned if the certificate of the peer does not match any of the pinned certificates.
+	ErrCertificateNotPinned = errors.New("certificate of the peer is not pinned")
+)
+
+// CertificatePin is the SHA-256 hash of a DER encoded certificate.
+type CertificatePin [sha256.Size]byte
+
+// String returns the hex representation of the pin.
+func (p CertificatePin) String() string {
+	return hex.EncodeToString(p[:])
+}
+
+// CertificatePinFromDER returns the pin of the given DER encoded certificate.
+func CertificatePinFromDER(der []byte) CertificatePin {
+	return sha256.Sum256(der)
+}
+
+// CertificatePinFromFile returns the pin of the first certificate in the given PEM file.
+func CertificatePinFromFile(certFilePath string) (CertificatePin, error) {
+	pemData, err := ioutil.ReadFile(certFilePath)
+	if err != nil {
+		return CertificatePin{}, fmt.Errorf("unable to read certificate file: %w", err)
+	}
+
+	block, _ := pem.Decode(pemData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return CertificatePin{}, fmt.Errorf("no PEM encoded certificate found in %s", certFilePath)
+	}
+
+	return CertificatePinFromDER(block.Bytes), nil
+}
+
+// ParseCertificatePins parses the hex representations of certificate pins.
+func ParseCertificatePins(pins []string) ([]CertificatePin, error) {
+	if len(pins) == 0 {
+		return nil, ErrNoPinnedCertificates
+	}
+
+	result := make([]CertificatePin, len(pins))
+	for i, pin := range pins {
+		pinBytes, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate pin %s: %w", pin, err)
+		}
+		if len(pinBytes) != sha256.Size {
+			return nil, fmt.Errorf("invalid certificate pin %s: wrong length", pin)
+		}
+		copy(result[i][:], pinBytes)
+	}
+
+	return result, nil
+}
+
+// verifyPinnedCertificate returns a function that checks that the leaf certificate of the peer matches one of the pins.
+// The pins replace the verification of the certificate chain, so self-signed certificates can be used.
+func verifyPinnedCertificate(pins []CertificatePin) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return ErrCertificateNotPinned
+		}
+
+		leaf, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("unable to parse certificate of the peer: %w", err)
+		}
+
+		pin := CertificatePinFromDER(leaf.Raw)
+		for i := range pins {
+			if bytes.Equal(pins[i][:], pin[:]) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %s", ErrCertificateNotPinned, pin)
+	}
+}
+
+// NewClientTLSConfig creates the TLS config for the connection to the remote signer.
+// The client authenticates itself with the given certificate and only accepts servers with a pinned certificate.
+func NewClientTLSConfig(certFilePath string, keyFilePath string, serverCertPins []CertificatePin) (*tls.Config, error) {
+	if len(serverCertPins) == 0 {
+		return nil, ErrNoPinnedCertificates
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load client certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+		// the default verification is replaced by the check of the pinned certificates
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyPinnedCertificate(serverCertPins),
+	}, nil
+}
+
+// NewServerTLSConfig creates the TLS config of the remote signer.
+// The server requires a client certificate and only accepts clients with a pinned certificate.
+func NewServerTLSConfig(certFilePath string, keyFilePath string, clientCertPins []CertificatePin) (*tls.Config, error) {
+	if len(clientCertPins) == 0 {
+		return nil, ErrNoPinnedCertificates
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load server certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+		// the default verification is replaced by the check of the pinned certificates
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: verifyPinnedCertificate(clientCertPins),
+	}, nil
+}
@@ -32,6 +32,7 @@ const (
 	PriorityFaucet  // depends on PriorityPoWHandler
 	PriorityStatusReport
 	PriorityMigrator
+	PriorityQuorum      // depends on PriorityFlushToDatabase
 	PriorityCoordinator // depends on PriorityPoWHandler
 	PriorityUpdateCheck
 	PriorityPrometheus
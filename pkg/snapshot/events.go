@@ -1,6 +1,7 @@
 package snapshot
 
 import (
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/iotaledger/hive.go/events"
 )
 
@@ -14,9 +15,15 @@ func PruningMetricsCaller(handler interface{}, params ...interface{}) {
 	handler.(func(metrics *PruningMetrics))(params[0].(*PruningMetrics))
 }
 
+// PruningStartedCaller is used to signal the milestone range of a started pruning run.
+func PruningStartedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(pruningIndex milestone.Index, targetIndex milestone.Index))(params[0].(milestone.Index), params[1].(milestone.Index))
+}
+
 type Events struct {
 	SnapshotMilestoneIndexChanged *events.Event
 	SnapshotMetricsUpdated        *events.Event
+	PruningStarted                *events.Event
 	PruningMilestoneIndexChanged  *events.Event
 	PruningMetricsUpdated         *events.Event
 }
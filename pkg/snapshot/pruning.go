@@ -92,7 +92,7 @@ func (s *SnapshotManager) pruneUnreferencedMessages(targetIndex milestone.Index)
 		messageIDsToDeleteMap[messageIDMapKey] = struct{}{}
 	}
 
-	msgCountDeleted = s.pruneMessages(messageIDsToDeleteMap)
+	msgCountDeleted = s.pruneMessages(messageIDsToDeleteMap, make(map[string]struct{}))
 	s.storage.DeleteUnreferencedMessages(targetIndex)
 
 	return msgCountDeleted, len(messageIDsToDeleteMap)
@@ -101,6 +101,18 @@ func (s *SnapshotManager) pruneUnreferencedMessages(targetIndex milestone.Index)
 // pruneMilestone prunes the milestone metadata and the ledger diffs from the database for the given milestone
 func (s *SnapshotManager) pruneMilestone(milestoneIndex milestone.Index, receiptMigratedAtIndex ...uint32) error {
 
+	if s.coldStorage != nil {
+		// move the milestone to the cold storage before it gets deleted
+		cachedMs := s.storage.CachedMilestoneOrNil(milestoneIndex) // milestone +1
+		if cachedMs != nil {
+			err := s.coldStorage.StoreMilestone(cachedMs.Milestone())
+			cachedMs.Release(true) // milestone -1
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := s.utxoManager.PruneMilestoneIndexWithoutLocking(milestoneIndex, s.pruneReceipts, receiptMigratedAtIndex...); err != nil {
 		return err
 	}
@@ -110,11 +122,18 @@ func (s *SnapshotManager) pruneMilestone(milestoneIndex milestone.Index, receipt
 	return nil
 }
 
-// pruneMessages removes all the associated data of the given message IDs from the database
-func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{}) int {
+// pruneMessages removes all the associated data of the given message IDs from the database and returns the amount of deleted messages.
+// Referenced messages that match the retention filter are kept together with their metadata and indexation,
+// they are added to the retained message IDs and skipped if they were already retained before.
+func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{}, retainedMessageIDs map[string]struct{}) int {
 
+	msgCountDeleted := 0
 	for messageIDToDelete := range messageIDsToDeleteMap {
 
+		if _, retained := retainedMessageIDs[messageIDToDelete]; retained {
+			continue
+		}
+
 		msgID := hornet.MessageIDFromMapKey(messageIDToDelete)
 
 		cachedMsg := s.storage.CachedMessageOrNil(msgID) // msg +1
@@ -122,6 +141,27 @@ func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{
 			continue
 		}
 
+		if s.retentionFilter != nil && cachedMsg.Metadata().IsReferenced() && s.retentionFilter.Retain(cachedMsg.Message()) {
+			cachedMsg.ConsumeMessage(func(msg *storage.Message) { // msg -1
+				// Delete the reference in the parents, the children relations below the pruning index are not walked anymore
+				for _, parent := range msg.Parents() {
+					s.storage.DeleteChild(parent, msgID)
+				}
+			})
+			retainedMessageIDs[messageIDToDelete] = struct{}{}
+			continue
+		}
+
+		if s.coldStorage != nil {
+			// move the message to the cold storage before it gets deleted
+			if err := s.coldStorage.StoreMessage(cachedMsg.Message(), cachedMsg.Metadata()); err != nil {
+				// keep the message in the database, otherwise the data would be lost
+				s.log.Warnf("Moving message (%s) to cold storage failed! %s", msgID.ToHex(), err)
+				cachedMsg.Release(true) // msg -1
+				continue
+			}
+		}
+
 		cachedMsg.ConsumeMessage(func(msg *storage.Message) { // msg -1
 			// Delete the reference in the parents
 			for _, parent := range msg.Parents() {
@@ -140,28 +180,16 @@ func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{
 		})
 
 		s.storage.DeleteMessage(msgID)
+		msgCountDeleted++
 	}
 
-	return len(messageIDsToDeleteMap)
+	return msgCountDeleted
 }
 
-func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal <-chan struct{}) (milestone.Index, error) {
-
-	if err := utils.ReturnErrIfCtxDone(s.shutdownCtx, common.ErrOperationAborted); err != nil {
-		// do not prune the database if the node was shut down
-		return 0, common.ErrOperationAborted
-	}
+// checkPruningTargetIndex checks the given pruning target index against the snapshot info
+// and returns the highest index that can be pruned safely.
+func (s *SnapshotManager) checkPruningTargetIndex(targetIndex milestone.Index, snapshotInfo *storage.SnapshotInfo) (milestone.Index, error) {
 
-	if s.database.CompactionRunning() {
-		return 0, ErrDatabaseCompactionRunning
-	}
-
-	snapshotInfo := s.storage.SnapshotInfo()
-	if snapshotInfo == nil {
-		s.log.Panic("No snapshotInfo found!")
-	}
-
-	//lint:ignore SA5011 nil pointer is already checked before with a panic
 	if snapshotInfo.SnapshotIndex < s.solidEntryPointCheckThresholdPast+s.additionalPruningThreshold+1 {
 		// Not enough history
 		return 0, errors.Wrapf(ErrNotEnoughHistory, "minimum index: %d, target index: %d", s.solidEntryPointCheckThresholdPast+s.additionalPruningThreshold+1, targetIndex)
@@ -182,12 +210,45 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 		return 0, errors.Wrapf(ErrNotEnoughHistory, "minimum index: %d, target index: %d", snapshotInfo.EntryPointIndex+s.additionalPruningThreshold+1, targetIndex)
 	}
 
+	return targetIndex, nil
+}
+
+// PruningProgressFunc is called with the milestone range of a pruning run and the milestone index that was pruned last.
+type PruningProgressFunc func(startIndex milestone.Index, currentIndex milestone.Index, targetIndex milestone.Index)
+
+func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal <-chan struct{}, progressFunc PruningProgressFunc) (milestone.Index, error) {
+
+	if err := utils.ReturnErrIfCtxDone(s.shutdownCtx, common.ErrOperationAborted); err != nil {
+		// do not prune the database if the node was shut down
+		return 0, common.ErrOperationAborted
+	}
+
+	if s.database.CompactionRunning() {
+		return 0, ErrDatabaseCompactionRunning
+	}
+
+	snapshotInfo := s.storage.SnapshotInfo()
+	if snapshotInfo == nil {
+		s.log.Panic("No snapshotInfo found!")
+	}
+
+	targetIndex, err := s.checkPruningTargetIndex(targetIndex, snapshotInfo)
+	if err != nil {
+		return 0, err
+	}
+
 	s.setIsPruning(true)
 	defer s.setIsPruning(false)
 
+	startIndex := snapshotInfo.PruningIndex
+	s.Events.PruningStarted.Trigger(startIndex, targetIndex)
+	if progressFunc != nil {
+		progressFunc(startIndex, startIndex, targetIndex)
+	}
+
 	// calculate solid entry points for the new end of the tangle history
 	var solidEntryPoints []*storage.SolidEntryPoint
-	err := s.forEachSolidEntryPoint(targetIndex, abortSignal, func(sep *storage.SolidEntryPoint) bool {
+	err = s.forEachSolidEntryPoint(targetIndex, abortSignal, func(sep *storage.SolidEntryPoint) bool {
 		solidEntryPoints = append(solidEntryPoints, sep)
 		return true
 	})
@@ -207,6 +268,7 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 
 	// we have to set the new solid entry point index.
 	// this way we can cleanly prune even if the pruning was aborted last time
+	//lint:ignore SA5011 nil pointer is already checked before with a panic
 	snapshotInfo.EntryPointIndex = targetIndex
 	if err = s.storage.SetSnapshotInfo(snapshotInfo); err != nil {
 		s.log.Panic(err)
@@ -215,6 +277,9 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 	// unreferenced msgs have to be pruned for PruningIndex as well, since this could be CMI at startup of the node
 	s.pruneUnreferencedMessages(snapshotInfo.PruningIndex)
 
+	// retained messages stay in the database and would be walked again in the cones of the following milestones
+	retainedMessageIDs := make(map[string]struct{})
+
 	// Iterate through all milestones that have to be pruned
 	for milestoneIndex := snapshotInfo.PruningIndex + 1; milestoneIndex <= targetIndex; milestoneIndex++ {
 		select {
@@ -244,6 +309,10 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 			// Caution: condition func is not in DFS order
 			func(cachedMsgMeta *storage.CachedMetadata) (bool, error) { // msg +1
 				defer cachedMsgMeta.Release(true) // msg -1
+				if _, retained := retainedMessageIDs[cachedMsgMeta.Metadata().MessageID().ToMapKey()]; retained {
+					// the retained message and its past cone were already handled with an older milestone
+					return false, nil
+				}
 				// everything that was referenced by that milestone can be pruned (even messages of older milestones)
 				return true, nil
 			},
@@ -290,7 +359,7 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 		cachedMsMsg.Release(true) // milestone msg -1
 
 		msgCountChecked += len(messageIDsToDeleteMap)
-		txCountDeleted += s.pruneMessages(messageIDsToDeleteMap)
+		txCountDeleted += s.pruneMessages(messageIDsToDeleteMap, retainedMessageIDs)
 		timePruneMessages := time.Now()
 
 		snapshotInfo.PruningIndex = milestoneIndex
@@ -302,6 +371,9 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 		s.log.Infof("Pruning milestone (%d) took %v. Pruned %d/%d messages. ", milestoneIndex, time.Since(timeStart).Truncate(time.Millisecond), txCountDeleted, msgCountChecked)
 
 		s.Events.PruningMilestoneIndexChanged.Trigger(milestoneIndex)
+		if progressFunc != nil {
+			progressFunc(startIndex, milestoneIndex, targetIndex)
+		}
 		timePruningMilestoneIndexChanged := time.Now()
 
 		s.Events.PruningMetricsUpdated.Trigger(&PruningMetrics{
@@ -326,40 +398,70 @@ func (s *SnapshotManager) pruneDatabase(targetIndex milestone.Index, abortSignal
 	}
 	s.storage.WriteUnlockSolidEntryPoints()
 
-	s.database.RunGarbageCollection()
+	if s.coldStorage != nil {
+		if err = s.coldStorage.Flush(); err != nil {
+			s.log.Warnf("Flushing cold storage failed! %s", err)
+		}
+	}
 
 	return targetIndex, nil
 }
 
-func (s *SnapshotManager) PruneDatabaseByDepth(depth milestone.Index) (milestone.Index, error) {
-	s.snapshotLock.Lock()
-	defer s.snapshotLock.Unlock()
-
-	confirmedMilestoneIndex := s.syncManager.ConfirmedMilestoneIndex()
-
-	if confirmedMilestoneIndex <= depth {
-		// Not enough history
-		return 0, ErrNotEnoughHistory
+// cleanupDatabase runs the database cleanup after pruning if it is enabled.
+// The cleanup can take a long time, so it must not be called while holding the snapshot lock.
+func (s *SnapshotManager) cleanupDatabase() {
+	if !s.pruningDatabaseCleanup {
+		return
 	}
 
-	return s.pruneDatabase(confirmedMilestoneIndex-depth, nil)
+	s.database.RunGarbageCollection()
 }
 
-func (s *SnapshotManager) PruneDatabaseByTargetIndex(targetIndex milestone.Index) (milestone.Index, error) {
-	s.snapshotLock.Lock()
-	defer s.snapshotLock.Unlock()
+// pruneDatabaseWithLock prunes the database to the target index returned by the given function
+// and runs the database cleanup after the snapshot lock was released.
+func (s *SnapshotManager) pruneDatabaseWithLock(targetIndexFunc func() (milestone.Index, error), abortSignal <-chan struct{}, progressFunc PruningProgressFunc) (milestone.Index, error) {
 
-	return s.pruneDatabase(targetIndex, nil)
-}
+	targetIndex, err := func() (milestone.Index, error) {
+		s.snapshotLock.Lock()
+		defer s.snapshotLock.Unlock()
 
-func (s *SnapshotManager) PruneDatabaseBySize(targetSizeBytes int64) (milestone.Index, error) {
-	s.snapshotLock.Lock()
-	defer s.snapshotLock.Unlock()
+		targetIndex, err := targetIndexFunc()
+		if err != nil {
+			return 0, err
+		}
 
-	targetIndex, err := s.calcTargetIndexBySize(targetSizeBytes)
+		return s.pruneDatabase(targetIndex, abortSignal, progressFunc)
+	}()
 	if err != nil {
 		return 0, err
 	}
 
-	return s.pruneDatabase(targetIndex, nil)
+	s.cleanupDatabase()
+
+	return targetIndex, nil
+}
+
+func (s *SnapshotManager) PruneDatabaseByDepth(depth milestone.Index, abortSignal <-chan struct{}, progressFunc PruningProgressFunc) (milestone.Index, error) {
+	return s.pruneDatabaseWithLock(func() (milestone.Index, error) {
+		confirmedMilestoneIndex := s.syncManager.ConfirmedMilestoneIndex()
+
+		if confirmedMilestoneIndex <= depth {
+			// Not enough history
+			return 0, ErrNotEnoughHistory
+		}
+
+		return confirmedMilestoneIndex - depth, nil
+	}, abortSignal, progressFunc)
+}
+
+func (s *SnapshotManager) PruneDatabaseByTargetIndex(targetIndex milestone.Index, abortSignal <-chan struct{}, progressFunc PruningProgressFunc) (milestone.Index, error) {
+	return s.pruneDatabaseWithLock(func() (milestone.Index, error) {
+		return targetIndex, nil
+	}, abortSignal, progressFunc)
+}
+
+func (s *SnapshotManager) PruneDatabaseBySize(targetSizeBytes int64, abortSignal <-chan struct{}, progressFunc PruningProgressFunc) (milestone.Index, error) {
+	return s.pruneDatabaseWithLock(func() (milestone.Index, error) {
+		return s.calcTargetIndexBySize(targetSizeBytes)
+	}, abortSignal, progressFunc)
 }
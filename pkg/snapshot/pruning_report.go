@@ -0,0 +1,224 @@
+package snapshot
+
+import (
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// the size of the database keys, used to estimate the size reduction of a pruning run.
+	prefixKeySize              = 1
+	messageKeySize             = prefixKeySize + iotago.MessageIDLength
+	childKeySize               = prefixKeySize + 2*iotago.MessageIDLength
+	indexationKeySize          = prefixKeySize + storage.IndexationIndexLength + iotago.MessageIDLength
+	unreferencedMessageKeySize = prefixKeySize + 4 + iotago.MessageIDLength
+	milestoneKeySize           = prefixKeySize + 4
+	milestoneValueSize         = iotago.MessageIDLength + 8
+)
+
+// PruningReport contains the estimated effect of a pruning run without mutating the database.
+type PruningReport struct {
+	// The milestone index the database would be pruned to.
+	TargetIndex milestone.Index `json:"targetIndex"`
+	// The amount of milestones that would be removed.
+	Milestones int `json:"milestones"`
+	// The amount of messages (including their metadata) that would be removed.
+	Messages int `json:"messages"`
+	// The amount of indexation entries that would be removed.
+	Indexations int `json:"indexations"`
+	// The amount of children entries that would be removed.
+	Children int `json:"children"`
+	// The amount of unreferenced messages that would be removed.
+	UnreferencedMessages int `json:"unreferencedMessages"`
+	// The amount of messages that would be kept because of the retention rules.
+	RetainedMessages int `json:"retainedMessages"`
+	// The estimated size reduction of the database in bytes (before compaction).
+	EstimatedSizeReductionBytes int64 `json:"estimatedSizeReductionBytes"`
+}
+
+// pruningReportWalker collects the data of all messages that would be removed by pruning.
+type pruningReportWalker struct {
+	s      *SnapshotManager
+	report *PruningReport
+
+	// messages that were already accounted for
+	messageIDsChecked map[string]struct{}
+}
+
+// addMessage accounts for the removal of the given message.
+func (w *pruningReportWalker) addMessage(messageID hornet.MessageID) {
+	messageIDMapKey := messageID.ToMapKey()
+	if _, exists := w.messageIDsChecked[messageIDMapKey]; exists {
+		return
+	}
+	w.messageIDsChecked[messageIDMapKey] = struct{}{}
+
+	cachedMsg := w.s.storage.CachedMessageOrNil(messageID) // msg +1
+	if cachedMsg == nil {
+		return
+	}
+	defer cachedMsg.Release(true) // msg -1
+
+	msg := cachedMsg.Message()
+
+	if w.s.retentionFilter != nil && cachedMsg.Metadata().IsReferenced() && w.s.retentionFilter.Retain(msg) {
+		w.report.RetainedMessages++
+		w.report.Children += len(msg.Parents())
+		w.report.EstimatedSizeReductionBytes += int64(len(msg.Parents()) * childKeySize)
+		return
+	}
+
+	w.report.Messages++
+	w.report.EstimatedSizeReductionBytes += int64(messageKeySize + len(msg.Data()))
+	w.report.EstimatedSizeReductionBytes += int64(messageKeySize + len(cachedMsg.Metadata().ObjectStorageValue()))
+
+	w.report.Children += len(msg.Parents())
+	w.report.EstimatedSizeReductionBytes += int64(len(msg.Parents()) * childKeySize)
+
+	if storage.CheckIfIndexation(msg) != nil {
+		w.report.Indexations++
+		w.report.EstimatedSizeReductionBytes += indexationKeySize
+	}
+}
+
+// addUnreferencedMessages accounts for the removal of the unreferenced messages of the given milestone.
+func (w *pruningReportWalker) addUnreferencedMessages(msIndex milestone.Index) {
+	for _, messageID := range w.s.storage.UnreferencedMessageIDs(msIndex) {
+		// the entry in the unreferenced messages storage is always removed
+		w.report.EstimatedSizeReductionBytes += unreferencedMessageKeySize
+
+		cachedMsgMeta := w.s.storage.CachedMessageMetadataOrNil(messageID) // meta +1
+		if cachedMsgMeta == nil {
+			continue
+		}
+		referenced := cachedMsgMeta.Metadata().IsReferenced()
+		cachedMsgMeta.Release(true) // meta -1
+
+		if referenced {
+			// message is pruned together with the milestone cone that referenced it
+			continue
+		}
+
+		if _, exists := w.messageIDsChecked[messageID.ToMapKey()]; !exists {
+			w.report.UnreferencedMessages++
+		}
+		w.addMessage(messageID)
+	}
+}
+
+// pruningReport walks the same cones as pruneDatabase and reports the amount of data that would be removed.
+func (s *SnapshotManager) pruningReport(targetIndex milestone.Index, abortSignal <-chan struct{}) (*PruningReport, error) {
+
+	if err := utils.ReturnErrIfCtxDone(s.shutdownCtx, common.ErrOperationAborted); err != nil {
+		return nil, common.ErrOperationAborted
+	}
+
+	snapshotInfo := s.storage.SnapshotInfo()
+	if snapshotInfo == nil {
+		s.log.Panic("No snapshotInfo found!")
+	}
+
+	targetIndex, err := s.checkPruningTargetIndex(targetIndex, snapshotInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	walker := &pruningReportWalker{
+		s:                 s,
+		report:            &PruningReport{TargetIndex: targetIndex},
+		messageIDsChecked: make(map[string]struct{}),
+	}
+
+	//lint:ignore SA5011 nil pointer is already checked before with a panic
+	walker.addUnreferencedMessages(snapshotInfo.PruningIndex)
+
+	for milestoneIndex := snapshotInfo.PruningIndex + 1; milestoneIndex <= targetIndex; milestoneIndex++ {
+		select {
+		case <-abortSignal:
+			return nil, ErrPruningAborted
+		default:
+		}
+
+		walker.addUnreferencedMessages(milestoneIndex)
+
+		cachedMs := s.storage.CachedMilestoneOrNil(milestoneIndex) // milestone +1
+		if cachedMs == nil {
+			continue
+		}
+		milestoneMessageID := cachedMs.Milestone().MessageID
+		cachedMs.Release(true) // milestone -1
+
+		walker.report.Milestones++
+		walker.report.EstimatedSizeReductionBytes += milestoneKeySize + milestoneValueSize
+
+		if err := dag.TraverseParentsOfMessage(s.storage, milestoneMessageID,
+			// traversal stops if no more messages pass the given condition
+			// Caution: condition func is not in DFS order
+			func(cachedMsgMeta *storage.CachedMetadata) (bool, error) { // msg +1
+				defer cachedMsgMeta.Release(true) // msg -1
+				// the cones of older milestones were already walked.
+				// in contrast to the real pruning, these messages still exist in the database.
+				_, checked := walker.messageIDsChecked[cachedMsgMeta.Metadata().MessageID().ToMapKey()]
+				return !checked, nil
+			},
+			// consumer
+			func(cachedMsgMeta *storage.CachedMetadata) error { // msg +1
+				defer cachedMsgMeta.Release(true) // msg -1
+				walker.addMessage(cachedMsgMeta.Metadata().MessageID())
+				return nil
+			},
+			// called on missing parents
+			func(parentMessageID hornet.MessageID) error { return nil },
+			// called on solid entry points
+			// Ignore solid entry points (snapshot milestone included)
+			nil,
+			// the pruning target index is also a solid entry point => traverse it anyways
+			true,
+			abortSignal); err != nil {
+			return nil, err
+		}
+	}
+
+	return walker.report, nil
+}
+
+// PruningReportByDepth reports the effect of pruning the database by the given depth without mutating the database.
+func (s *SnapshotManager) PruningReportByDepth(depth milestone.Index, abortSignal <-chan struct{}) (*PruningReport, error) {
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+
+	confirmedMilestoneIndex := s.syncManager.ConfirmedMilestoneIndex()
+
+	if confirmedMilestoneIndex <= depth {
+		// Not enough history
+		return nil, ErrNotEnoughHistory
+	}
+
+	return s.pruningReport(confirmedMilestoneIndex-depth, abortSignal)
+}
+
+// PruningReportByTargetIndex reports the effect of pruning the database up to the given target index without mutating the database.
+func (s *SnapshotManager) PruningReportByTargetIndex(targetIndex milestone.Index, abortSignal <-chan struct{}) (*PruningReport, error) {
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+
+	return s.pruningReport(targetIndex, abortSignal)
+}
+
+// PruningReportBySize reports the effect of pruning the database to the given size without mutating the database.
+func (s *SnapshotManager) PruningReportBySize(targetSizeBytes int64, abortSignal <-chan struct{}) (*PruningReport, error) {
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+
+	targetIndex, err := s.calcTargetIndexBySize(targetSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pruningReport(targetIndex, abortSignal)
+}
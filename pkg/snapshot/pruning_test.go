@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func TestPruneMessagesWithRetention(t *testing.T) {
+
+	s, err := storage.New(mapdb.NewMapDB())
+	require.NoError(t, err)
+
+	storeMessage := func(index string, parents hornet.MessageIDs, referencedIndex milestone.Index) hornet.MessageID {
+		msg, err := storage.NewMessage(&iotago.Message{
+			NetworkID: 1,
+			Parents:   parents.ToSliceOfArrays(),
+			Payload:   &iotago.Indexation{Index: []byte(index), Data: randBytes(32)},
+		}, iotago.DeSeriModeNoValidation)
+		require.NoError(t, err)
+
+		cachedMsg, _ := s.StoreMessageIfAbsent(msg) // msg +1
+		cachedMsg.Metadata().SetReferenced(true, referencedIndex)
+		cachedMsg.Release(true) // msg -1
+
+		for _, parent := range parents {
+			s.StoreChild(parent, msg.MessageID()).Release(true) // child +-0
+		}
+		s.StoreIndexation([]byte(index), msg.MessageID()).Release(true) // indexation +-0
+
+		return msg.MessageID()
+	}
+
+	prunedID := storeMessage("spam", hornet.MessageIDs{randMessageID()}, 3)
+	retainedID := storeMessage("app.events", hornet.MessageIDs{prunedID}, 4)
+	prunedChildID := storeMessage("spam", hornet.MessageIDs{retainedID}, 5)
+	retainedChildID := storeMessage("app.events", hornet.MessageIDs{retainedID, prunedChildID}, 5)
+	storeMessage("spam", hornet.MessageIDs{retainedChildID}, 12)
+
+	snapshotManager := &SnapshotManager{
+		storage:         s,
+		retentionFilter: NewRetentionFilter([][]byte{[]byte("app.")}, nil),
+	}
+
+	retainedMessageIDs := make(map[string]struct{})
+	pruned := snapshotManager.pruneMessages(map[string]struct{}{
+		prunedID.ToMapKey():        {},
+		retainedID.ToMapKey():      {},
+		prunedChildID.ToMapKey():   {},
+		retainedChildID.ToMapKey(): {},
+	}, retainedMessageIDs)
+	require.Equal(t, 2, pruned)
+	require.Len(t, retainedMessageIDs, 2)
+
+	// the retained messages are part of the cones of the following milestones again, but they are not counted twice
+	pruned = snapshotManager.pruneMessages(map[string]struct{}{
+		retainedID.ToMapKey():      {},
+		retainedChildID.ToMapKey(): {},
+		randMessageID().ToMapKey(): {},
+	}, retainedMessageIDs)
+	require.Zero(t, pruned)
+	require.Len(t, retainedMessageIDs, 2)
+	require.NoError(t, s.SetSnapshotMilestone(1, 10, 10, 10, time.Now()))
+	s.FlushStorages()
+
+	require.False(t, s.MessageExistsInStore(prunedID))
+	require.False(t, s.MessageExistsInStore(prunedChildID))
+	require.True(t, s.MessageExistsInStore(retainedID))
+	require.True(t, s.MessageExistsInStore(retainedChildID))
+
+	report, err := s.CheckConsistency(false)
+	require.NoError(t, err)
+	require.Empty(t, report.Issues)
+	require.Equal(t, 3, report.MessagesChecked)
+}
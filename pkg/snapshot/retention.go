@@ -0,0 +1,116 @@
+package snapshot
+
+import (
+	"bytes"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+// RetentionFilter decides which referenced messages are kept in the database during pruning.
+// A message is retained if its indexation matches one of the configured prefixes,
+// or if its transaction consumes or creates funds on one of the configured addresses.
+type RetentionFilter struct {
+	indexationPrefixes [][]byte
+	addresses          map[iotago.Ed25519Address]struct{}
+}
+
+// NewRetentionFilter creates a new RetentionFilter.
+func NewRetentionFilter(indexationPrefixes [][]byte, addresses []*iotago.Ed25519Address) *RetentionFilter {
+	addressesMap := make(map[iotago.Ed25519Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		addressesMap[*address] = struct{}{}
+	}
+
+	return &RetentionFilter{
+		indexationPrefixes: indexationPrefixes,
+		addresses:          addressesMap,
+	}
+}
+
+// IsEmpty returns whether no retention rules are configured.
+func (f *RetentionFilter) IsEmpty() bool {
+	return len(f.indexationPrefixes) == 0 && len(f.addresses) == 0
+}
+
+// Retain returns whether the given message should be kept during pruning.
+func (f *RetentionFilter) Retain(msg *storage.Message) bool {
+	return f.matchesIndexation(msg) || f.touchesAddress(msg)
+}
+
+func (f *RetentionFilter) matchesIndexation(msg *storage.Message) bool {
+	if len(f.indexationPrefixes) == 0 {
+		return false
+	}
+
+	indexationPayload := storage.CheckIfIndexation(msg)
+	if indexationPayload == nil {
+		return false
+	}
+
+	for _, prefix := range f.indexationPrefixes {
+		if bytes.HasPrefix(indexationPayload.Index, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *RetentionFilter) touchesAddress(msg *storage.Message) bool {
+	if len(f.addresses) == 0 {
+		return false
+	}
+
+	transaction := msg.Transaction()
+	if transaction == nil {
+		return false
+	}
+
+	// check the addresses that unlocked the inputs
+	for _, unlockBlock := range transaction.UnlockBlocks {
+		signatureUnlockBlock, ok := unlockBlock.(*iotago.SignatureUnlockBlock)
+		if !ok {
+			// reference unlock blocks point to a signature that is checked anyway
+			continue
+		}
+
+		signature, ok := signatureUnlockBlock.Signature.(*iotago.Ed25519Signature)
+		if !ok {
+			continue
+		}
+
+		if f.containsAddress(iotago.AddressFromEd25519PubKey(signature.PublicKey[:])) {
+			return true
+		}
+	}
+
+	essence := msg.TransactionEssence()
+	if essence == nil {
+		return false
+	}
+
+	// check the addresses of the created outputs
+	for _, output := range essence.Outputs {
+		var address iotago.Serializable
+		switch o := output.(type) {
+		case *iotago.SigLockedSingleOutput:
+			address = o.Address
+		case *iotago.SigLockedDustAllowanceOutput:
+			address = o.Address
+		default:
+			continue
+		}
+
+		if ed25519Address, ok := address.(*iotago.Ed25519Address); ok && f.containsAddress(*ed25519Address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *RetentionFilter) containsAddress(address iotago.Ed25519Address) bool {
+	_, exists := f.addresses[address]
+	return exists
+}
@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+func newTestMessage(t *testing.T, payload iotago.Serializable) *storage.Message {
+	msg, err := storage.NewMessage(&iotago.Message{
+		NetworkID: 1,
+		Parents:   hornet.MessageIDs{randMessageID()}.ToSliceOfArrays(),
+		Payload:   payload,
+	}, iotago.DeSeriModeNoValidation)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestRetentionFilterIndexation(t *testing.T) {
+
+	filter := NewRetentionFilter([][]byte{[]byte("app.")}, nil)
+	require.False(t, filter.IsEmpty())
+
+	require.True(t, filter.Retain(newTestMessage(t, &iotago.Indexation{Index: []byte("app.events"), Data: []byte("data")})))
+	require.False(t, filter.Retain(newTestMessage(t, &iotago.Indexation{Index: []byte("spam"), Data: []byte("data")})))
+	require.False(t, filter.Retain(newTestMessage(t, nil)))
+}
+
+func TestRetentionFilterAddresses(t *testing.T) {
+
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	senderAddress := iotago.AddressFromEd25519PubKey(pubKey)
+	receiverAddress := randomAddress()
+
+	newTransactionMessage := func(outputAddress *iotago.Ed25519Address) *storage.Message {
+		signature := &iotago.Ed25519Signature{}
+		copy(signature.PublicKey[:], pubKey)
+
+		return newTestMessage(t, &iotago.Transaction{
+			Essence: &iotago.TransactionEssence{
+				Inputs:  iotago.Serializables{&iotago.UTXOInput{}},
+				Outputs: iotago.Serializables{&iotago.SigLockedSingleOutput{Address: outputAddress, Amount: 1_000_000}},
+			},
+			UnlockBlocks: iotago.Serializables{&iotago.SignatureUnlockBlock{Signature: signature}},
+		})
+	}
+
+	require.True(t, NewRetentionFilter(nil, []*iotago.Ed25519Address{&senderAddress}).Retain(newTransactionMessage(randomAddress())))
+	require.True(t, NewRetentionFilter(nil, []*iotago.Ed25519Address{receiverAddress}).Retain(newTransactionMessage(receiverAddress)))
+	require.False(t, NewRetentionFilter(nil, []*iotago.Ed25519Address{randomAddress()}).Retain(newTransactionMessage(receiverAddress)))
+	require.False(t, NewRetentionFilter(nil, []*iotago.Ed25519Address{receiverAddress}).Retain(newTestMessage(t, &iotago.Indexation{Index: []byte("app"), Data: []byte("data")})))
+	require.True(t, NewRetentionFilter(nil, nil).IsEmpty())
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/dag"
 	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/coldstorage"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -69,6 +70,8 @@ type SnapshotManager struct {
 	storage                              *storage.Storage
 	syncManager                          *syncmanager.SyncManager
 	utxoManager                          *utxo.Manager
+	coldStorage                          *coldstorage.ColdStorage
+	retentionFilter                      *RetentionFilter
 	networkID                            uint64
 	networkIDSource                      string
 	snapshotFullPath                     string
@@ -87,6 +90,7 @@ type SnapshotManager struct {
 	pruningSizeThresholdPercentage       float64
 	pruningSizeCooldownTime              time.Duration
 	pruneReceipts                        bool
+	pruningDatabaseCleanup               bool
 
 	snapshotLock          syncutils.Mutex
 	statusLock            syncutils.RWMutex
@@ -121,7 +125,10 @@ func NewSnapshotManager(shutdownCtx context.Context,
 	pruningSizeTargetSizeBytes int64,
 	pruningSizeThresholdPercentage float64,
 	pruningSizeCooldownTime time.Duration,
-	pruneReceipts bool) *SnapshotManager {
+	pruneReceipts bool,
+	pruningDatabaseCleanup bool,
+	coldStorage *coldstorage.ColdStorage,
+	retentionFilter *RetentionFilter) *SnapshotManager {
 
 	return &SnapshotManager{
 		shutdownCtx:                          shutdownCtx,
@@ -130,6 +137,8 @@ func NewSnapshotManager(shutdownCtx context.Context,
 		storage:                              storage,
 		syncManager:                          syncManager,
 		utxoManager:                          utxoManager,
+		coldStorage:                          coldStorage,
+		retentionFilter:                      retentionFilter,
 		networkID:                            networkID,
 		networkIDSource:                      networkIDSource,
 		snapshotFullPath:                     snapshotFullPath,
@@ -148,9 +157,11 @@ func NewSnapshotManager(shutdownCtx context.Context,
 		pruningSizeThresholdPercentage:       pruningSizeThresholdPercentage,
 		pruningSizeCooldownTime:              pruningSizeCooldownTime,
 		pruneReceipts:                        pruneReceipts,
+		pruningDatabaseCleanup:               pruningDatabaseCleanup,
 		Events: &Events{
 			SnapshotMilestoneIndexChanged: events.NewEvent(milestone.IndexCaller),
 			SnapshotMetricsUpdated:        events.NewEvent(SnapshotMetricsCaller),
+			PruningStarted:                events.NewEvent(PruningStartedCaller),
 			PruningMilestoneIndexChanged:  events.NewEvent(milestone.IndexCaller),
 			PruningMetricsUpdated:         events.NewEvent(PruningMetricsCaller),
 		},
@@ -428,6 +439,14 @@ func (s *SnapshotManager) HandleNewConfirmedMilestoneEvent(confirmedMilestoneInd
 		return
 	}
 
+	// the database cleanup after pruning runs after the snapshot lock was released
+	databasePruned := false
+	defer func() {
+		if databasePruned {
+			s.cleanupDatabase()
+		}
+	}()
+
 	s.snapshotLock.Lock()
 	defer s.snapshotLock.Unlock()
 
@@ -470,8 +489,10 @@ func (s *SnapshotManager) HandleNewConfirmedMilestoneEvent(confirmedMilestoneInd
 		return
 	}
 
-	if _, err := s.pruneDatabase(targetIndex, shutdownSignal); err != nil {
+	if _, err := s.pruneDatabase(targetIndex, shutdownSignal, nil); err != nil {
 		s.log.Debugf("pruning aborted: %v", err)
+	} else {
+		databasePruned = true
 	}
 
 	if pruningBySize {
@@ -5,11 +5,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -19,7 +21,12 @@ import (
 
 const (
 	// The supported snapshot file version.
-	SupportedFormatVersion byte = 1
+	SupportedFormatVersion byte = 2
+	// The oldest snapshot file version that can still be read.
+	// Snapshot files of this version don't contain the key ranges announced by the coordinator.
+	MinSupportedFormatVersion byte = 1
+	// The first snapshot file version that contains the key ranges announced by the coordinator.
+	keyRangesFormatVersion byte = 2
 	// The length of a solid entry point hash.
 	SolidEntryPointHashLength = iotago.MessageIDLength
 
@@ -265,6 +272,9 @@ type FileHeader struct {
 	// The treasury output existing for the given ledger milestone index.
 	// This field must be populated if a Full snapshot is created/read.
 	TreasuryOutput *utxo.TreasuryOutput
+	// The key ranges announced by the coordinator.
+	// This field is only written/read if the snapshot version supports it.
+	KeyRanges []*keymanager.KeyRange
 }
 
 // ReadFileHeader is a FileHeader but with additional content read from the snapshot.
@@ -352,6 +362,12 @@ func StreamSnapshotDataTo(writeSeeker io.WriteSeeker, timestamp uint64, header *
 		}
 	}
 
+	if header.Version >= keyRangesFormatVersion {
+		if err := writeKeyRanges(writeSeeker, header.KeyRanges); err != nil {
+			return nil, err
+		}
+	}
+
 	timeHeader := time.Now()
 
 	for {
@@ -498,9 +514,73 @@ func ReadSnapshotHeader(reader io.Reader) (*ReadFileHeader, error) {
 		readHeader.TreasuryOutput = to
 	}
 
+	if readHeader.Version >= keyRangesFormatVersion {
+		keyRanges, err := readKeyRanges(reader)
+		if err != nil {
+			return nil, err
+		}
+		readHeader.KeyRanges = keyRanges
+	}
+
 	return readHeader, nil
 }
 
+// writeKeyRanges writes the amount of key ranges (uint16) followed by the key ranges:
+// public key, start index (uint32) and end index (uint32).
+func writeKeyRanges(writer io.Writer, keyRanges []*keymanager.KeyRange) error {
+	if len(keyRanges) > math.MaxUint16 {
+		return fmt.Errorf("unable to write LS key ranges: too many key ranges: %d", len(keyRanges))
+	}
+
+	if err := binary.Write(writer, binary.LittleEndian, uint16(len(keyRanges))); err != nil {
+		return fmt.Errorf("unable to write LS key ranges count: %w", err)
+	}
+
+	for i, keyRange := range keyRanges {
+		if _, err := writer.Write(keyRange.PublicKey[:]); err != nil {
+			return fmt.Errorf("unable to write LS key range #%d public key: %w", i, err)
+		}
+		if err := binary.Write(writer, binary.LittleEndian, uint32(keyRange.StartIndex)); err != nil {
+			return fmt.Errorf("unable to write LS key range #%d start index: %w", i, err)
+		}
+		if err := binary.Write(writer, binary.LittleEndian, uint32(keyRange.EndIndex)); err != nil {
+			return fmt.Errorf("unable to write LS key range #%d end index: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// readKeyRanges reads the key ranges written by writeKeyRanges.
+func readKeyRanges(reader io.Reader) ([]*keymanager.KeyRange, error) {
+	var keyRangesCount uint16
+	if err := binary.Read(reader, binary.LittleEndian, &keyRangesCount); err != nil {
+		return nil, fmt.Errorf("unable to read LS key ranges count: %w", err)
+	}
+
+	var keyRanges []*keymanager.KeyRange
+	for i := 0; i < int(keyRangesCount); i++ {
+		keyRange := &keymanager.KeyRange{}
+		if _, err := io.ReadFull(reader, keyRange.PublicKey[:]); err != nil {
+			return nil, fmt.Errorf("unable to read LS key range #%d public key: %w", i, err)
+		}
+
+		var startIndex, endIndex uint32
+		if err := binary.Read(reader, binary.LittleEndian, &startIndex); err != nil {
+			return nil, fmt.Errorf("unable to read LS key range #%d start index: %w", i, err)
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &endIndex); err != nil {
+			return nil, fmt.Errorf("unable to read LS key range #%d end index: %w", i, err)
+		}
+		keyRange.StartIndex = milestone.Index(startIndex)
+		keyRange.EndIndex = milestone.Index(endIndex)
+
+		keyRanges = append(keyRanges, keyRange)
+	}
+
+	return keyRanges, nil
+}
+
 // StreamSnapshotDataFrom consumes a snapshot from the given reader.
 // OutputConsumerFunc must not be nil if the snapshot is not a delta snapshot.
 func StreamSnapshotDataFrom(reader io.Reader,
@@ -12,6 +12,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -56,6 +57,7 @@ func TestStreamLocalSnapshotDataToAndFrom(t *testing.T) {
 				SEPMilestoneIndex:    milestone.Index(rand.Intn(10000)),
 				LedgerMilestoneIndex: milestone.Index(rand.Intn(10000)),
 				TreasuryOutput:       &utxo.TreasuryOutput{MilestoneID: iotago.MilestoneID{}, Amount: 13337},
+				KeyRanges:            randKeyRanges(3),
 			}
 
 			originTimestamp := uint64(time.Now().Unix())
@@ -127,6 +129,41 @@ func TestStreamLocalSnapshotDataToAndFrom(t *testing.T) {
 			}
 			return t
 		}(),
+		func() test {
+			originHeader := &snapshot.FileHeader{
+				Type:                 snapshot.Delta,
+				Version:              snapshot.MinSupportedFormatVersion,
+				NetworkID:            666666666,
+				SEPMilestoneIndex:    milestone.Index(rand.Intn(10000)),
+				LedgerMilestoneIndex: milestone.Index(rand.Intn(10000)),
+			}
+
+			originTimestamp := uint64(time.Now().Unix())
+
+			// create generators and consumers
+			sepIterFunc, sepGenRetriever := newSEPGenerator(150)
+			sepConsumerFunc, sepsCollRetriever := newSEPCollector()
+
+			msDiffIterFunc, msDiffGenRetriever := newMsDiffGenerator(50)
+			msDiffConsumerFunc, msDiffCollRetriever := newMsDiffCollector()
+
+			t := test{
+				name:               "delta version 1: 150 seps, 50 ms diffs",
+				snapshotFileName:   "delta_snapshot_v1.bin",
+				originHeader:       originHeader,
+				originTimestamp:    originTimestamp,
+				sepGenerator:       sepIterFunc,
+				sepGenRetriever:    sepGenRetriever,
+				msDiffGenerator:    msDiffIterFunc,
+				msDiffGenRetriever: msDiffGenRetriever,
+				headerConsumer:     headerEqualFunc(t, originHeader),
+				sepConsumer:        sepConsumerFunc,
+				sepConRetriever:    sepsCollRetriever,
+				msDiffConsumer:     msDiffConsumerFunc,
+				msDiffConRetriever: msDiffCollRetriever,
+			}
+			return t
+		}(),
 	}
 
 	for _, tt := range testCases {
@@ -316,6 +353,18 @@ func unspentTreasuryOutputEqualFunc(t *testing.T, originUnspentTreasuryOutput *u
 	}
 }
 
+func randKeyRanges(count int) []*keymanager.KeyRange {
+	keyRanges := make([]*keymanager.KeyRange, count)
+	for i := 0; i < count; i++ {
+		keyRanges[i] = &keymanager.KeyRange{
+			StartIndex: milestone.Index(rand.Intn(10000)),
+			EndIndex:   milestone.Index(rand.Intn(10000)),
+		}
+		copy(keyRanges[i].PublicKey[:], randBytes(iotago.MilestonePublicKeyLength))
+	}
+	return keyRanges
+}
+
 func randBytes(length int) []byte {
 	var b []byte
 	for i := 0; i < length; i++ {
@@ -19,8 +19,8 @@ import (
 // the given targetHeader is populated with the value of the read file header.
 func newFileHeaderConsumer(targetHeader *ReadFileHeader, utxoManager *utxo.Manager, wantedType Type, wantedNetworkID ...uint64) HeaderConsumerFunc {
 	return func(header *ReadFileHeader) error {
-		if header.Version != SupportedFormatVersion {
-			return errors.Wrapf(ErrUnsupportedSnapshot, "snapshot file version is %d but this HORNET version only supports %v", header.Version, SupportedFormatVersion)
+		if header.Version < MinSupportedFormatVersion || header.Version > SupportedFormatVersion {
+			return errors.Wrapf(ErrUnsupportedSnapshot, "snapshot file version is %d but this HORNET version only supports %d to %d", header.Version, MinSupportedFormatVersion, SupportedFormatVersion)
 		}
 
 		if header.Type != wantedType {
@@ -206,6 +206,13 @@ func loadSnapshotFileToStorage(
 		return nil, err
 	}
 
+	// the key ranges announced by the coordinator are needed to verify the milestones after the snapshot
+	for _, keyRange := range header.KeyRanges {
+		if err = dbStorage.StoreKeyRange(keyRange); err != nil {
+			return nil, err
+		}
+	}
+
 	var ledgerIndex milestone.Index
 	ledgerIndex, err = dbStorage.UTXOManager().ReadLedgerIndex()
 	if err != nil {
@@ -0,0 +1,74 @@
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func storedKeyRanges(t *testing.T, dbStorage *storage.Storage) []*keymanager.KeyRange {
+	var keyRanges []*keymanager.KeyRange
+	require.NoError(t, dbStorage.ForEachKeyRange(func(keyRange *keymanager.KeyRange) bool {
+		keyRanges = append(keyRanges, keyRange)
+		return true
+	}))
+	return keyRanges
+}
+
+// TestLoadSnapshotWithKeyRanges checks that the key ranges announced by the coordinator are part of a snapshot
+// and that they are stored in the database of a node that bootstraps from the snapshot.
+func TestLoadSnapshotWithKeyRanges(t *testing.T) {
+
+	const (
+		networkID   = 1337133713371337
+		ledgerIndex = milestone.Index(10)
+	)
+
+	sourceStorage, err := storage.New(mapdb.NewMapDB())
+	require.NoError(t, err)
+
+	require.NoError(t, sourceStorage.UTXOManager().StoreLedgerIndex(ledgerIndex))
+	require.NoError(t, sourceStorage.UTXOManager().StoreUnspentTreasuryOutput(&utxo.TreasuryOutput{Amount: iotago.TokenSupply}))
+	require.NoError(t, sourceStorage.SetSnapshotMilestone(networkID, ledgerIndex, ledgerIndex, ledgerIndex, time.Now()))
+
+	announcedKeyRange1 := &keymanager.KeyRange{StartIndex: 5, EndIndex: 100}
+	copy(announcedKeyRange1.PublicKey[:], randBytes(iotago.MilestonePublicKeyLength))
+	require.NoError(t, sourceStorage.StoreKeyRange(announcedKeyRange1))
+
+	announcedKeyRange2 := &keymanager.KeyRange{StartIndex: 90, EndIndex: 90}
+	copy(announcedKeyRange2.PublicKey[:], randBytes(iotago.MilestonePublicKeyLength))
+	require.NoError(t, sourceStorage.StoreKeyRange(announcedKeyRange2))
+
+	sourceKeyRanges := storedKeyRanges(t, sourceStorage)
+	require.Len(t, sourceKeyRanges, 2)
+
+	snapshotFilePath := filepath.Join(t.TempDir(), "full_snapshot.bin")
+	_, err = createSnapshotFromCurrentStorageState(sourceStorage, snapshotFilePath)
+	require.NoError(t, err)
+
+	readHeader, err := ReadSnapshotHeaderFromFile(snapshotFilePath)
+	require.NoError(t, err)
+	require.Equal(t, SupportedFormatVersion, readHeader.Version)
+	require.ElementsMatch(t, sourceKeyRanges, readHeader.KeyRanges)
+
+	// bootstrap a new node from the snapshot
+	targetStorage, err := storage.New(mapdb.NewMapDB())
+	require.NoError(t, err)
+
+	fullHeader, _, err := LoadSnapshotFilesToStorage(context.Background(), targetStorage, snapshotFilePath)
+	require.NoError(t, err)
+	require.Equal(t, ledgerIndex, fullHeader.LedgerMilestoneIndex)
+	require.Equal(t, ledgerIndex, targetStorage.SnapshotInfo().SnapshotIndex)
+
+	require.ElementsMatch(t, sourceKeyRanges, storedKeyRanges(t, targetStorage))
+}
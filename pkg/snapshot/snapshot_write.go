@@ -11,6 +11,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -332,6 +333,19 @@ func newMsDiffsProducer(mrf MilestoneRetrieverFunc, utxoManager *utxo.Manager, d
 	}
 }
 
+// reads out the key ranges announced by the coordinator.
+func readStoredKeyRanges(dbStorage *storage.Storage) ([]*keymanager.KeyRange, error) {
+	var keyRanges []*keymanager.KeyRange
+	if err := dbStorage.ForEachKeyRange(func(keyRange *keymanager.KeyRange) bool {
+		keyRanges = append(keyRanges, keyRange)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	return keyRanges, nil
+}
+
 // reads out the index of the milestone which currently represents the ledger state.
 func (s *SnapshotManager) readLedgerIndex() (milestone.Index, error) {
 	ledgerMilestoneIndex, err := s.utxoManager.ReadLedgerIndexWithoutLocking()
@@ -413,11 +427,17 @@ func (s *SnapshotManager) createSnapshotWithoutLocking(
 		return err
 	}
 
+	keyRanges, err := readStoredKeyRanges(s.storage)
+	if err != nil {
+		return err
+	}
+
 	header := &FileHeader{
 		Version:           SupportedFormatVersion,
 		Type:              snapshotType,
 		NetworkID:         snapshotInfo.NetworkID,
 		SEPMilestoneIndex: targetIndex,
+		KeyRanges:         keyRanges,
 	}
 
 	targetMsTimestamp, err := s.readTargetMilestoneTimestamp(targetIndex)
@@ -567,6 +587,11 @@ func createSnapshotFromCurrentStorageState(dbStorage *storage.Storage, filePath
 		return nil, fmt.Errorf("unable to get unspent treasury output: %w", err)
 	}
 
+	keyRanges, err := readStoredKeyRanges(dbStorage)
+	if err != nil {
+		return nil, err
+	}
+
 	snapshotFileHeader := &FileHeader{
 		Version:              SupportedFormatVersion,
 		Type:                 Full,
@@ -574,6 +599,7 @@ func createSnapshotFromCurrentStorageState(dbStorage *storage.Storage, filePath
 		SEPMilestoneIndex:    ledgerIndex,
 		LedgerMilestoneIndex: ledgerIndex,
 		TreasuryOutput:       unspentTreasuryOutput,
+		KeyRanges:            keyRanges,
 	}
 
 	// returns a producer which returns all solid entry points in the database.
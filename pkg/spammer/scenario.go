@@ -0,0 +1,171 @@
+package spammer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadType is the kind of payload of a scenario entry.
+type PayloadType string
+
+const (
+	// PayloadIndexation issues a message with an indexation payload.
+	PayloadIndexation PayloadType = "indexation"
+	// PayloadTransaction issues a message with a signed value transaction.
+	PayloadTransaction PayloadType = "transaction"
+	// PayloadConflict issues two messages with transactions that spend the same output.
+	PayloadConflict PayloadType = "conflict"
+)
+
+// ParentsSelection is the way the parents of the messages of a scenario entry are selected.
+type ParentsSelection string
+
+const (
+	// ParentsSpammer selects the parents like the default spammer (semi-lazy tips if the threshold is reached).
+	ParentsSpammer ParentsSelection = "spammer"
+	// ParentsNonLazy selects non-lazy tips.
+	ParentsNonLazy ParentsSelection = "nonLazy"
+	// ParentsSemiLazy selects semi-lazy tips.
+	ParentsSemiLazy ParentsSelection = "semiLazy"
+	// ParentsBelowMaxDepth selects an old milestone message that is below max depth.
+	ParentsBelowMaxDepth ParentsSelection = "belowMaxDepth"
+)
+
+const (
+	// the maximum size of the data of the indexation payload, the message has to fit into MessageBinSerializedMaxSize.
+	maxScenarioDataSize = 30 * 1024
+)
+
+var (
+	// ErrInvalidScenario is returned if a scenario is invalid.
+	ErrInvalidScenario = errors.New("invalid scenario")
+)
+
+// ScenarioEntry describes a kind of message that is issued by the scenario spammer.
+type ScenarioEntry struct {
+	// The name of the entry.
+	Name string `json:"name"`
+	// The relative frequency of the entry.
+	Weight int `json:"weight"`
+	// The kind of payload.
+	Payload PayloadType `json:"payload"`
+	// The size of the data of the indexation payload (0 = only the spam message).
+	DataSize int `json:"dataSize"`
+	// The way the parents are selected (default "spammer").
+	Parents ParentsSelection `json:"parents"`
+}
+
+// Scenario describes a mix of messages that is issued by the spammer to reproduce a specific tangle shape.
+type Scenario struct {
+	// The target messages per second of the scenario (0 = use the rate limit of the spammer).
+	MPSTarget float64 `json:"mpsTarget"`
+	// The entries of the scenario.
+	Entries []*ScenarioEntry `json:"entries"`
+
+	totalWeight int
+}
+
+// LoadScenario loads a scenario from the given JSON file.
+func LoadScenario(filePath string) (*Scenario, error) {
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scenario file: %w", err)
+	}
+
+	scenario := &Scenario{}
+	if err := json.Unmarshal(data, scenario); err != nil {
+		return nil, fmt.Errorf("unable to parse scenario file: %w", err)
+	}
+
+	if err := scenario.init(); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+// init validates the scenario and applies the defaults.
+func (s *Scenario) init() error {
+
+	if s.MPSTarget < 0 {
+		return fmt.Errorf("%w: negative target MPS", ErrInvalidScenario)
+	}
+
+	if len(s.Entries) == 0 {
+		return fmt.Errorf("%w: no entries", ErrInvalidScenario)
+	}
+
+	s.totalWeight = 0
+	for i, entry := range s.Entries {
+		if entry.Name == "" {
+			entry.Name = fmt.Sprintf("%s_%d", entry.Payload, i)
+		}
+
+		if entry.Weight <= 0 {
+			return fmt.Errorf("%w: entry %s: weight must be greater than zero", ErrInvalidScenario, entry.Name)
+		}
+
+		switch entry.Payload {
+		case PayloadIndexation, PayloadTransaction, PayloadConflict:
+		default:
+			return fmt.Errorf("%w: entry %s: unknown payload type \"%s\"", ErrInvalidScenario, entry.Name, entry.Payload)
+		}
+
+		if entry.DataSize < 0 || entry.DataSize > maxScenarioDataSize {
+			return fmt.Errorf("%w: entry %s: data size must be between 0 and %d", ErrInvalidScenario, entry.Name, maxScenarioDataSize)
+		}
+
+		if entry.Parents == "" {
+			entry.Parents = ParentsSpammer
+		}
+
+		switch entry.Parents {
+		case ParentsSpammer, ParentsNonLazy, ParentsSemiLazy, ParentsBelowMaxDepth:
+		default:
+			return fmt.Errorf("%w: entry %s: unknown parents selection \"%s\"", ErrInvalidScenario, entry.Name, entry.Parents)
+		}
+
+		s.totalWeight += entry.Weight
+	}
+
+	return nil
+}
+
+// NeedsWallet returns whether the scenario issues value transactions.
+func (s *Scenario) NeedsWallet() bool {
+	for _, entry := range s.Entries {
+		if entry.Payload != PayloadIndexation {
+			return true
+		}
+	}
+	return false
+}
+
+// HasConflicts returns whether the scenario issues conflicting transactions.
+func (s *Scenario) HasConflicts() bool {
+	for _, entry := range s.Entries {
+		if entry.Payload == PayloadConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// NextEntry randomly picks an entry of the scenario according to the weights.
+func (s *Scenario) NextEntry() *ScenarioEntry {
+
+	r := rand.Intn(s.totalWeight)
+	for _, entry := range s.Entries {
+		if r < entry.Weight {
+			return entry
+		}
+		r -= entry.Weight
+	}
+
+	return s.Entries[len(s.Entries)-1]
+}
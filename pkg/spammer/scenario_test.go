@@ -0,0 +1,59 @@
+package spammer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeScenario(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0600))
+	return filePath
+}
+
+func TestLoadScenario(t *testing.T) {
+
+	_, err := LoadScenario(writeScenario(t, `{"entries": []}`))
+	require.ErrorIs(t, err, ErrInvalidScenario)
+
+	_, err = LoadScenario(writeScenario(t, `{"entries": [{"weight": 1, "payload": "unknown"}]}`))
+	require.ErrorIs(t, err, ErrInvalidScenario)
+
+	_, err = LoadScenario(writeScenario(t, `{"entries": [{"weight": 0, "payload": "indexation"}]}`))
+	require.ErrorIs(t, err, ErrInvalidScenario)
+
+	_, err = LoadScenario(writeScenario(t, `{"entries": [{"weight": 1, "payload": "indexation", "parents": "unknown"}]}`))
+	require.ErrorIs(t, err, ErrInvalidScenario)
+
+	_, err = LoadScenario(writeScenario(t, `{"entries": [{"weight": 1, "payload": "indexation", "dataSize": 100000}]}`))
+	require.ErrorIs(t, err, ErrInvalidScenario)
+
+	scenario, err := LoadScenario(writeScenario(t, `{
+		"mpsTarget": 20,
+		"entries": [
+			{"name": "small", "weight": 3, "payload": "indexation", "dataSize": 64},
+			{"weight": 1, "payload": "indexation", "parents": "belowMaxDepth"}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 20.0, scenario.MPSTarget)
+	require.False(t, scenario.NeedsWallet())
+
+	// the defaults are applied
+	require.Equal(t, "indexation_1", scenario.Entries[1].Name)
+	require.Equal(t, ParentsSpammer, scenario.Entries[0].Parents)
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		counts[scenario.NextEntry().Name]++
+	}
+	require.InDelta(t, 3000, counts["small"], 200)
+	require.InDelta(t, 1000, counts["indexation_1"], 200)
+
+	scenario, err = LoadScenario(writeScenario(t, `{"entries": [{"weight": 1, "payload": "conflict"}]}`))
+	require.NoError(t, err)
+	require.True(t, scenario.NeedsWallet())
+}
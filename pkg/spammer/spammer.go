@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -11,12 +13,30 @@ import (
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
+var (
+	// ErrValueSpamDisabled is returned if value transactions are spammed without a wallet.
+	ErrValueSpamDisabled = errors.New("value spam disabled, no wallet given")
+)
+
 // SendMessageFunc is a function which sends a message to the network.
 type SendMessageFunc = func(msg *storage.Message) error
 
 // SpammerTipselFunc selects tips for the spammer.
 type SpammerTipselFunc = func() (isSemiLazy bool, tips hornet.MessageIDs, err error)
 
+// ParentsFunc selects the parents of a message of a scenario.
+type ParentsFunc = func() (hornet.MessageIDs, error)
+
+// ParentsFuncs are the functions used to select the parents of the messages of a scenario.
+type ParentsFuncs struct {
+	// selects non-lazy tips.
+	NonLazy ParentsFunc
+	// selects semi-lazy tips.
+	SemiLazy ParentsFunc
+	// selects old messages that are below max depth.
+	BelowMaxDepth ParentsFunc
+}
+
 // Spammer is used to issue messages to the IOTA network to create load on the tangle.
 type Spammer struct {
 	networkID       uint64
@@ -27,10 +47,16 @@ type Spammer struct {
 	powHandler      *pow.Handler
 	sendMessageFunc SendMessageFunc
 	serverMetrics   *metrics.ServerMetrics
+	wallet          *Wallet
+	parentsFuncs    *ParentsFuncs
+	tracker         *Tracker
 }
 
 // New creates a new spammer instance.
-func New(networkID uint64, message string, index string, indexSemiLazy string, tipselFunc SpammerTipselFunc, powHandler *pow.Handler, sendMessageFunc SendMessageFunc, serverMetrics *metrics.ServerMetrics) *Spammer {
+// The wallet is used to spam value transactions, it can be nil if only indexation messages are spammed.
+// The parents functions are used to select the parents of the messages of a scenario.
+// The tracker is used to track the sent messages until they are referenced, it can be nil.
+func New(networkID uint64, message string, index string, indexSemiLazy string, tipselFunc SpammerTipselFunc, powHandler *pow.Handler, sendMessageFunc SendMessageFunc, serverMetrics *metrics.ServerMetrics, wallet *Wallet, parentsFuncs *ParentsFuncs, tracker *Tracker) *Spammer {
 
 	return &Spammer{
 		networkID:       networkID,
@@ -41,13 +67,123 @@ func New(networkID uint64, message string, index string, indexSemiLazy string, t
 		powHandler:      powHandler,
 		sendMessageFunc: sendMessageFunc,
 		serverMetrics:   serverMetrics,
+		wallet:          wallet,
+		parentsFuncs:    parentsFuncs,
+		tracker:         tracker,
 	}
 }
 
+// Wallet returns the wallet used to spam value transactions.
+func (s *Spammer) Wallet() *Wallet {
+	return s.wallet
+}
+
+// DoSpam issues a message with an indexation payload.
 func (s *Spammer) DoSpam(shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
+	return s.doSpam(shutdownSignal, string(PayloadIndexation), s.tipselFunc, 0, func(indexation *iotago.Indexation) ([]iotago.Serializable, error) {
+		return []iotago.Serializable{indexation}, nil
+	})
+}
+
+// DoValueSpam issues a message with a signed value transaction between the addresses of the wallet.
+func (s *Spammer) DoValueSpam(shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
+	return s.doSpam(shutdownSignal, string(PayloadTransaction), s.tipselFunc, 0, s.transactionPayloads)
+}
+
+// DoScenarioSpam issues the messages described by the given scenario entry.
+func (s *Spammer) DoScenarioSpam(entry *ScenarioEntry, shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
+
+	tipselFunc, err := s.scenarioTipselFunc(entry.Parents)
+	if err != nil {
+		return time.Duration(0), time.Duration(0), err
+	}
+
+	var payloadsFunc func(indexation *iotago.Indexation) ([]iotago.Serializable, error)
+	switch entry.Payload {
+	case PayloadIndexation:
+		payloadsFunc = func(indexation *iotago.Indexation) ([]iotago.Serializable, error) {
+			return []iotago.Serializable{indexation}, nil
+		}
+	case PayloadTransaction:
+		payloadsFunc = s.transactionPayloads
+	case PayloadConflict:
+		payloadsFunc = s.conflictingTransactionPayloads
+	default:
+		return time.Duration(0), time.Duration(0), fmt.Errorf("%w: unknown payload type \"%s\"", ErrInvalidScenario, entry.Payload)
+	}
+
+	return s.doSpam(shutdownSignal, entry.Name, tipselFunc, entry.DataSize, payloadsFunc)
+}
+
+// scenarioTipselFunc returns the function that selects the parents for the given selection.
+func (s *Spammer) scenarioTipselFunc(parents ParentsSelection) (SpammerTipselFunc, error) {
+
+	if parents == ParentsSpammer {
+		return s.tipselFunc, nil
+	}
+
+	if s.parentsFuncs == nil {
+		return nil, fmt.Errorf("%w: parents selection \"%s\" not supported", ErrInvalidScenario, parents)
+	}
+
+	var parentsFunc ParentsFunc
+	switch parents {
+	case ParentsNonLazy:
+		parentsFunc = s.parentsFuncs.NonLazy
+	case ParentsSemiLazy:
+		parentsFunc = s.parentsFuncs.SemiLazy
+	case ParentsBelowMaxDepth:
+		parentsFunc = s.parentsFuncs.BelowMaxDepth
+	default:
+		return nil, fmt.Errorf("%w: unknown parents selection \"%s\"", ErrInvalidScenario, parents)
+	}
+
+	return func() (bool, hornet.MessageIDs, error) {
+		tips, err := parentsFunc()
+		return parents == ParentsSemiLazy, tips, err
+	}, nil
+}
+
+// transactionPayloads creates a signed value transaction between the addresses of the wallet.
+func (s *Spammer) transactionPayloads(indexation *iotago.Indexation) ([]iotago.Serializable, error) {
+	if s.wallet == nil {
+		return nil, ErrValueSpamDisabled
+	}
+
+	tx, err := s.wallet.buildTransaction(indexation)
+	if err != nil {
+		return nil, err
+	}
+
+	return []iotago.Serializable{tx}, nil
+}
+
+// conflictingTransactionPayloads creates two signed value transactions that spend the same output of the wallet.
+func (s *Spammer) conflictingTransactionPayloads(indexation *iotago.Indexation) ([]iotago.Serializable, error) {
+	if s.wallet == nil {
+		return nil, ErrValueSpamDisabled
+	}
+
+	txs, err := s.wallet.buildConflictingTransactions(indexation)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([]iotago.Serializable, len(txs))
+	for i, tx := range txs {
+		payloads[i] = tx
+	}
+
+	return payloads, nil
+}
+
+// doSpam selects tips, creates the payloads with the given function and issues a message for every payload.
+// the data of the indexation payload is padded to the given size.
+// the sent messages are tracked with the given entry name.
+func (s *Spammer) doSpam(shutdownSignal <-chan struct{}, entry string, tipselFunc SpammerTipselFunc, dataSize int, payloadsFunc func(indexation *iotago.Indexation) ([]iotago.Serializable, error)) (time.Duration, time.Duration, error) {
 
 	timeStart := time.Now()
-	isSemiLazy, tips, err := s.tipselFunc()
+	isSemiLazy, tips, err := tipselFunc()
 	if err != nil {
 		return time.Duration(0), time.Duration(0), err
 	}
@@ -71,29 +207,47 @@ func (s *Spammer) DoSpam(shutdownSignal <-chan struct{}) (time.Duration, time.Du
 	messageString += fmt.Sprintf("\nTimestamp: %s", now.Format(time.RFC3339))
 	messageString += fmt.Sprintf("\nTipselection: %v", durationGTTA.Truncate(time.Microsecond))
 
-	iotaMsg := &iotago.Message{
-		NetworkID: s.networkID,
-		Parents:   tips.ToSliceOfArrays(),
-		Payload:   &iotago.Indexation{Index: index, Data: []byte(messageString)},
-	}
-
-	timeStart = time.Now()
-	if err := s.powHandler.DoPoW(iotaMsg, shutdownSignal, 1, func() (tips hornet.MessageIDs, err error) {
-		// refresh tips of the spammer if PoW takes longer than a configured duration.
-		_, refreshedTips, err := s.tipselFunc()
-		return refreshedTips, err
-	}); err != nil {
-		return time.Duration(0), time.Duration(0), err
+	data := []byte(messageString)
+	if dataSize > 0 {
+		data = make([]byte, dataSize)
+		copy(data, messageString)
 	}
-	durationPOW := time.Since(timeStart)
 
-	msg, err := storage.NewMessage(iotaMsg, iotago.DeSeriModePerformValidation)
+	payloads, err := payloadsFunc(&iotago.Indexation{Index: index, Data: data})
 	if err != nil {
 		return time.Duration(0), time.Duration(0), err
 	}
 
-	if err := s.sendMessageFunc(msg); err != nil {
-		return time.Duration(0), time.Duration(0), err
+	var durationPOW time.Duration
+	for _, payload := range payloads {
+		iotaMsg := &iotago.Message{
+			NetworkID: s.networkID,
+			Parents:   tips.ToSliceOfArrays(),
+			Payload:   payload,
+		}
+
+		timeStart = time.Now()
+		if err := s.powHandler.DoPoW(iotaMsg, shutdownSignal, 1, func() (tips hornet.MessageIDs, err error) {
+			// refresh tips of the spammer if PoW takes longer than a configured duration.
+			_, refreshedTips, err := tipselFunc()
+			return refreshedTips, err
+		}); err != nil {
+			return time.Duration(0), time.Duration(0), err
+		}
+		durationPOW += time.Since(timeStart)
+
+		msg, err := storage.NewMessage(iotaMsg, iotago.DeSeriModePerformValidation)
+		if err != nil {
+			return time.Duration(0), time.Duration(0), err
+		}
+
+		if err := s.sendMessageFunc(msg); err != nil {
+			return time.Duration(0), time.Duration(0), err
+		}
+
+		if s.tracker != nil {
+			s.tracker.TrackMessage(msg.MessageID(), entry)
+		}
 	}
 
 	return durationGTTA, durationPOW, nil
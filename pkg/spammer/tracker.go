@@ -0,0 +1,373 @@
+package spammer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// MessageState is the state of a message sent by the spammer.
+type MessageState string
+
+const (
+	// MessageStatePending means the message was not referenced by a milestone yet.
+	MessageStatePending MessageState = "pending"
+	// MessageStateReferenced means the message was referenced by a milestone without a ledger conflict.
+	MessageStateReferenced MessageState = "referenced"
+	// MessageStateConflicting means the message was referenced by a milestone, but the transaction was conflicting.
+	MessageStateConflicting MessageState = "conflicting"
+	// MessageStateUnreferenced means the message was not referenced by a milestone within the timeout.
+	MessageStateUnreferenced MessageState = "unreferenced"
+)
+
+var (
+	// LatencyBuckets are the upper bounds of the buckets of the confirmation latency histogram.
+	LatencyBuckets = []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		5 * time.Second,
+		10 * time.Second,
+		15 * time.Second,
+		20 * time.Second,
+		30 * time.Second,
+		45 * time.Second,
+		60 * time.Second,
+		90 * time.Second,
+		120 * time.Second,
+		180 * time.Second,
+		300 * time.Second,
+	}
+)
+
+// TrackedMessage is a message sent by the spammer that is tracked until it is referenced by a milestone.
+type TrackedMessage struct {
+	// The ID of the message.
+	MessageID hornet.MessageID
+	// The name of the spam kind or scenario entry of the message.
+	Entry string
+	// The time the message was sent.
+	SentTime time.Time
+	// The state of the message.
+	State MessageState
+	// The time the message was referenced by a milestone.
+	ReferencedTime time.Time
+	// The index of the milestone that referenced the message.
+	ReferencedByMilestoneIndex milestone.Index
+	// The reason why the transaction of the message was conflicting.
+	Conflict storage.Conflict
+}
+
+// Latency returns the duration between sending the message and the reference by a milestone.
+func (m *TrackedMessage) Latency() time.Duration {
+	if m.ReferencedTime.IsZero() {
+		return 0
+	}
+	return m.ReferencedTime.Sub(m.SentTime)
+}
+
+// LatencyBucket is a bucket of the confirmation latency histogram.
+type LatencyBucket struct {
+	// The upper bound of the bucket in seconds (0 = no upper bound).
+	UpperBound float64 `json:"upperBound"`
+	// The amount of messages in the bucket.
+	Count uint64 `json:"count"`
+}
+
+// TrackerStatistics are the statistics of the messages sent by the spammer.
+type TrackerStatistics struct {
+	// The amount of tracked messages.
+	Sent uint64 `json:"sent"`
+	// The amount of messages that were not referenced yet.
+	Pending int `json:"pending"`
+	// The amount of messages that were referenced without a ledger conflict.
+	Referenced uint64 `json:"referenced"`
+	// The amount of messages that were referenced with a conflicting transaction.
+	Conflicting uint64 `json:"conflicting"`
+	// The amount of messages that were not referenced within the timeout.
+	Unreferenced uint64 `json:"unreferenced"`
+	// The ratio of referenced messages to all finished messages.
+	ReferencedRatio float64 `json:"referencedRatio"`
+	// The ratio of conflicting messages to all finished messages.
+	ConflictingRatio float64 `json:"conflictingRatio"`
+	// The ratio of unreferenced messages to all finished messages.
+	UnreferencedRatio float64 `json:"unreferencedRatio"`
+	// The average confirmation latency of the referenced messages in seconds.
+	AverageLatency float64 `json:"averageLatency"`
+	// The confirmation latency histogram of the referenced messages.
+	LatencyHistogram []*LatencyBucket `json:"latencyHistogram"`
+}
+
+// TrackerEvents are the events issued by the tracker.
+type TrackerEvents struct {
+	// Fired when a tracked message was referenced by a milestone or is considered unreferenced.
+	MessageFinished *events.Event
+}
+
+// TrackedMessageCaller is used to signal a finished TrackedMessage.
+func TrackedMessageCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*TrackedMessage))(params[0].(*TrackedMessage))
+}
+
+// Tracker tracks the messages sent by the spammer until they are referenced by a milestone.
+type Tracker struct {
+	sync.RWMutex
+
+	// events of the tracker.
+	Events *TrackerEvents
+
+	// the duration after which a message that was not referenced is considered unreferenced.
+	unreferencedTimeout time.Duration
+	// the maximum amount of finished messages that are kept for the report.
+	maxReportEntries int
+
+	// the messages that were not referenced yet.
+	pending map[string]*TrackedMessage
+	// the latest finished messages.
+	finished []*TrackedMessage
+
+	sent          uint64
+	referenced    uint64
+	conflicting   uint64
+	unreferenced  uint64
+	latencySum    time.Duration
+	latencyCounts []uint64
+}
+
+// NewTracker creates a new tracker.
+func NewTracker(unreferencedTimeout time.Duration, maxReportEntries int) *Tracker {
+	t := &Tracker{
+		Events: &TrackerEvents{
+			MessageFinished: events.NewEvent(TrackedMessageCaller),
+		},
+		unreferencedTimeout: unreferencedTimeout,
+		maxReportEntries:    maxReportEntries,
+	}
+	t.reset()
+
+	return t
+}
+
+// reset removes all tracked messages and statistics.
+// write lock must be acquired outside.
+func (t *Tracker) reset() {
+	t.pending = make(map[string]*TrackedMessage)
+	t.finished = make([]*TrackedMessage, 0)
+	t.sent = 0
+	t.referenced = 0
+	t.conflicting = 0
+	t.unreferenced = 0
+	t.latencySum = 0
+	t.latencyCounts = make([]uint64, len(LatencyBuckets)+1)
+}
+
+// Reset removes all tracked messages and statistics.
+func (t *Tracker) Reset() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.reset()
+}
+
+// TrackMessage adds a sent message to the tracker.
+func (t *Tracker) TrackMessage(messageID hornet.MessageID, entry string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.pending[messageID.ToMapKey()] = &TrackedMessage{
+		MessageID: messageID,
+		Entry:     entry,
+		SentTime:  time.Now(),
+		State:     MessageStatePending,
+	}
+	t.sent++
+}
+
+// finish moves a pending message to the finished messages and updates the statistics.
+// write lock must be acquired outside.
+func (t *Tracker) finish(msg *TrackedMessage) {
+	delete(t.pending, msg.MessageID.ToMapKey())
+
+	switch msg.State {
+	case MessageStateReferenced, MessageStateConflicting:
+		if msg.State == MessageStateReferenced {
+			t.referenced++
+		} else {
+			t.conflicting++
+		}
+
+		latency := msg.Latency()
+		t.latencySum += latency
+
+		bucket := sort.Search(len(LatencyBuckets), func(i int) bool { return latency <= LatencyBuckets[i] })
+		t.latencyCounts[bucket]++
+
+	case MessageStateUnreferenced:
+		t.unreferenced++
+	}
+
+	t.finished = append(t.finished, msg)
+	if t.maxReportEntries > 0 && len(t.finished) > t.maxReportEntries {
+		t.finished = t.finished[len(t.finished)-t.maxReportEntries:]
+	}
+}
+
+// MessageReferenced marks a tracked message as referenced by the given milestone.
+// Messages that are not tracked are ignored.
+func (t *Tracker) MessageReferenced(messageID hornet.MessageID, msIndex milestone.Index, conflict storage.Conflict) {
+
+	msg := func() *TrackedMessage {
+		t.Lock()
+		defer t.Unlock()
+
+		msg, exists := t.pending[messageID.ToMapKey()]
+		if !exists {
+			return nil
+		}
+
+		msg.State = MessageStateReferenced
+		if conflict != storage.ConflictNone {
+			msg.State = MessageStateConflicting
+		}
+		msg.ReferencedTime = time.Now()
+		msg.ReferencedByMilestoneIndex = msIndex
+		msg.Conflict = conflict
+
+		t.finish(msg)
+
+		// the event handlers get a copy, so they don't need the lock of the tracker
+		msgCopy := *msg
+		return &msgCopy
+	}()
+
+	if msg != nil {
+		t.Events.MessageFinished.Trigger(msg)
+	}
+}
+
+// CheckUnreferenced marks all messages that were not referenced within the timeout as unreferenced.
+func (t *Tracker) CheckUnreferenced() {
+
+	unreferencedMsgs := func() []*TrackedMessage {
+		t.Lock()
+		defer t.Unlock()
+
+		var unreferencedMsgs []*TrackedMessage
+		for _, msg := range t.pending {
+			if time.Since(msg.SentTime) <= t.unreferencedTimeout {
+				continue
+			}
+
+			msg.State = MessageStateUnreferenced
+			t.finish(msg)
+
+			msgCopy := *msg
+			unreferencedMsgs = append(unreferencedMsgs, &msgCopy)
+		}
+
+		return unreferencedMsgs
+	}()
+
+	for _, msg := range unreferencedMsgs {
+		t.Events.MessageFinished.Trigger(msg)
+	}
+}
+
+// Statistics returns the statistics of the tracked messages.
+func (t *Tracker) Statistics() *TrackerStatistics {
+	t.RLock()
+	defer t.RUnlock()
+
+	stats := &TrackerStatistics{
+		Sent:             t.sent,
+		Pending:          len(t.pending),
+		Referenced:       t.referenced,
+		Conflicting:      t.conflicting,
+		Unreferenced:     t.unreferenced,
+		LatencyHistogram: make([]*LatencyBucket, len(t.latencyCounts)),
+	}
+
+	if finished := t.referenced + t.conflicting + t.unreferenced; finished > 0 {
+		stats.ReferencedRatio = float64(t.referenced) / float64(finished)
+		stats.ConflictingRatio = float64(t.conflicting) / float64(finished)
+		stats.UnreferencedRatio = float64(t.unreferenced) / float64(finished)
+	}
+
+	if referenced := t.referenced + t.conflicting; referenced > 0 {
+		stats.AverageLatency = t.latencySum.Seconds() / float64(referenced)
+	}
+
+	for i, count := range t.latencyCounts {
+		bucket := &LatencyBucket{Count: count}
+		if i < len(LatencyBuckets) {
+			bucket.UpperBound = LatencyBuckets[i].Seconds()
+		}
+		stats.LatencyHistogram[i] = bucket
+	}
+
+	return stats
+}
+
+// Messages returns copies of the latest finished and all pending messages, sorted by the time they were sent.
+func (t *Tracker) Messages() []TrackedMessage {
+	t.RLock()
+	defer t.RUnlock()
+
+	// the pending messages are modified by the tracker, so they are copied while the lock is held
+	msgs := make([]TrackedMessage, 0, len(t.finished)+len(t.pending))
+	for _, msg := range t.finished {
+		msgs = append(msgs, *msg)
+	}
+	for _, msg := range t.pending {
+		msgs = append(msgs, *msg)
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].SentTime.Before(msgs[j].SentTime)
+	})
+
+	return msgs
+}
+
+// WriteCSV writes a report of the latest finished and all pending messages in CSV format.
+func (t *Tracker) WriteCSV(w io.Writer) error {
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"message_id", "entry", "state", "sent_time", "referenced_time", "latency_ms", "milestone_index", "conflict"}); err != nil {
+		return fmt.Errorf("unable to write CSV header: %w", err)
+	}
+
+	for _, msg := range t.Messages() {
+		var referencedTime, latency, msIndex string
+		if !msg.ReferencedTime.IsZero() {
+			referencedTime = msg.ReferencedTime.Format(time.RFC3339Nano)
+			latency = strconv.FormatInt(msg.Latency().Milliseconds(), 10)
+			msIndex = strconv.FormatUint(uint64(msg.ReferencedByMilestoneIndex), 10)
+		}
+
+		if err := csvWriter.Write([]string{
+			msg.MessageID.ToHex(),
+			msg.Entry,
+			string(msg.State),
+			msg.SentTime.Format(time.RFC3339Nano),
+			referencedTime,
+			latency,
+			msIndex,
+			strconv.Itoa(int(msg.Conflict)),
+		}); err != nil {
+			return fmt.Errorf("unable to write CSV record: %w", err)
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
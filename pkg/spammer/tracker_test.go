@@ -0,0 +1,132 @@
+package spammer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/events"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func randMessageID() hornet.MessageID {
+	messageID := make(hornet.MessageID, iotago.MessageIDLength)
+	rand.Read(messageID)
+	return messageID
+}
+
+func TestTracker(t *testing.T) {
+
+	tracker := NewTracker(time.Minute, 2)
+
+	var finished []*TrackedMessage
+	tracker.Events.MessageFinished.Attach(events.NewClosure(func(msg *TrackedMessage) {
+		finished = append(finished, msg)
+	}))
+
+	referencedID := randMessageID()
+	conflictingID := randMessageID()
+	unreferencedID := randMessageID()
+	pendingID := randMessageID()
+
+	tracker.TrackMessage(referencedID, "indexation")
+	tracker.TrackMessage(conflictingID, "conflict")
+	tracker.TrackMessage(unreferencedID, "indexation")
+	tracker.TrackMessage(pendingID, "indexation")
+
+	// messages that are not tracked are ignored
+	tracker.MessageReferenced(randMessageID(), 10, storage.ConflictNone)
+
+	tracker.MessageReferenced(referencedID, 10, storage.ConflictNone)
+	tracker.MessageReferenced(conflictingID, 11, storage.ConflictInputUTXOAlreadySpent)
+
+	// the same message is only finished once
+	tracker.MessageReferenced(referencedID, 12, storage.ConflictNone)
+
+	// let the unreferenced message time out
+	tracker.pending[unreferencedID.ToMapKey()].SentTime = time.Now().Add(-2 * time.Minute)
+	tracker.CheckUnreferenced()
+
+	require.Len(t, finished, 3)
+	require.Equal(t, MessageStateReferenced, finished[0].State)
+	require.Equal(t, MessageStateConflicting, finished[1].State)
+	require.Equal(t, MessageStateUnreferenced, finished[2].State)
+
+	stats := tracker.Statistics()
+	require.EqualValues(t, 4, stats.Sent)
+	require.Equal(t, 1, stats.Pending)
+	require.EqualValues(t, 1, stats.Referenced)
+	require.EqualValues(t, 1, stats.Conflicting)
+	require.EqualValues(t, 1, stats.Unreferenced)
+	require.InDelta(t, 1.0/3.0, stats.ReferencedRatio, 0.0001)
+	require.InDelta(t, 1.0/3.0, stats.ConflictingRatio, 0.0001)
+	require.InDelta(t, 1.0/3.0, stats.UnreferencedRatio, 0.0001)
+
+	// both referenced messages are in the first bucket
+	require.Len(t, stats.LatencyHistogram, len(LatencyBuckets)+1)
+	require.EqualValues(t, 2, stats.LatencyHistogram[0].Count)
+	require.Equal(t, 0.0, stats.LatencyHistogram[len(LatencyBuckets)].UpperBound)
+
+	// only the latest two finished messages and the pending message are kept for the report
+	var buf bytes.Buffer
+	require.NoError(t, tracker.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, "message_id", records[0][0])
+
+	states := make(map[string]string)
+	for _, record := range records[1:] {
+		states[record[0]] = record[2]
+	}
+	require.Equal(t, map[string]string{
+		conflictingID.ToHex():  string(MessageStateConflicting),
+		unreferencedID.ToHex(): string(MessageStateUnreferenced),
+		pendingID.ToHex():      string(MessageStatePending),
+	}, states)
+
+	tracker.Reset()
+	require.EqualValues(t, 0, tracker.Statistics().Sent)
+	require.Empty(t, tracker.Messages())
+}
+
+func TestTrackerConcurrentReport(t *testing.T) {
+
+	tracker := NewTracker(time.Minute, 100)
+
+	messageIDs := make(hornet.MessageIDs, 1000)
+	for i := range messageIDs {
+		messageIDs[i] = randMessageID()
+		tracker.TrackMessage(messageIDs[i], "indexation")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, messageID := range messageIDs {
+			tracker.MessageReferenced(messageID, milestone.Index(i), storage.ConflictNone)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tracker.WriteCSV(io.Discard))
+	}
+	wg.Wait()
+
+	// the returned messages are copies
+	msgs := tracker.Messages()
+	require.Len(t, msgs, 100)
+	msgs[0].State = MessageStatePending
+	require.Equal(t, MessageStateReferenced, tracker.Messages()[0].State)
+}
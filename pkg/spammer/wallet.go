@@ -0,0 +1,216 @@
+package spammer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	iotago "github.com/iotaledger/iota.go/v2"
+	"github.com/iotaledger/iota.go/v2/ed25519"
+)
+
+const (
+	// the duration after which an output that was spent by the spammer,
+	// but is still unspent in the ledger, is used again.
+	inFlightOutputTimeout = 2 * time.Minute
+	// the maximum amount of unspent outputs that are read per address to find a spendable output.
+	maxOutputsPerAddress = 128
+)
+
+var (
+	// ErrNoSpendableOutputs is returned if none of the wallet addresses holds a spendable output.
+	ErrNoSpendableOutputs = errors.New("no spendable outputs")
+	// ErrNotEnoughAddresses is returned if conflicting transactions are requested from a wallet with a single address.
+	ErrNotEnoughAddresses = errors.New("conflicting transactions need at least two addresses")
+)
+
+// walletAddress is an address of the wallet.
+type walletAddress struct {
+	address *iotago.Ed25519Address
+	signer  iotago.AddressSigner
+}
+
+// Wallet holds the addresses the spammer uses to issue value transactions.
+// The addresses are derived from a seed, the funds sent to any of the addresses are cycled between all of them.
+// Only confirmed outputs are spent, and all created outputs respect the dust protection rules.
+type Wallet struct {
+	sync.Mutex
+
+	// used to get the outputs.
+	utxoManager *utxo.Manager
+	// the addresses of the wallet.
+	addresses []*walletAddress
+	// the index of the address the next output is searched on.
+	nextAddressIndex int
+	// the outputs that were spent by the spammer, but are not confirmed yet.
+	inFlight map[string]time.Time
+}
+
+// NewWallet creates a new wallet with the given amount of addresses derived from the seed.
+func NewWallet(utxoManager *utxo.Manager, seed []byte, addressCount int) (*Wallet, error) {
+
+	if len(seed) == 0 {
+		return nil, errors.New("seed is empty")
+	}
+
+	if addressCount < 1 {
+		return nil, fmt.Errorf("at least one address is needed, got %d", addressCount)
+	}
+
+	addresses := make([]*walletAddress, addressCount)
+	for i := 0; i < addressCount; i++ {
+		indexBytes := make([]byte, 4)
+		binary.LittleEndian.PutUint32(indexBytes, uint32(i))
+
+		keySeed := blake2b.Sum256(append(append([]byte{}, seed...), indexBytes...))
+		privateKey := ed25519.NewKeyFromSeed(keySeed[:])
+
+		address := iotago.AddressFromEd25519PubKey(privateKey.Public().(ed25519.PublicKey))
+		addresses[i] = &walletAddress{
+			address: &address,
+			signer:  iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(&address, privateKey)),
+		}
+	}
+
+	return &Wallet{
+		utxoManager: utxoManager,
+		addresses:   addresses,
+		inFlight:    make(map[string]time.Time),
+	}, nil
+}
+
+// FundingAddress returns the address that has to be funded to use the wallet.
+func (w *Wallet) FundingAddress() *iotago.Ed25519Address {
+	return w.addresses[0].address
+}
+
+// AddressCount returns the amount of addresses of the wallet.
+func (w *Wallet) AddressCount() int {
+	return len(w.addresses)
+}
+
+// Balance returns the sum of the confirmed funds on all addresses of the wallet.
+func (w *Wallet) Balance() (uint64, error) {
+
+	var balance uint64
+	for _, addr := range w.addresses {
+		addressBalance, _, err := w.utxoManager.ComputeBalance(utxo.FilterAddress(addr.address), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput))
+		if err != nil {
+			return 0, err
+		}
+		balance += addressBalance
+	}
+
+	return balance, nil
+}
+
+// nextOutput searches a confirmed output that is not in flight, starting at the next address of the wallet.
+// the output is marked as in flight.
+func (w *Wallet) nextOutput() (int, *utxo.Output, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	now := time.Now()
+	for outputID, spentTime := range w.inFlight {
+		if now.Sub(spentTime) > inFlightOutputTimeout {
+			delete(w.inFlight, outputID)
+		}
+	}
+
+	for i := 0; i < len(w.addresses); i++ {
+		addressIndex := (w.nextAddressIndex + i) % len(w.addresses)
+
+		unspentOutputs, err := w.utxoManager.UnspentOutputs(utxo.FilterAddress(w.addresses[addressIndex].address), utxo.FilterOutputType(iotago.OutputSigLockedSingleOutput), utxo.MaxResultCount(maxOutputsPerAddress))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, output := range unspentOutputs {
+			if output.Amount() < iotago.OutputSigLockedDustAllowanceOutputMinDeposit {
+				// dust outputs are not used, they would need a dust allowance on the target address
+				continue
+			}
+
+			outputKey := string(output.OutputID()[:])
+			if _, exists := w.inFlight[outputKey]; exists {
+				continue
+			}
+
+			w.inFlight[outputKey] = now
+			w.nextAddressIndex = (addressIndex + 1) % len(w.addresses)
+
+			return addressIndex, output, nil
+		}
+	}
+
+	return 0, nil, ErrNoSpendableOutputs
+}
+
+// buildTransaction creates a signed transaction that spends a confirmed output of the wallet.
+// the funds are sent to the next address, or split between the next two addresses if both parts are above the dust threshold.
+func (w *Wallet) buildTransaction(indexationPayload *iotago.Indexation) (*iotago.Transaction, error) {
+
+	addressIndex, output, err := w.nextOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	input := w.addresses[addressIndex]
+	target1 := w.addresses[(addressIndex+1)%len(w.addresses)]
+	target2 := w.addresses[(addressIndex+2)%len(w.addresses)]
+
+	txBuilder := iotago.NewTransactionBuilder()
+	txBuilder.AddIndexationPayload(indexationPayload)
+	txBuilder.AddInput(&iotago.ToBeSignedUTXOInput{Address: input.address, Input: output.UTXOInput()})
+
+	amount := output.Amount()
+	if amount >= 2*iotago.OutputSigLockedDustAllowanceOutputMinDeposit && target1 != target2 {
+		// split the funds to increase the amount of outputs that can be spent in parallel
+		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: target1.address, Amount: amount / 2})
+		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: target2.address, Amount: amount - amount/2})
+	} else {
+		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: target1.address, Amount: amount})
+	}
+
+	return txBuilder.Build(input.signer)
+}
+
+// buildConflictingTransactions creates two signed transactions that spend the same confirmed output of the wallet.
+// the funds are sent to different addresses, so only one of the transactions can be applied to the ledger.
+func (w *Wallet) buildConflictingTransactions(indexationPayload *iotago.Indexation) ([]*iotago.Transaction, error) {
+
+	if len(w.addresses) < 2 {
+		// both transactions would be equal
+		return nil, ErrNotEnoughAddresses
+	}
+
+	addressIndex, output, err := w.nextOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	input := w.addresses[addressIndex]
+
+	txs := make([]*iotago.Transaction, 2)
+	for i := range txs {
+		target := w.addresses[(addressIndex+1+i)%len(w.addresses)]
+
+		txBuilder := iotago.NewTransactionBuilder()
+		txBuilder.AddIndexationPayload(indexationPayload)
+		txBuilder.AddInput(&iotago.ToBeSignedUTXOInput{Address: input.address, Input: output.UTXOInput()})
+		txBuilder.AddOutput(&iotago.SigLockedSingleOutput{Address: target.address, Amount: output.Amount()})
+
+		tx, err := txBuilder.Build(input.signer)
+		if err != nil {
+			return nil, err
+		}
+		txs[i] = tx
+	}
+
+	return txs, nil
+}
@@ -0,0 +1,139 @@
+package spammer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func randOutput(address *iotago.Ed25519Address, amount uint64) *utxo.Output {
+	outputID := &iotago.UTXOInputID{}
+	rand.Read(outputID[:iotago.TransactionIDLength])
+	messageID := hornet.MessageID(make([]byte, iotago.MessageIDLength))
+	rand.Read(messageID)
+	return utxo.CreateOutput(outputID, messageID, iotago.OutputSigLockedSingleOutput, address, amount)
+}
+
+func TestWallet(t *testing.T) {
+
+	utxoManager := utxo.New(mapdb.NewMapDB())
+
+	_, err := NewWallet(utxoManager, []byte("seed"), 0)
+	require.Error(t, err)
+
+	wallet, err := NewWallet(utxoManager, []byte("seed"), 3)
+	require.NoError(t, err)
+
+	// the addresses are derived deterministically from the seed
+	sameWallet, err := NewWallet(utxoManager, []byte("seed"), 3)
+	require.NoError(t, err)
+	require.Equal(t, wallet.FundingAddress(), sameWallet.FundingAddress())
+
+	_, err = wallet.buildTransaction(&iotago.Indexation{Index: []byte("test")})
+	require.ErrorIs(t, err, ErrNoSpendableOutputs)
+
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(wallet.FundingAddress(), 3000000)))
+	// dust outputs are ignored
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(wallet.addresses[1].address, 500000)))
+
+	balance, err := wallet.Balance()
+	require.NoError(t, err)
+	require.EqualValues(t, 3500000, balance)
+
+	tx, err := wallet.buildTransaction(&iotago.Indexation{Index: []byte("test")})
+	require.NoError(t, err)
+
+	essence := tx.Essence.(*iotago.TransactionEssence)
+	require.Len(t, essence.Inputs, 1)
+	require.Len(t, essence.Outputs, 2)
+
+	var sum uint64
+	for _, output := range essence.Outputs {
+		amount := output.(*iotago.SigLockedSingleOutput).Amount
+		require.GreaterOrEqual(t, amount, iotago.OutputSigLockedDustAllowanceOutputMinDeposit)
+		sum += amount
+	}
+	require.EqualValues(t, 3000000, sum)
+
+	// the output is in flight until it is confirmed
+	_, err = wallet.buildTransaction(&iotago.Indexation{Index: []byte("test")})
+	require.ErrorIs(t, err, ErrNoSpendableOutputs)
+
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(wallet.addresses[2].address, 1500000)))
+
+	txs, err := wallet.buildConflictingTransactions(&iotago.Indexation{Index: []byte("test")})
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+
+	essence1 := txs[0].Essence.(*iotago.TransactionEssence)
+	essence2 := txs[1].Essence.(*iotago.TransactionEssence)
+	require.Equal(t, essence1.Inputs, essence2.Inputs)
+	require.NotEqual(t, essence1.Outputs, essence2.Outputs)
+}
+
+func TestConflictingTransactionPayloads(t *testing.T) {
+
+	indexation := &iotago.Indexation{Index: []byte("test")}
+
+	utxoManager := utxo.New(mapdb.NewMapDB())
+
+	// a single address can't be used for conflicts, both transactions would be equal
+	singleAddressWallet, err := NewWallet(utxoManager, []byte("single"), 1)
+	require.NoError(t, err)
+	require.NoError(t, utxoManager.AddUnspentOutput(randOutput(singleAddressWallet.FundingAddress(), 2000000)))
+
+	_, err = (&Spammer{wallet: singleAddressWallet}).conflictingTransactionPayloads(indexation)
+	require.ErrorIs(t, err, ErrNotEnoughAddresses)
+
+	// value spam works with a single address
+	_, err = singleAddressWallet.buildTransaction(indexation)
+	require.NoError(t, err)
+
+	_, err = (&Spammer{}).conflictingTransactionPayloads(indexation)
+	require.ErrorIs(t, err, ErrValueSpamDisabled)
+
+	wallet, err := NewWallet(utxoManager, []byte("seed"), 2)
+	require.NoError(t, err)
+
+	spentOutput := randOutput(wallet.FundingAddress(), 2000000)
+	require.NoError(t, utxoManager.AddUnspentOutput(spentOutput))
+
+	payloads, err := (&Spammer{wallet: wallet}).conflictingTransactionPayloads(indexation)
+	require.NoError(t, err)
+	require.Len(t, payloads, 2)
+
+	transactionIDs := make(map[iotago.TransactionID]struct{})
+	targetAddresses := make(map[iotago.Ed25519Address]struct{})
+	for _, payload := range payloads {
+		tx, ok := payload.(*iotago.Transaction)
+		require.True(t, ok)
+
+		transactionID, err := tx.ID()
+		require.NoError(t, err)
+		transactionIDs[*transactionID] = struct{}{}
+
+		// both transactions spend the same output
+		essence := tx.Essence.(*iotago.TransactionEssence)
+		require.Len(t, essence.Inputs, 1)
+		require.Equal(t, *spentOutput.OutputID(), essence.Inputs[0].(*iotago.UTXOInput).ID())
+		require.Equal(t, indexation, essence.Payload)
+
+		// all funds are sent to a single address
+		require.Len(t, essence.Outputs, 1)
+		output := essence.Outputs[0].(*iotago.SigLockedSingleOutput)
+		require.EqualValues(t, 2000000, output.Amount)
+		targetAddresses[*output.Address.(*iotago.Ed25519Address)] = struct{}{}
+
+		require.Len(t, tx.UnlockBlocks, 1)
+	}
+
+	// the transactions are different and send the funds to different addresses
+	require.Len(t, transactionIDs, 2)
+	require.Len(t, targetAddresses, 2)
+}
@@ -0,0 +1,101 @@
+package tangle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+var (
+	// ErrNoParentsGiven is returned if the white flag confirmation should be computed without parents.
+	ErrNoParentsGiven = errors.New("no parents given")
+	// ErrParentsNotSolid is returned if the parents did not become solid in time.
+	ErrParentsNotSolid = errors.New("parents not solid")
+)
+
+// ComputeWhiteFlagMutations computes the white flag confirmation for the cone of the given parents.
+// It waits until all parents are solid or the context is done.
+func (t *Tangle) ComputeWhiteFlagMutations(ctx context.Context, index milestone.Index, parents hornet.MessageIDs) (*whiteflag.WhiteFlagMutations, error) {
+
+	// check if the requested milestone index would be the next one
+	if index > t.syncManager.ConfirmedMilestoneIndex()+1 {
+		return nil, common.ErrNodeNotSynced
+	}
+
+	if len(parents) < 1 {
+		return nil, ErrNoParentsGiven
+	}
+
+	// register all parents for message solid events
+	// this has to be done, even if the parents may be solid already, to prevent race conditions
+	msgSolidEventChans := make([]chan struct{}, len(parents))
+	for i, parent := range parents {
+		msgSolidEventChans[i] = t.RegisterMessageSolidEvent(parent)
+	}
+
+	// check all parents for solidity
+	for _, parent := range parents {
+		cachedMsgMeta := t.storage.CachedMessageMetadataOrNil(parent)
+		if cachedMsgMeta == nil {
+			if t.storage.SolidEntryPointsContain(parent) {
+				// deregister the event, because the parent is already solid (this also fires the event)
+				t.DeregisterMessageSolidEvent(parent)
+			}
+			continue
+		}
+
+		cachedMsgMeta.ConsumeMetadata(func(metadata *storage.MessageMetadata) { // metadata -1
+			if !metadata.IsSolid() {
+				return
+			}
+
+			// deregister the event, because the parent is already solid (this also fires the event)
+			t.DeregisterMessageSolidEvent(parent)
+		})
+	}
+
+	messagesMemcache := storage.NewMessagesMemcache(t.storage)
+	metadataMemcache := storage.NewMetadataMemcache(t.storage)
+
+	defer func() {
+		// deregister the events to free the memory
+		for _, parent := range parents {
+			t.DeregisterMessageSolidEvent(parent)
+		}
+
+		// release all messages at the end
+		messagesMemcache.Cleanup(true)
+
+		// Release all message metadata at the end
+		metadataMemcache.Cleanup(true)
+	}()
+
+	// check if all requested parents are solid
+	solid, _ := t.SolidQueueCheck(messagesMemcache, metadataMemcache, index, parents, nil)
+
+	if !solid {
+		for _, msgSolidEventChan := range msgSolidEventChans {
+			// wait until the message is solid
+			if err := utils.WaitForChannelClosed(ctx, msgSolidEventChan); err != nil {
+				return nil, ErrParentsNotSolid
+			}
+		}
+	}
+
+	// at this point all parents are solid
+	// compute merkle tree root
+	mutations, err := whiteflag.ComputeWhiteFlagMutations(t.storage, index, metadataMemcache, messagesMemcache, parents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute white flag mutations: %w", err)
+	}
+
+	return mutations, nil
+}
@@ -17,32 +17,34 @@ import (
 	"github.com/iotaledger/iota.go/v2/ed25519"
 )
 
+// SendMessage stores the message in the database and updates the latest milestone index if the message is a milestone.
+// It is used as the SendMessageFunc of the coordinator instances.
+func (te *TestEnvironment) SendMessage(msg *storage.Message, _ ...milestone.Index) error {
+	cachedMessage := te.StoreMessage(msg) // no need to release, since we remember all the messages for later cleanup
+
+	ms := cachedMessage.Message().Milestone()
+	if ms != nil {
+		te.syncManager.SetLatestMilestoneIndex(milestone.Index(ms.Index))
+	}
+
+	return nil
+}
+
 // configureCoordinator configures a new coordinator with clean state for the tests.
 // the node is initialized, the network is bootstrapped and the first milestone is confirmed.
 func (te *TestEnvironment) configureCoordinator(cooPrivateKeys []ed25519.PrivateKey, keyManager *keymanager.KeyManager) {
 
-	storeMessageFunc := func(msg *storage.Message, _ ...milestone.Index) error {
-		cachedMessage := te.StoreMessage(msg) // no need to release, since we remember all the messages for later cleanup
-
-		ms := cachedMessage.Message().Milestone()
-		if ms != nil {
-			te.syncManager.SetLatestMilestoneIndex(milestone.Index(ms.Index))
-		}
-
-		return nil
-	}
-
-	inMemoryEd25519MilestoneSignerProvider := coordinator.NewInMemoryEd25519MilestoneSignerProvider(cooPrivateKeys, keyManager, len(cooPrivateKeys))
+	te.milestoneSignerProvider = coordinator.NewInMemoryEd25519MilestoneSignerProvider(cooPrivateKeys, keyManager, len(cooPrivateKeys))
 
 	coo, err := coordinator.New(
 		te.storage,
 		te.syncManager,
 		te.networkID,
-		inMemoryEd25519MilestoneSignerProvider,
+		te.milestoneSignerProvider,
 		nil,
 		nil,
 		te.PoWHandler,
-		storeMessageFunc,
+		te.SendMessage,
 		coordinator.WithStateFilePath(fmt.Sprintf("%s/coordinator.state", te.tempDir)),
 		coordinator.WithMilestoneInterval(time.Duration(10)*time.Second),
 	)
@@ -108,7 +110,12 @@ func (te *TestEnvironment) IssueAndConfirmMilestoneOnTips(tips hornet.MessageIDs
 
 	te.VerifyLMI(currentIndex + 1)
 
-	milestoneIndex := currentIndex + 1
+	return te.ConfirmMilestone(currentIndex+1, createConfirmationGraph)
+}
+
+// ConfirmMilestone confirms the milestone with the given index, which was issued by a coordinator instance.
+func (te *TestEnvironment) ConfirmMilestone(milestoneIndex milestone.Index, createConfirmationGraph bool) (*whiteflag.Confirmation, *whiteflag.ConfirmedMilestoneStats) {
+
 	ms := te.storage.CachedMilestoneOrNil(milestoneIndex)
 	require.NotNil(te.TestInterface, ms)
 
@@ -130,7 +137,7 @@ func (te *TestEnvironment) IssueAndConfirmMilestoneOnTips(tips hornet.MessageIDs
 		func(txMeta *storage.CachedMetadata, index milestone.Index, confTime uint64) {},
 		func(confirmation *whiteflag.Confirmation) {
 			wfConf = confirmation
-			err = te.syncManager.SetConfirmedMilestoneIndex(confirmation.MilestoneIndex, true)
+			err := te.syncManager.SetConfirmedMilestoneIndex(confirmation.MilestoneIndex, true)
 			require.NoError(te.TestInterface, err)
 		},
 		func(index milestone.Index, output *utxo.Output) {},
@@ -139,7 +146,7 @@ func (te *TestEnvironment) IssueAndConfirmMilestoneOnTips(tips hornet.MessageIDs
 	)
 	require.NoError(te.TestInterface, err)
 
-	require.Equal(te.TestInterface, currentIndex+1, confirmedMilestoneStats.Index)
+	require.Equal(te.TestInterface, milestoneIndex, confirmedMilestoneStats.Index)
 	te.VerifyCMI(confirmedMilestoneStats.Index)
 
 	te.AssertTotalSupplyStillValid()
@@ -158,3 +165,32 @@ func (te *TestEnvironment) IssueAndConfirmMilestoneOnTips(tips hornet.MessageIDs
 
 	return wfConf, confirmedMilestoneStats
 }
+
+// NewCoordinatorInstance creates an additional coordinator instance that uses the given shared state backend.
+// The instance shares the database and the keys with the coordinator of the test environment.
+// If sendMessageFunc is nil, the messages of the instance are stored in the database of the test environment.
+func (te *TestEnvironment) NewCoordinatorInstance(stateBackend coordinator.StateBackend, instanceID string, leaseDuration time.Duration, sendMessageFunc coordinator.SendMessageFunc) *coordinator.Coordinator {
+
+	if sendMessageFunc == nil {
+		sendMessageFunc = te.SendMessage
+	}
+
+	coo, err := coordinator.New(
+		te.storage,
+		te.syncManager,
+		te.networkID,
+		te.milestoneSignerProvider,
+		nil,
+		nil,
+		te.PoWHandler,
+		sendMessageFunc,
+		coordinator.WithMilestoneInterval(time.Duration(10)*time.Second),
+		coordinator.WithStateBackend(stateBackend, instanceID, leaseDuration),
+	)
+	require.NoError(te.TestInterface, err)
+
+	err = coo.InitState(false, 0)
+	require.NoError(te.TestInterface, err)
+
+	return coo
+}
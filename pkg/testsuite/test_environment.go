@@ -53,6 +53,9 @@ type TestEnvironment struct {
 	// coo holds the coordinator instance.
 	coo *coordinator.Coordinator
 
+	// milestoneSignerProvider is the signer provider of the coordinator instances.
+	milestoneSignerProvider coordinator.MilestoneSignerProvider
+
 	// lastMilestoneMessageID is the message ID of the last issued milestone.
 	lastMilestoneMessageID hornet.MessageID
 
@@ -87,7 +90,7 @@ func SetupTestEnvironment(testInterface testing.TB, genesisAddress *iotago.Ed255
 		Milestones:             make(storage.CachedMilestones, 0),
 		cachedMessages:         make(storage.CachedMessages, 0),
 		showConfirmationGraphs: showConfirmationGraphs,
-		PoWHandler:             pow.New(nil, targetScore, 5*time.Second, "", 30*time.Second),
+		PoWHandler:             pow.New(nil, targetScore, 5*time.Second, "", 30*time.Second, nil),
 		networkID:              iotago.NetworkIDFromString("alphanet1"),
 		lastMilestoneMessageID: hornet.NullMessageID(),
 		serverMetrics:          &metrics.ServerMetrics{},
@@ -0,0 +1,109 @@
+package toolset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/configuration"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+func coordinatorKeyAnnouncement(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [NETWORK_ID_STR] [KEY_RANGES...]", ToolCoordinatorKeyAnnounce))
+		println()
+		println("   [NETWORK_ID_STR] - the network ID for which this announcement is meant for")
+		println("   [KEY_RANGES...]  - the announced key ranges in the format 'PUBLIC_KEY:START_INDEX:END_INDEX'")
+		println()
+		println("   the announcement is signed with the private keys read from the environment variable 'COO_PRV_KEYS'")
+		println(fmt.Sprintf("   the key ranges have to start at least %d milestones after the milestone that confirms the announcement", keymanager.MinKeyRangeStartDistance))
+		println()
+		println(fmt.Sprintf("example: %s %s %s", ToolCoordinatorKeyAnnounce, "private_tangle@1", "ed3c3f1a319ff4e909cf2771d79fece0ac9bd9fd2ee49ea6c0885c9cb3b1248c:100000:200000"))
+	}
+
+	// check arguments
+	if len(args) < 2 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolCoordinatorKeyAnnounce)
+	}
+
+	networkID := iotago.NetworkIDFromString(args[0])
+
+	keyRanges := make([]*keymanager.KeyRange, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		keyRange, err := parseKeyRange(arg)
+		if err != nil {
+			return err
+		}
+		keyRanges = append(keyRanges, keyRange)
+	}
+
+	privateKeys, err := utils.LoadEd25519PrivateKeysFromEnvironment("COO_PRV_KEYS")
+	if err != nil {
+		return err
+	}
+
+	announcement, err := keymanager.NewKeyRangeAnnouncement(networkID, keyRanges)
+	if err != nil {
+		return err
+	}
+	announcement.Sign(privateKeys)
+
+	payload, err := json.Marshal(&iotago.Indexation{
+		Index: []byte(keymanager.KeyRangeAnnouncementIndex),
+		Data:  announcement.Serialize(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to serialize the indexation payload: %w", err)
+	}
+
+	messageJSON, err := json.MarshalIndent(map[string]json.RawMessage{"payload": payload}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize the message: %w", err)
+	}
+
+	fmt.Printf("Key range announcement for network ID %d signed by %d keys.\n", networkID, len(privateKeys))
+	fmt.Println()
+	fmt.Println("Issue the following message via the 'POST /api/v1/messages' endpoint of the coordinator node:")
+	fmt.Println(string(messageJSON))
+
+	return nil
+}
+
+// parseKeyRange parses a key range in the format 'PUBLIC_KEY:START_INDEX:END_INDEX'.
+func parseKeyRange(keyRangeStr string) (*keymanager.KeyRange, error) {
+
+	parts := strings.Split(keyRangeStr, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid key range '%s', expected 'PUBLIC_KEY:START_INDEX:END_INDEX'", keyRangeStr)
+	}
+
+	pubKey, err := utils.ParseEd25519PublicKeyFromString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key '%s': %w", parts[0], err)
+	}
+
+	startIndex, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start index '%s': %w", parts[1], err)
+	}
+
+	endIndex, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end index '%s': %w", parts[2], err)
+	}
+
+	keyRange := &keymanager.KeyRange{
+		StartIndex: milestone.Index(startIndex),
+		EndIndex:   milestone.Index(endIndex),
+	}
+	copy(keyRange.PublicKey[:], pubKey)
+
+	return keyRange, nil
+}
@@ -0,0 +1,51 @@
+package toolset
+
+import (
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+func coordinatorStateServer(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [BIND_ADDRESS] [STATE_DIRECTORY]", ToolCoordinatorStateServer))
+		println()
+		println("   [BIND_ADDRESS]    - the bind address of the state server")
+		println("   [STATE_DIRECTORY] - the directory the lease and the state of the coordinator instances are stored in")
+		println()
+		printHTTPServerSettingsUsage("COO_STATE_SERVER", "coordinator instances")
+		println()
+		println(fmt.Sprintf("example: %s %s %s", ToolCoordinatorStateServer, "0.0.0.0:14267", "shared"))
+	}
+
+	// check arguments
+	if len(args) != 2 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolCoordinatorStateServer)
+	}
+
+	bindAddress := args[0]
+	stateDirectory := args[1]
+
+	stateBackend, err := coordinator.NewFileStateBackend(stateDirectory)
+	if err != nil {
+		return err
+	}
+
+	settings, err := loadHTTPServerSettings("COO_STATE_SERVER")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Coordinator state server listening on %s (state directory: %s, auth token: %t, TLS: %t)\n", bindAddress, stateDirectory, settings.authToken != "", settings.tls())
+
+	if err := serveHTTP(bindAddress, coordinator.NewStateServer(stateBackend, settings.authToken).Handler(), settings); err != nil {
+		return fmt.Errorf("coordinator state server failed: %w", err)
+	}
+
+	fmt.Println("Coordinator state server stopped")
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+func databaseBackup(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [DATABASE_PATH] [BACKUP_PATH] [STATE_FILE_PATHS...]", ToolDatabaseBackup))
+		println()
+		println("   [DATABASE_PATH]       - the path to the database")
+		println("   [BACKUP_PATH]         - the path to the backup folder that will be created")
+		println("   [STATE_FILE_PATHS...] - the paths to state files that should be included in the backup (optional)")
+		println()
+		println(fmt.Sprintf("example: %s %s %s %s %s", ToolDatabaseBackup, "mainnetdb", "backups/mainnetdb_backup", "./coordinator.state", "./migrator.state"))
+	}
+
+	// check arguments
+	if len(args) < 2 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolDatabaseBackup)
+	}
+
+	databasePath := args[0]
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("DATABASE_PATH (%s) does not exist", databasePath)
+	}
+
+	backupPath := args[1]
+	if _, err := os.Stat(backupPath); err == nil || !os.IsNotExist(err) {
+		return fmt.Errorf("BACKUP_PATH (%s) already exist", backupPath)
+	}
+
+	ts := time.Now()
+	fmt.Printf("Creating database backup... (database: \"%s\")\n", databasePath)
+
+	info, err := database.CreateBackup(backupPath, func(targetDir string) error {
+		return database.CreateCheckpointFromPath(databasePath, targetDir)
+	}, args[2:]...)
+	if err != nil {
+		return fmt.Errorf("creating database backup failed: %w", err)
+	}
+
+	for _, stateFile := range info.StateFiles {
+		fmt.Printf("Added state file to backup: %s\n", stateFile.Path)
+	}
+
+	fmt.Printf("Backup successful! (ledger index: %d) took: %v\n", info.LedgerIndex, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
+
+func databaseRestore(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [BACKUP_PATH] [DATABASE_PATH]", ToolDatabaseRestore))
+		println()
+		println("   [BACKUP_PATH]   - the path to the backup folder")
+		println("   [DATABASE_PATH] - the path to the database that will be created")
+		println()
+		println(fmt.Sprintf("example: %s %s %s", ToolDatabaseRestore, "backups/mainnetdb_backup", "mainnetdb"))
+	}
+
+	// check arguments
+	if len(args) != 2 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolDatabaseRestore)
+	}
+
+	backupPath := args[0]
+	if _, err := os.Stat(backupPath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("BACKUP_PATH (%s) does not exist", backupPath)
+	}
+
+	databasePath := args[1]
+	if _, err := os.Stat(databasePath); err == nil || !os.IsNotExist(err) {
+		return fmt.Errorf("DATABASE_PATH (%s) already exist", databasePath)
+	}
+
+	info, err := database.LoadBackupInfo(backupPath)
+	if err != nil {
+		return err
+	}
+
+	ts := time.Now()
+	fmt.Printf("Restoring database backup... (created at: %s, engine: %s, ledger index: %d)\n", info.CreatedAt.Format(time.RFC3339), info.Engine, info.LedgerIndex)
+
+	if err := database.RestoreBackupDatabase(backupPath, databasePath); err != nil {
+		return fmt.Errorf("restoring database failed: %w", err)
+	}
+
+	fmt.Println("Validating ledger state of the restored database...")
+	if err := validateRestoredDatabase(databasePath, info.LedgerIndex); err != nil {
+		// do not leave an invalid database behind
+		_ = os.RemoveAll(databasePath)
+		return fmt.Errorf("restored database is invalid: %w", err)
+	}
+
+	if err := database.RestoreBackupStateFiles(backupPath, info); err != nil {
+		return err
+	}
+
+	for _, stateFile := range info.StateFiles {
+		fmt.Printf("Restored state file: %s\n", stateFile.Path)
+	}
+
+	databasePathAbs, err := filepath.Abs(databasePath)
+	if err != nil {
+		databasePathAbs = databasePath
+	}
+
+	fmt.Printf("Restore successful! (database: \"%s\") took: %v\n", databasePathAbs, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
+
+// validateRestoredDatabase checks the ledger state of the database in the given path.
+func validateRestoredDatabase(databasePath string, ledgerIndex milestone.Index) error {
+
+	store, err := database.StoreWithDefaultSettings(databasePath, false)
+	if err != nil {
+		return fmt.Errorf("database initialization failed: %w", err)
+	}
+
+	// clean up store
+	defer func() {
+		store.Shutdown()
+		_ = store.Close()
+	}()
+
+	dbStorage, err := storage.New(store)
+	if err != nil {
+		return err
+	}
+	defer dbStorage.ShutdownStorages()
+
+	if dbStorage.SnapshotInfo() == nil {
+		return errors.New("no snapshot info found")
+	}
+
+	databaseCorrupted, err := dbStorage.IsDatabaseCorrupted()
+	if err != nil {
+		return err
+	}
+	if databaseCorrupted {
+		// the health status is cleared in backups of a running node, so the database was not shut down properly
+		fmt.Println("The database of the backup was not shut down properly, HORNET will revalidate the database at the first start (\"--revalidate\" or \"db.autoRevalidation\").")
+	}
+
+	dbLedgerIndex, err := dbStorage.UTXOManager().ReadLedgerIndex()
+	if err != nil {
+		return err
+	}
+
+	if dbLedgerIndex != ledgerIndex {
+		return fmt.Errorf("ledger index does not match the backup info: %d != %d", dbLedgerIndex, ledgerIndex)
+	}
+
+	return dbStorage.UTXOManager().CheckLedgerState()
+}
@@ -0,0 +1,133 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+const (
+	// the flag to repair the found inconsistencies.
+	databaseCheckRepairFlag = "--repair"
+	// the maximum amount of printed issues per type.
+	databaseCheckMaxPrintedIssues = 10
+)
+
+func databaseCheck(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [DATABASE_PATH] [%s]", ToolDatabaseCheck, databaseCheckRepairFlag))
+		println()
+		println("   [DATABASE_PATH] - the path to the database")
+		println(fmt.Sprintf("   [%s]      - repair the found inconsistencies (optional)", databaseCheckRepairFlag))
+		println()
+		println(fmt.Sprintf("example: %s %s %s", ToolDatabaseCheck, "mainnetdb", databaseCheckRepairFlag))
+	}
+
+	// check arguments
+	if len(args) < 1 || len(args) > 2 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolDatabaseCheck)
+	}
+
+	repair := false
+	if len(args) == 2 {
+		if strings.ToLower(args[1]) != databaseCheckRepairFlag {
+			printUsage()
+			return fmt.Errorf("unknown argument for '%s': %s", ToolDatabaseCheck, args[1])
+		}
+		repair = true
+	}
+
+	databasePath := args[0]
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("DATABASE_PATH (%s) does not exist", databasePath)
+	}
+
+	store, err := database.StoreWithDefaultSettings(databasePath, false)
+	if err != nil {
+		return fmt.Errorf("database initialization failed: %w", err)
+	}
+
+	// clean up store
+	defer func() {
+		store.Shutdown()
+		_ = store.Close()
+	}()
+
+	dbStorage, err := storage.New(store)
+	if err != nil {
+		return err
+	}
+	defer dbStorage.ShutdownStorages()
+
+	if repair {
+		fmt.Printf("Checking and repairing database consistency... (database: \"%s\")\n", databasePath)
+	} else {
+		fmt.Printf("Checking database consistency... (database: \"%s\")\n", databasePath)
+	}
+
+	report, err := dbStorage.CheckConsistency(repair)
+	if err != nil {
+		return fmt.Errorf("checking database consistency failed: %w", err)
+	}
+
+	fmt.Printf(`> 
+	- Messages checked %d
+	- Children checked %d
+	- Indexations checked %d
+	- Milestones checked %d
+	- Issues found %d`+"\n\n",
+		report.MessagesChecked,
+		report.ChildrenChecked,
+		report.IndexationsChecked,
+		report.MilestonesChecked,
+		len(report.Issues),
+	)
+
+	issueCounts := report.IssueCounts()
+
+	issueTypes := make([]string, 0, len(issueCounts))
+	for issueType := range issueCounts {
+		issueTypes = append(issueTypes, string(issueType))
+	}
+	sort.Strings(issueTypes)
+
+	for _, issueType := range issueTypes {
+		fmt.Printf("%s: %d\n", issueType, issueCounts[storage.ConsistencyIssueType(issueType)])
+
+		printed := 0
+		for _, issue := range report.Issues {
+			if string(issue.Type) != issueType {
+				continue
+			}
+
+			if printed == databaseCheckMaxPrintedIssues {
+				fmt.Println("	...")
+				break
+			}
+			printed++
+
+			if issue.Repaired {
+				fmt.Printf("	%s (repaired)\n", issue.Description)
+				continue
+			}
+			fmt.Printf("	%s\n", issue.Description)
+		}
+	}
+
+	if len(report.Issues) > 0 && !repair {
+		fmt.Printf("\nThe database is inconsistent, use '%s' to repair the issues. took: %v\n", databaseCheckRepairFlag, report.Duration.Truncate(time.Millisecond))
+		return nil
+	}
+
+	fmt.Printf("Database check successful! took: %v\n", report.Duration.Truncate(time.Millisecond))
+
+	return nil
+}
@@ -49,6 +49,10 @@ func databaseMigration(_ *configuration.Configuration, args []string) error {
 		return err
 	}
 
+	if engineTarget == database.EngineMapDB {
+		return fmt.Errorf("TARGET_DATABASE_ENGINE (%s) is not persistent", dbEngineTarget)
+	}
+
 	storeSource, err := database.StoreWithDefaultSettings(sourcePath, false)
 	if err != nil {
 		return fmt.Errorf("source database initialization failed: %w", err)
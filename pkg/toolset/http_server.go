@@ -0,0 +1,83 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+// httpServerSettings are the optional settings of the HTTP servers of the tools.
+type httpServerSettings struct {
+	// the bearer token the clients have to send.
+	authToken string
+	// the paths to the TLS certificate and its private key.
+	tlsCertPath string
+	tlsKeyPath  string
+}
+
+// loadHTTPServerSettings loads the optional settings of an HTTP server from the environment variables
+// with the given prefix, e.g. POW_WORKER_TOKEN, POW_WORKER_TLS_CERT_PATH and POW_WORKER_TLS_KEY_PATH.
+func loadHTTPServerSettings(envPrefix string) (*httpServerSettings, error) {
+
+	authToken, _ := utils.LoadStringFromEnvironment(envPrefix + "_TOKEN")
+	tlsCertPath, _ := utils.LoadStringFromEnvironment(envPrefix + "_TLS_CERT_PATH")
+	tlsKeyPath, _ := utils.LoadStringFromEnvironment(envPrefix + "_TLS_KEY_PATH")
+
+	if (tlsCertPath == "") != (tlsKeyPath == "") {
+		return nil, fmt.Errorf("both '%s_TLS_CERT_PATH' and '%s_TLS_KEY_PATH' have to be set to use TLS", envPrefix, envPrefix)
+	}
+
+	return &httpServerSettings{
+		authToken:   authToken,
+		tlsCertPath: tlsCertPath,
+		tlsKeyPath:  tlsKeyPath,
+	}, nil
+}
+
+// printHTTPServerSettingsUsage prints the usage of the environment variables with the given prefix.
+func printHTTPServerSettingsUsage(envPrefix string, clients string) {
+	println(fmt.Sprintf("   if the environment variable '%s_TOKEN' is set, the %s have to send the same token", envPrefix, clients))
+	println(fmt.Sprintf("   if the environment variables '%s_TLS_CERT_PATH' and '%s_TLS_KEY_PATH' are set, TLS is used", envPrefix, envPrefix))
+	println("   without TLS, the server must only be reachable via a private network")
+}
+
+// tls returns whether TLS is used.
+func (s *httpServerSettings) tls() bool {
+	return s.tlsCertPath != ""
+}
+
+// serveHTTP serves the handler on the bind address until the tool is interrupted.
+func serveHTTP(bindAddress string, handler http.Handler, settings *httpServerSettings) error {
+
+	server := &http.Server{
+		Addr:    bindAddress,
+		Handler: handler,
+	}
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		_ = server.Shutdown(context.Background())
+	}()
+
+	var err error
+	if settings.tls() {
+		err = server.ListenAndServeTLS(settings.tlsCertPath, settings.tlsKeyPath)
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package toolset
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/gohornet/hornet/pkg/pow"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+const (
+	// the default maximum target score of a PoW request, the target score of the mainnet is 4000.
+	defaultPoWWorkerMaxTargetScore = 4000.0
+	// the default maximum amount of concurrent PoW requests, every request already uses all configured cores.
+	defaultPoWWorkerMaxRequests = 2
+)
+
+func powWorker(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [BIND_ADDRESS] [PARALLELISM] [MAX_TARGET_SCORE] [MAX_REQUESTS]", ToolPoWWorker))
+		println()
+		println("   [BIND_ADDRESS]     - the bind address of the remote PoW worker")
+		println("   [PARALLELISM]      - the amount of CPU cores used for a single PoW request (optional, default: all cores)")
+		println(fmt.Sprintf("   [MAX_TARGET_SCORE] - the maximum target score of a PoW request (optional, default: %0.0f)", defaultPoWWorkerMaxTargetScore))
+		println(fmt.Sprintf("   [MAX_REQUESTS]     - the maximum amount of concurrent PoW requests (optional, default: %d)", defaultPoWWorkerMaxRequests))
+		println()
+		printHTTPServerSettingsUsage("POW_WORKER", "nodes")
+		println()
+		println(fmt.Sprintf("example: %s %s %s %s %s", ToolPoWWorker, "0.0.0.0:14266", "4", "4000", "2"))
+	}
+
+	// check arguments
+	if len(args) < 1 || len(args) > 4 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolPoWWorker)
+	}
+
+	bindAddress := args[0]
+
+	parallelism := runtime.NumCPU()
+	if len(args) >= 2 {
+		var err error
+		parallelism, err = strconv.Atoi(args[1])
+		if err != nil || parallelism < 1 {
+			return fmt.Errorf("invalid parallelism '%s'", args[1])
+		}
+	}
+
+	maxTargetScore := defaultPoWWorkerMaxTargetScore
+	if len(args) >= 3 {
+		var err error
+		maxTargetScore, err = strconv.ParseFloat(args[2], 64)
+		if err != nil || maxTargetScore <= 0 {
+			return fmt.Errorf("invalid max target score '%s'", args[2])
+		}
+	}
+
+	maxRequests := defaultPoWWorkerMaxRequests
+	if len(args) == 4 {
+		var err error
+		maxRequests, err = strconv.Atoi(args[3])
+		if err != nil || maxRequests < 1 {
+			return fmt.Errorf("invalid max requests '%s'", args[3])
+		}
+	}
+
+	settings, err := loadHTTPServerSettings("POW_WORKER")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Remote PoW worker listening on %s (parallelism: %d, max target score: %0.2f, max requests: %d, auth token: %t, TLS: %t)\n", bindAddress, parallelism, maxTargetScore, maxRequests, settings.authToken != "", settings.tls())
+
+	if err := serveHTTP(bindAddress, pow.NewWorkerServer(parallelism, maxTargetScore, maxRequests, settings.authToken).Handler(), settings); err != nil {
+		return fmt.Errorf("remote PoW worker failed: %w", err)
+	}
+
+	fmt.Println("Remote PoW worker stopped")
+
+	return nil
+}
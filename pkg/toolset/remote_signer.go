@@ -0,0 +1,135 @@
+package toolset
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/remotesigner"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+func remoteSigner(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [BIND_ADDRESS] [CERT_PATH] [KEY_PATH] [STATE_FILE_PATH] [CLIENT_CERT_PINS...]", ToolRemoteSigner))
+		println()
+		println("   [BIND_ADDRESS]        - the bind address of the remote signer")
+		println("   [CERT_PATH]           - the path to the server certificate")
+		println("   [KEY_PATH]            - the path to the private key of the server certificate")
+		println("   [STATE_FILE_PATH]     - the path to the state file for the replay protection")
+		println("   [CLIENT_CERT_PINS...] - the SHA-256 hashes of the pinned client certificates of the coordinators")
+		println()
+		println("   the private keys of the milestones are read from the environment variable 'COO_PRV_KEYS'")
+		println("   if the environment variable 'REMOTE_SIGNER_RESIGN_INDEX' is set to the latest signed milestone index,")
+		println("   a different milestone essence is signed once for that index (only if the signed milestone was never sent!)")
+		println()
+		println(fmt.Sprintf("example: %s %s %s %s %s %s", ToolRemoteSigner, "0.0.0.0:12345", "signer.crt", "signer.key", "signer.state", "8f3c...b1d2"))
+	}
+
+	// check arguments
+	if len(args) < 5 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolRemoteSigner)
+	}
+
+	bindAddress := args[0]
+	certPath := args[1]
+	keyPath := args[2]
+	stateFilePath := args[3]
+
+	clientCertPins, err := remotesigner.ParseCertificatePins(args[4:])
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := remotesigner.NewServerTLSConfig(certPath, keyPath, clientCertPins)
+	if err != nil {
+		return err
+	}
+
+	serverCertPin, err := remotesigner.CertificatePinFromFile(certPath)
+	if err != nil {
+		return err
+	}
+
+	privateKeys, err := utils.LoadEd25519PrivateKeysFromEnvironment("COO_PRV_KEYS")
+	if err != nil {
+		return err
+	}
+
+	server, err := remotesigner.NewServer(privateKeys, stateFilePath)
+	if err != nil {
+		return err
+	}
+
+	// the override is optional
+	if resignIndexString, err := utils.LoadStringFromEnvironment("REMOTE_SIGNER_RESIGN_INDEX"); err == nil {
+		resignIndex, err := strconv.ParseUint(resignIndexString, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid milestone index in 'REMOTE_SIGNER_RESIGN_INDEX': %w", err)
+		}
+
+		if err := server.AllowResigning(milestone.Index(resignIndex)); err != nil {
+			return err
+		}
+
+		fmt.Printf("WARNING: a different milestone essence is signed once for milestone index %d\n", resignIndex)
+	}
+
+	listener, err := net.Listen("tcp", bindAddress)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", bindAddress, err)
+	}
+
+	grpcServer := remotesigner.NewGRPCServer(server, tlsConfig)
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		grpcServer.GracefulStop()
+	}()
+
+	state := server.State()
+	fmt.Printf("Remote signer listening on %s (server certificate pin: %s, latest signed milestone index: %d)\n", bindAddress, serverCertPin, state.LatestMilestoneIndex)
+
+	if err := grpcServer.Serve(listener); err != nil {
+		return fmt.Errorf("remote signer failed: %w", err)
+	}
+
+	fmt.Println("Remote signer stopped")
+
+	return nil
+}
+
+func certificatePin(_ *configuration.Configuration, args []string) error {
+	printUsage := func() {
+		println("Usage:")
+		println(fmt.Sprintf("	%s [CERT_PATH]", ToolCertificatePin))
+		println()
+		println("   [CERT_PATH] - the path to the PEM encoded certificate")
+		println()
+		println(fmt.Sprintf("example: %s %s", ToolCertificatePin, "coordinator.crt"))
+	}
+
+	// check arguments
+	if len(args) != 1 {
+		printUsage()
+		return fmt.Errorf("wrong argument count for '%s'", ToolCertificatePin)
+	}
+
+	pin, err := remotesigner.CertificatePinFromFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Certificate pin:", pin)
+
+	return nil
+}
@@ -55,7 +55,8 @@ func printSnapshotHeaderInfo(name string, path string, header *snapshot.ReadFile
 	- Snapshot index %d
 	- UTXOs count %d
 	- SEPs count %d
-	- Milestone diffs count %d`+"\n", name, path,
+	- Milestone diffs count %d
+	- Key ranges count %d`+"\n", name, path,
 		time.Unix(int64(header.Timestamp), 0),
 		header.NetworkID,
 		func() string {
@@ -69,5 +70,6 @@ func printSnapshotHeaderInfo(name string, path string, header *snapshot.ReadFile
 		header.OutputCount,
 		header.SEPCount,
 		header.MilestoneDiffCount,
+		len(header.KeyRanges),
 	)
 }
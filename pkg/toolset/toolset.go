@@ -23,7 +23,15 @@ const (
 	ToolBenchmarkCPU            = "bench-cpu"
 	ToolDatabaseMigration       = "db-migration"
 	ToolDatabaseLedgerHash      = "db-hash"
+	ToolDatabaseBackup          = "db-backup"
+	ToolDatabaseRestore         = "db-restore"
+	ToolDatabaseCheck           = "db-check"
 	ToolCoordinatorFixStateFile = "coo-fix-state"
+	ToolRemoteSigner            = "remote-signer"
+	ToolCertificatePin          = "cert-pin"
+	ToolCoordinatorKeyAnnounce  = "coo-key-announce"
+	ToolPoWWorker               = "pow-worker"
+	ToolCoordinatorStateServer  = "coo-state-server"
 )
 
 // HandleTools handles available tools.
@@ -64,7 +72,15 @@ func HandleTools(nodeConfig *configuration.Configuration) {
 		ToolBenchmarkCPU:            benchmarkCPU,
 		ToolDatabaseMigration:       databaseMigration,
 		ToolDatabaseLedgerHash:      databaseLedgerHash,
+		ToolDatabaseBackup:          databaseBackup,
+		ToolDatabaseRestore:         databaseRestore,
+		ToolDatabaseCheck:           databaseCheck,
 		ToolCoordinatorFixStateFile: coordinatorFixStateFile,
+		ToolRemoteSigner:            remoteSigner,
+		ToolCertificatePin:          certificatePin,
+		ToolCoordinatorKeyAnnounce:  coordinatorKeyAnnouncement,
+		ToolPoWWorker:               powWorker,
+		ToolCoordinatorStateServer:  coordinatorStateServer,
 	}
 
 	tool, exists := tools[strings.ToLower(args[1])]
@@ -97,5 +113,13 @@ func listTools() {
 	fmt.Printf("%-20s benchmarks the CPU performance\n", fmt.Sprintf("%s:", ToolBenchmarkCPU))
 	fmt.Printf("%-20s migrates the database to another engine\n", fmt.Sprintf("%s:", ToolDatabaseMigration))
 	fmt.Printf("%-20s calculates the sha256 hash of the ledger state of a database\n", fmt.Sprintf("%s:", ToolDatabaseLedgerHash))
+	fmt.Printf("%-20s creates a backup of a database and the given state files\n", fmt.Sprintf("%s:", ToolDatabaseBackup))
+	fmt.Printf("%-20s restores a database backup and validates the ledger state\n", fmt.Sprintf("%s:", ToolDatabaseRestore))
+	fmt.Printf("%-20s checks the referential integrity of a database and optionally repairs it\n", fmt.Sprintf("%s:", ToolDatabaseCheck))
 	fmt.Printf("%-20s applies the latest milestone in the database to the coordinator state file\n", fmt.Sprintf("%s:", ToolCoordinatorFixStateFile))
+	fmt.Printf("%-20s runs a remote milestone signer with mutual TLS and replay protection\n", fmt.Sprintf("%s:", ToolRemoteSigner))
+	fmt.Printf("%-20s calculates the SHA-256 pin of a TLS certificate\n", fmt.Sprintf("%s:", ToolCertificatePin))
+	fmt.Printf("%-20s builds a signed announcement of upcoming coordinator key ranges\n", fmt.Sprintf("%s:", ToolCoordinatorKeyAnnounce))
+	fmt.Printf("%-20s runs a remote PoW worker for the nodes\n", fmt.Sprintf("%s:", ToolPoWWorker))
+	fmt.Printf("%-20s serves the shared state of the active/standby coordinator instances\n", fmt.Sprintf("%s:", ToolCoordinatorStateServer))
 }
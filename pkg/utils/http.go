@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+)
+
+// BearerTokenHandler checks the bearer token of the requests before they are passed to the given handler.
+// The requests are not checked if the token is empty.
+func BearerTokenHandler(token string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// WriteJSONResponse writes the given object as JSON response.
+func WriteJSONResponse(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,178 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// the maximum duration a milestone request waits until the coordinator is ready to issue it.
+	milestoneRequestQueueTimeout = 5 * time.Second
+)
+
+// milestoneRequest is a milestone requested via the control API.
+type milestoneRequest struct {
+	// the tips the milestone references, the tips are selected by the coordinator if empty.
+	tips hornet.MessageIDs
+	// the result of the milestone request.
+	result chan *milestoneRequestResult
+}
+
+// milestoneRequestResult is the result of a milestone requested via the control API.
+type milestoneRequestResult struct {
+	index     milestone.Index
+	messageID hornet.MessageID
+	err       error
+}
+
+// audit triggers an audit event for an action performed via the control API.
+func audit(c echo.Context, action string, details string, err error) {
+	deps.Coordinator.Events.Audit.Trigger(&coordinator.AuditEntry{
+		Time:          time.Now(),
+		Action:        action,
+		RemoteAddress: c.RealIP(),
+		Details:       details,
+		Err:           err,
+	})
+}
+
+func status() *statusResponse {
+
+	var quorumStatus []*quorumClientStatus
+	for _, entry := range deps.Coordinator.QuorumStats() {
+		clientStatus := &quorumClientStatus{
+			Group:               entry.Group,
+			Alias:               entry.Alias,
+			BaseURL:             entry.BaseURL,
+			PeerID:              entry.PeerID,
+			ResponseTimeSeconds: entry.ResponseTimeSeconds,
+		}
+		if entry.Error != nil {
+			clientStatus.Error = entry.Error.Error()
+		}
+		quorumStatus = append(quorumStatus, clientStatus)
+	}
+
+	return &statusResponse{
+		Active:       deps.Coordinator.IsActive(),
+		Paused:       paused.IsSet(),
+		BackPressure: deps.Coordinator.IsUnderBackPressure(),
+		PacingMode:   pacingMode,
+		State:        deps.Coordinator.State(),
+		Quorum:       quorumStatus,
+	}
+}
+
+func pause(c echo.Context) {
+	if !paused.SetToIf(false, true) {
+		audit(c, "pause", "milestone issuance already paused", nil)
+		return
+	}
+	audit(c, "pause", "milestone issuance paused", nil)
+}
+
+func resume(c echo.Context) {
+	if !paused.SetToIf(true, false) {
+		audit(c, "resume", "milestone issuance not paused", nil)
+		return
+	}
+	audit(c, "resume", "milestone issuance resumed", nil)
+}
+
+func triggerMilestone(c echo.Context) {
+	signalNextMilestone()
+	audit(c, "trigger", "next milestone triggered", nil)
+}
+
+// validateMilestoneTips checks that the tips are known, solid and not below max depth.
+func validateMilestoneTips(tips hornet.MessageIDs) error {
+
+	if len(tips) > MilestoneMaxAdditionalTipsLimit {
+		return errors.WithMessagef(restapipkg.ErrInvalidParameter, "too many tips given, maximum: %d", MilestoneMaxAdditionalTipsLimit)
+	}
+
+	for _, tip := range tips {
+		cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(tip) // meta +1
+		if cachedMsgMeta == nil {
+			return errors.WithMessagef(restapipkg.ErrInvalidParameter, "tip not found: %s", tip.ToHex())
+		}
+
+		if !cachedMsgMeta.Metadata().IsSolid() {
+			cachedMsgMeta.Release(true) // meta -1
+			return errors.WithMessagef(restapipkg.ErrInvalidParameter, "tip not solid: %s", tip.ToHex())
+		}
+
+		if isBelowMaxDepth(cachedMsgMeta) { // meta -1
+			return errors.WithMessagef(restapipkg.ErrInvalidParameter, "tip below max depth: %s", tip.ToHex())
+		}
+	}
+
+	return nil
+}
+
+func issueMilestone(c echo.Context) (*issueMilestoneResponse, error) {
+
+	request := &issueMilestoneRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapipkg.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	tips, err := hornet.MessageIDsFromHex(request.Tips)
+	if err != nil {
+		return nil, errors.WithMessagef(restapipkg.ErrInvalidParameter, "invalid tips, error: %s", err)
+	}
+
+	if err := validateMilestoneTips(tips); err != nil {
+		audit(c, "issueMilestone", "invalid tips", err)
+		return nil, err
+	}
+
+	if !deps.Coordinator.IsActive() {
+		audit(c, "issueMilestone", "", coordinator.ErrCoordinatorNotActive)
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, coordinator.ErrCoordinatorNotActive.Error())
+	}
+
+	milestoneReq := &milestoneRequest{
+		tips: tips,
+		// buffered channel, so the coordinator doesn't block if the client is gone
+		result: make(chan *milestoneRequestResult, 1),
+	}
+
+	select {
+	case milestoneRequests <- milestoneReq:
+	case <-time.After(milestoneRequestQueueTimeout):
+		audit(c, "issueMilestone", "", errors.New("coordinator is busy"))
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "coordinator is busy")
+	}
+
+	var result *milestoneRequestResult
+	select {
+	case result = <-milestoneReq.result:
+	case <-c.Request().Context().Done():
+		audit(c, "issueMilestone", "client disconnected before the milestone was issued", c.Request().Context().Err())
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "request canceled")
+	}
+
+	if result.err != nil {
+		audit(c, "issueMilestone", "", result.err)
+		if common.IsCriticalError(result.err) != nil {
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "issuing milestone failed: %s", result.err)
+		}
+		return nil, errors.WithMessagef(echo.ErrServiceUnavailable, "issuing milestone failed: %s", result.err)
+	}
+
+	audit(c, "issueMilestone", "milestone issued: "+result.messageID.ToHex(), nil)
+
+	return &issueMilestoneResponse{
+		Index:     uint32(result.index),
+		MessageID: result.messageID.ToHex(),
+	}, nil
+}
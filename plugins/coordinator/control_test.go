@@ -0,0 +1,100 @@
+package coordinator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+)
+
+func setupControlTest(t *testing.T) *[]*coordinator.AuditEntry {
+	deps.Coordinator = &coordinator.Coordinator{
+		Events: &coordinator.Events{
+			Audit: events.NewEvent(coordinator.AuditCaller),
+		},
+	}
+	nextMilestoneSignal = make(chan struct{}, 1)
+	paused.UnSet()
+
+	var auditEntries []*coordinator.AuditEntry
+	deps.Coordinator.Events.Audit.Attach(events.NewClosure(func(entry *coordinator.AuditEntry) {
+		auditEntries = append(auditEntries, entry)
+	}))
+
+	t.Cleanup(func() {
+		deps.Coordinator = nil
+		nextMilestoneSignal = nil
+		paused.UnSet()
+	})
+
+	return &auditEntries
+}
+
+func newControlContext(route string) echo.Context {
+	req := httptest.NewRequest(http.MethodPost, RouteCoordinator+route, nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestPauseResume(t *testing.T) {
+	auditEntries := setupControlTest(t)
+
+	pause(newControlContext(RouteCoordinatorPause))
+	require.True(t, paused.IsSet())
+
+	// pausing twice keeps the coordinator paused
+	pause(newControlContext(RouteCoordinatorPause))
+	require.True(t, paused.IsSet())
+
+	resume(newControlContext(RouteCoordinatorResume))
+	require.False(t, paused.IsSet())
+
+	// resuming twice keeps the coordinator running
+	resume(newControlContext(RouteCoordinatorResume))
+	require.False(t, paused.IsSet())
+
+	require.Len(t, *auditEntries, 4)
+	expected := []struct {
+		action  string
+		details string
+	}{
+		{"pause", "milestone issuance paused"},
+		{"pause", "milestone issuance already paused"},
+		{"resume", "milestone issuance resumed"},
+		{"resume", "milestone issuance not paused"},
+	}
+	for i, entry := range *auditEntries {
+		require.Equal(t, expected[i].action, entry.Action)
+		require.Equal(t, expected[i].details, entry.Details)
+		require.Equal(t, "10.0.0.1", entry.RemoteAddress)
+		require.NoError(t, entry.Err)
+		require.False(t, entry.Time.IsZero())
+	}
+}
+
+func TestTriggerMilestone(t *testing.T) {
+	auditEntries := setupControlTest(t)
+
+	triggerMilestone(newControlContext(RouteCoordinatorMilestoneTrigger))
+	require.Len(t, nextMilestoneSignal, 1)
+
+	// triggering again does not block if a signal is already waiting
+	triggerMilestone(newControlContext(RouteCoordinatorMilestoneTrigger))
+	require.Len(t, nextMilestoneSignal, 1)
+
+	<-nextMilestoneSignal
+
+	require.Len(t, *auditEntries, 2)
+	for _, entry := range *auditEntries {
+		require.Equal(t, "trigger", entry.Action)
+		require.Equal(t, "next milestone triggered", entry.Details)
+		require.Equal(t, "10.0.0.1", entry.RemoteAddress)
+		require.NoError(t, entry.Err)
+	}
+}
@@ -14,7 +14,20 @@ const (
 	CfgCoordinatorStateFilePath = "coordinator.stateFilePath"
 	// CfgCoordinatorInterval is the interval at which milestones are issued.
 	CfgCoordinatorInterval = "coordinator.interval"
-	// CfgCoordinatorSigningProvider the signing provider the coordinator uses to sign a milestone (local/remote).
+	// CfgCoordinatorPacingMode defines when milestones are issued (fixed/adaptive/onDemand).
+	CfgCoordinatorPacingMode = "coordinator.pacing.mode"
+	// CfgCoordinatorPacingAdaptiveMinInterval defines the minimum interval milestones are issued in the adaptive pacing mode.
+	CfgCoordinatorPacingAdaptiveMinInterval = "coordinator.pacing.adaptive.minInterval"
+	// CfgCoordinatorPacingAdaptiveMaxInterval defines the maximum interval milestones are issued in the adaptive pacing mode.
+	CfgCoordinatorPacingAdaptiveMaxInterval = "coordinator.pacing.adaptive.maxInterval"
+	// CfgCoordinatorPacingAdaptiveMPSThreshold defines the amount of new messages per second at which the minimum interval is used.
+	CfgCoordinatorPacingAdaptiveMPSThreshold = "coordinator.pacing.adaptive.mpsThreshold"
+	// CfgCoordinatorPacingAdaptiveBacklogThreshold defines the amount of unreferenced messages at which the minimum interval is used.
+	CfgCoordinatorPacingAdaptiveBacklogThreshold = "coordinator.pacing.adaptive.backlogThreshold"
+	// CfgCoordinatorPacingOnDemandNewMessagesThreshold defines the amount of new messages that trigger a milestone in the on-demand pacing mode.
+	// if set to 0, milestones are only issued if they are triggered via the REST API.
+	CfgCoordinatorPacingOnDemandNewMessagesThreshold = "coordinator.pacing.onDemand.newMessagesThreshold"
+	// CfgCoordinatorSigningProvider the signing provider the coordinator uses to sign a milestone (local/remote/remoteTLS/pkcs11/multiParty).
 	CfgCoordinatorSigningProvider = "coordinator.signing.provider"
 	// CfgCoordinatorSigningRetryAmount defines the number of signing retries to perform before shutting down the node.
 	CfgCoordinatorSigningRetryAmount = "coordinator.signing.retryAmount"
@@ -22,14 +35,39 @@ const (
 	CfgCoordinatorSigningRetryTimeout = "coordinator.signing.retryTimeout"
 	// CfgCoordinatorSigningRemoteAddress the address of the remote signing provider (insecure connection!).
 	CfgCoordinatorSigningRemoteAddress = "coordinator.signing.remoteAddress"
+	// CfgCoordinatorSigningRemoteTLSCertPath the path to the client certificate for the mutual TLS connection to the remote signing provider.
+	CfgCoordinatorSigningRemoteTLSCertPath = "coordinator.signing.remoteTLS.certPath"
+	// CfgCoordinatorSigningRemoteTLSKeyPath the path to the private key of the client certificate for the mutual TLS connection to the remote signing provider.
+	CfgCoordinatorSigningRemoteTLSKeyPath = "coordinator.signing.remoteTLS.keyPath"
+	// CfgCoordinatorSigningRemoteTLSServerCertPins the SHA-256 hashes of the pinned certificates of the remote signing provider.
+	CfgCoordinatorSigningRemoteTLSServerCertPins = "coordinator.signing.remoteTLS.serverCertPins"
+	// CfgCoordinatorSigningPKCS11ModulePath the path to the PKCS#11 module of the hardware security module.
+	CfgCoordinatorSigningPKCS11ModulePath = "coordinator.signing.pkcs11.modulePath"
+	// CfgCoordinatorSigningPKCS11TokenLabel the label of the PKCS#11 token that holds the milestone keys.
+	CfgCoordinatorSigningPKCS11TokenLabel = "coordinator.signing.pkcs11.tokenLabel"
 	// CfgCoordinatorPoWWorkerCount the amount of workers used for calculating PoW when issuing checkpoints and milestones.
 	CfgCoordinatorPoWWorkerCount = "coordinator.powWorkerCount"
+	// CfgCoordinatorSigningMultiPartySigners the signer services the multi party signing provider collects the partial signatures from.
+	CfgCoordinatorSigningMultiPartySigners = "coordinator.signing.multiParty.signers"
+	// CfgCoordinatorSigningMultiPartyTimeout the timeout until a signer service must have answered a signing request.
+	CfgCoordinatorSigningMultiPartyTimeout = "coordinator.signing.multiParty.timeout"
 	// CfgCoordinatorQuorumEnabled defines whether the coordinator quorum is enabled.
 	CfgCoordinatorQuorumEnabled = "coordinator.quorum.enabled"
 	// CfgCoordinatorQuorumGroups defines the quorum groups used to ask other nodes for correct ledger state of the coordinator.
 	CfgCoordinatorQuorumGroups = "coordinator.quorum.groups"
 	// CfgCoordinatorQuorumTimeout defines the timeout until a node in the quorum must have answered.
 	CfgCoordinatorQuorumTimeout = "coordinator.quorum.timeout"
+	// CfgCoordinatorHighAvailabilityEnabled defines whether the coordinator runs as an active/standby instance with a shared state.
+	CfgCoordinatorHighAvailabilityEnabled = "coordinator.highAvailability.enabled"
+	// CfgCoordinatorHighAvailabilityInstanceID defines the unique ID of the coordinator instance.
+	CfgCoordinatorHighAvailabilityInstanceID = "coordinator.highAvailability.instanceID"
+	// CfgCoordinatorHighAvailabilitySharedStatePath defines the path to the directory that is shared between the coordinator instances.
+	CfgCoordinatorHighAvailabilitySharedStatePath = "coordinator.highAvailability.sharedStatePath"
+	// CfgCoordinatorHighAvailabilityStateServerURL defines the URL of the state server that is shared between the coordinator instances.
+	// the shared state path is not used if a state server is configured.
+	CfgCoordinatorHighAvailabilityStateServerURL = "coordinator.highAvailability.stateServerURL"
+	// CfgCoordinatorHighAvailabilityLeaseDuration defines the duration of the lease of the active coordinator instance.
+	CfgCoordinatorHighAvailabilityLeaseDuration = "coordinator.highAvailability.leaseDuration"
 	// CfgCoordinatorCheckpointsMaxTrackedMessages defines the maximum amount of known messages for milestone tipselection
 	// if this limit is exceeded, a new checkpoint is issued.
 	CfgCoordinatorCheckpointsMaxTrackedMessages = "coordinator.checkpoints.maxTrackedMessages"
@@ -52,13 +90,30 @@ var params = &node.PluginParams{
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.String(CfgCoordinatorStateFilePath, "coordinator.state", "the path to the state file of the coordinator")
 			fs.Duration(CfgCoordinatorInterval, 10*time.Second, "the interval milestones are issued")
+			fs.String(CfgCoordinatorPacingMode, "fixed", "defines when milestones are issued (fixed/adaptive/onDemand)")
+			fs.Duration(CfgCoordinatorPacingAdaptiveMinInterval, 2*time.Second, "the minimum interval milestones are issued in the adaptive pacing mode")
+			fs.Duration(CfgCoordinatorPacingAdaptiveMaxInterval, 10*time.Second, "the maximum interval milestones are issued in the adaptive pacing mode")
+			fs.Float64(CfgCoordinatorPacingAdaptiveMPSThreshold, 100, "the amount of new messages per second at which the minimum interval is used")
+			fs.Int(CfgCoordinatorPacingAdaptiveBacklogThreshold, 5000, "the amount of unreferenced messages at which the minimum interval is used")
+			fs.Int(CfgCoordinatorPacingOnDemandNewMessagesThreshold, 0, "the amount of new messages that trigger a milestone in the on-demand pacing mode (0 = only triggered via REST API)")
 			fs.Duration(CfgCoordinatorSigningRetryTimeout, 2*time.Second, "defines the timeout between signing retries")
 			fs.Int(CfgCoordinatorSigningRetryAmount, 10, "defines the number of signing retries to perform before shutting down the node")
-			fs.String(CfgCoordinatorSigningProvider, "local", "the signing provider the coordinator uses to sign a milestone (local/remote)")
-			fs.String(CfgCoordinatorSigningRemoteAddress, "localhost:12345", "the address of the remote signing provider (insecure connection!)")
+			fs.String(CfgCoordinatorSigningProvider, "local", "the signing provider the coordinator uses to sign a milestone (local/remote/remoteTLS/pkcs11/multiParty)")
+			fs.String(CfgCoordinatorSigningRemoteAddress, "localhost:12345", "the address of the remote signing provider (insecure connection if the provider is 'remote'!)")
+			fs.String(CfgCoordinatorSigningRemoteTLSCertPath, "coordinator.crt", "the path to the client certificate for the mutual TLS connection to the remote signing provider")
+			fs.String(CfgCoordinatorSigningRemoteTLSKeyPath, "coordinator.key", "the path to the private key of the client certificate for the mutual TLS connection to the remote signing provider")
+			fs.StringSlice(CfgCoordinatorSigningRemoteTLSServerCertPins, []string{}, "the SHA-256 hashes of the pinned certificates of the remote signing provider")
+			fs.String(CfgCoordinatorSigningPKCS11ModulePath, "/usr/lib/softhsm/libsofthsm2.so", "the path to the PKCS#11 module of the hardware security module")
+			fs.String(CfgCoordinatorSigningPKCS11TokenLabel, "coordinator", "the label of the PKCS#11 token that holds the milestone keys")
+			fs.Duration(CfgCoordinatorSigningMultiPartyTimeout, 2*time.Second, "the timeout until a signer service must have answered a signing request")
 			fs.Int(CfgCoordinatorPoWWorkerCount, runtime.NumCPU()-1, "the amount of workers used for calculating PoW when issuing checkpoints and milestones")
 			fs.Bool(CfgCoordinatorQuorumEnabled, false, "whether the coordinator quorum is enabled")
 			fs.Duration(CfgCoordinatorQuorumTimeout, 2*time.Second, "the timeout until a node in the quorum must have answered")
+			fs.Bool(CfgCoordinatorHighAvailabilityEnabled, false, "whether the coordinator runs as an active/standby instance with a shared state")
+			fs.String(CfgCoordinatorHighAvailabilityInstanceID, "", "the unique ID of the coordinator instance (hostname if empty)")
+			fs.String(CfgCoordinatorHighAvailabilitySharedStatePath, "shared", "the path to the directory that is shared between the coordinator instances")
+			fs.String(CfgCoordinatorHighAvailabilityStateServerURL, "", "the URL of the state server that is shared between the coordinator instances (the shared state path is used if empty)")
+			fs.Duration(CfgCoordinatorHighAvailabilityLeaseDuration, 30*time.Second, "the duration of the lease of the active coordinator instance")
 			fs.Int(CfgCoordinatorCheckpointsMaxTrackedMessages, 10000, "maximum amount of known messages for milestone tipselection")
 			fs.Int(CfgCoordinatorTipselectMinHeaviestBranchUnreferencedMessagesThreshold, 20, "minimum threshold of unreferenced messages in the heaviest branch")
 			fs.Int(CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint, 10, "maximum amount of checkpoint messages with heaviest branch tips")
@@ -1,8 +1,13 @@
 package coordinator
 
 import (
+	"crypto/tls"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/labstack/echo/v4"
+	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/dig"
@@ -10,6 +15,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/hsm"
 	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/model/hornet"
@@ -22,13 +28,18 @@ import (
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
+	quorumprotocol "github.com/gohornet/hornet/pkg/protocol/quorum"
+	"github.com/gohornet/hornet/pkg/remotesigner"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/gohornet/hornet/plugins/restapi"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/syncutils"
 	"github.com/iotaledger/hive.go/timeutil"
+	"github.com/iotaledger/hive.go/typeutils"
+	iotago "github.com/iotaledger/iota.go/v2"
 	"github.com/iotaledger/iota.go/v2/ed25519"
 )
 
@@ -39,6 +50,8 @@ const (
 	CfgCoordinatorStartIndex = "cooStartIndex"
 	// the maximum limit of additional tips that fit into a milestone (besides the last milestone and checkpoint hash)
 	MilestoneMaxAdditionalTipsLimit = 6
+	// the interval the pacing policy is asked whether the next milestone is due
+	pacingCheckInterval = 100 * time.Millisecond
 )
 
 var (
@@ -76,12 +89,30 @@ var (
 
 	heaviestSelectorLock syncutils.RWMutex
 
+	// the interval the lease of the shared state backend is renewed or acquired by a standby instance.
+	leaseRenewalInterval time.Duration
+
+	// the pacer decides when milestones are issued, nil if milestones are issued in a fixed interval.
+	pacer *coordinator.Pacer
+	// the configured pacing mode.
+	pacingMode string
+	// whether issuing milestones in the interval of the pacing mode is paused.
+	paused = typeutils.NewAtomicBool()
+	// milestones requested via the control API.
+	milestoneRequests chan *milestoneRequest
+
+	// the messages issued by this coordinator instance that are not solid yet, they are not counted by the pacer.
+	issuedMessageIDs     = make(map[string]struct{})
+	issuedMessageIDsLock syncutils.Mutex
+
 	lastCheckpointIndex     int
 	lastCheckpointMessageID hornet.MessageID
 	lastMilestoneMessageID  hornet.MessageID
 
 	// Closures
 	onMessageSolid                   *events.Closure
+	onMPSMetricsUpdated              *events.Closure
+	onAudit                          *events.Closure
 	onConfirmedMilestoneIndexChanged *events.Closure
 	onIssuedCheckpoint               *events.Closure
 	onIssuedMilestone                *events.Closure
@@ -98,6 +129,7 @@ type dependencies struct {
 	Coordinator      *coordinator.Coordinator
 	Selector         *mselection.HeaviestSelector
 	ShutdownHandler  *shutdown.ShutdownHandler
+	Echo             *echo.Echo `optional:"true"`
 }
 
 func provide(c *dig.Container) {
@@ -128,6 +160,7 @@ func provide(c *dig.Container) {
 		PoWHandler              *pow.Handler
 		MigratorService         *migrator.MigratorService `optional:"true"`
 		UTXOManager             *utxo.Manager
+		Host                    host.Host
 		NodeConfig              *configuration.Configuration `name:"nodeConfig"`
 		NetworkID               uint64                       `name:"networkId"`
 		MilestonePublicKeyCount int                          `name:"milestonePublicKeyCount"`
@@ -138,8 +171,7 @@ func provide(c *dig.Container) {
 		initCoordinator := func() (*coordinator.Coordinator, error) {
 
 			signingProvider, err := initSigningProvider(
-				deps.NodeConfig.String(CfgCoordinatorSigningProvider),
-				deps.NodeConfig.String(CfgCoordinatorSigningRemoteAddress),
+				deps.NodeConfig,
 				deps.KeyManager,
 				deps.MilestonePublicKeyCount,
 			)
@@ -156,10 +188,22 @@ func provide(c *dig.Container) {
 				Plugin.LogInfo("running Coordinator with quorum enabled")
 			}
 
+			// the quorum protocol is used for quorum clients that are connected peers
+			quorumPeerClient := quorumprotocol.NewClient(deps.Host, quorumprotocol.ProtocolID(deps.NetworkID))
+
 			if deps.MigratorService == nil {
 				Plugin.LogInfo("running Coordinator without migration enabled")
 			}
 
+			stateBackend, instanceID, err := initStateBackend(deps.NodeConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize shared state backend: %s", err)
+			}
+
+			if stateBackend != nil {
+				Plugin.LogInfof("running Coordinator with high availability enabled, instance ID: %s", instanceID)
+			}
+
 			coo, err := coordinator.New(
 				deps.Storage,
 				deps.SyncManager,
@@ -173,9 +217,10 @@ func provide(c *dig.Container) {
 				coordinator.WithStateFilePath(deps.NodeConfig.String(CfgCoordinatorStateFilePath)),
 				coordinator.WithMilestoneInterval(deps.NodeConfig.Duration(CfgCoordinatorInterval)),
 				coordinator.WithPoWWorkerCount(deps.NodeConfig.Int(CfgCoordinatorPoWWorkerCount)),
-				coordinator.WithQuorum(deps.NodeConfig.Bool(CfgCoordinatorQuorumEnabled), quorumGroups, deps.NodeConfig.Duration(CfgCoordinatorQuorumTimeout)),
+				coordinator.WithQuorum(deps.NodeConfig.Bool(CfgCoordinatorQuorumEnabled), quorumGroups, deps.NodeConfig.Duration(CfgCoordinatorQuorumTimeout), quorumPeerClient),
 				coordinator.WithSigningRetryAmount(deps.NodeConfig.Int(CfgCoordinatorSigningRetryAmount)),
 				coordinator.WithSigningRetryTimeout(deps.NodeConfig.Duration(CfgCoordinatorSigningRetryTimeout)),
+				coordinator.WithStateBackend(stateBackend, instanceID, deps.NodeConfig.Duration(CfgCoordinatorHighAvailabilityLeaseDuration)),
 			)
 			if err != nil {
 				return nil, err
@@ -220,10 +265,28 @@ func configure() {
 
 	maxTrackedMessages = deps.NodeConfig.Int(CfgCoordinatorCheckpointsMaxTrackedMessages)
 
+	leaseRenewalInterval = deps.NodeConfig.Duration(CfgCoordinatorHighAvailabilityLeaseDuration) / 3
+
+	milestoneRequests = make(chan *milestoneRequest)
+
+	pacingMode = deps.NodeConfig.String(CfgCoordinatorPacingMode)
+	pacer, err = initPacer(deps.NodeConfig)
+	if err != nil {
+		Plugin.Panicf("failed to initialize pacing policy: %s", err)
+	}
+
+	// the REST API is optional, milestones can't be triggered manually without it
+	if !Plugin.Node.IsSkipped(restapi.Plugin) {
+		setupRoutes(deps.Echo.Group(RouteCoordinator))
+	}
+
 	// set the node as synced at startup, so the coo plugin can select tips
 	deps.Tangle.SetUpdateSyncedAtStartup(true)
 
 	configureEvents()
+
+	// the control API can be used while the coordinator instance is in standby mode
+	deps.Coordinator.Events.Audit.Attach(onAudit)
 }
 
 // handleError checks for critical errors and returns true if the node should shutdown.
@@ -253,19 +316,47 @@ func run() {
 	// create a background worker that signals to issue new milestones
 	if err := Plugin.Daemon().BackgroundWorker("Coordinator[MilestoneTicker]", func(shutdownSignal <-chan struct{}) {
 
+		if pacer == nil {
+			// issue milestones in a fixed interval
+			ticker := timeutil.NewTicker(func() {
+				if paused.IsSet() {
+					return
+				}
+				signalNextMilestone()
+			}, deps.Coordinator.Interval(), shutdownSignal)
+			ticker.WaitForGracefulShutdown()
+			return
+		}
+
 		ticker := timeutil.NewTicker(func() {
-			// issue next milestone
-			select {
-			case nextMilestoneSignal <- struct{}{}:
-			default:
-				// do not block if already another signal is waiting
+			if paused.IsSet() {
+				return
 			}
-		}, deps.Coordinator.Interval(), shutdownSignal)
+
+			if pacer.MilestoneDue(deps.Selector.TrackedMessagesCount()) {
+				signalNextMilestone()
+			}
+		}, pacingCheckInterval, shutdownSignal)
 		ticker.WaitForGracefulShutdown()
 	}, shutdown.PriorityCoordinator); err != nil {
 		Plugin.Panicf("failed to start worker: %s", err)
 	}
 
+	if deps.NodeConfig.Bool(CfgCoordinatorHighAvailabilityEnabled) {
+		// create a background worker that renews the lease of the active coordinator instance
+		if err := Plugin.Daemon().BackgroundWorker("Coordinator[Lease]", func(shutdownSignal <-chan struct{}) {
+
+			ticker := timeutil.NewTicker(func() {
+				if err := deps.Coordinator.RenewLease(); err != nil {
+					handleError(err)
+				}
+			}, leaseRenewalInterval, shutdownSignal)
+			ticker.WaitForGracefulShutdown()
+		}, shutdown.PriorityCoordinator); err != nil {
+			Plugin.Panicf("failed to start worker: %s", err)
+		}
+	}
+
 	// create a background worker that issues milestones
 	if err := Plugin.Daemon().BackgroundWorker("Coordinator", func(shutdownSignal <-chan struct{}) {
 		// wait until all background workers of the tangle plugin are started
@@ -273,128 +364,251 @@ func run() {
 
 		attachEvents()
 
-		// bootstrap the network if not done yet
-		milestoneMessageID, err := deps.Coordinator.Bootstrap()
+	activationLoop:
+		for {
+			// wait until this coordinator instance is allowed to issue milestones
+			if !waitForActivation(shutdownSignal) {
+				break activationLoop
+			}
+
+			// bootstrap the network if not done yet
+			milestoneMessageID, err := deps.Coordinator.Bootstrap()
+			if handleError(err) {
+				// critical error => stop worker
+				break activationLoop
+			}
+			if err != nil {
+				// the coordinator instance lost the lease in the meantime
+				continue
+			}
+
+			// init the last milestone message ID
+			lastMilestoneMessageID = milestoneMessageID
+
+			// init the checkpoints
+			lastCheckpointMessageID = milestoneMessageID
+			lastCheckpointIndex = 0
+
+			if !issueMilestones(shutdownSignal) {
+				break activationLoop
+			}
+
+			Plugin.LogWarn("coordinator instance lost the lease, switching to standby mode")
+		}
+
+		if err := deps.Coordinator.Deactivate(); err != nil {
+			Plugin.LogWarnf("failed to release the lease: %s", err)
+		}
+
+		detachEvents()
+	}, shutdown.PriorityCoordinator); err != nil {
+		Plugin.Panicf("failed to start worker: %s", err)
+	}
+
+}
+
+// signalNextMilestone signals to issue the next milestone.
+func signalNextMilestone() {
+	select {
+	case nextMilestoneSignal <- struct{}{}:
+	default:
+		// do not block if already another signal is waiting
+	}
+}
+
+// wasIssuedByCoordinator checks whether the message was issued by this coordinator instance and forgets it.
+func wasIssuedByCoordinator(messageID hornet.MessageID) bool {
+	issuedMessageIDsLock.Lock()
+	defer issuedMessageIDsLock.Unlock()
+
+	if _, exists := issuedMessageIDs[messageID.ToMapKey()]; !exists {
+		return false
+	}
+	delete(issuedMessageIDs, messageID.ToMapKey())
+
+	return true
+}
+
+// waitForActivation waits until the coordinator instance holds the lease of the shared state backend.
+// Without high availability the coordinator instance is always active.
+// Returns false if the node is shutting down or a critical error occurred.
+func waitForActivation(shutdownSignal <-chan struct{}) bool {
+
+	standbyLogged := false
+	for {
+		active, err := deps.Coordinator.Activate()
 		if handleError(err) {
-			// critical error => stop worker
-			detachEvents()
-			return
+			return false
 		}
 
-		// init the last milestone message ID
-		lastMilestoneMessageID = milestoneMessageID
+		if active {
+			if standbyLogged {
+				Plugin.LogInfo("coordinator instance took over, issuing milestones")
+			}
+			return true
+		}
 
-		// init the checkpoints
-		lastCheckpointMessageID = milestoneMessageID
-		lastCheckpointIndex = 0
+		if !standbyLogged {
+			Plugin.LogInfo("another coordinator instance is active, running in standby mode")
+			standbyLogged = true
+		}
 
-	coordinatorLoop:
-		for {
-			select {
-			case <-nextCheckpointSignal:
-				// check the thresholds again, because a new milestone could have been issued in the meantime
-				if trackedMessagesCount := deps.Selector.TrackedMessagesCount(); trackedMessagesCount < maxTrackedMessages {
-					continue
-				}
+		select {
+		case <-shutdownSignal:
+			return false
+		case <-time.After(leaseRenewalInterval):
+		}
+	}
+}
 
-				func() {
-					// this lock is necessary, otherwise a checkpoint could be issued
-					// while a milestone gets confirmed. In that case the checkpoint could
-					// contain messages that are already below max depth.
-					heaviestSelectorLock.RLock()
-					defer heaviestSelectorLock.RUnlock()
-
-					tips, err := deps.Selector.SelectTips(0)
-					if err != nil {
-						// issuing checkpoint failed => not critical
-						if !errors.Is(err, mselection.ErrNoTipsAvailable) {
-							Plugin.LogWarn(err)
-						}
-						return
-					}
+// issueMilestones issues checkpoints and milestones as long as the coordinator instance is active.
+// Returns false if the node is shutting down or a critical error occurred.
+func issueMilestones(shutdownSignal <-chan struct{}) bool {
 
-					// issue a checkpoint
-					checkpointMessageID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointMessageID, tips)
-					if err != nil {
-						// issuing checkpoint failed => not critical
-						Plugin.LogWarn(err)
-						return
-					}
-					lastCheckpointIndex++
-					lastCheckpointMessageID = checkpointMessageID
-				}()
+	for {
+		select {
+		case <-nextCheckpointSignal:
+			// check the thresholds again, because a new milestone could have been issued in the meantime
+			if trackedMessagesCount := deps.Selector.TrackedMessagesCount(); trackedMessagesCount < maxTrackedMessages {
+				continue
+			}
 
-			case <-nextMilestoneSignal:
-				var milestoneTips hornet.MessageIDs
+			func() {
+				// this lock is necessary, otherwise a checkpoint could be issued
+				// while a milestone gets confirmed. In that case the checkpoint could
+				// contain messages that are already below max depth.
+				heaviestSelectorLock.RLock()
+				defer heaviestSelectorLock.RUnlock()
 
-				// issue a new checkpoint right in front of the milestone
-				checkpointTips, err := deps.Selector.SelectTips(1)
+				tips, err := deps.Selector.SelectTips(0)
 				if err != nil {
 					// issuing checkpoint failed => not critical
 					if !errors.Is(err, mselection.ErrNoTipsAvailable) {
 						Plugin.LogWarn(err)
 					}
-				} else {
-					if len(checkpointTips) > MilestoneMaxAdditionalTipsLimit {
-						// issue a checkpoint with all the tips that wouldn't fit into the milestone (more than MilestoneMaxAdditionalTipsLimit)
-						checkpointMessageID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointMessageID, checkpointTips[MilestoneMaxAdditionalTipsLimit:])
-						if err != nil {
-							// issuing checkpoint failed => not critical
-							Plugin.LogWarn(err)
-						} else {
-							// use the new checkpoint message ID
-							lastCheckpointMessageID = checkpointMessageID
-						}
-
-						// use the other tips for the milestone
-						milestoneTips = checkpointTips[:MilestoneMaxAdditionalTipsLimit]
-					} else {
-						// do not issue a checkpoint and use the tips for the milestone instead since they fit into the milestone directly
-						milestoneTips = checkpointTips
-					}
+					return
 				}
 
-				milestoneTips = append(milestoneTips, hornet.MessageIDs{lastMilestoneMessageID, lastCheckpointMessageID}...)
-
-				milestoneMessageID, err := deps.Coordinator.IssueMilestone(milestoneTips)
-				if handleError(err) {
-					// critical error => quit loop
-					break coordinatorLoop
-				}
+				// issue a checkpoint
+				checkpointMessageID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointMessageID, tips)
 				if err != nil {
-					// non-critical errors
-					if errors.Is(err, common.ErrNodeNotSynced) {
-						// Coordinator is not synchronized, trigger the solidifier manually
-						deps.Tangle.TriggerSolidifier()
-					}
+					// issuing checkpoint failed => not critical
+					Plugin.LogWarn(err)
+					return
+				}
+				lastCheckpointIndex++
+				lastCheckpointMessageID = checkpointMessageID
+			}()
+
+		case <-nextMilestoneSignal:
+			err := issueNextMilestone(selectMilestoneTips())
+			if handleError(err) {
+				// critical error => quit loop
+				return false
+			}
+			if err != nil && !deps.Coordinator.IsActive() {
+				// another coordinator instance took over
+				return true
+			}
 
-					// reset the checkpoints
-					lastCheckpointMessageID = lastMilestoneMessageID
-					lastCheckpointIndex = 0
+		case request := <-milestoneRequests:
+			// issue a milestone that was requested via the control API
+			tips := request.tips
+			if len(tips) == 0 {
+				tips = selectMilestoneTips()
+			}
 
-					continue
-				}
+			err := issueNextMilestone(tips)
+			request.result <- &milestoneRequestResult{
+				index:     deps.Coordinator.State().LatestMilestoneIndex,
+				messageID: lastMilestoneMessageID,
+				err:       err,
+			}
+			if handleError(err) {
+				// critical error => quit loop
+				return false
+			}
+			if err != nil && !deps.Coordinator.IsActive() {
+				// another coordinator instance took over
+				return true
+			}
 
-				// remember the last milestone message ID
-				lastMilestoneMessageID = milestoneMessageID
+		case <-shutdownSignal:
+			return false
+		}
+	}
+}
 
-				// reset the checkpoints
-				lastCheckpointMessageID = milestoneMessageID
-				lastCheckpointIndex = 0
+// selectMilestoneTips selects the additional tips for the next milestone.
+// It issues a checkpoint with the tips that don't fit into the milestone.
+func selectMilestoneTips() hornet.MessageIDs {
+	var milestoneTips hornet.MessageIDs
 
-			case <-shutdownSignal:
-				break coordinatorLoop
-			}
+	// issue a new checkpoint right in front of the milestone
+	checkpointTips, err := deps.Selector.SelectTips(1)
+	if err != nil {
+		// issuing checkpoint failed => not critical
+		if !errors.Is(err, mselection.ErrNoTipsAvailable) {
+			Plugin.LogWarn(err)
 		}
+		return nil
+	}
 
-		detachEvents()
-	}, shutdown.PriorityCoordinator); err != nil {
-		Plugin.Panicf("failed to start worker: %s", err)
+	if len(checkpointTips) > MilestoneMaxAdditionalTipsLimit {
+		// issue a checkpoint with all the tips that wouldn't fit into the milestone (more than MilestoneMaxAdditionalTipsLimit)
+		checkpointMessageID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointMessageID, checkpointTips[MilestoneMaxAdditionalTipsLimit:])
+		if err != nil {
+			// issuing checkpoint failed => not critical
+			Plugin.LogWarn(err)
+		} else {
+			// use the new checkpoint message ID
+			lastCheckpointMessageID = checkpointMessageID
+		}
+
+		// use the other tips for the milestone
+		milestoneTips = checkpointTips[:MilestoneMaxAdditionalTipsLimit]
+	} else {
+		// do not issue a checkpoint and use the tips for the milestone instead since they fit into the milestone directly
+		milestoneTips = checkpointTips
+	}
+
+	return milestoneTips
+}
+
+// issueNextMilestone issues the next milestone that references the given tips, the last milestone and the last checkpoint.
+// Returns non-critical and critical errors.
+func issueNextMilestone(milestoneTips hornet.MessageIDs) error {
+
+	milestoneTips = append(milestoneTips, hornet.MessageIDs{lastMilestoneMessageID, lastCheckpointMessageID}...)
+
+	milestoneMessageID, err := deps.Coordinator.IssueMilestone(milestoneTips)
+	if err != nil {
+		if errors.Is(err, common.ErrNodeNotSynced) {
+			// Coordinator is not synchronized, trigger the solidifier manually
+			deps.Tangle.TriggerSolidifier()
+		}
+
+		// reset the checkpoints
+		lastCheckpointMessageID = lastMilestoneMessageID
+		lastCheckpointIndex = 0
+
+		return err
 	}
 
+	// remember the last milestone message ID
+	lastMilestoneMessageID = milestoneMessageID
+
+	// reset the checkpoints
+	lastCheckpointMessageID = milestoneMessageID
+	lastCheckpointIndex = 0
+
+	return nil
 }
 
-func initSigningProvider(signingProviderType string, remoteEndpoint string, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
+func initSigningProvider(nodeConfig *configuration.Configuration, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
+
+	signingProviderType := nodeConfig.String(CfgCoordinatorSigningProvider)
+	remoteEndpoint := nodeConfig.String(CfgCoordinatorSigningRemoteAddress)
 
 	switch signingProviderType {
 	case "local":
@@ -422,11 +636,198 @@ func initSigningProvider(signingProviderType string, remoteEndpoint string, keyM
 
 		return coordinator.NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint, keyManager, milestonePublicKeyCount), nil
 
+	case "remoteTLS":
+		if remoteEndpoint == "" {
+			return nil, errors.New("no address given for remote signing provider")
+		}
+
+		serverCertPins, err := remotesigner.ParseCertificatePins(nodeConfig.Strings(CfgCoordinatorSigningRemoteTLSServerCertPins))
+		if err != nil {
+			return nil, fmt.Errorf("invalid server certificate pins for remote signing provider: %w", err)
+		}
+
+		tlsConfig, err := remotesigner.NewClientTLSConfig(
+			nodeConfig.String(CfgCoordinatorSigningRemoteTLSCertPath),
+			nodeConfig.String(CfgCoordinatorSigningRemoteTLSKeyPath),
+			serverCertPins,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return coordinator.NewRemoteEd25519MilestoneSignerProvider(remoteEndpoint, tlsConfig, keyManager, milestonePublicKeyCount), nil
+
+	case "multiParty":
+		// all signer services are secured with the same client certificate, the pins contain the certificates of all signer services
+		serverCertPins, err := remotesigner.ParseCertificatePins(nodeConfig.Strings(CfgCoordinatorSigningRemoteTLSServerCertPins))
+		if err != nil {
+			return nil, fmt.Errorf("invalid server certificate pins for multi party signing provider: %w", err)
+		}
+
+		tlsConfig, err := remotesigner.NewClientTLSConfig(
+			nodeConfig.String(CfgCoordinatorSigningRemoteTLSCertPath),
+			nodeConfig.String(CfgCoordinatorSigningRemoteTLSKeyPath),
+			serverCertPins,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		signerServices, err := initSignerServices(nodeConfig, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return coordinator.NewMultiPartyEd25519MilestoneSignerProvider(signerServices, keyManager, milestonePublicKeyCount), nil
+
+	case "pkcs11":
+		pin, exists := os.LookupEnv("COO_PKCS11_PIN")
+		if !exists {
+			return nil, errors.New("environment variable 'COO_PKCS11_PIN' not set")
+		}
+
+		// the token stays open for the lifetime of the node
+		token, err := hsm.OpenPKCS11Token(
+			nodeConfig.String(CfgCoordinatorSigningPKCS11ModulePath),
+			nodeConfig.String(CfgCoordinatorSigningPKCS11TokenLabel),
+			pin,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(token.PublicKeys()) == 0 {
+			_ = token.Close()
+			return nil, errors.New("no Ed25519 key pairs found on the PKCS#11 token")
+		}
+
+		return coordinator.NewHSMEd25519MilestoneSignerProvider(token, keyManager, milestonePublicKeyCount), nil
+
 	default:
 		return nil, fmt.Errorf("unknown milestone signing provider: %s", signingProviderType)
 	}
 }
 
+// initPacer creates the pacer for the configured pacing mode.
+// Returns nil if milestones are issued in a fixed interval.
+func initPacer(nodeConfig *configuration.Configuration) (*coordinator.Pacer, error) {
+
+	switch pacingMode := nodeConfig.String(CfgCoordinatorPacingMode); pacingMode {
+	case coordinator.PacingModeFixed:
+		return nil, nil
+
+	case coordinator.PacingModeAdaptive:
+		policy, err := coordinator.NewAdaptivePacingPolicy(
+			nodeConfig.Duration(CfgCoordinatorPacingAdaptiveMinInterval),
+			nodeConfig.Duration(CfgCoordinatorPacingAdaptiveMaxInterval),
+			nodeConfig.Float64(CfgCoordinatorPacingAdaptiveMPSThreshold),
+			nodeConfig.Int(CfgCoordinatorPacingAdaptiveBacklogThreshold),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return coordinator.NewPacer(policy), nil
+
+	case coordinator.PacingModeOnDemand:
+		return coordinator.NewPacer(coordinator.NewOnDemandPacingPolicy(nodeConfig.Int(CfgCoordinatorPacingOnDemandNewMessagesThreshold))), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", coordinator.ErrUnknownPacingMode, pacingMode)
+	}
+}
+
+// initStateBackend creates the backend that is shared between the coordinator instances if high availability is enabled.
+func initStateBackend(nodeConfig *configuration.Configuration) (coordinator.StateBackend, string, error) {
+
+	if !nodeConfig.Bool(CfgCoordinatorHighAvailabilityEnabled) {
+		return nil, "", nil
+	}
+
+	instanceID := nodeConfig.String(CfgCoordinatorHighAvailabilityInstanceID)
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to determine instance ID: %w", err)
+		}
+		instanceID = hostname
+	}
+
+	if stateServerURL := nodeConfig.String(CfgCoordinatorHighAvailabilityStateServerURL); stateServerURL != "" {
+		// the auth token is optional
+		authToken, _ := utils.LoadStringFromEnvironment("COO_STATE_SERVER_TOKEN")
+
+		// a request must not block the renewal of the lease
+		return coordinator.NewHTTPStateBackend(stateServerURL, authToken, nodeConfig.Duration(CfgCoordinatorHighAvailabilityLeaseDuration)/3), instanceID, nil
+	}
+
+	stateBackend, err := coordinator.NewFileStateBackend(nodeConfig.String(CfgCoordinatorHighAvailabilitySharedStatePath))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return stateBackend, instanceID, nil
+}
+
+// signerServiceConfig is the config of a signer service of the multi party signing provider.
+type signerServiceConfig struct {
+	// optional alias of the signer service.
+	Alias string `json:"alias" koanf:"alias"`
+	// address of the signer service.
+	Address string `json:"address" koanf:"address"`
+	// the public keys of the private keys the signer service holds.
+	PublicKeys []string `json:"publicKeys" koanf:"publicKeys"`
+}
+
+func initSignerServices(nodeConfig *configuration.Configuration, tlsConfig *tls.Config) ([]*coordinator.SignerService, error) {
+
+	signerServicesConfig := []*signerServiceConfig{}
+	if err := nodeConfig.Unmarshal(CfgCoordinatorSigningMultiPartySigners, &signerServicesConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse signer services: %s, %s", CfgCoordinatorSigningMultiPartySigners, err)
+	}
+
+	if len(signerServicesConfig) == 0 {
+		return nil, fmt.Errorf("invalid signer services: %s, no entries", CfgCoordinatorSigningMultiPartySigners)
+	}
+
+	timeout := nodeConfig.Duration(CfgCoordinatorSigningMultiPartyTimeout)
+
+	signerServices := make([]*coordinator.SignerService, 0, len(signerServicesConfig))
+	for _, entry := range signerServicesConfig {
+		if entry.Address == "" {
+			return nil, fmt.Errorf("invalid signer services: %s, missing address in entry", CfgCoordinatorSigningMultiPartySigners)
+		}
+
+		alias := entry.Alias
+		if alias == "" {
+			alias = entry.Address
+		}
+
+		if len(entry.PublicKeys) == 0 {
+			return nil, fmt.Errorf("invalid signer service: %s, no public keys given", alias)
+		}
+
+		pubKeys := make([]iotago.MilestonePublicKey, 0, len(entry.PublicKeys))
+		for _, pubKeyHex := range entry.PublicKeys {
+			pubKey, err := utils.ParseEd25519PublicKeyFromString(pubKeyHex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid signer service: %s, %w", alias, err)
+			}
+
+			var msPubKey iotago.MilestonePublicKey
+			copy(msPubKey[:], pubKey)
+			pubKeys = append(pubKeys, msPubKey)
+		}
+
+		signerServices = append(signerServices, &coordinator.SignerService{
+			Alias:       alias,
+			PublicKeys:  pubKeys,
+			SigningFunc: remotesigner.Ed25519MilestoneSignerWithTimeout(entry.Address, tlsConfig, timeout),
+		})
+	}
+
+	return signerServices, nil
+}
+
 func initQuorumGroups(nodeConfig *configuration.Configuration) (map[string][]*coordinator.QuorumClientConfig, error) {
 	// parse quorum groups config
 	quorumGroups := make(map[string][]*coordinator.QuorumClientConfig)
@@ -443,8 +844,8 @@ func initQuorumGroups(nodeConfig *configuration.Configuration) (map[string][]*co
 		}
 
 		for _, entry := range groupConfig {
-			if entry.BaseURL == "" {
-				return nil, fmt.Errorf("invalid group: %s, missing baseURL in entry", configKey)
+			if entry.BaseURL == "" && entry.PeerID == "" {
+				return nil, fmt.Errorf("invalid group: %s, missing baseURL or peerID in entry", configKey)
 			}
 		}
 
@@ -458,6 +859,11 @@ func sendMessage(msg *storage.Message, msIndex ...milestone.Index) error {
 
 	var err error
 
+	// the message must not be counted by the pacer
+	issuedMessageIDsLock.Lock()
+	issuedMessageIDs[msg.MessageID().ToMapKey()] = struct{}{}
+	issuedMessageIDsLock.Unlock()
+
 	msgSolidEventChan := deps.Tangle.RegisterMessageSolidEvent(msg.MessageID())
 
 	var milestoneConfirmedEventChan chan struct{}
@@ -468,6 +874,7 @@ func sendMessage(msg *storage.Message, msIndex ...milestone.Index) error {
 
 	defer func() {
 		if err != nil {
+			wasIssuedByCoordinator(msg.MessageID())
 			deps.Tangle.DeregisterMessageSolidEvent(msg.MessageID())
 			if len(msIndex) > 0 {
 				deps.Tangle.DeregisterMilestoneConfirmedEvent(msIndex[0])
@@ -519,11 +926,18 @@ func configureEvents() {
 	onMessageSolid = events.NewClosure(func(cachedMsgMeta *storage.CachedMetadata) {
 		defer cachedMsgMeta.Release(true)
 
+		issuedByCoordinator := wasIssuedByCoordinator(cachedMsgMeta.Metadata().MessageID())
+
 		if isBelowMaxDepth(cachedMsgMeta.Retain()) {
 			// ignore tips that are below max depth
 			return
 		}
 
+		if pacer != nil && !issuedByCoordinator {
+			// the own milestones and checkpoints are not counted
+			pacer.MessageSeen()
+		}
+
 		// add tips to the heaviest branch selector
 		if trackedMessagesCount := deps.Selector.OnNewSolidMessage(cachedMsgMeta.Metadata()); trackedMessagesCount >= maxTrackedMessages {
 			Plugin.LogDebugf("Coordinator Tipselector: trackedMessagesCount: %d", trackedMessagesCount)
@@ -537,6 +951,12 @@ func configureEvents() {
 		}
 	})
 
+	onMPSMetricsUpdated = events.NewClosure(func(mpsMetrics *tangle.MPSMetrics) {
+		if pacer != nil {
+			pacer.UpdateMPS(float64(mpsMetrics.New))
+		}
+	})
+
 	onConfirmedMilestoneIndexChanged = events.NewClosure(func(_ milestone.Index) {
 		heaviestSelectorLock.Lock()
 		defer heaviestSelectorLock.Unlock()
@@ -558,11 +978,24 @@ func configureEvents() {
 
 	onIssuedMilestone = events.NewClosure(func(index milestone.Index, messageID hornet.MessageID) {
 		Plugin.LogInfof("milestone issued (%d): %v", index, messageID.ToHex())
+
+		if pacer != nil {
+			pacer.Reset()
+		}
+	})
+
+	onAudit = events.NewClosure(func(entry *coordinator.AuditEntry) {
+		if entry.Err != nil {
+			Plugin.LogWarnf("audit: action %s by %s failed: %s, %s", entry.Action, entry.RemoteAddress, entry.Details, entry.Err)
+			return
+		}
+		Plugin.LogInfof("audit: action %s by %s: %s", entry.Action, entry.RemoteAddress, entry.Details)
 	})
 }
 
 func attachEvents() {
 	deps.Tangle.Events.MessageSolid.Attach(onMessageSolid)
+	deps.Tangle.Events.MPSMetricsUpdated.Attach(onMPSMetricsUpdated)
 	deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Attach(onConfirmedMilestoneIndexChanged)
 	deps.Coordinator.Events.IssuedCheckpointMessage.Attach(onIssuedCheckpoint)
 	deps.Coordinator.Events.IssuedMilestone.Attach(onIssuedMilestone)
@@ -570,6 +1003,7 @@ func attachEvents() {
 
 func detachEvents() {
 	deps.Tangle.Events.MessageSolid.Detach(onMessageSolid)
+	deps.Tangle.Events.MPSMetricsUpdated.Detach(onMPSMetricsUpdated)
 	deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Detach(onConfirmedMilestoneIndexChanged)
 	deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
 }
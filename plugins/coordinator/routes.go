@@ -0,0 +1,68 @@
+package coordinator
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// RouteCoordinator is the route group of the coordinator plugin.
+	RouteCoordinator = "/api/plugins/coordinator"
+
+	// RouteCoordinatorStatus is the route to get the status of the coordinator.
+	// GET returns the state, the quorum statistics and the back pressure status of the coordinator.
+	RouteCoordinatorStatus = "/status"
+
+	// RouteCoordinatorPause is the route to pause issuing milestones.
+	// POST pauses issuing milestones in the interval of the pacing mode.
+	RouteCoordinatorPause = "/pause"
+
+	// RouteCoordinatorResume is the route to resume issuing milestones.
+	// POST resumes issuing milestones in the interval of the pacing mode.
+	RouteCoordinatorResume = "/resume"
+
+	// RouteCoordinatorMilestones is the route to issue a milestone.
+	// POST issues a milestone that references the given tips and returns its index and message ID.
+	RouteCoordinatorMilestones = "/milestones"
+
+	// RouteCoordinatorMilestoneTrigger is the route to trigger the next milestone.
+	// POST signals to issue the next milestone.
+	RouteCoordinatorMilestoneTrigger = "/milestones/trigger"
+)
+
+func setupRoutes(routeGroup *echo.Group) {
+
+	routeGroup.GET(RouteCoordinatorStatus, func(c echo.Context) error {
+		return restapipkg.JSONResponse(c, http.StatusOK, status())
+	})
+
+	routeGroup.POST(RouteCoordinatorPause, func(c echo.Context) error {
+		pause(c)
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.POST(RouteCoordinatorResume, func(c echo.Context) error {
+		resume(c)
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.POST(RouteCoordinatorMilestones, func(c echo.Context) error {
+		resp, err := issueMilestone(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteCoordinatorMilestoneTrigger, func(c echo.Context) error {
+		triggerMilestone(c)
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
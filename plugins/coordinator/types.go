@@ -0,0 +1,52 @@
+package coordinator
+
+import (
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+)
+
+// quorumClientStatus defines the statistics of a quorum client in the coordinator status.
+type quorumClientStatus struct {
+	// The name of the quorum group the client is member of.
+	Group string `json:"group"`
+	// The alias of the quorum client.
+	Alias string `json:"alias,omitempty"`
+	// The baseURL of the quorum client.
+	BaseURL string `json:"baseURL,omitempty"`
+	// The peerID of the quorum client.
+	PeerID string `json:"peerID,omitempty"`
+	// The last response time of the white flag request.
+	ResponseTimeSeconds float64 `json:"responseTimeSeconds"`
+	// The error of the last white flag request.
+	Error string `json:"error,omitempty"`
+}
+
+// statusResponse defines the response of a GET coordinator status REST API call.
+type statusResponse struct {
+	// Whether the coordinator instance is allowed to issue milestones.
+	Active bool `json:"active"`
+	// Whether issuing milestones is paused.
+	Paused bool `json:"paused"`
+	// Whether any back pressure function is signaling congestion.
+	BackPressure bool `json:"backPressure"`
+	// The used pacing mode.
+	PacingMode string `json:"pacingMode"`
+	// The state of the coordinator.
+	State *coordinator.State `json:"state"`
+	// The statistics of the quorum clients.
+	Quorum []*quorumClientStatus `json:"quorum"`
+}
+
+// issueMilestoneRequest defines the request of a POST coordinator milestones REST API call.
+type issueMilestoneRequest struct {
+	// The hex encoded message IDs of the tips the milestone references.
+	// If no tips are given, the tips are selected by the coordinator.
+	Tips []string `json:"tips"`
+}
+
+// issueMilestoneResponse defines the response of a POST coordinator milestones REST API call.
+type issueMilestoneResponse struct {
+	// The index of the issued milestone.
+	Index uint32 `json:"index"`
+	// The hex encoded message ID of the issued milestone.
+	MessageID string `json:"messageId"`
+}
@@ -19,16 +19,20 @@ var (
 type DBSizeMetric struct {
 	Total    int64
 	Snapshot int64
+	// The estimated sizes of the storages in the database (only set if supported by the database engine).
+	Prefixes map[string]int64
 	Time     time.Time
 }
 
 func (s *DBSizeMetric) MarshalJSON() ([]byte, error) {
 	size := struct {
-		Total int64 `json:"total"`
-		Time  int64 `json:"ts"`
+		Total    int64            `json:"total"`
+		Prefixes map[string]int64 `json:"prefixes,omitempty"`
+		Time     int64            `json:"ts"`
 	}{
-		Total: s.Total,
-		Time:  s.Time.Unix(),
+		Total:    s.Total,
+		Prefixes: s.Prefixes,
+		Time:     s.Time.Unix(),
 	}
 
 	return json.Marshal(size)
@@ -45,6 +49,18 @@ func currentDatabaseSize() *DBSizeMetric {
 		Total: dbSize,
 		Time:  time.Now(),
 	}
+
+	if deps.Database.PrefixSizeEstimationSupported() {
+		prefixes, err := deps.Database.EstimatePrefixSizes()
+		if err != nil {
+			Plugin.LogWarnf("error in database prefix size estimation: %s", err)
+		} else {
+			newValue.Prefixes = make(map[string]int64, len(prefixes))
+			for _, prefix := range prefixes {
+				newValue.Prefixes[prefix.Name] = prefix.SizeBytes
+			}
+		}
+	}
 	cachedDBSizeMetrics = append(cachedDBSizeMetrics, newValue)
 	if len(cachedDBSizeMetrics) > 600 {
 		cachedDBSizeMetrics = cachedDBSizeMetrics[len(cachedDBSizeMetrics)-600:]
@@ -16,8 +16,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/restapi"
-	"github.com/gohornet/hornet/pkg/utils"
-	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/gohornet/hornet/pkg/tangle"
 	v1 "github.com/gohornet/hornet/plugins/restapi/v1"
 	"github.com/iotaledger/hive.go/kvstore"
 	iotago "github.com/iotaledger/iota.go/v2"
@@ -30,85 +29,25 @@ func computeWhiteFlagMutations(c echo.Context) (*computeWhiteFlagMutationsRespon
 		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
 	}
 
-	// check if the requested milestone index would be the next one
-	if request.Index > deps.SyncManager.ConfirmedMilestoneIndex()+1 {
-		return nil, errors.WithMessage(echo.ErrServiceUnavailable, common.ErrNodeNotSynced.Error())
-	}
-
-	if len(request.Parents) < 1 {
-		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "no parents given")
-	}
-
 	parents, err := hornet.MessageIDsFromHex(request.Parents)
 	if err != nil {
 		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid parents, error: %s", err)
 	}
 
-	// register all parents for message solid events
-	// this has to be done, even if the parents may be solid already, to prevent race conditions
-	msgSolidEventChans := make([]chan struct{}, len(parents))
-	for i, parent := range parents {
-		msgSolidEventChans[i] = deps.Tangle.RegisterMessageSolidEvent(parent)
-	}
-
-	// check all parents for solidity
-	for _, parent := range parents {
-		cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(parent)
-		if cachedMsgMeta == nil {
-			if deps.Storage.SolidEntryPointsContain(parent) {
-				// deregister the event, because the parent is already solid (this also fires the event)
-				deps.Tangle.DeregisterMessageSolidEvent(parent)
-			}
-			continue
-		}
-
-		cachedMsgMeta.ConsumeMetadata(func(metadata *storage.MessageMetadata) { // metadata -1
-			if !metadata.IsSolid() {
-				return
-			}
-
-			// deregister the event, because the parent is already solid (this also fires the event)
-			deps.Tangle.DeregisterMessageSolidEvent(parent)
-		})
-	}
+	// wait for at most "whiteFlagParentsSolidTimeout" for the parents to become solid
+	ctx, cancel := context.WithTimeout(context.Background(), whiteflagParentsSolidTimeout)
+	defer cancel()
 
-	messagesMemcache := storage.NewMessagesMemcache(deps.Storage)
-	metadataMemcache := storage.NewMetadataMemcache(deps.Storage)
-
-	defer func() {
-		// deregister the events to free the memory
-		for _, parent := range parents {
-			deps.Tangle.DeregisterMessageSolidEvent(parent)
-		}
-
-		// release all messages at the end
-		messagesMemcache.Cleanup(true)
-
-		// Release all message metadata at the end
-		metadataMemcache.Cleanup(true)
-	}()
-
-	// check if all requested parents are solid
-	solid, _ := deps.Tangle.SolidQueueCheck(messagesMemcache, metadataMemcache, request.Index, parents, nil)
-
-	if !solid {
-		// wait for at most "whiteFlagParentsSolidTimeout" for the parents to become solid
-		ctx, cancel := context.WithTimeout(context.Background(), whiteflagParentsSolidTimeout)
-		defer cancel()
-
-		for _, msgSolidEventChan := range msgSolidEventChans {
-			// wait until the message is solid
-			if err := utils.WaitForChannelClosed(ctx, msgSolidEventChan); err != nil {
-				return nil, errors.WithMessage(echo.ErrServiceUnavailable, "parents not solid")
-			}
-		}
-	}
-
-	// at this point all parents are solid
-	// compute merkle tree root
-	mutations, err := whiteflag.ComputeWhiteFlagMutations(deps.Storage, request.Index, metadataMemcache, messagesMemcache, parents)
+	mutations, err := deps.Tangle.ComputeWhiteFlagMutations(ctx, request.Index, parents)
 	if err != nil {
-		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to compute white flag mutations: %s", err)
+		switch {
+		case errors.Is(err, common.ErrNodeNotSynced), errors.Is(err, tangle.ErrParentsNotSolid):
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+		case errors.Is(err, tangle.ErrNoParentsGiven):
+			return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+		default:
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "%s", err)
+		}
 	}
 
 	return &computeWhiteFlagMutationsResponse{
@@ -0,0 +1,169 @@
+package faucet
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/faucet"
+	"github.com/gohornet/hornet/pkg/restapi"
+)
+
+func setupAdminRoutes(routeGroup *echo.Group) {
+
+	routeGroup.GET(RouteFaucetHistory, func(c echo.Context) error {
+		resp, err := getFaucetHistory(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteFaucetBlacklist, func(c echo.Context) error {
+		resp, err := getFaucetBlacklist(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteFaucetBlacklist, func(c echo.Context) error {
+		if err := addToFaucetBlacklist(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.DELETE(RouteFaucetBlacklistAddress, func(c echo.Context) error {
+		if err := removeFromFaucetBlacklist(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.GET(RouteFaucetAmounts, func(c echo.Context) error {
+		return restapi.JSONResponse(c, http.StatusOK, deps.Faucet.Amounts())
+	})
+
+	routeGroup.PUT(RouteFaucetAmounts, func(c echo.Context) error {
+		resp, err := setFaucetAmounts(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteFaucetRefunds, func(c echo.Context) error {
+		resp, err := addFaucetRefundToQueue(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusAccepted, resp)
+	})
+}
+
+func getFaucetHistory(_ echo.Context) (*faucetHistoryResponse, error) {
+
+	maxResults := deps.RestAPILimitsMaxResults
+	history, err := deps.Faucet.History(maxResults)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading faucet history failed, error: %s", err)
+	}
+
+	return &faucetHistoryResponse{
+		MaxResults: uint32(maxResults),
+		Count:      uint32(len(history)),
+		History:    history,
+	}, nil
+}
+
+func getFaucetBlacklist(_ echo.Context) (*faucetBlacklistResponse, error) {
+
+	blacklist, err := deps.Faucet.Blacklist()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading faucet blacklist failed, error: %s", err)
+	}
+
+	return &faucetBlacklistResponse{Addresses: blacklist}, nil
+}
+
+func addToFaucetBlacklist(c echo.Context) error {
+
+	request := &faucetBlacklistRequest{}
+	if err := c.Bind(request); err != nil {
+		return errors.WithMessagef(restapi.ErrInvalidParameter, "Invalid Request! Error: %s", err)
+	}
+
+	if _, err := parseBech32Address(request.Address); err != nil {
+		return err
+	}
+
+	if err := deps.Faucet.AddToBlacklist(request.Address); err != nil {
+		return errors.WithMessagef(echo.ErrInternalServerError, "adding address to faucet blacklist failed, error: %s", err)
+	}
+
+	Plugin.LogInfof("address %s was added to the blacklist by %s", request.Address, clientIP(c))
+
+	return nil
+}
+
+func removeFromFaucetBlacklist(c echo.Context) error {
+
+	bech32Addr := c.Param(ParameterAddress)
+	if _, err := parseBech32Address(bech32Addr); err != nil {
+		return err
+	}
+
+	if err := deps.Faucet.RemoveFromBlacklist(bech32Addr); err != nil {
+		return errors.WithMessagef(echo.ErrInternalServerError, "removing address from faucet blacklist failed, error: %s", err)
+	}
+
+	Plugin.LogInfof("address %s was removed from the blacklist by %s", bech32Addr, clientIP(c))
+
+	return nil
+}
+
+func setFaucetAmounts(c echo.Context) (*faucet.FaucetAmountsResponse, error) {
+
+	request := &faucetAmountsRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Invalid Request! Error: %s", err)
+	}
+
+	if err := deps.Faucet.SetAmounts(request.Amount, request.SmallAmount); err != nil {
+		return nil, err
+	}
+
+	Plugin.LogInfof("amounts were changed to %d/%d by %s", request.Amount, request.SmallAmount, clientIP(c))
+
+	return deps.Faucet.Amounts(), nil
+}
+
+func addFaucetRefundToQueue(c echo.Context) (*faucet.FaucetEnqueueResponse, error) {
+
+	request := &faucetRefundRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Invalid Request! Error: %s", err)
+	}
+
+	ed25519Addr, err := parseBech32Address(request.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := deps.Faucet.EnqueueRefund(request.Address, ed25519Addr, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	Plugin.LogInfof("refund of %d to %s was enqueued by %s", request.Amount, request.Address, clientIP(c))
+
+	return response, nil
+}
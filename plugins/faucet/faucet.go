@@ -1,14 +1,48 @@
 package faucet
 
 import (
+	"encoding/hex"
+	"strconv"
+
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/model/faucet"
 	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/utils"
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
+// newIPExtractor returns an IPExtractor that uses the address of the connection,
+// or the X-Forwarded-For header if the request was sent by one of the trusted proxies.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	trustOptions := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, trustedProxy := range trustedProxies {
+		ipNet, err := utils.ParseIPNet(trustedProxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy: %s", trustedProxy)
+		}
+		trustOptions = append(trustOptions, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(trustOptions...), nil
+}
+
+// clientIP returns the IP of the requester.
+func clientIP(c echo.Context) string {
+	return ipExtractor(c.Request())
+}
+
 func parseBech32Address(addressParam string) (*iotago.Ed25519Address, error) {
 
 	hrp, bech32Address, err := iotago.ParseBech32(addressParam)
@@ -45,7 +79,23 @@ func addFaucetOutputToQueue(c echo.Context) (*faucet.FaucetEnqueueResponse, erro
 		return nil, err
 	}
 
-	response, err := deps.Faucet.Enqueue(bech32Addr, ed25519Addr)
+	solution := &faucet.ChallengeSolution{CaptchaToken: request.CaptchaToken}
+	if request.PoWChallengeSeed != "" {
+		seed, err := hex.DecodeString(request.PoWChallengeSeed)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Invalid proof-of-work seed provided! Error: %s", err)
+		}
+		solution.Seed = seed
+	}
+	if request.Nonce != "" {
+		nonce, err := strconv.ParseUint(request.Nonce, 10, 64)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "Invalid nonce provided! Error: %s", err)
+		}
+		solution.Nonce = nonce
+	}
+
+	response, err := deps.Faucet.Enqueue(c.Request().Context(), bech32Addr, ed25519Addr, clientIP(c), solution)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +24,22 @@ const (
 	CfgFaucetBatchTimeout = "faucet.batchTimeout"
 	// the amount of workers used for calculating PoW when issuing faucet messages.
 	CfgFaucetPoWWorkerCount = "faucet.powWorkerCount"
+	// the amount of unspent outputs on a funding account that triggers a consolidation of the outputs (0 = disabled).
+	CfgFaucetConsolidationThreshold = "faucet.consolidationThreshold"
+	// how long the payouts of the faucet are kept in the history (0 = forever).
+	CfgFaucetHistoryRetention = "faucet.historyRetention"
+	// the duration an address has to wait before it can request funds again (0 = disabled).
+	CfgFaucetRateLimitAddressCooldown = "faucet.rateLimit.addressCooldown"
+	// the duration an IP has to wait before it can request funds again (0 = disabled).
+	CfgFaucetRateLimitIPCooldown = "faucet.rateLimit.ipCooldown"
+	// the IPs or IP ranges of the reverse proxies that are trusted to set the X-Forwarded-For header.
+	CfgFaucetRateLimitTrustedProxies = "faucet.rateLimit.trustedProxies"
+	// the minimum proof-of-work score the requester has to reach before funds are enqueued (0 = disabled).
+	CfgFaucetChallengePoWMinScore = "faucet.challenge.powMinScore"
+	// whether the requester has to solve a captcha before funds are enqueued.
+	CfgFaucetChallengeCaptchaEnabled = "faucet.challenge.captcha.enabled"
+	// the URL of the "siteverify" endpoint used to verify the captcha tokens.
+	CfgFaucetChallengeCaptchaVerifyURL = "faucet.challenge.captcha.verifyURL"
 	// the bind address on which the faucet website can be accessed from
 	CfgFaucetWebsiteBindAddress = "faucet.website.bindAddress"
 	// whether to host the faucet website
@@ -41,6 +57,14 @@ var params = &node.PluginParams{
 			fs.String(CfgFaucetIndexationMessage, "HORNET FAUCET", "the faucet transaction indexation payload")
 			fs.Duration(CfgFaucetBatchTimeout, 2*time.Second, "the maximum duration for collecting faucet batches")
 			fs.Int(CfgFaucetPoWWorkerCount, 0, "the amount of workers used for calculating PoW when issuing faucet messages")
+			fs.Int(CfgFaucetConsolidationThreshold, 64, "the amount of unspent outputs on a funding account that triggers a consolidation of the outputs (0 = disabled)")
+			fs.Duration(CfgFaucetHistoryRetention, 7*24*time.Hour, "how long the payouts of the faucet are kept in the history (0 = forever)")
+			fs.Duration(CfgFaucetRateLimitAddressCooldown, 0, "the duration an address has to wait before it can request funds again (0 = disabled)")
+			fs.Duration(CfgFaucetRateLimitIPCooldown, 0, "the duration an IP has to wait before it can request funds again (0 = disabled)")
+			fs.StringSlice(CfgFaucetRateLimitTrustedProxies, []string{}, "the IPs or IP ranges of the reverse proxies that are trusted to set the X-Forwarded-For header")
+			fs.Float64(CfgFaucetChallengePoWMinScore, 0, "the minimum proof-of-work score the requester has to reach before funds are enqueued (0 = disabled)")
+			fs.Bool(CfgFaucetChallengeCaptchaEnabled, false, "whether the requester has to solve a captcha before funds are enqueued")
+			fs.String(CfgFaucetChallengeCaptchaVerifyURL, "https://hcaptcha.com/siteverify", "the URL of the \"siteverify\" endpoint used to verify the captcha tokens")
 			fs.String(CfgFaucetWebsiteBindAddress, "localhost:8091", "the bind address on which the faucet website can be accessed from")
 			fs.Bool(CfgFaucetWebsiteEnabled, false, "whether to host the faucet website")
 			return fs
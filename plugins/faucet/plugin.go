@@ -3,6 +3,7 @@ package faucet
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"go.uber.org/dig"
 	"golang.org/x/time/rate"
 
+	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/model/faucet"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
@@ -25,6 +27,7 @@ import (
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/timeutil"
 	iotago "github.com/iotaledger/iota.go/v2"
 	"github.com/iotaledger/iota.go/v2/ed25519"
 )
@@ -38,6 +41,34 @@ const (
 	// RouteFaucetEnqueue is the route to tell the faucet to pay out some funds to the given address.
 	// POST enqueues a new request.
 	RouteFaucetEnqueue = "/enqueue"
+
+	// RouteFaucetHistory is the route to get the past payouts of the faucet.
+	// GET returns the latest payouts.
+	RouteFaucetHistory = "/history"
+
+	// RouteFaucetBlacklist is the route to manage the blacklisted addresses.
+	// GET returns all blacklisted addresses.
+	// POST adds an address to the blacklist.
+	RouteFaucetBlacklist = "/blacklist"
+
+	// RouteFaucetBlacklistAddress is the route to remove an address from the blacklist.
+	// DELETE removes the address from the blacklist.
+	RouteFaucetBlacklistAddress = "/blacklist/:" + ParameterAddress
+
+	// RouteFaucetAmounts is the route to manage the amounts of funds the requesters receive.
+	// GET returns the current amounts.
+	// PUT changes the amounts.
+	RouteFaucetAmounts = "/amounts"
+
+	// RouteFaucetRefunds is the route to manually pay out funds to an address.
+	// POST enqueues a payout with the given amount.
+	RouteFaucetRefunds = "/refunds"
+
+	// ParameterAddress is used to identify an address.
+	ParameterAddress = "address"
+
+	// the interval in which expired cooldown windows and payouts are removed from the database.
+	cleanupInterval = 10 * time.Minute
 )
 
 func init() {
@@ -57,16 +88,20 @@ func init() {
 var (
 	Plugin *node.Plugin
 	deps   dependencies
+
+	// used to get the IP of the requester.
+	ipExtractor echo.IPExtractor
 )
 
 type dependencies struct {
 	dig.In
-	NodeConfig            *configuration.Configuration `name:"nodeConfig"`
-	RestAPIBindAddress    string                       `name:"restAPIBindAddress"`
-	FaucetAllowedAPIRoute restapi.AllowedRoute         `name:"faucetAllowedAPIRoute"`
-	Faucet                *faucet.Faucet
-	Echo                  *echo.Echo
-	ShutdownHandler       *shutdown.ShutdownHandler
+	NodeConfig              *configuration.Configuration `name:"nodeConfig"`
+	RestAPIBindAddress      string                       `name:"restAPIBindAddress"`
+	FaucetAllowedAPIRoute   restapi.AllowedRoute         `name:"faucetAllowedAPIRoute"`
+	Faucet                  *faucet.Faucet
+	Echo                    *echo.Echo
+	RestAPILimitsMaxResults int `name:"restAPILimitsMaxResults"`
+	ShutdownHandler         *shutdown.ShutdownHandler
 }
 
 func provide(c *dig.Container) {
@@ -80,18 +115,20 @@ func provide(c *dig.Container) {
 		Plugin.Panic("loading faucet private key failed, err: no private keys given")
 	}
 
-	if len(privateKeys) > 1 {
-		Plugin.Panic("loading faucet private key failed, err: too many private keys given")
-	}
+	// every private key is used as a separate funding account
+	fundingAccounts := make([]*faucet.FundingAccount, len(privateKeys))
+	for i, privateKey := range privateKeys {
+		if len(privateKey) != ed25519.PrivateKeySize {
+			Plugin.Panic("loading faucet private key failed, err: wrong private key length")
+		}
 
-	privateKey := privateKeys[0]
-	if len(privateKey) != ed25519.PrivateKeySize {
-		Plugin.Panic("loading faucet private key failed, err: wrong private key length")
+		faucetAddress := iotago.AddressFromEd25519PubKey(privateKey.Public().(ed25519.PublicKey))
+		fundingAccounts[i] = &faucet.FundingAccount{
+			Address: &faucetAddress,
+			Signer:  iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(&faucetAddress, privateKey)),
+		}
 	}
 
-	faucetAddress := iotago.AddressFromEd25519PubKey(privateKey.Public().(ed25519.PublicKey))
-	faucetSigner := iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(&faucetAddress, privateKey))
-
 	type faucetDeps struct {
 		dig.In
 		Storage          *storage.Storage
@@ -107,17 +144,8 @@ func provide(c *dig.Container) {
 	}
 
 	if err := c.Provide(func(deps faucetDeps) *faucet.Faucet {
-		return faucet.New(
-			deps.Storage,
-			deps.SyncManager,
-			deps.NetworkID,
-			deps.BelowMaxDepth,
-			deps.UTXOManager,
-			&faucetAddress,
-			faucetSigner,
-			deps.TipSelector.SelectNonLazyTips,
-			deps.PowHandler,
-			deps.MessageProcessor.Emit,
+
+		faucetOpts := []faucet.Option{
 			faucet.WithLogger(Plugin.Logger()),
 			faucet.WithHRPNetworkPrefix(deps.Bech32HRP),
 			faucet.WithAmount(uint64(deps.NodeConfig.Int64(CfgFaucetAmount))),
@@ -127,7 +155,39 @@ func provide(c *dig.Container) {
 			faucet.WithIndexationMessage(deps.NodeConfig.String(CfgFaucetIndexationMessage)),
 			faucet.WithBatchTimeout(deps.NodeConfig.Duration(CfgFaucetBatchTimeout)),
 			faucet.WithPowWorkerCount(deps.NodeConfig.Int(CfgFaucetPoWWorkerCount)),
+			faucet.WithConsolidationThreshold(deps.NodeConfig.Int(CfgFaucetConsolidationThreshold)),
+			faucet.WithHistoryRetention(deps.NodeConfig.Duration(CfgFaucetHistoryRetention)),
+			faucet.WithAddressCooldown(deps.NodeConfig.Duration(CfgFaucetRateLimitAddressCooldown)),
+			faucet.WithIPCooldown(deps.NodeConfig.Duration(CfgFaucetRateLimitIPCooldown)),
+			faucet.WithPoWChallenge(deps.NodeConfig.Float64(CfgFaucetChallengePoWMinScore)),
+		}
+
+		if deps.NodeConfig.Bool(CfgFaucetChallengeCaptchaEnabled) {
+			captchaSecret, exists := os.LookupEnv("FAUCET_CAPTCHA_SECRET")
+			if !exists {
+				Plugin.Panic("loading faucet captcha secret failed, err: environment variable 'FAUCET_CAPTCHA_SECRET' not set")
+			}
+			faucetOpts = append(faucetOpts, faucet.WithCaptchaChallenge(faucet.NewCaptchaVerifyFunc(deps.NodeConfig.String(CfgFaucetChallengeCaptchaVerifyURL), captchaSecret)))
+		}
+
+		f, err := faucet.New(
+			deps.Storage,
+			deps.Storage.KVStore().WithRealm([]byte{common.StorePrefixFaucet}),
+			deps.SyncManager,
+			deps.NetworkID,
+			deps.BelowMaxDepth,
+			deps.UTXOManager,
+			fundingAccounts,
+			deps.TipSelector.SelectNonLazyTips,
+			deps.PowHandler,
+			deps.MessageProcessor.Emit,
+			faucetOpts...,
 		)
+		if err != nil {
+			Plugin.Panicf("loading faucet failed, err: %s", err)
+		}
+
+		return f
 	}); err != nil {
 		Plugin.Panic(err)
 	}
@@ -135,6 +195,17 @@ func provide(c *dig.Container) {
 
 func configure() {
 
+	trustedProxies := deps.NodeConfig.Strings(CfgFaucetRateLimitTrustedProxies)
+	if deps.NodeConfig.Bool(CfgFaucetWebsiteEnabled) {
+		// the faucet website forwards the requests to the REST API via localhost
+		trustedProxies = append(trustedProxies, "127.0.0.1", "::1")
+	}
+
+	var err error
+	if ipExtractor, err = newIPExtractor(trustedProxies); err != nil {
+		Plugin.Panicf("loading trusted proxies failed, err: %s", err)
+	}
+
 	routeGroup := deps.Echo.Group("/api/plugins/faucet")
 
 	allowedRoutes := map[string][]string{
@@ -170,7 +241,7 @@ func configure() {
 			},
 		),
 		IdentifierExtractor: func(ctx echo.Context) (string, error) {
-			id := ctx.RealIP()
+			id := clientIP(ctx)
 			return id, nil
 		},
 		ErrorHandler: func(context echo.Context, err error) error {
@@ -201,7 +272,7 @@ func configure() {
 			var e *echo.HTTPError
 			if errors.As(err, &e) {
 				statusCode = e.Code
-				if errors.Is(err, restapi.ErrInvalidParameter) {
+				if errors.Is(err, restapi.ErrInvalidParameter) || errors.Is(err, echo.ErrTooManyRequests) || errors.Is(err, echo.ErrForbidden) {
 					message = strings.Replace(err.Error(), ": "+errors.Unwrap(err).Error(), "", 1)
 				} else {
 					message = err.Error()
@@ -216,6 +287,9 @@ func configure() {
 
 		return restapi.JSONResponse(c, http.StatusAccepted, resp)
 	})
+
+	// the admin routes are not rate limited and not reachable via the faucet website
+	setupAdminRoutes(deps.Echo.Group("/api/plugins/faucet"))
 }
 
 func run() {
@@ -228,6 +302,21 @@ func run() {
 		Plugin.Panicf("failed to start worker: %s", err)
 	}
 
+	// create a background worker that removes the expired cooldown windows and payouts
+	if err := Plugin.Daemon().BackgroundWorker("Faucet[Cleanup]", func(shutdownSignal <-chan struct{}) {
+		ticker := timeutil.NewTicker(func() {
+			if err := deps.Faucet.CleanupCooldowns(); err != nil {
+				Plugin.LogWarnf("cleaning up faucet cooldowns failed: %s", err)
+			}
+			if err := deps.Faucet.CleanupHistory(); err != nil {
+				Plugin.LogWarnf("cleaning up faucet history failed: %s", err)
+			}
+		}, cleanupInterval, shutdownSignal)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PriorityFaucet); err != nil {
+		Plugin.Panicf("failed to start worker: %s", err)
+	}
+
 	websiteEnabled := deps.NodeConfig.Bool(CfgFaucetWebsiteEnabled)
 
 	if websiteEnabled {
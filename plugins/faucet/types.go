@@ -1,7 +1,55 @@
 package faucet
 
+import (
+	"github.com/gohornet/hornet/pkg/model/faucet"
+)
+
 // faucetEnqueueRequest defines the request for a POST RouteFaucetEnqueue REST API call.
 type faucetEnqueueRequest struct {
 	// The bech32 address.
 	Address string `json:"address"`
+	// The hex encoded seed of the proof-of-work challenge.
+	PoWChallengeSeed string `json:"powChallengeSeed,omitempty"`
+	// The nonce of the proof-of-work challenge.
+	Nonce string `json:"nonce,omitempty"`
+	// The response token of the captcha challenge.
+	CaptchaToken string `json:"captchaToken,omitempty"`
+}
+
+// faucetHistoryResponse defines the response of a GET RouteFaucetHistory REST API call.
+type faucetHistoryResponse struct {
+	// The maximum count of results that are returned by the node.
+	MaxResults uint32 `json:"maxResults"`
+	// The actual count of results that are returned.
+	Count uint32 `json:"count"`
+	// The latest payouts of the faucet, newest first.
+	History []*faucet.FaucetHistoryEntry `json:"history"`
+}
+
+// faucetBlacklistRequest defines the request for a POST RouteFaucetBlacklist REST API call.
+type faucetBlacklistRequest struct {
+	// The bech32 address.
+	Address string `json:"address"`
+}
+
+// faucetBlacklistResponse defines the response of a GET RouteFaucetBlacklist REST API call.
+type faucetBlacklistResponse struct {
+	// The blacklisted bech32 addresses.
+	Addresses []string `json:"addresses"`
+}
+
+// faucetAmountsRequest defines the request for a PUT RouteFaucetAmounts REST API call.
+type faucetAmountsRequest struct {
+	// The amount of funds the requester receives.
+	Amount uint64 `json:"amount"`
+	// The amount of funds the requester receives if the target address has more funds than the faucet amount and less than maximum.
+	SmallAmount uint64 `json:"smallAmount"`
+}
+
+// faucetRefundRequest defines the request for a POST RouteFaucetRefunds REST API call.
+type faucetRefundRequest struct {
+	// The bech32 address.
+	Address string `json:"address"`
+	// The amount of funds the address receives.
+	Amount uint64 `json:"amount"`
 }
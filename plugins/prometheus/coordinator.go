@@ -43,7 +43,7 @@ func configureCoordinator() {
 			Help:      "Latest response time by quorum client. [s]",
 			Buckets:   prometheus.DefBuckets,
 		},
-		[]string{"group", "alias", "baseURL"},
+		[]string{"group", "alias", "baseURL", "peerID"},
 	)
 
 	coordinatorQuorumNodesErrorCounters = prometheus.NewCounterVec(
@@ -53,7 +53,7 @@ func configureCoordinator() {
 			Name:      "quorum_nodes_error_counters",
 			Help:      "Number encountered errors by quorum client.",
 		},
-		[]string{"group", "alias", "baseURL"},
+		[]string{"group", "alias", "baseURL", "peerID"},
 	)
 
 	coordinatorSoftErrEncountered = prometheus.NewCounter(
@@ -84,6 +84,7 @@ func configureCoordinator() {
 				"group":   entry.Group,
 				"alias":   entry.Alias,
 				"baseURL": entry.BaseURL,
+				"peerID":  entry.PeerID,
 			}
 			coordinatorQuorumNodesResponseTimes.With(labelsResponseTime).Observe(entry.ResponseTimeSeconds)
 
@@ -91,6 +92,7 @@ func configureCoordinator() {
 				"group":   entry.Group,
 				"alias":   entry.Alias,
 				"baseURL": entry.BaseURL,
+				"peerID":  entry.PeerID,
 			}
 
 			if entry.Error != nil {
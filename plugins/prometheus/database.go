@@ -6,15 +6,20 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/iotaledger/hive.go/events"
 )
 
 var (
 	databaseSizeBytes prometheus.Gauge
+	prefixSizeBytes   *prometheus.GaugeVec
 	compactionCount   prometheus.Counter
 	compactionRunning prometheus.Gauge
 	pruningCount      prometheus.Counter
 	pruningRunning    prometheus.Gauge
+	cleanupCount      prometheus.Counter
+	cleanupRunning    prometheus.Gauge
+	cleanupDuration   prometheus.Gauge
 )
 
 func configureDatabase() {
@@ -27,6 +32,16 @@ func configureDatabase() {
 			Help:      "Database sizes in bytes.",
 		})
 
+	prefixSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "database",
+			Name:      "prefix_size_bytes",
+			Help:      "Estimated sizes of the storages in the database in bytes.",
+		},
+		[]string{"storage"},
+	)
+
 	compactionCount = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "iota",
@@ -71,11 +86,49 @@ func configureDatabase() {
 		Help:      "Current state of database pruning process.",
 	})
 
+	cleanupCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "database",
+			Name:      "cleanup_count",
+			Help:      "The total amount of database cleanups.",
+		},
+	)
+
+	cleanupRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "iota",
+		Subsystem: "database",
+		Name:      "cleanup_running",
+		Help:      "Current state of database cleanup process.",
+	})
+
+	cleanupDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "iota",
+		Subsystem: "database",
+		Name:      "cleanup_duration_seconds",
+		Help:      "The duration of the last database cleanup in seconds.",
+	})
+
+	deps.Database.Events().DatabaseCleanup.Attach(events.NewClosure(func(cleanup *database.DatabaseCleanup) {
+		if cleanup.End.IsZero() {
+			cleanupCount.Inc()
+			cleanupRunning.Set(1)
+			return
+		}
+
+		cleanupRunning.Set(0)
+		cleanupDuration.Set(cleanup.End.Sub(cleanup.Start).Seconds())
+	}))
+
 	registry.MustRegister(databaseSizeBytes)
+	registry.MustRegister(prefixSizeBytes)
 	registry.MustRegister(compactionCount)
 	registry.MustRegister(compactionRunning)
 	registry.MustRegister(pruningCount)
 	registry.MustRegister(pruningRunning)
+	registry.MustRegister(cleanupCount)
+	registry.MustRegister(cleanupRunning)
+	registry.MustRegister(cleanupDuration)
 
 	addCollect(collectDatabase)
 }
@@ -87,6 +140,15 @@ func collectDatabase() {
 		databaseSizeBytes.Set(float64(dbSize))
 	}
 
+	if deps.Database.PrefixSizeEstimationSupported() {
+		prefixes, err := deps.Database.EstimatePrefixSizes()
+		if err == nil {
+			for _, prefix := range prefixes {
+				prefixSizeBytes.WithLabelValues(prefix.Name).Set(float64(prefix.SizeBytes))
+			}
+		}
+	}
+
 	compactionRunning.Set(0)
 	if deps.Database.CompactionRunning() {
 		compactionRunning.Set(1)
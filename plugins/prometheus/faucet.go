@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+var (
+	faucetBalances            *prometheus.GaugeVec
+	faucetUnspentOutputs      *prometheus.GaugeVec
+	faucetPendingRequests     prometheus.Gauge
+	faucetIssuedMessages      prometheus.Counter
+	faucetConsolidatedOutputs prometheus.Counter
+	faucetSoftErrEncountered  prometheus.Counter
+)
+
+func configureFaucet() {
+
+	faucetBalances = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "balance",
+			Help:      "The balance of the faucet funding account.",
+		},
+		[]string{"address"},
+	)
+
+	faucetUnspentOutputs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "unspent_outputs",
+			Help:      "The amount of unspent outputs of the faucet funding account.",
+		},
+		[]string{"address"},
+	)
+
+	faucetPendingRequests = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "pending_requests",
+			Help:      "The amount of requests waiting in the faucet queue.",
+		},
+	)
+
+	faucetIssuedMessages = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "issued_messages",
+			Help:      "The amount of messages issued by the faucet.",
+		},
+	)
+
+	faucetConsolidatedOutputs = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "consolidated_outputs",
+			Help:      "The amount of outputs consolidated by the faucet.",
+		},
+	)
+
+	faucetSoftErrEncountered = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "faucet",
+			Name:      "soft_error_count",
+			Help:      "The faucet's encountered soft error count.",
+		},
+	)
+
+	registry.MustRegister(faucetBalances)
+	registry.MustRegister(faucetUnspentOutputs)
+	registry.MustRegister(faucetPendingRequests)
+	registry.MustRegister(faucetIssuedMessages)
+	registry.MustRegister(faucetConsolidatedOutputs)
+	registry.MustRegister(faucetSoftErrEncountered)
+
+	deps.Faucet.Events.IssuedMessage.Attach(events.NewClosure(func() {
+		faucetIssuedMessages.Inc()
+	}))
+
+	deps.Faucet.Events.ConsolidatedOutputs.Attach(events.NewClosure(func(count int) {
+		faucetConsolidatedOutputs.Add(float64(count))
+	}))
+
+	deps.Faucet.Events.SoftError.Attach(events.NewClosure(func(_ error) {
+		faucetSoftErrEncountered.Inc()
+	}))
+
+	addCollect(collectFaucet)
+}
+
+func collectFaucet() {
+	faucetPendingRequests.Set(float64(deps.Faucet.PendingRequests()))
+
+	accountsInfo, err := deps.Faucet.AccountsInfo()
+	if err != nil {
+		return
+	}
+
+	for _, info := range accountsInfo {
+		faucetBalances.WithLabelValues(info.Address).Set(float64(info.Balance))
+		faucetUnspentOutputs.WithLabelValues(info.Address).Set(float64(info.UnspentOutputs))
+	}
+}
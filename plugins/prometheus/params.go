@@ -29,6 +29,10 @@ const (
 	CfgPrometheusMigration = "prometheus.migrationMetrics"
 	// include coordinator metrics.
 	CfgPrometheusCoordinator = "prometheus.coordinatorMetrics"
+	// include faucet metrics.
+	CfgPrometheusFaucet = "prometheus.faucetMetrics"
+	// include spammer metrics.
+	CfgPrometheusSpammer = "prometheus.spammerMetrics"
 	// include debug metrics.
 	CfgPrometheusDebug = "prometheus.debugMetrics"
 	// include go metrics.
@@ -54,6 +58,8 @@ var params = &node.PluginParams{
 			fs.Bool(CfgPrometheusRestAPI, true, "include restAPI metrics")
 			fs.Bool(CfgPrometheusMigration, true, "include migration metrics")
 			fs.Bool(CfgPrometheusCoordinator, true, "include coordinator metrics")
+			fs.Bool(CfgPrometheusFaucet, true, "include faucet metrics")
+			fs.Bool(CfgPrometheusSpammer, true, "include spammer metrics")
 			fs.Bool(CfgPrometheusDebug, false, "include debug metrics")
 			fs.Bool(CfgPrometheusGoMetrics, false, "include go metrics")
 			fs.Bool(CfgPrometheusProcessMetrics, false, "include process metrics")
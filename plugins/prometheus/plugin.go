@@ -20,6 +20,7 @@ import (
 	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/faucet"
 	"github.com/gohornet/hornet/pkg/model/migrator"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
@@ -28,6 +29,7 @@ import (
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/pkg/spammer"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/iotaledger/hive.go/configuration"
@@ -83,6 +85,8 @@ type dependencies struct {
 	TipSelector      *tipselect.TipSelector `optional:"true"`
 	SnapshotManager  *snapshot.SnapshotManager
 	Coordinator      *coordinator.Coordinator `optional:"true"`
+	Faucet           *faucet.Faucet           `optional:"true"`
+	SpammerTracker   *spammer.Tracker         `optional:"true"`
 }
 
 func configure() {
@@ -113,6 +117,12 @@ func configure() {
 	if deps.NodeConfig.Bool(CfgPrometheusCoordinator) && deps.Coordinator != nil {
 		configureCoordinator()
 	}
+	if deps.NodeConfig.Bool(CfgPrometheusFaucet) && deps.Faucet != nil {
+		configureFaucet()
+	}
+	if deps.NodeConfig.Bool(CfgPrometheusSpammer) && deps.SpammerTracker != nil {
+		configureSpammer()
+	}
 	if deps.NodeConfig.Bool(CfgPrometheusDebug) {
 		configureDebug()
 	}
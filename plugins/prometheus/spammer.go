@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gohornet/hornet/pkg/spammer"
+	"github.com/iotaledger/hive.go/events"
+)
+
+var (
+	spammerConfirmationLatency *prometheus.HistogramVec
+	spammerFinishedMessages    *prometheus.CounterVec
+	spammerPendingMessages     prometheus.Gauge
+)
+
+func configureSpammer() {
+
+	buckets := make([]float64, len(spammer.LatencyBuckets))
+	for i, bucket := range spammer.LatencyBuckets {
+		buckets[i] = bucket.Seconds()
+	}
+
+	spammerConfirmationLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "spammer",
+			Name:      "confirmation_latency_seconds",
+			Help:      "The duration between sending a spam message and the reference by a milestone.",
+			Buckets:   buckets,
+		},
+		[]string{"entry"},
+	)
+
+	spammerFinishedMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "spammer",
+			Name:      "finished_messages",
+			Help:      "The amount of spam messages that were referenced, conflicting or unreferenced.",
+		},
+		[]string{"state"},
+	)
+
+	spammerPendingMessages = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "spammer",
+			Name:      "pending_messages",
+			Help:      "The amount of spam messages that were not referenced by a milestone yet.",
+		},
+	)
+
+	registry.MustRegister(spammerConfirmationLatency)
+	registry.MustRegister(spammerFinishedMessages)
+	registry.MustRegister(spammerPendingMessages)
+
+	deps.SpammerTracker.Events.MessageFinished.Attach(events.NewClosure(func(msg *spammer.TrackedMessage) {
+		spammerFinishedMessages.WithLabelValues(string(msg.State)).Inc()
+
+		if msg.State == spammer.MessageStateUnreferenced {
+			return
+		}
+		spammerConfirmationLatency.WithLabelValues(msg.Entry).Observe(msg.Latency().Seconds())
+	}))
+
+	addCollect(collectSpammer)
+}
+
+func collectSpammer() {
+	spammerPendingMessages.Set(float64(deps.SpammerTracker.Statistics().Pending))
+}
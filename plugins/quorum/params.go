@@ -0,0 +1,31 @@
+package quorum
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// the peer IDs of the coordinators that are allowed to request white flag computations
+	CfgQuorumAllowedPeers = "quorum.allowedPeers"
+	// the maximum duration for the parents to become solid during a white flag computation request
+	CfgQuorumWhiteFlagParentsSolidTimeout = "quorum.whiteFlagParentsSolidTimeout"
+	// the maximum duration of a white flag computation request including the transfer of the request and response
+	CfgQuorumStreamTimeout = "quorum.streamTimeout"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.StringSlice(CfgQuorumAllowedPeers, []string{}, "the peer IDs of the coordinators that are allowed to request white flag computations")
+			fs.Duration(CfgQuorumWhiteFlagParentsSolidTimeout, 2*time.Second, "defines the the maximum duration for the parents to become solid during a white flag computation request")
+			fs.Duration(CfgQuorumStreamTimeout, 10*time.Second, "the maximum duration of a white flag computation request including the transfer of the request and response")
+			return fs
+		}(),
+	},
+	Masked: nil,
+}
@@ -0,0 +1,99 @@
+package quorum
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"go.uber.org/dig"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/node"
+	quorumprotocol "github.com/gohornet/hornet/pkg/protocol/quorum"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+func init() {
+	Plugin = &node.Plugin{
+		Status: node.StatusDisabled,
+		Pluggable: node.Pluggable{
+			Name:      "Quorum",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Configure: configure,
+			Run:       run,
+		},
+	}
+}
+
+var (
+	Plugin *node.Plugin
+	deps   dependencies
+
+	server *quorumprotocol.Server
+
+	whiteflagParentsSolidTimeout time.Duration
+)
+
+type dependencies struct {
+	dig.In
+	Tangle     *tangle.Tangle
+	Host       host.Host
+	NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	NetworkID  uint64                       `name:"networkId"`
+}
+
+func configure() {
+	whiteflagParentsSolidTimeout = deps.NodeConfig.Duration(CfgQuorumWhiteFlagParentsSolidTimeout)
+
+	var allowedPeers []peer.ID
+	for _, allowedPeer := range deps.NodeConfig.Strings(CfgQuorumAllowedPeers) {
+		peerID, err := peer.Decode(allowedPeer)
+		if err != nil {
+			Plugin.Panicf("invalid peer ID in %s: %s, %s", CfgQuorumAllowedPeers, allowedPeer, err)
+		}
+		allowedPeers = append(allowedPeers, peerID)
+	}
+
+	if len(allowedPeers) == 0 {
+		Plugin.LogWarnf("no peers allowed to request white flag computations, please configure %s", CfgQuorumAllowedPeers)
+	}
+
+	server = quorumprotocol.NewServer(
+		deps.Host,
+		quorumprotocol.ProtocolID(deps.NetworkID),
+		computeWhiteFlagMerkleTreeHash,
+		allowedPeers,
+		quorumprotocol.WithLogger(Plugin.Logger()),
+		quorumprotocol.WithStreamTimeout(deps.NodeConfig.Duration(CfgQuorumStreamTimeout)),
+	)
+}
+
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("Quorum", func(shutdownSignal <-chan struct{}) {
+		server.Start()
+		<-shutdownSignal
+		server.Stop()
+	}, shutdown.PriorityQuorum); err != nil {
+		Plugin.Panicf("failed to start worker: %s", err)
+	}
+}
+
+// computeWhiteFlagMerkleTreeHash computes the merkle tree hash of the white flag confirmation for the cone of the given parents.
+func computeWhiteFlagMerkleTreeHash(ctx context.Context, index milestone.Index, parents hornet.MessageIDs) (quorumprotocol.MerkleTreeHash, error) {
+
+	// wait for at most "whiteFlagParentsSolidTimeout" for the parents to become solid
+	ctx, cancel := context.WithTimeout(ctx, whiteflagParentsSolidTimeout)
+	defer cancel()
+
+	mutations, err := deps.Tangle.ComputeWhiteFlagMutations(ctx, index, parents)
+	if err != nil {
+		return quorumprotocol.MerkleTreeHash{}, err
+	}
+
+	return mutations.MerkleTreeHash, nil
+}
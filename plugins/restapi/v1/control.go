@@ -2,17 +2,57 @@ package v1
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/bytes"
 	"github.com/pkg/errors"
 
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/jobs"
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/plugins/coordinator"
+	"github.com/gohornet/hornet/plugins/migrator"
+	"github.com/iotaledger/hive.go/events"
 )
 
-func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
+const (
+	controlJobTypePruneDatabase       = "pruneDatabase"
+	controlJobTypePruneDatabaseDryRun = "pruneDatabaseDryRun"
+	controlJobTypeCreateSnapshots     = "createSnapshots"
+	controlJobTypeDatabaseBackup      = "databaseBackup"
+	controlJobTypeDatabaseMigration   = "databaseMigration"
+	controlJobTypeDatabaseSizes       = "databaseSizes"
+)
+
+var (
+	// controlJobs keeps track of the asynchronously executed control jobs.
+	controlJobs *jobs.Manager
+)
+
+// startControlJob starts the given control job if no other control job is running.
+func startControlJob(jobType string, jobFunc jobs.Func) (*controlJobResponse, error) {
+
+	job, err := controlJobs.Start(jobType, jobFunc)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobAlreadyRunning) {
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, "another control job is already running")
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "starting job failed: %s", err)
+	}
+
+	return &controlJobResponse{
+		JobID: job.ID(),
+	}, nil
+}
+
+func pruneDatabase(c echo.Context) (*controlJobResponse, error) {
 
 	if deps.SnapshotManager.IsSnapshottingOrPruning() {
 		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is already creating a snapshot or pruning is running")
@@ -30,33 +70,49 @@ func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "either index, depth or size has to be specified")
 	}
 
-	var err error
-	var targetIndex milestone.Index
-
-	if request.Index != nil {
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseByTargetIndex(*request.Index)
+	var pruningTargetDatabaseSizeBytes int64
+	if request.TargetDatabaseSize != nil {
+		var err error
+		pruningTargetDatabaseSizeBytes, err = bytes.Parse(*request.TargetDatabaseSize)
 		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid targetDatabaseSize, error: %s", err)
 		}
 	}
 
-	if request.Depth != nil {
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseByDepth(*request.Depth)
-		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
-		}
+	jobType := controlJobTypePruneDatabase
+	jobFunc := func(job *jobs.Job) (interface{}, error) {
+		return pruneDatabaseJob(job, request, pruningTargetDatabaseSizeBytes)
 	}
 
-	if request.TargetDatabaseSize != nil {
-		pruningTargetDatabaseSizeBytes, err := bytes.Parse(*request.TargetDatabaseSize)
-		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+	if request.DryRun {
+		jobType = controlJobTypePruneDatabaseDryRun
+		jobFunc = func(job *jobs.Job) (interface{}, error) {
+			return pruneDatabaseDryRunJob(job, request, pruningTargetDatabaseSizeBytes)
 		}
+	}
 
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseBySize(pruningTargetDatabaseSizeBytes)
-		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
-		}
+	return startControlJob(jobType, jobFunc)
+}
+
+func pruneDatabaseJob(job *jobs.Job, request *pruneDatabaseRequest, pruningTargetDatabaseSizeBytes int64) (*pruneDatabaseResponse, error) {
+
+	progressFunc := func(startIndex milestone.Index, currentIndex milestone.Index, targetIndex milestone.Index) {
+		job.SetProgress(jobs.NewProgress(startIndex, currentIndex, targetIndex))
+	}
+
+	var err error
+	var targetIndex milestone.Index
+
+	switch {
+	case request.Index != nil:
+		targetIndex, err = deps.SnapshotManager.PruneDatabaseByTargetIndex(*request.Index, job.AbortSignal(), progressFunc)
+	case request.Depth != nil:
+		targetIndex, err = deps.SnapshotManager.PruneDatabaseByDepth(*request.Depth, job.AbortSignal(), progressFunc)
+	default:
+		targetIndex, err = deps.SnapshotManager.PruneDatabaseBySize(pruningTargetDatabaseSizeBytes, job.AbortSignal(), progressFunc)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "pruning database failed")
 	}
 
 	return &pruneDatabaseResponse{
@@ -64,7 +120,32 @@ func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
 	}, nil
 }
 
-func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
+func pruneDatabaseDryRunJob(job *jobs.Job, request *pruneDatabaseRequest, pruningTargetDatabaseSizeBytes int64) (*pruneDatabaseResponse, error) {
+
+	var err error
+	var report *snapshot.PruningReport
+
+	switch {
+	case request.Index != nil:
+		report, err = deps.SnapshotManager.PruningReportByTargetIndex(*request.Index, job.AbortSignal())
+	case request.Depth != nil:
+		report, err = deps.SnapshotManager.PruningReportByDepth(*request.Depth, job.AbortSignal())
+	default:
+		report, err = deps.SnapshotManager.PruningReportBySize(pruningTargetDatabaseSizeBytes, job.AbortSignal())
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "pruning database dry run failed")
+	}
+
+	job.SetProgress(jobs.NewProgress(report.TargetIndex, report.TargetIndex, report.TargetIndex))
+
+	return &pruneDatabaseResponse{
+		Index:        report.TargetIndex,
+		DryRunReport: report,
+	}, nil
+}
+
+func createSnapshots(c echo.Context) (*controlJobResponse, error) {
 
 	if deps.SnapshotManager.IsSnapshottingOrPruning() {
 		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is already creating a snapshot or pruning is running")
@@ -79,28 +160,50 @@ func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "at least fullIndex or deltaIndex has to be specified")
 	}
 
+	return startControlJob(controlJobTypeCreateSnapshots, func(job *jobs.Job) (interface{}, error) {
+		return createSnapshotsJob(job, request)
+	})
+}
+
+func createSnapshotsJob(job *jobs.Job, request *createSnapshotsRequest) (*createSnapshotsResponse, error) {
+
 	var fullIndex, deltaIndex milestone.Index
 	var fullSnapshotFilePath, deltaSnapshotFilePath string
 
+	// the progress of snapshot creation is reported per created snapshot file
+	stepsTotal := 0
 	if request.FullIndex != nil {
-		fullIndex = milestone.Index(*request.FullIndex)
+		stepsTotal++
+	}
+	if request.DeltaIndex != nil {
+		stepsTotal++
+	}
+	stepsDone := 0
+
+	if request.FullIndex != nil {
+		fullIndex = *request.FullIndex
 		fullSnapshotFilePath = filepath.Join(filepath.Dir(deps.SnapshotsFullPath), fmt.Sprintf("full_snapshot_%d.bin", fullIndex))
 
-		// ToDo: abort signal?
-		if err := deps.SnapshotManager.CreateFullSnapshot(fullIndex, fullSnapshotFilePath, false, nil); err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating full snapshot failed: %s", err)
+		job.SetProgress(jobs.Progress{TargetIndex: fullIndex})
+		if err := deps.SnapshotManager.CreateFullSnapshot(fullIndex, fullSnapshotFilePath, false, job.AbortSignal()); err != nil {
+			return nil, errors.WithMessage(err, "creating full snapshot failed")
 		}
+
+		stepsDone++
+		job.SetProgress(jobs.Progress{CurrentIndex: fullIndex, TargetIndex: fullIndex, Percentage: float64(stepsDone) * 100.0 / float64(stepsTotal)})
 	}
 
 	if request.DeltaIndex != nil {
-		deltaIndex = milestone.Index(*request.DeltaIndex)
+		deltaIndex = *request.DeltaIndex
 		deltaSnapshotFilePath = filepath.Join(filepath.Dir(deps.SnapshotsDeltaPath), fmt.Sprintf("delta_snapshot_%d.bin", deltaIndex))
 
-		// ToDo: abort signal?
+		job.SetProgress(jobs.Progress{CurrentIndex: fullIndex, TargetIndex: deltaIndex, Percentage: float64(stepsDone) * 100.0 / float64(stepsTotal)})
 		// if no full snapshot was created, the last existing full snapshot will be used
-		if err := deps.SnapshotManager.CreateDeltaSnapshot(deltaIndex, deltaSnapshotFilePath, false, nil, fullSnapshotFilePath); err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating delta snapshot failed: %s", err)
+		if err := deps.SnapshotManager.CreateDeltaSnapshot(deltaIndex, deltaSnapshotFilePath, false, job.AbortSignal(), fullSnapshotFilePath); err != nil {
+			return nil, errors.WithMessage(err, "creating delta snapshot failed")
 		}
+
+		job.SetProgress(jobs.Progress{CurrentIndex: deltaIndex, TargetIndex: deltaIndex, Percentage: 100.0})
 	}
 
 	return &createSnapshotsResponse{
@@ -110,3 +213,223 @@ func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
 		DeltaFilePath: deltaSnapshotFilePath,
 	}, nil
 }
+
+func createDatabaseBackup(_ echo.Context) (*controlJobResponse, error) {
+
+	if !deps.Database.CheckpointSupported() {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "database engine does not support checkpoints")
+	}
+
+	return startControlJob(controlJobTypeDatabaseBackup, func(job *jobs.Job) (interface{}, error) {
+		return createDatabaseBackupJob(job)
+	})
+}
+
+func createDatabaseBackupJob(job *jobs.Job) (*createDatabaseBackupResponse, error) {
+
+	// the state files of the coordinator and the migrator are needed to restore a node with these plugins
+	var stateFilePaths []string
+	if !Plugin.Node.IsSkipped(coordinator.Plugin) {
+		stateFilePaths = append(stateFilePaths, deps.NodeConfig.String(coordinator.CfgCoordinatorStateFilePath))
+	}
+	if !Plugin.Node.IsSkipped(migrator.Plugin) {
+		stateFilePaths = append(stateFilePaths, deps.NodeConfig.String(migrator.CfgMigratorStateFilePath))
+	}
+
+	backupPath := filepath.Join(deps.DatabaseBackupPath, fmt.Sprintf("backup_%d", time.Now().Unix()))
+
+	// the checkpoint and the state files have to match, so no milestone is issued or confirmed while the backup is created.
+	// the coordinator is locked first, because it waits for the confirmation of the milestone it is issuing.
+	if deps.Coordinator != nil {
+		deps.Coordinator.LockMilestoneIssuance()
+	}
+	deps.UTXOManager.WriteLockLedger()
+
+	info, err := database.CreateBackup(backupPath, createDatabaseCheckpoint, stateFilePaths...)
+
+	deps.UTXOManager.WriteUnlockLedger()
+	if deps.Coordinator != nil {
+		deps.Coordinator.UnlockMilestoneIssuance()
+	}
+
+	if err != nil {
+		// do not leave a partial backup behind
+		_ = os.RemoveAll(backupPath)
+		return nil, errors.WithMessage(err, "creating database backup failed")
+	}
+
+	job.SetProgress(jobs.NewProgress(info.LedgerIndex, info.LedgerIndex, info.LedgerIndex))
+
+	stateFiles := make([]string, len(info.StateFiles))
+	for i, stateFile := range info.StateFiles {
+		stateFiles[i] = stateFile.Path
+	}
+
+	return &createDatabaseBackupResponse{
+		LedgerIndex: info.LedgerIndex,
+		BackupPath:  backupPath,
+		StateFiles:  stateFiles,
+	}, nil
+}
+
+// createDatabaseCheckpoint creates a checkpoint of the database of the running node in the given directory.
+// The ledger has to be locked, otherwise the checkpoint could contain a partially applied milestone confirmation.
+func createDatabaseCheckpoint(targetDir string) error {
+
+	// the latest changes in the caches of the object storages need to be part of the checkpoint
+	deps.Storage.FlushStorages()
+
+	if err := deps.Database.CreateCheckpoint(targetDir); err != nil {
+		return err
+	}
+
+	// the checkpoint contains the health status of the running node, which is always marked as corrupted
+	store, err := database.StoreWithDefaultSettings(targetDir, false)
+	if err != nil {
+		return fmt.Errorf("checkpoint database initialization failed: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	return storage.MarkStoreHealthy(store)
+}
+
+func migrateDatabase(c echo.Context) (*controlJobResponse, error) {
+
+	if deps.Database.MigrationRunning() {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "database migration already running or waiting for the restart of the node")
+	}
+
+	request := &migrateDatabaseRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	engine, err := database.DatabaseEngine(strings.ToLower(request.Engine))
+	if err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid engine, error: %s", err)
+	}
+
+	return startControlJob(controlJobTypeDatabaseMigration, func(job *jobs.Job) (interface{}, error) {
+		return migrateDatabaseJob(job, engine)
+	})
+}
+
+func migrateDatabaseJob(job *jobs.Job, engine database.Engine) (*migrateDatabaseResponse, error) {
+
+	var keysCopied int64
+	onDatabaseMigration := events.NewClosure(func(migration *database.DatabaseMigration) {
+		keysCopied = migration.KeysCopied
+		job.SetProgress(jobs.Progress{Percentage: migration.Percentage})
+	})
+
+	deps.Database.Events().DatabaseMigration.Attach(onDatabaseMigration)
+	defer deps.Database.Events().DatabaseMigration.Detach(onDatabaseMigration)
+
+	if err := deps.Database.MigrateEngine(engine, job.AbortSignal()); err != nil {
+		return nil, errors.WithMessage(err, "migrating database failed")
+	}
+
+	return &migrateDatabaseResponse{
+		Engine:       string(engine),
+		DatabasePath: database.MigrationDatabasePath(deps.DatabasePath),
+		KeysCopied:   keysCopied,
+	}, nil
+}
+
+func databaseSizes(_ echo.Context) (*databaseSizesResponse, error) {
+
+	prefixes, err := deps.Database.EstimatePrefixSizes()
+	if err != nil {
+		if errors.Is(err, database.ErrPrefixSizeEstimationNotSupported) {
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, "database engine does not support size estimations, the sizes need to be counted")
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "estimating database sizes failed: %s", err)
+	}
+
+	return newDatabaseSizesResponse(prefixes)
+}
+
+func countDatabaseSizes(_ echo.Context) (*controlJobResponse, error) {
+
+	return startControlJob(controlJobTypeDatabaseSizes, func(job *jobs.Job) (interface{}, error) {
+		return countDatabaseSizesJob(job)
+	})
+}
+
+func countDatabaseSizesJob(job *jobs.Job) (*databaseSizesResponse, error) {
+
+	prefixes, err := deps.Database.CountPrefixSizes(func(counted int, total int) {
+		job.SetProgress(jobs.Progress{Percentage: float64(counted) * 100 / float64(total)})
+	}, job.AbortSignal())
+	if err != nil {
+		return nil, errors.WithMessage(err, "counting database sizes failed")
+	}
+
+	return newDatabaseSizesResponse(prefixes)
+}
+
+func newDatabaseSizesResponse(prefixes []*database.PrefixStatistics) (*databaseSizesResponse, error) {
+
+	totalSize, err := deps.Database.Size()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "calculating database size failed: %s", err)
+	}
+
+	return &databaseSizesResponse{
+		Engine:    string(deps.DatabaseEngine),
+		TotalSize: totalSize,
+		Prefixes:  prefixes,
+	}, nil
+}
+
+func controlJobByID(c echo.Context) (*controlJobStatusResponse, error) {
+
+	job, err := controlJobs.Job(c.Param(ParameterJobID))
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "job not found: %s", c.Param(ParameterJobID))
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading job failed: %s", err)
+	}
+
+	info := job.Info()
+
+	resp := &controlJobStatusResponse{
+		JobID:  info.ID,
+		Type:   info.Type,
+		Status: string(info.Status),
+		Progress: &controlJobProgress{
+			StartIndex:   info.Progress.StartIndex,
+			CurrentIndex: info.Progress.CurrentIndex,
+			TargetIndex:  info.Progress.TargetIndex,
+			Percentage:   info.Progress.Percentage,
+		},
+		Result:    info.Result,
+		CreatedAt: info.CreatedAt.Unix(),
+	}
+
+	if info.Error != nil {
+		resp.Error = info.Error.Error()
+	}
+
+	if !info.FinishedAt.IsZero() {
+		resp.FinishedAt = info.FinishedAt.Unix()
+	}
+
+	return resp, nil
+}
+
+func cancelControlJob(c echo.Context) error {
+
+	if err := controlJobs.Cancel(c.Param(ParameterJobID)); err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return errors.WithMessagef(echo.ErrNotFound, "job not found: %s", c.Param(ParameterJobID))
+		}
+		if errors.Is(err, jobs.ErrJobNotRunning) {
+			return errors.WithMessagef(restapi.ErrInvalidParameter, "job is not running: %s", c.Param(ParameterJobID))
+		}
+		return errors.WithMessagef(echo.ErrInternalServerError, "canceling job failed: %s", err)
+	}
+
+	return nil
+}
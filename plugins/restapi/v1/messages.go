@@ -43,11 +43,49 @@ func messageMetadataByID(c echo.Context) (*messageMetadataResponse, error) {
 
 	cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID)
 	if cachedMsgMeta == nil {
-		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+		metadata, err := messageMetadataFromColdStorage(messageID)
+		if err != nil {
+			return nil, err
+		}
+		return newMessageMetadataResponse(metadata), nil
 	}
 	defer cachedMsgMeta.Release(true)
 
 	metadata := cachedMsgMeta.Metadata()
+	messageMetadataResponse := newMessageMetadataResponse(metadata)
+
+	if !metadata.IsReferenced() && metadata.IsSolid() {
+		// determine info about the quality of the tip if not referenced
+		cmi := deps.SyncManager.ConfirmedMilestoneIndex()
+		ycri, ocri := dag.ConeRootIndexes(deps.Storage, cachedMsgMeta.Retain(), cmi)
+
+		// if none of the following checks is true, the tip is non-lazy, so there is no need to promote or reattach
+		shouldPromote := false
+		shouldReattach := false
+
+		if (cmi - ocri) > milestone.Index(deps.BelowMaxDepth) {
+			// if the OCRI to CMI delta is over BelowMaxDepth/below-max-depth, then the tip is lazy and should be reattached
+			shouldPromote = false
+			shouldReattach = true
+		} else if (cmi - ycri) > milestone.Index(deps.MaxDeltaMsgYoungestConeRootIndexToCMI) {
+			// if the CMI to YCRI delta is over CfgTipSelMaxDeltaMsgYoungestConeRootIndexToCMI, then the tip is lazy and should be promoted
+			shouldPromote = true
+			shouldReattach = false
+		} else if (cmi - ocri) > milestone.Index(deps.MaxDeltaMsgOldestConeRootIndexToCMI) {
+			// if the OCRI to CMI delta is over CfgTipSelMaxDeltaMsgOldestConeRootIndexToCMI, the tip is semi-lazy and should be promoted
+			shouldPromote = true
+			shouldReattach = false
+		}
+
+		messageMetadataResponse.ShouldPromote = &shouldPromote
+		messageMetadataResponse.ShouldReattach = &shouldReattach
+	}
+
+	return messageMetadataResponse, nil
+}
+
+// newMessageMetadataResponse creates the response for the given message metadata without the tip quality information.
+func newMessageMetadataResponse(metadata *storage.MessageMetadata) *messageMetadataResponse {
 
 	var referencedByMilestone *milestone.Index = nil
 	referenced, referencedIndex := metadata.ReferencedWithIndex()
@@ -79,34 +117,9 @@ func messageMetadataByID(c echo.Context) (*messageMetadataResponse, error) {
 		}
 
 		messageMetadataResponse.LedgerInclusionState = &inclusionState
-	} else if metadata.IsSolid() {
-		// determine info about the quality of the tip if not referenced
-		cmi := deps.SyncManager.ConfirmedMilestoneIndex()
-		ycri, ocri := dag.ConeRootIndexes(deps.Storage, cachedMsgMeta.Retain(), cmi)
-
-		// if none of the following checks is true, the tip is non-lazy, so there is no need to promote or reattach
-		shouldPromote := false
-		shouldReattach := false
-
-		if (cmi - ocri) > milestone.Index(deps.BelowMaxDepth) {
-			// if the OCRI to CMI delta is over BelowMaxDepth/below-max-depth, then the tip is lazy and should be reattached
-			shouldPromote = false
-			shouldReattach = true
-		} else if (cmi - ycri) > milestone.Index(deps.MaxDeltaMsgYoungestConeRootIndexToCMI) {
-			// if the CMI to YCRI delta is over CfgTipSelMaxDeltaMsgYoungestConeRootIndexToCMI, then the tip is lazy and should be promoted
-			shouldPromote = true
-			shouldReattach = false
-		} else if (cmi - ocri) > milestone.Index(deps.MaxDeltaMsgOldestConeRootIndexToCMI) {
-			// if the OCRI to CMI delta is over CfgTipSelMaxDeltaMsgOldestConeRootIndexToCMI, the tip is semi-lazy and should be promoted
-			shouldPromote = true
-			shouldReattach = false
-		}
-
-		messageMetadataResponse.ShouldPromote = &shouldPromote
-		messageMetadataResponse.ShouldReattach = &shouldReattach
 	}
 
-	return messageMetadataResponse, nil
+	return messageMetadataResponse
 }
 
 func messageByID(c echo.Context) (*iotago.Message, error) {
@@ -119,7 +132,11 @@ func messageByID(c echo.Context) (*iotago.Message, error) {
 
 	cachedMsg := deps.Storage.CachedMessageOrNil(messageID)
 	if cachedMsg == nil {
-		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageIDHex)
+		msg, err := messageFromColdStorage(messageID)
+		if err != nil {
+			return nil, err
+		}
+		return msg.Message(), nil
 	}
 	defer cachedMsg.Release(true)
 
@@ -136,13 +153,51 @@ func messageBytesByID(c echo.Context) ([]byte, error) {
 
 	cachedMsg := deps.Storage.CachedMessageOrNil(messageID)
 	if cachedMsg == nil {
-		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageIDHex)
+		msg, err := messageFromColdStorage(messageID)
+		if err != nil {
+			return nil, err
+		}
+		return msg.Data(), nil
 	}
 	defer cachedMsg.Release(true)
 
 	return cachedMsg.Message().Data(), nil
 }
 
+// messageFromColdStorage returns the message from the cold storage if the node runs in permanode mode.
+func messageFromColdStorage(messageID hornet.MessageID) (*storage.Message, error) {
+	if deps.ColdStorage == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	msg, err := deps.ColdStorage.MessageOrNil(messageID)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to load message from cold storage: %s, error: %s", messageID.ToHex(), err)
+	}
+	if msg == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	return msg, nil
+}
+
+// messageMetadataFromColdStorage returns the message metadata from the cold storage if the node runs in permanode mode.
+func messageMetadataFromColdStorage(messageID hornet.MessageID) (*storage.MessageMetadata, error) {
+	if deps.ColdStorage == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	metadata, err := deps.ColdStorage.MessageMetadataOrNil(messageID)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to load message metadata from cold storage: %s, error: %s", messageID.ToHex(), err)
+	}
+	if metadata == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	return metadata, nil
+}
+
 func childrenIDsByID(c echo.Context) (*childrenResponse, error) {
 	messageIDHex := strings.ToLower(c.Param(ParameterMessageID))
 
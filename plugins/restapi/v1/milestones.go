@@ -36,7 +36,7 @@ func milestoneByIndex(c echo.Context) (*milestoneResponse, error) {
 
 	cachedMilestone := deps.Storage.CachedMilestoneOrNil(msIndex) // milestone +1
 	if cachedMilestone == nil {
-		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone not found: %d", msIndex)
+		return milestoneFromColdStorage(msIndex)
 	}
 	defer cachedMilestone.Release(true)
 
@@ -47,6 +47,27 @@ func milestoneByIndex(c echo.Context) (*milestoneResponse, error) {
 	}, nil
 }
 
+// milestoneFromColdStorage returns the milestone from the cold storage if the node runs in permanode mode.
+func milestoneFromColdStorage(msIndex milestone.Index) (*milestoneResponse, error) {
+	if deps.ColdStorage == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone not found: %d", msIndex)
+	}
+
+	ms, err := deps.ColdStorage.MilestoneOrNil(msIndex)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to load milestone from cold storage: %d, error: %s", msIndex, err)
+	}
+	if ms == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone not found: %d", msIndex)
+	}
+
+	return &milestoneResponse{
+		Index:     uint32(ms.Index),
+		MessageID: ms.MessageID.ToHex(),
+		Time:      ms.Timestamp.Unix(),
+	}, nil
+}
+
 func milestoneUTXOChangesByIndex(c echo.Context) (*milestoneUTXOChangesResponse, error) {
 
 	msIndex, err := ParseMilestoneIndexParam(c)
@@ -9,6 +9,10 @@ import (
 	"go.uber.org/dig"
 
 	"github.com/gohornet/hornet/pkg/app"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/jobs"
+	"github.com/gohornet/hornet/pkg/model/coldstorage"
+	coordinatorpkg "github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -17,6 +21,7 @@ import (
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/snapshot"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/tipselect"
@@ -47,6 +52,9 @@ const (
 
 	// ParameterPeerID is used to identify a peer.
 	ParameterPeerID = "peerID"
+
+	// ParameterJobID is used to identify a control job.
+	ParameterJobID = "jobID"
 )
 
 const (
@@ -135,12 +143,30 @@ const (
 	RoutePeers = "/peers"
 
 	// RouteControlDatabasePrune is the control route to manually prune the database.
-	// POST prunes the database.
+	// POST starts a job that prunes the database (or only reports the effect of the pruning if "dryRun" is set).
 	RouteControlDatabasePrune = "/control/database/prune"
 
 	// RouteControlSnapshotsCreate is the control route to manually create a snapshot files.
-	// POST creates a snapshot (full, delta or both).
+	// POST starts a job that creates a snapshot (full, delta or both).
 	RouteControlSnapshotsCreate = "/control/snapshots/create"
+
+	// RouteControlDatabaseBackup is the control route to manually create a backup of the database.
+	// POST starts a job that creates a consistent checkpoint of the database together with the state files.
+	RouteControlDatabaseBackup = "/control/database/backup"
+
+	// RouteControlDatabaseMigrate is the control route to migrate the database to another engine while the node is running.
+	// POST starts a job that mirrors all writes to the new database and copies the existing keys in the background.
+	RouteControlDatabaseMigrate = "/control/database/migrate"
+
+	// RouteControlDatabaseSizes is the control route to get the sizes of the storages in the database.
+	// GET returns the estimated sizes on disk of all storages (if supported by the database engine).
+	// POST starts a job that counts the keys and the sizes of all storages.
+	RouteControlDatabaseSizes = "/control/database/sizes"
+
+	// RouteControlJob is the control route to manage control jobs by their jobID.
+	// GET returns the status, progress and result of the job.
+	// DELETE cancels the job.
+	RouteControlJob = "/control/jobs/:" + ParameterJobID
 )
 
 func init() {
@@ -177,21 +203,27 @@ type dependencies struct {
 	PoWHandler                            *pow.Handler
 	MessageProcessor                      *gossip.MessageProcessor
 	SnapshotManager                       *snapshot.SnapshotManager
+	Database                              *database.Database
+	ColdStorage                           *coldstorage.ColdStorage
 	AppInfo                               *app.AppInfo
 	NodeConfig                            *configuration.Configuration `name:"nodeConfig"`
 	PeeringConfigManager                  *p2p.ConfigManager
-	NetworkID                             uint64                 `name:"networkId"`
-	NetworkIDName                         string                 `name:"networkIdName"`
-	MaxDeltaMsgYoungestConeRootIndexToCMI int                    `name:"maxDeltaMsgYoungestConeRootIndexToCMI"`
-	MaxDeltaMsgOldestConeRootIndexToCMI   int                    `name:"maxDeltaMsgOldestConeRootIndexToCMI"`
-	BelowMaxDepth                         int                    `name:"belowMaxDepth"`
-	MinPoWScore                           float64                `name:"minPoWScore"`
-	Bech32HRP                             iotago.NetworkPrefix   `name:"bech32HRP"`
-	RestAPILimitsMaxResults               int                    `name:"restAPILimitsMaxResults"`
-	SnapshotsFullPath                     string                 `name:"snapshotsFullPath"`
-	SnapshotsDeltaPath                    string                 `name:"snapshotsDeltaPath"`
-	TipSelector                           *tipselect.TipSelector `optional:"true"`
-	Echo                                  *echo.Echo             `optional:"true"`
+	NetworkID                             uint64                      `name:"networkId"`
+	NetworkIDName                         string                      `name:"networkIdName"`
+	MaxDeltaMsgYoungestConeRootIndexToCMI int                         `name:"maxDeltaMsgYoungestConeRootIndexToCMI"`
+	MaxDeltaMsgOldestConeRootIndexToCMI   int                         `name:"maxDeltaMsgOldestConeRootIndexToCMI"`
+	BelowMaxDepth                         int                         `name:"belowMaxDepth"`
+	MinPoWScore                           float64                     `name:"minPoWScore"`
+	Bech32HRP                             iotago.NetworkPrefix        `name:"bech32HRP"`
+	RestAPILimitsMaxResults               int                         `name:"restAPILimitsMaxResults"`
+	SnapshotsFullPath                     string                      `name:"snapshotsFullPath"`
+	SnapshotsDeltaPath                    string                      `name:"snapshotsDeltaPath"`
+	DatabaseBackupPath                    string                      `name:"databaseBackupPath"`
+	DatabasePath                          string                      `name:"databasePath"`
+	DatabaseEngine                        database.Engine             `name:"databaseEngine"`
+	TipSelector                           *tipselect.TipSelector      `optional:"true"`
+	Coordinator                           *coordinatorpkg.Coordinator `optional:"true"`
+	Echo                                  *echo.Echo                  `optional:"true"`
 }
 
 func configure() {
@@ -202,6 +234,9 @@ func configure() {
 
 	routeGroup := deps.Echo.Group("/api/v1")
 
+	// the control jobs (pruning, snapshot creation) are canceled on shutdown and the shutdown waits until they finished
+	controlJobs = jobs.NewManager(Plugin.Daemon(), "Control jobs", shutdown.PriorityRestAPI)
+
 	powEnabled = deps.NodeConfig.Bool(restapi.CfgRestAPIPoWEnabled)
 	powWorkerCount = deps.NodeConfig.Int(restapi.CfgRestAPIPoWWorkerCount)
 
@@ -422,7 +457,7 @@ func configure() {
 			return err
 		}
 
-		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
 	})
 
 	routeGroup.POST(RouteControlSnapshotsCreate, func(c echo.Context) error {
@@ -431,6 +466,59 @@ func configure() {
 			return err
 		}
 
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.POST(RouteControlDatabaseBackup, func(c echo.Context) error {
+		resp, err := createDatabaseBackup(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.POST(RouteControlDatabaseMigrate, func(c echo.Context) error {
+		resp, err := migrateDatabase(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.GET(RouteControlDatabaseSizes, func(c echo.Context) error {
+		resp, err := databaseSizes(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteControlDatabaseSizes, func(c echo.Context) error {
+		resp, err := countDatabaseSizes(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.GET(RouteControlJob, func(c echo.Context) error {
+		resp, err := controlJobByID(c)
+		if err != nil {
+			return err
+		}
+
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
+
+	routeGroup.DELETE(RouteControlJob, func(c echo.Context) error {
+		if err := cancelControlJob(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
 }
@@ -31,16 +31,51 @@ func messageByTransactionID(c echo.Context) (*iotago.Message, error) {
 	output, err := deps.UTXOManager.ReadOutputByOutputIDWithoutLocking(outputID)
 	if err != nil {
 		if errors.Is(err, kvstore.ErrKeyNotFound) {
-			return nil, errors.WithMessagef(echo.ErrNotFound, "output for transaction not found: %s", transactionIDHex)
+			// the output may have been spent and pruned already
+			return messageByTransactionIDFromColdStorage(transactionID, transactionIDHex)
 		}
 		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to load output for transaction: %s", transactionIDHex)
 	}
 
 	cachedMsg := deps.Storage.CachedMessageOrNil(output.MessageID())
 	if cachedMsg == nil {
-		return nil, errors.WithMessagef(echo.ErrNotFound, "transaction not found: %s", transactionIDHex)
+		if deps.ColdStorage == nil {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "transaction not found: %s", transactionIDHex)
+		}
+
+		msg, err := messageFromColdStorage(output.MessageID())
+		if err != nil {
+			return nil, err
+		}
+		return msg.Message(), nil
 	}
 	defer cachedMsg.Release(true)
 
 	return cachedMsg.Message().Message(), nil
 }
+
+// messageByTransactionIDFromColdStorage returns the message that included the given transaction from the cold storage
+// if the node runs in permanode mode.
+func messageByTransactionIDFromColdStorage(transactionID []byte, transactionIDHex string) (*iotago.Message, error) {
+	if deps.ColdStorage == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "output for transaction not found: %s", transactionIDHex)
+	}
+
+	txID := &iotago.TransactionID{}
+	copy(txID[:], transactionID)
+
+	messageID, err := deps.ColdStorage.IncludedMessageIDOrNil(txID)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to load included message from cold storage: %s, error: %s", transactionIDHex, err)
+	}
+	if messageID == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "transaction not found: %s", transactionIDHex)
+	}
+
+	msg, err := messageFromColdStorage(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Message(), nil
+}
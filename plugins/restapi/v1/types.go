@@ -3,10 +3,12 @@ package v1
 import (
 	"encoding/json"
 
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
+	"github.com/gohornet/hornet/pkg/snapshot"
 )
 
 // infoResponse defines the response of a GET info REST API call.
@@ -208,12 +210,16 @@ type pruneDatabaseRequest struct {
 	Depth *milestone.Index `json:"depth,omitempty"`
 	// The target size of the database.
 	TargetDatabaseSize *string `json:"targetDatabaseSize,omitempty"`
+	// Whether to only report the effect of the pruning without mutating the database.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // pruneDatabaseResponse defines the response of a prune database REST API call.
 type pruneDatabaseResponse struct {
 	// The index of the snapshot.
 	Index milestone.Index `json:"index"`
+	// The estimated effect of the pruning (only set in dry-run mode).
+	DryRunReport *snapshot.PruningReport `json:"dryRunReport,omitempty"`
 }
 
 // createSnapshotsRequest defines the request of a create snapshots REST API call.
@@ -235,3 +241,77 @@ type createSnapshotsResponse struct {
 	// The file path of the delta snapshot file.
 	DeltaFilePath string `json:"deltaFilePath,omitempty"`
 }
+
+// createDatabaseBackupResponse defines the result of a create database backup job.
+type createDatabaseBackupResponse struct {
+	// The ledger index of the database backup.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The path of the backup folder.
+	BackupPath string `json:"backupPath"`
+	// The paths of the state files that are part of the backup.
+	StateFiles []string `json:"stateFiles"`
+}
+
+// migrateDatabaseRequest defines the request of a migrate database REST API call.
+type migrateDatabaseRequest struct {
+	// The engine of the target database.
+	Engine string `json:"engine"`
+}
+
+// migrateDatabaseResponse defines the result of a migrate database job.
+type migrateDatabaseResponse struct {
+	// The engine of the target database.
+	Engine string `json:"engine"`
+	// The path of the target database.
+	DatabasePath string `json:"databasePath"`
+	// The amount of existing keys that were copied.
+	KeysCopied int64 `json:"keysCopied"`
+}
+
+// databaseSizesResponse defines the response of a GET database sizes REST API call and the result of a count database sizes job.
+type databaseSizesResponse struct {
+	// The engine of the database.
+	Engine string `json:"engine"`
+	// The total size of the database folder in bytes.
+	TotalSize int64 `json:"totalSize"`
+	// The sizes of the storages in the database.
+	Prefixes []*database.PrefixStatistics `json:"prefixes"`
+}
+
+// controlJobResponse defines the response of a REST API call that started a control job.
+type controlJobResponse struct {
+	// The ID of the started job.
+	JobID string `json:"jobId"`
+}
+
+// controlJobProgress defines the progress of a control job.
+type controlJobProgress struct {
+	// The milestone index the job started at.
+	StartIndex milestone.Index `json:"startIndex"`
+	// The milestone index the job is currently processing.
+	CurrentIndex milestone.Index `json:"currentIndex"`
+	// The milestone index the job is working towards.
+	TargetIndex milestone.Index `json:"targetIndex"`
+	// The progress of the job in percent.
+	Percentage float64 `json:"percentage"`
+}
+
+// controlJobStatusResponse defines the response of a GET control job REST API call.
+type controlJobStatusResponse struct {
+	// The ID of the job.
+	JobID string `json:"jobId"`
+	// The type of the job.
+	Type string `json:"type"`
+	// The status of the job (running, finished, failed, canceled).
+	Status string `json:"status"`
+	// The progress of the job.
+	Progress *controlJobProgress `json:"progress"`
+	// The result of the job (only set if the job finished successfully).
+	Result interface{} `json:"result,omitempty"`
+	// The error of the job (only set if the job failed or was canceled).
+	Error string `json:"error,omitempty"`
+	// The unix time the job was created.
+	CreatedAt int64 `json:"createdAt"`
+	// The unix time the job finished.
+	FinishedAt int64 `json:"finishedAt,omitempty"`
+}
@@ -1,10 +1,14 @@
 package spammer
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"runtime"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/restapi"
 )
@@ -24,17 +28,29 @@ const (
 	// RouteSpammerStop is the route to stop the spammer.
 	// POST to stop the spammer.
 	RouteSpammerStop = "/stop"
+
+	// RouteSpammerStatistics is the route to get the statistics of the messages sent by the spammer.
+	// GET the confirmation latency histogram and the referenced/conflicting/unreferenced ratios.
+	RouteSpammerStatistics = "/statistics"
+
+	// RouteSpammerReport is the route to export the messages sent by the spammer.
+	// GET a CSV report of the latest finished and all pending messages.
+	RouteSpammerReport = "/report"
 )
 
 type spammerStatus struct {
 	Running           bool    `json:"running"`
+	Mode              string  `json:"mode"`
 	MpsRateLimit      float64 `json:"mpsRateLimit"`
 	CPUMaxUsage       float64 `json:"cpuMaxUsage"`
 	SpammerWorkers    int     `json:"spammerWorkers"`
 	SpammerWorkersMax int     `json:"spammerWorkersMax"`
+	FundingAddress    string  `json:"fundingAddress,omitempty"`
+	WalletBalance     uint64  `json:"walletBalance,omitempty"`
 }
 
 type startCommand struct {
+	Mode           *string  `json:"mode,omitempty"`
 	MpsRateLimit   *float64 `json:"mpsRateLimit,omitempty"`
 	CPUMaxUsage    *float64 `json:"cpuMaxUsage,omitempty"`
 	SpammerWorkers *int     `json:"spammerWorkers,omitempty"`
@@ -43,13 +59,40 @@ type startCommand struct {
 func setupRoutes(g *echo.Group) {
 
 	g.GET(RouteSpammerStatus, func(c echo.Context) error {
-		return restapi.JSONResponse(c, http.StatusOK, &spammerStatus{
+		status := &spammerStatus{
 			Running:           isRunning,
+			Mode:              modeRunning,
 			MpsRateLimit:      mpsRateLimitRunning,
 			CPUMaxUsage:       cpuMaxUsageRunning,
 			SpammerWorkers:    spammerWorkersRunning,
 			SpammerWorkersMax: runtime.NumCPU() - 1,
-		})
+		}
+
+		if wallet := spammerInstance.Wallet(); wallet != nil {
+			balance, err := wallet.Balance()
+			if err != nil {
+				return errors.WithMessagef(echo.ErrInternalServerError, "reading wallet balance failed, error: %s", err)
+			}
+
+			status.FundingAddress = wallet.FundingAddress().Bech32(deps.Bech32HRP)
+			status.WalletBalance = balance
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, status)
+	})
+
+	g.GET(RouteSpammerStatistics, func(c echo.Context) error {
+		return restapi.JSONResponse(c, http.StatusOK, deps.Tracker.Statistics())
+	})
+
+	g.GET(RouteSpammerReport, func(c echo.Context) error {
+		var buf bytes.Buffer
+		if err := deps.Tracker.WriteCSV(&buf); err != nil {
+			return errors.WithMessagef(echo.ErrInternalServerError, "creating spammer report failed, error: %s", err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"spammer_report_%s.csv\"", time.Now().Format("20060102_150405")))
+		return c.Blob(http.StatusOK, "text/csv", buf.Bytes())
 	})
 
 	g.POST(RouteSpammerStart, func(c echo.Context) error {
@@ -58,7 +101,7 @@ func setupRoutes(g *echo.Group) {
 			return err
 		}
 
-		if err := start(cmd.MpsRateLimit, cmd.CPUMaxUsage, cmd.SpammerWorkers); err != nil {
+		if err := start(cmd.Mode, cmd.MpsRateLimit, cmd.CPUMaxUsage, cmd.SpammerWorkers); err != nil {
 			return err
 		}
 		return c.JSON(http.StatusAccepted, nil)
@@ -1,6 +1,8 @@
 package spammer
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 
 	"github.com/gohornet/hornet/pkg/node"
@@ -21,6 +23,16 @@ const (
 	CfgSpammerWorkers = "spammer.workers"
 	// CfgSpammerAutostart automatically starts the spammer on node startup
 	CfgSpammerAutostart = "spammer.autostart"
+	// the kind of messages the spammer issues ("indexation", "value" or "scenario")
+	CfgSpammerMode = "spammer.mode"
+	// the path to the scenario file that describes the mix of messages in the "scenario" mode
+	CfgSpammerScenarioPath = "spammer.scenarioPath"
+	// the amount of addresses derived from the seed that are used to spam value transactions (at least two are needed for conflicts)
+	CfgSpammerValueAddressCount = "spammer.value.addressCount"
+	// the duration after which a sent message that was not referenced by a milestone is considered unreferenced
+	CfgSpammerTrackingUnreferencedTimeout = "spammer.tracking.unreferencedTimeout"
+	// the maximum amount of finished messages that are kept for the report
+	CfgSpammerTrackingMaxReportEntries = "spammer.tracking.maxReportEntries"
 )
 
 var params = &node.PluginParams{
@@ -34,6 +46,11 @@ var params = &node.PluginParams{
 			fs.Float64(CfgSpammerMPSRateLimit, 0.0, "the rate limit for the spammer (0 = no limit)")
 			fs.Int(CfgSpammerWorkers, 0, "the amount of parallel running spammers")
 			fs.Bool(CfgSpammerAutostart, false, "automatically start the spammer on node startup")
+			fs.String(CfgSpammerMode, spammerModeIndexation, "the kind of messages the spammer issues (\"indexation\", \"value\" or \"scenario\")")
+			fs.String(CfgSpammerScenarioPath, "", "the path to the scenario file that describes the mix of messages in the \"scenario\" mode")
+			fs.Int(CfgSpammerValueAddressCount, 10, "the amount of addresses derived from the seed that are used to spam value transactions (at least two are needed for conflicts)")
+			fs.Duration(CfgSpammerTrackingUnreferencedTimeout, 5*time.Minute, "the duration after which a sent message that was not referenced by a milestone is considered unreferenced")
+			fs.Int(CfgSpammerTrackingMaxReportEntries, 10000, "the maximum amount of finished messages that are kept for the report")
 			return fs
 		}(),
 	},
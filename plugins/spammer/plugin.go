@@ -2,7 +2,9 @@ package spammer
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"os"
 	"runtime"
 	"sync"
 	"time"
@@ -14,14 +16,18 @@ import (
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/spammer"
+	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/gohornet/hornet/plugins/coordinator"
@@ -31,6 +37,24 @@ import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/syncutils"
 	"github.com/iotaledger/hive.go/timeutil"
+	iotago "github.com/iotaledger/iota.go/v2"
+)
+
+const (
+	// the spammer issues messages with indexation payloads.
+	spammerModeIndexation = "indexation"
+	// the spammer issues messages with signed value transactions.
+	spammerModeValue = "value"
+	// the spammer issues a mix of messages described by a scenario file.
+	spammerModeScenario = "scenario"
+
+	// the interval in which the tracker checks for unreferenced messages.
+	trackerCheckInterval = 10 * time.Second
+
+	// the duration a spammer waits after the first failed attempt, it is doubled after every further failed attempt.
+	spamErrorBackoffMin = 100 * time.Millisecond
+	// the maximum duration a spammer waits after a failed attempt.
+	spamErrorBackoffMax = 5 * time.Second
 )
 
 func init() {
@@ -40,6 +64,7 @@ func init() {
 			Name:      "Spammer",
 			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
 			Params:    params,
+			Provide:   provide,
 			Configure: configure,
 			Run:       run,
 		},
@@ -51,6 +76,7 @@ var (
 	deps   dependencies
 
 	spammerInstance *spammer.Spammer
+	spammerScenario *spammer.Scenario
 	spammerLock     syncutils.RWMutex
 
 	spammerStartTime    time.Time
@@ -58,6 +84,7 @@ var (
 	lastSentSpamMsgsCnt uint32
 
 	isRunning             bool
+	modeRunning           string
 	mpsRateLimitRunning   float64
 	cpuMaxUsageRunning    float64
 	spammerWorkersRunning int
@@ -69,6 +96,9 @@ var (
 	processID        atomic.Uint32
 	spammerWaitGroup sync.WaitGroup
 
+	// Closures
+	onMessageReferenced *events.Closure
+
 	// events of the spammer
 	Events = &spammer.SpammerEvents{
 		SpamPerformed:         events.NewEvent(spammer.SpamStatsCaller),
@@ -77,21 +107,46 @@ var (
 
 	// ErrSpammerDisabled is returned if the spammer plugin is disabled.
 	ErrSpammerDisabled = errors.New("spammer plugin disabled")
+	// ErrUnknownSpammerMode is returned if an unknown spammer mode is given.
+	ErrUnknownSpammerMode = errors.New("unknown spammer mode")
 )
 
 type dependencies struct {
 	dig.In
 	MessageProcessor *gossip.MessageProcessor
+	Storage          *storage.Storage
+	Tangle           *tangle.Tangle
 	SyncManager      *syncmanager.SyncManager
 	ServerMetrics    *metrics.ServerMetrics
 	PoWHandler       *pow.Handler
 	PeeringManager   *p2p.Manager
+	UTXOManager      *utxo.Manager
+	Tracker          *spammer.Tracker
 	TipSelector      *tipselect.TipSelector       `optional:"true"`
 	NodeConfig       *configuration.Configuration `name:"nodeConfig"`
 	NetworkID        uint64                       `name:"networkId"`
+	BelowMaxDepth    int                          `name:"belowMaxDepth"`
+	Bech32HRP        iotago.NetworkPrefix         `name:"bech32HRP"`
 	Echo             *echo.Echo                   `optional:"true"`
 }
 
+func provide(c *dig.Container) {
+
+	type trackerDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	}
+
+	if err := c.Provide(func(deps trackerDeps) *spammer.Tracker {
+		return spammer.NewTracker(
+			deps.NodeConfig.Duration(CfgSpammerTrackingUnreferencedTimeout),
+			deps.NodeConfig.Int(CfgSpammerTrackingMaxReportEntries),
+		)
+	}); err != nil {
+		Plugin.Panic(err)
+	}
+}
+
 func configure() {
 	// check if RestAPI plugin is disabled
 	if Plugin.Node.IsSkipped(restapi.Plugin) {
@@ -120,6 +175,31 @@ func configure() {
 		return nil
 	}
 
+	// the wallet is only needed to spam value transactions
+	var wallet *spammer.Wallet
+	if seedHex, exists := os.LookupEnv("SPAMMER_SEED"); exists {
+		seed, err := hex.DecodeString(seedHex)
+		if err != nil {
+			Plugin.Panicf("loading spammer seed failed, err: %s", err)
+		}
+
+		wallet, err = spammer.NewWallet(deps.UTXOManager, seed, deps.NodeConfig.Int(CfgSpammerValueAddressCount))
+		if err != nil {
+			Plugin.Panicf("loading spammer wallet failed, err: %s", err)
+		}
+
+		Plugin.LogInfof("Fund the address %s to spam value transactions", wallet.FundingAddress().Bech32(deps.Bech32HRP))
+	}
+
+	if scenarioPath := deps.NodeConfig.String(CfgSpammerScenarioPath); scenarioPath != "" {
+		var err error
+		spammerScenario, err = spammer.LoadScenario(scenarioPath)
+		if err != nil {
+			Plugin.Panicf("loading spammer scenario failed, err: %s", err)
+		}
+	}
+
+	modeRunning = deps.NodeConfig.String(CfgSpammerMode)
 	mpsRateLimitRunning = deps.NodeConfig.Float64(CfgSpammerMPSRateLimit)
 	cpuMaxUsageRunning = deps.NodeConfig.Float64(CfgSpammerCPUMaxUsage)
 	spammerWorkersRunning = deps.NodeConfig.Int(CfgSpammerWorkers)
@@ -137,7 +217,33 @@ func configure() {
 		deps.PoWHandler,
 		sendMessage,
 		deps.ServerMetrics,
+		wallet,
+		&spammer.ParentsFuncs{
+			NonLazy:       deps.TipSelector.SelectNonLazyTips,
+			SemiLazy:      deps.TipSelector.SelectSemiLazyTips,
+			BelowMaxDepth: belowMaxDepthParents,
+		},
+		deps.Tracker,
 	)
+
+	configureEvents()
+}
+
+func configureEvents() {
+	onMessageReferenced = events.NewClosure(func(cachedMetadata *storage.CachedMetadata, index milestone.Index, _ uint64) {
+		defer cachedMetadata.Release(true) // meta -1
+
+		metadata := cachedMetadata.Metadata()
+		deps.Tracker.MessageReferenced(metadata.MessageID(), index, metadata.Conflict())
+	})
+}
+
+func attachEvents() {
+	deps.Tangle.Events.MessageReferenced.Attach(onMessageReferenced)
+}
+
+func detachEvents() {
+	deps.Tangle.Events.MessageReferenced.Detach(onMessageReferenced)
 }
 
 func run() {
@@ -154,14 +260,27 @@ func run() {
 		Plugin.Panicf("failed to start worker: %s", err)
 	}
 
+	// create a background worker that tracks the sent spam messages until they are referenced by a milestone
+	if err := Plugin.Daemon().BackgroundWorker("Spammer[Tracker]", func(shutdownSignal <-chan struct{}) {
+		// the sent spam messages are only tracked while the spammer runs
+		defer detachEvents()
+
+		ticker := timeutil.NewTicker(deps.Tracker.CheckUnreferenced, trackerCheckInterval, shutdownSignal)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PrioritySpammer); err != nil {
+		Plugin.Panicf("failed to start worker: %s", err)
+	}
+
 	// automatically start the spammer on node startup if the flag is set
 	if deps.NodeConfig.Bool(CfgSpammerAutostart) {
-		_ = start(nil, nil, nil)
+		if err := start(nil, nil, nil, nil); err != nil {
+			Plugin.LogWarnf("failed to start spammer: %s", err)
+		}
 	}
 }
 
 // start starts the spammer to spam with the given settings, otherwise it uses the settings from the config.
-func start(mpsRateLimit *float64, cpuMaxUsage *float64, spammerWorkers *int) error {
+func start(mode *string, mpsRateLimit *float64, cpuMaxUsage *float64, spammerWorkers *int) error {
 	if spammerInstance == nil {
 		return ErrSpammerDisabled
 	}
@@ -171,11 +290,40 @@ func start(mpsRateLimit *float64, cpuMaxUsage *float64, spammerWorkers *int) err
 
 	stopWithoutLocking()
 
+	modeCfg := deps.NodeConfig.String(CfgSpammerMode)
 	mpsRateLimitCfg := deps.NodeConfig.Float64(CfgSpammerMPSRateLimit)
 	cpuMaxUsageCfg := deps.NodeConfig.Float64(CfgSpammerCPUMaxUsage)
 	spammerWorkerCount := deps.NodeConfig.Int(CfgSpammerWorkers)
 	checkPeersConnected := Plugin.Node.IsSkipped(coordinator.Plugin)
 
+	if mode != nil {
+		modeCfg = *mode
+	}
+
+	switch modeCfg {
+	case spammerModeIndexation:
+	case spammerModeValue:
+		if spammerInstance.Wallet() == nil {
+			return errors.WithMessage(echo.ErrBadRequest, "value spam needs a seed in the environment variable 'SPAMMER_SEED'")
+		}
+	case spammerModeScenario:
+		if spammerScenario == nil {
+			return errors.WithMessagef(echo.ErrBadRequest, "scenario spam needs a scenario file in '%s'", CfgSpammerScenarioPath)
+		}
+		if spammerScenario.NeedsWallet() && spammerInstance.Wallet() == nil {
+			return errors.WithMessage(echo.ErrBadRequest, "the scenario contains transactions and needs a seed in the environment variable 'SPAMMER_SEED'")
+		}
+		if spammerScenario.HasConflicts() && spammerInstance.Wallet().AddressCount() < 2 {
+			return errors.WithMessagef(echo.ErrBadRequest, "the scenario contains conflicts and needs at least two addresses in '%s'", CfgSpammerValueAddressCount)
+		}
+		if mpsRateLimit == nil && spammerScenario.MPSTarget > 0 {
+			// the target MPS of the scenario is used if no rate limit was given
+			mpsRateLimitCfg = spammerScenario.MPSTarget
+		}
+	default:
+		return errors.WithMessagef(echo.ErrBadRequest, "%s: %s", ErrUnknownSpammerMode, modeCfg)
+	}
+
 	if mpsRateLimit != nil {
 		mpsRateLimitCfg = *mpsRateLimit
 	}
@@ -205,17 +353,23 @@ func start(mpsRateLimit *float64, cpuMaxUsage *float64, spammerWorkers *int) err
 		spammerWorkerCount = 1
 	}
 
-	startSpammerWorkers(mpsRateLimitCfg, cpuMaxUsageCfg, spammerWorkerCount, checkPeersConnected)
+	// the statistics of the tracker always belong to the current run of the spammer
+	deps.Tracker.Reset()
+
+	startSpammerWorkers(modeCfg, mpsRateLimitCfg, cpuMaxUsageCfg, spammerWorkerCount, checkPeersConnected)
 
 	return nil
 }
 
-func startSpammerWorkers(mpsRateLimit float64, cpuMaxUsage float64, spammerWorkerCount int, checkPeersConnected bool) {
+func startSpammerWorkers(mode string, mpsRateLimit float64, cpuMaxUsage float64, spammerWorkerCount int, checkPeersConnected bool) {
+	modeRunning = mode
 	mpsRateLimitRunning = mpsRateLimit
 	cpuMaxUsageRunning = cpuMaxUsage
 	spammerWorkersRunning = spammerWorkerCount
 	isRunning = true
 
+	attachEvents()
+
 	var rateLimitChannel chan struct{} = nil
 	var rateLimitAbortSignal chan struct{} = nil
 
@@ -279,6 +433,16 @@ func startSpammerWorkers(mpsRateLimit float64, cpuMaxUsage float64, spammerWorke
 		}
 	}
 
+	doSpam := spammerInstance.DoSpam
+	switch mode {
+	case spammerModeValue:
+		doSpam = spammerInstance.DoValueSpam
+	case spammerModeScenario:
+		doSpam = func(shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
+			return spammerInstance.DoScenarioSpam(spammerScenario.NextEntry(), shutdownSignal)
+		}
+	}
+
 	spammerCnt := atomic.NewInt32(0)
 	for i := 0; i < spammerWorkerCount; i++ {
 		if err := Plugin.Daemon().BackgroundWorker(fmt.Sprintf("Spammer_%d", i), func(shutdownSignal <-chan struct{}) {
@@ -290,6 +454,8 @@ func startSpammerWorkers(mpsRateLimit float64, cpuMaxUsage float64, spammerWorke
 
 			Plugin.LogInfof("Starting Spammer %d... done", spammerIndex)
 
+			errorBackoff := time.Duration(0)
+
 		spammerLoop:
 			for {
 				select {
@@ -334,10 +500,32 @@ func startSpammerWorkers(mpsRateLimit float64, cpuMaxUsage float64, spammerWorke
 						spammerStartTime = time.Now()
 					}
 
-					durationGTTA, durationPOW, err := spammerInstance.DoSpam(shutdownSignal)
+					durationGTTA, durationPOW, err := doSpam(shutdownSignal)
 					if err != nil {
+						if errors.Is(err, common.ErrOperationAborted) {
+							continue
+						}
+
+						// wait before the next attempt, e.g. until the wallet is funded or the pending transactions are confirmed
+						switch {
+						case errorBackoff == 0:
+							errorBackoff = spamErrorBackoffMin
+						case errorBackoff < spamErrorBackoffMax:
+							errorBackoff *= 2
+							if errorBackoff > spamErrorBackoffMax {
+								errorBackoff = spamErrorBackoffMax
+							}
+						}
+
+						select {
+						case <-shutdownSignal:
+							break spammerLoop
+						case <-time.After(errorBackoff):
+						}
 						continue
 					}
+					errorBackoff = 0
+
 					Events.SpamPerformed.Trigger(&spammer.SpamStats{Tipselection: float32(durationGTTA.Seconds()), ProofOfWork: float32(durationPOW.Seconds())})
 				}
 			}
@@ -350,6 +538,25 @@ func startSpammerWorkers(mpsRateLimit float64, cpuMaxUsage float64, spammerWorke
 	}
 }
 
+// belowMaxDepthParents returns the message of the milestone that is just below max depth.
+func belowMaxDepthParents() (hornet.MessageIDs, error) {
+
+	cmi := deps.SyncManager.ConfirmedMilestoneIndex()
+	if cmi <= milestone.Index(deps.BelowMaxDepth)+1 {
+		return nil, fmt.Errorf("%w: no milestone below max depth", tipselect.ErrNoTipsAvailable)
+	}
+
+	msIndex := cmi - milestone.Index(deps.BelowMaxDepth) - 1
+
+	cachedMs := deps.Storage.CachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMs == nil {
+		return nil, fmt.Errorf("%w: milestone %d not found", tipselect.ErrNoTipsAvailable, msIndex)
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	return hornet.MessageIDs{cachedMs.Milestone().MessageID}, nil
+}
+
 // stop stops the spammer.
 func stop() error {
 	if spammerInstance == nil {
@@ -373,6 +580,8 @@ func stopWithoutLocking() {
 	// wait until all spammers are stopped
 	spammerWaitGroup.Wait()
 
+	detachEvents()
+
 	// reset the start time to stop the metrics
 	spammerStartTime = time.Time{}
 